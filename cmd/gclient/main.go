@@ -2,13 +2,29 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/ArtemShalinFe/gophkeeper/internal/build"
+	"github.com/ArtemShalinFe/gophkeeper/internal/cli"
 	"github.com/ArtemShalinFe/gophkeeper/internal/client"
+	"github.com/ArtemShalinFe/gophkeeper/internal/config"
+	"github.com/ArtemShalinFe/gophkeeper/internal/tracing"
 	"go.uber.org/zap"
 )
 
+const (
+	// tracesFile - the file in the data directory where the TUI writes the spans of the stdout exporter.
+	tracesFile     = "traces.json"
+	tracesFilePerm = 0o600
+	dataDirPerm    = 0o700
+)
+
 func main() {
 	ctx := context.Background()
 	log, err := zap.NewProduction()
@@ -17,6 +33,85 @@ func main() {
 		return
 	}
 
+	cfg, args, err := config.LoadClientCfg(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(cli.ExitOK)
+		}
+		fmt.Fprintf(os.Stderr, "gclient: %v\n", err)
+		os.Exit(cli.ExitUsage)
+	}
+
+	if len(args) > 0 {
+		shutdownTracing, err := tracing.Setup(ctx, cfg.TracesExporter, "gclient", os.Stderr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gclient: %v\n", err)
+			os.Exit(cli.ExitUsage)
+		}
+
+		c := cli.NewCLI(log, os.Stdin, os.Stdout, os.Stderr)
+		c.SetConfig(cfg)
+		code := c.Run(ctx, args)
+		flushTraces(log, shutdownTracing)
+		os.Exit(code)
+	}
+
+	// The terminal is drawn by the TUI, so the spans of the stdout exporter are written to a file.
+	traces, err := openTracesFile(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gclient: %v\n", err)
+		os.Exit(cli.ExitUsage)
+	}
+	var w io.Writer
+	if traces != nil {
+		w = traces
+	}
+	shutdownTracing, err := tracing.Setup(ctx, cfg.TracesExporter, "gclient", w)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gclient: %v\n", err)
+		os.Exit(cli.ExitUsage)
+	}
+
 	log.Info(fmt.Sprintf("%s", build.NewBuild()))
-	client.NewApp(log).Start(ctx)
+	client.NewApp(log, cfg).Start(ctx)
+	flushTraces(log, shutdownTracing)
+	if traces != nil {
+		if err := traces.Close(); err != nil {
+			log.Error("an error occured while close traces file", zap.Error(err))
+		}
+	}
+}
+
+// openTracesFile - opens the file in the data directory that the spans of the stdout exporter are appended to.
+// Returns nil if the stdout exporter is not used.
+func openTracesFile(cfg *config.ClientCfg) (*os.File, error) {
+	if cfg.TracesExporter != tracing.ExporterStdout {
+		return nil, nil
+	}
+
+	dir, err := cfg.GetDataDir()
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while get data dir, err: %w", err)
+	}
+	if err := os.MkdirAll(dir, dataDirPerm); err != nil {
+		return nil, fmt.Errorf("an error occured while create data dir, err: %w", err)
+	}
+
+	f, err := os.OpenFile(filepath.Join(dir, tracesFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, tracesFilePerm)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while open traces file, err: %w", err)
+	}
+
+	return f, nil
+}
+
+// flushTraces - sends the spans that have not been exported yet.
+func flushTraces(log *zap.Logger, shutdown tracing.Shutdown) {
+	const flushTimeout = 5 * time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+	if err := shutdown(ctx); err != nil {
+		log.Error("an error occured while flush traces", zap.Error(err))
+	}
 }
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/admin"
+	"github.com/ArtemShalinFe/gophkeeper/internal/config"
+	"github.com/ArtemShalinFe/gophkeeper/internal/storage/sql"
+)
+
+// runAdmin - runs the administrative command against the database of the configuration
+// and returns the exit code of the program.
+func runAdmin(cfg *config.ServerCfg, args []string) int {
+	ctx, cancelCtx := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGQUIT)
+	defer cancelCtx()
+
+	// The output of the command is meant for the operator, so only the warnings of the storage are logged.
+	log, err := newLogger("warn")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gserver admin: %v\n", err)
+		return admin.ExitError
+	}
+	defer log.Sync() //nolint:errcheck // nothing to do with the error of the flush at exit
+
+	// The migrations are applied only by the server, so the check command sees the schema as it is.
+	db, err := sql.OpenDB(ctx, cfg.DSN, sql.PoolCfg{
+		MaxConns:        cfg.DBMaxConns,
+		MinConns:        cfg.DBMinConns,
+		MaxConnLifetime: cfg.DBMaxConnLifetime,
+		MaxConnIdleTime: cfg.DBMaxConnIdleTime,
+	}, log)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gserver admin: an error occured when init db, err: %v\n", err)
+		return admin.ExitError
+	}
+	defer db.Close()
+
+	return admin.NewAdmin(os.Stdout, os.Stderr, db, cfg.TombstoneRetention).Run(ctx, args)
+}
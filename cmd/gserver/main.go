@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -12,24 +14,80 @@ import (
 	"time"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
 	"github.com/ArtemShalinFe/gophkeeper/internal/build"
 	"github.com/ArtemShalinFe/gophkeeper/internal/config"
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
 	"github.com/ArtemShalinFe/gophkeeper/internal/server"
 	"github.com/ArtemShalinFe/gophkeeper/internal/storage/sql"
+	"github.com/ArtemShalinFe/gophkeeper/internal/tracing"
 )
 
 const timeoutShutdown = time.Second * 30
 const timeoutServerShutdown = time.Second * 10
 const componentsCount = 3
 
+// exitUsage - The exit code of the invalid flags or configuration.
+const exitUsage = 2
+
 func main() {
-	if err := run(); err != nil {
+	if len(os.Args) > 1 && os.Args[1] == "version" {
+		b := build.NewBuild()
+		fmt.Printf("Server version: %s\n", b.Version())
+		fmt.Printf("Server build date: %s\n", b.Date())
+		fmt.Printf("Server build commit: %s\n", b.Commit())
+		fmt.Printf("Server protocol: %d (supports %d-%d)\n",
+			models.ProtocolVersion, models.MinProtocolVersion, models.ProtocolVersion)
+		return
+	}
+
+	cfg, args, err := config.LoadServerCfg(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintf(os.Stderr, "gserver: %v\n", err)
+		os.Exit(exitUsage)
+	}
+
+	if len(args) > 0 {
+		if args[0] != "admin" {
+			fmt.Fprintf(os.Stderr, "gserver: unexpected arguments %v\n", args)
+			os.Exit(exitUsage)
+		}
+		os.Exit(runAdmin(cfg, args[1:]))
+	}
+
+	if cfg.PrintConfig {
+		if err := cfg.Print(os.Stdout); err != nil {
+			log.Fatalf("an occured fatal error, err: %v", err)
+		}
+		return
+	}
+
+	if err := run(cfg); err != nil {
 		log.Fatalf("an occured fatal error, err: %v", err)
 	}
 }
 
-func run() error {
+// newLogger - returns the production logger with the level of the configuration.
+func newLogger(level string) (*zap.Logger, error) {
+	lvl, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while parse log level, err: %w", err)
+	}
+
+	zcfg := zap.NewProductionConfig()
+	zcfg.Level = zap.NewAtomicLevelAt(lvl)
+	l, err := zcfg.Build()
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while build logger, err: %w", err)
+	}
+	return l, nil
+}
+
+func run(cfg *config.ServerCfg) error {
 	ctx, cancelCtx := signal.NotifyContext(context.Background(),
 		os.Interrupt,
 		os.Kill,
@@ -38,7 +96,7 @@ func run() error {
 
 	defer cancelCtx()
 
-	log, err := zap.NewProduction()
+	log, err := newLogger(cfg.LogLevel)
 	if err != nil {
 		return fmt.Errorf("an error occured while init logger err: %w ", err)
 	}
@@ -74,14 +132,31 @@ func run() error {
 		}
 	}(log, componentsErrs)
 
-	cfg := config.NewServerCfg()
-	if err := config.ReadEnvServerCfg(cfg); err != nil {
-		return fmt.Errorf("an error occured when reading environment for server config, err: %w", err)
+	shutdownTracing, err := tracing.Setup(ctx, cfg.TracesExporter, "gserver", os.Stdout)
+	if err != nil {
+		return fmt.Errorf("an error occured when setup tracing, err: %w", err)
 	}
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), timeoutServerShutdown)
+		defer cancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			log.Error("an error occured when flush traces", zap.Error(err))
+		}
+	}()
+
 	log.Info("server will be running", zap.String("Addr", cfg.Addr))
 
-	db, err := sql.NewDB(ctx, cfg.DSN, log)
+	db, err := sql.NewDB(ctx, cfg.DSN, sql.PoolCfg{
+		MaxConns:        cfg.DBMaxConns,
+		MinConns:        cfg.DBMinConns,
+		MaxConnLifetime: cfg.DBMaxConnLifetime,
+		MaxConnIdleTime: cfg.DBMaxConnIdleTime,
+	}, log)
 	if err != nil {
 		return fmt.Errorf("an error occured when init db, err: %w", err)
 	}
@@ -97,9 +172,14 @@ func run() error {
 
 	log.Info("database is connected")
 
-	gkServer, err := server.InitServer(db, db, log, cfg)
+	gkServer, err := server.InitServer(db, db, db, db, log, cfg)
 	if err != nil {
-		componentsErrs <- fmt.Errorf("an occured error when init server, err: %w", err)
+		return fmt.Errorf("an occured error when init server, err: %w", err)
+	}
+	gkServer.AddDependency("postgres", db.Ping)
+	gkServer.AddDependency("migrations", db.CheckMigrations)
+	if err := gkServer.AddCollector(db.Collector()); err != nil {
+		return err
 	}
 	go func(srv *server.GKServer, errs chan<- error) {
 		if err := srv.ListenAndServe(); err != nil {
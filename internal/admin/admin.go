@@ -0,0 +1,179 @@
+// Package admin - The administrative commands of the gophkeeper server, they work with the storage of the server.
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+// Exit codes of the commands.
+const (
+	// ExitOK - the command was completed successfully.
+	ExitOK = 0
+	// ExitError - the command failed or the check has found problems.
+	ExitError = 1
+	// ExitUsage - the command or its arguments are not correct.
+	ExitUsage = 2
+	// ExitNotFound - the user was not found.
+	ExitNotFound = 4
+)
+
+// errUsage - An error that is returned if the command or its arguments are not correct.
+var errUsage = errors.New("usage error")
+
+// Storage - The storages that the commands work with, the database of the server implements all of them.
+type Storage interface {
+	models.AdminStorage
+	models.RecordStorage
+	models.DeviceStorage
+	models.AuditStorage
+}
+
+// migrationChecker - An optional interface of the storage that checks the schema of the database.
+type migrationChecker interface {
+	CheckMigrations(ctx context.Context) error
+}
+
+// command - The description of the subcommand.
+type command struct {
+	run   func(ctx context.Context, a *Admin, args []string) error
+	usage string
+}
+
+var commands = map[string]command{
+	"users":           {run: runUsers, usage: "users [--json]"},
+	"disable":         {run: runDisable, usage: "disable LOGIN"},
+	"enable":          {run: runEnable, usage: "enable LOGIN"},
+	"reset-password":  {run: runResetPassword, usage: "reset-password LOGIN [--password P]"},
+	"revoke-sessions": {run: runRevokeSessions, usage: "revoke-sessions LOGIN"},
+	"usage":           {run: runUsage, usage: "usage [LOGIN] [--json]"},
+	"purge":           {run: runPurge, usage: "purge [--older-than D] [--json]"},
+	"check":           {run: runCheck, usage: "check [--json]"},
+}
+
+// Admin - The object that runs one administrative command.
+type Admin struct {
+	stdout io.Writer
+	stderr io.Writer
+	stg    Storage
+	// retention - the deleted records older than it are removed by the purge by default.
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewAdmin - Object Constructor.
+func NewAdmin(stdout io.Writer, stderr io.Writer, stg Storage, retention time.Duration) *Admin {
+	return &Admin{
+		stdout:    stdout,
+		stderr:    stderr,
+		stg:       stg,
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// Run - Runs the command and returns the exit code of the program.
+func (a *Admin) Run(ctx context.Context, args []string) int {
+	err := a.run(ctx, args)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintf(a.stderr, "gserver admin: %v\n", err)
+	}
+	if errors.Is(err, errUsage) {
+		a.printUsage()
+	}
+
+	return exitCode(err)
+}
+
+func (a *Admin) run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: command is not specified", errUsage)
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("%w: unknown command %q", errUsage, args[0])
+	}
+
+	return cmd.run(ctx, a, args[1:])
+}
+
+func (a *Admin) printUsage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(a.stderr, "Usage: gserver [FLAGS] admin COMMAND [ARGS]\n\nCommands:")
+	for _, name := range names {
+		fmt.Fprintf(a.stderr, "  %s\n", commands[name].usage)
+	}
+}
+
+func newFlagSet(a *Admin, name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	return fs
+}
+
+// parseArgs - parses flags that may be placed before, after or between positional arguments.
+// Returns the positional arguments.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var pos []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, flag.ErrHelp
+			}
+			return nil, fmt.Errorf("%w: %w", errUsage, err)
+		}
+		if fs.NArg() == 0 {
+			return pos, nil
+		}
+		pos = append(pos, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// loginArg - returns the only positional argument, the login of the user.
+func loginArg(fs *flag.FlagSet, args []string) (string, error) {
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return "", err
+	}
+	if len(pos) != 1 {
+		return "", fmt.Errorf("%w: exactly one login is expected", errUsage)
+	}
+	return pos[0], nil
+}
+
+func (a *Admin) printJSON(v any) error {
+	enc := json.NewEncoder(a.stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("an error occured while encode output, err: %w", err)
+	}
+	return nil
+}
+
+func exitCode(err error) int {
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return ExitOK
+	case errors.Is(err, errUsage):
+		return ExitUsage
+	case errors.Is(err, models.ErrUnknowUser):
+		return ExitNotFound
+	default:
+		return ExitError
+	}
+}
@@ -0,0 +1,176 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	gomock "go.uber.org/mock/gomock"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+// testStorage - the storage of the commands that is made of the mocks.
+type testStorage struct {
+	*models.MockAdminStorage
+	*models.MockRecordStorage
+	*models.MockDeviceStorage
+	*models.MockAuditStorage
+}
+
+func newTestStorage(t *testing.T) *testStorage {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	return &testStorage{
+		MockAdminStorage:  models.NewMockAdminStorage(ctrl),
+		MockRecordStorage: models.NewMockRecordStorage(ctrl),
+		MockDeviceStorage: models.NewMockDeviceStorage(ctrl),
+		MockAuditStorage:  models.NewMockAuditStorage(ctrl),
+	}
+}
+
+func newTextRecord(t *testing.T, id string) *models.Record {
+	t.Helper()
+
+	r, err := models.NewRecord(id, "note", models.TextType, time.Now(), time.Now(), &models.Text{Data: "text"}, nil, false, 1)
+	if err != nil {
+		t.Fatalf("an error occured while create record, err: %v", err)
+	}
+	return r
+}
+
+func TestAdmin_Run(t *testing.T) {
+	u := &models.UserInfo{ID: "5e0b9b7f-0f3c-4c5e-9d3a-1e0c1f5f6a11", Login: "alice", Created: time.Now()}
+	now := time.Date(2023, time.November, 20, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		args       []string
+		prepare    func(t *testing.T, stg *testStorage)
+		wantCode   int
+		wantStdout string
+	}{
+		{
+			name:     "command is not specified",
+			wantCode: ExitUsage,
+		},
+		{
+			name:     "unknown command",
+			args:     []string{"drop"},
+			wantCode: ExitUsage,
+		},
+		{
+			name:     "login is not specified",
+			args:     []string{"disable"},
+			wantCode: ExitUsage,
+		},
+		{
+			name: "unknown user",
+			args: []string{"enable", "bob"},
+			prepare: func(t *testing.T, stg *testStorage) {
+				stg.MockAdminStorage.EXPECT().GetUserInfo(gomock.Any(), "bob").Return(nil, models.ErrUnknowUser)
+			},
+			wantCode: ExitNotFound,
+		},
+		{
+			name: "disable revokes active sessions",
+			args: []string{"disable", u.Login},
+			prepare: func(t *testing.T, stg *testStorage) {
+				stg.MockAdminStorage.EXPECT().GetUserInfo(gomock.Any(), u.Login).Return(u, nil)
+				stg.MockAdminStorage.EXPECT().SetUserDisabled(gomock.Any(), u.ID, true).Return(nil)
+				stg.MockDeviceStorage.EXPECT().ListDevices(gomock.Any(), u.ID).Return([]*models.Device{
+					{ID: "d1"}, {ID: "d2", Revoked: true},
+				}, nil)
+				stg.MockDeviceStorage.EXPECT().RevokeDevice(gomock.Any(), u.ID, "d1").Return(nil)
+				stg.MockAuditStorage.EXPECT().AddAuditEvents(gomock.Any(), gomock.Len(1)).
+					DoAndReturn(func(_ context.Context, es []*models.AuditEvent) error {
+						if es[0].UserID != u.ID || es[0].Action != models.AuditUserDisable {
+							t.Errorf("AddAuditEvents() event = %+v, want %s of the user", es[0], models.AuditUserDisable)
+						}
+						return nil
+					})
+				stg.MockAuditStorage.EXPECT().AddAuditEvents(gomock.Any(), gomock.Len(1)).
+					DoAndReturn(func(_ context.Context, es []*models.AuditEvent) error {
+						if es[0].Action != models.AuditDeviceRevoke || es[0].DeviceID != "d1" {
+							t.Errorf("AddAuditEvents() event = %+v, want %s of d1", es[0], models.AuditDeviceRevoke)
+						}
+						return nil
+					})
+			},
+			wantCode:   ExitOK,
+			wantStdout: "user alice is disabled, 1 sessions revoked",
+		},
+		{
+			name: "enable succeeds if the audit log fails",
+			args: []string{"enable", u.Login},
+			prepare: func(t *testing.T, stg *testStorage) {
+				stg.MockAdminStorage.EXPECT().GetUserInfo(gomock.Any(), u.Login).Return(u, nil)
+				stg.MockAdminStorage.EXPECT().SetUserDisabled(gomock.Any(), u.ID, false).Return(nil)
+				stg.MockAuditStorage.EXPECT().AddAuditEvents(gomock.Any(), gomock.Len(1)).
+					Return(errors.New("connection refused"))
+			},
+			wantCode:   ExitOK,
+			wantStdout: "user alice is enabled",
+		},
+		{
+			name: "purge uses the retention by default",
+			args: []string{"purge", "--json"},
+			prepare: func(t *testing.T, stg *testStorage) {
+				stg.MockAdminStorage.EXPECT().PurgeTombstones(gomock.Any(), now.Add(-time.Hour)).Return(3, nil)
+			},
+			wantCode:   ExitOK,
+			wantStdout: `"purged": 3`,
+		},
+		{
+			name: "usage of the user",
+			args: []string{"usage", u.Login},
+			prepare: func(t *testing.T, stg *testStorage) {
+				stg.MockAdminStorage.EXPECT().GetUserInfo(gomock.Any(), u.Login).Return(u, nil)
+				stg.MockAdminStorage.EXPECT().Usage(gomock.Any(), u.ID).Return(&models.Usage{Records: 2, Bytes: 10}, nil)
+				stg.MockDeviceStorage.EXPECT().ListDevices(gomock.Any(), u.ID).Return(nil, nil)
+			},
+			wantCode:   ExitOK,
+			wantStdout: "alice  2        10     0        never",
+		},
+		{
+			name: "check finds corrupted record",
+			args: []string{"check"},
+			prepare: func(t *testing.T, stg *testStorage) {
+				good := newTextRecord(t, "r1")
+				bad := newTextRecord(t, "r2")
+				bad.Hashsum = "corrupted"
+				stg.MockAdminStorage.EXPECT().ListUsers(gomock.Any()).Return([]*models.UserInfo{u}, nil)
+				stg.MockRecordStorage.EXPECT().ListRecords(gomock.Any(), u.ID, 0, models.DefaultLimit).
+					Return([]*models.Record{good, bad}, nil)
+				stg.MockRecordStorage.EXPECT().ListRecords(gomock.Any(), u.ID, models.DefaultLimit, models.DefaultLimit).
+					Return(nil, nil)
+			},
+			wantCode:   ExitError,
+			wantStdout: "2 records checked, 1 problems found",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			stg := newTestStorage(t)
+			if tt.prepare != nil {
+				tt.prepare(t, stg)
+			}
+
+			stdout := &bytes.Buffer{}
+			a := NewAdmin(stdout, &bytes.Buffer{}, stg, time.Hour)
+			a.now = func() time.Time { return now }
+
+			if got := a.Run(context.Background(), tt.args); got != tt.wantCode {
+				t.Errorf("Admin.Run() = %v, want %v", got, tt.wantCode)
+			}
+			if !strings.Contains(stdout.String(), tt.wantStdout) {
+				t.Errorf("Admin.Run() stdout = %q, want to contain %q", stdout.String(), tt.wantStdout)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+// errIntegrity - The error is returned if the check has found problems in the data.
+var errIntegrity = errors.New("integrity check has failed")
+
+// problemView - The representation of the problem found by the check in the command output.
+type problemView struct {
+	Login    string `json:"login,omitempty"`
+	RecordID string `json:"record_id,omitempty"`
+	Problem  string `json:"problem"`
+}
+
+func runCheck(ctx context.Context, a *Admin, args []string) error {
+	fs := newFlagSet(a, "check")
+	asJSON := fs.Bool("json", false, "print the problems as JSON")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	problems, checked, err := a.check(ctx)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		if err := a.printJSON(problems); err != nil {
+			return err
+		}
+	} else {
+		w := a.tabWriter()
+		if len(problems) > 0 {
+			fmt.Fprintln(w, "LOGIN\tRECORD\tPROBLEM")
+		}
+		for _, p := range problems {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", p.Login, p.RecordID, p.Problem)
+		}
+		if err := w.Flush(); err != nil {
+			return fmt.Errorf("an error occured while print problems, err: %w", err)
+		}
+		fmt.Fprintf(a.stdout, "%d records checked, %d problems found\n", checked, len(problems))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %d problems found", errIntegrity, len(problems))
+	}
+	return nil
+}
+
+// check - checks the schema of the storage and every record that is not deleted:
+// the data must match the hashsum and must be decoded according to the type of the record.
+// Returns the problems and the number of the checked records.
+func (a *Admin) check(ctx context.Context) ([]*problemView, int, error) {
+	var problems []*problemView
+
+	if mc, ok := a.stg.(migrationChecker); ok {
+		if err := mc.CheckMigrations(ctx); err != nil {
+			problems = append(problems, &problemView{Problem: err.Error()})
+		}
+	}
+
+	us, err := a.stg.ListUsers(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("an error occured while list users, err: %w", err)
+	}
+
+	checked := 0
+	for _, u := range us {
+		// The records are listed in the order of their ID, so the pages neither skip nor repeat them.
+		for offset := 0; ; offset += models.DefaultLimit {
+			rs, err := a.stg.ListRecords(ctx, u.ID, offset, models.DefaultLimit)
+			if err != nil {
+				return nil, 0, fmt.Errorf("an error occured while list records of user %s, err: %w", u.Login, err)
+			}
+			if len(rs) == 0 {
+				break
+			}
+
+			for _, r := range rs {
+				if r.Deleted {
+					continue
+				}
+				checked++
+				if err := checkRecord(r); err != nil {
+					problems = append(problems, &problemView{Login: u.Login, RecordID: r.ID, Problem: err.Error()})
+				}
+			}
+		}
+	}
+
+	return problems, checked, nil
+}
+
+func checkRecord(r *models.Record) error {
+	if err := r.VerifyHashsum(); err != nil {
+		return err
+	}
+	if _, err := r.DecodeData(); err != nil {
+		return fmt.Errorf("an error occured while decode record data, err: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,334 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+const (
+	timeFormat  = time.RFC3339
+	tabMinWidth = 0
+	tabWidth    = 4
+	tabPadding  = 2
+	tabPadChar  = ' '
+	never       = "never"
+)
+
+// userView - The representation of the user in the command output.
+type userView struct {
+	ID       string    `json:"id"`
+	Login    string    `json:"login"`
+	Created  time.Time `json:"created"`
+	Disabled bool      `json:"disabled"`
+}
+
+// usageView - The representation of the usage of the user in the command output.
+type usageView struct {
+	Login    string     `json:"login"`
+	Records  int        `json:"records"`
+	Bytes    int64      `json:"bytes"`
+	Devices  int        `json:"devices"`
+	LastSync *time.Time `json:"last_sync,omitempty"`
+}
+
+func (a *Admin) tabWriter() *tabwriter.Writer {
+	return tabwriter.NewWriter(a.stdout, tabMinWidth, tabWidth, tabPadding, tabPadChar, 0)
+}
+
+func runUsers(ctx context.Context, a *Admin, args []string) error {
+	fs := newFlagSet(a, "users")
+	asJSON := fs.Bool("json", false, "print users as JSON")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	us, err := a.stg.ListUsers(ctx)
+	if err != nil {
+		return fmt.Errorf("an error occured while list users, err: %w", err)
+	}
+
+	views := make([]*userView, 0, len(us))
+	for _, u := range us {
+		views = append(views, &userView{ID: u.ID, Login: u.Login, Created: u.Created, Disabled: u.Disabled})
+	}
+	if *asJSON {
+		return a.printJSON(views)
+	}
+
+	w := a.tabWriter()
+	fmt.Fprintln(w, "LOGIN\tID\tCREATED\tSTATUS")
+	for _, v := range views {
+		st := "active"
+		if v.Disabled {
+			st = "disabled"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", v.Login, v.ID, v.Created.Format(timeFormat), st)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("an error occured while print users, err: %w", err)
+	}
+	return nil
+}
+
+func runDisable(ctx context.Context, a *Admin, args []string) error {
+	login, err := loginArg(newFlagSet(a, "disable"), args)
+	if err != nil {
+		return err
+	}
+
+	u, err := a.stg.GetUserInfo(ctx, login)
+	if err != nil {
+		return fmt.Errorf("an error occured while retrieving user %s, err: %w", login, err)
+	}
+	if err := a.stg.SetUserDisabled(ctx, u.ID, true); err != nil {
+		return fmt.Errorf("an error occured while disable user %s, err: %w", login, err)
+	}
+	a.audit(ctx, a.auditEvent(u.ID, models.AuditUserDisable))
+	// The devices that have already logged in would keep working, so their sessions are ended as well.
+	n, err := a.revokeSessions(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "user %s is disabled, %d sessions revoked\n", login, n)
+	return nil
+}
+
+func runEnable(ctx context.Context, a *Admin, args []string) error {
+	login, err := loginArg(newFlagSet(a, "enable"), args)
+	if err != nil {
+		return err
+	}
+
+	u, err := a.stg.GetUserInfo(ctx, login)
+	if err != nil {
+		return fmt.Errorf("an error occured while retrieving user %s, err: %w", login, err)
+	}
+	if err := a.stg.SetUserDisabled(ctx, u.ID, false); err != nil {
+		return fmt.Errorf("an error occured while enable user %s, err: %w", login, err)
+	}
+	a.audit(ctx, a.auditEvent(u.ID, models.AuditUserEnable))
+
+	fmt.Fprintf(a.stdout, "user %s is enabled\n", login)
+	return nil
+}
+
+func runResetPassword(ctx context.Context, a *Admin, args []string) error {
+	fs := newFlagSet(a, "reset-password")
+	password := fs.String("password", "", "the new password, a random one is generated by default")
+	login, err := loginArg(fs, args)
+	if err != nil {
+		return err
+	}
+
+	u, err := a.stg.GetUserInfo(ctx, login)
+	if err != nil {
+		return fmt.Errorf("an error occured while retrieving user %s, err: %w", login, err)
+	}
+
+	p := *password
+	if p == "" {
+		s, err := models.GeneratePassword(models.DefaultPasswordOptions())
+		if err != nil {
+			return fmt.Errorf("an error occured while generate password, err: %w", err)
+		}
+		p = s.Value
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(p), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("an error occured while retrieving hash from password err: %w", err)
+	}
+
+	if err := a.stg.SetUserPassword(ctx, u.ID, string(hash)); err != nil {
+		return fmt.Errorf("an error occured while reset password of user %s, err: %w", login, err)
+	}
+	a.audit(ctx, a.auditEvent(u.ID, models.AuditPasswordReset))
+	// All the devices have to log in again with the new password.
+	n, err := a.revokeSessions(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "the password of user %s is reset, %d sessions revoked\n", login, n)
+	if *password == "" {
+		fmt.Fprintf(a.stdout, "new password: %s\n", p)
+	}
+	return nil
+}
+
+func runRevokeSessions(ctx context.Context, a *Admin, args []string) error {
+	login, err := loginArg(newFlagSet(a, "revoke-sessions"), args)
+	if err != nil {
+		return err
+	}
+
+	u, err := a.stg.GetUserInfo(ctx, login)
+	if err != nil {
+		return fmt.Errorf("an error occured while retrieving user %s, err: %w", login, err)
+	}
+	n, err := a.revokeSessions(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "%d sessions of user %s revoked\n", n, login)
+	return nil
+}
+
+// revokeSessions - revokes all the active devices of the user and returns their number.
+func (a *Admin) revokeSessions(ctx context.Context, userID string) (int, error) {
+	ds, err := a.stg.ListDevices(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("an error occured while list devices, err: %w", err)
+	}
+
+	var events []*models.AuditEvent
+	// The devices that have been revoked before the error are written to the audit log as well.
+	defer func() { a.audit(ctx, events...) }()
+
+	for _, d := range ds {
+		if d.Revoked {
+			continue
+		}
+		if err := a.stg.RevokeDevice(ctx, userID, d.ID); err != nil {
+			return len(events), fmt.Errorf("an error occured while revoke device %s, err: %w", d.ID, err)
+		}
+		e := a.auditEvent(userID, models.AuditDeviceRevoke)
+		e.DeviceID = d.ID
+		events = append(events, e)
+	}
+
+	return len(events), nil
+}
+
+// auditEvent - returns the event of the administrative command for the audit log of the user.
+func (a *Admin) auditEvent(userID string, action models.AuditAction) *models.AuditEvent {
+	return &models.AuditEvent{
+		UserID:  userID,
+		Action:  action,
+		Time:    a.now(),
+		Details: "by the administrator",
+	}
+}
+
+// audit - writes the events to the audit log. The command has already been done,
+// so the error of the audit log is reported but does not fail the command.
+func (a *Admin) audit(ctx context.Context, events ...*models.AuditEvent) {
+	if len(events) == 0 {
+		return
+	}
+	if err := a.stg.AddAuditEvents(ctx, events); err != nil {
+		fmt.Fprintf(a.stderr, "gserver admin: an error occured while write audit log, err: %v\n", err)
+	}
+}
+
+func runUsage(ctx context.Context, a *Admin, args []string) error {
+	fs := newFlagSet(a, "usage")
+	asJSON := fs.Bool("json", false, "print usage as JSON")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 1 {
+		return fmt.Errorf("%w: at most one login is expected", errUsage)
+	}
+
+	var us []*models.UserInfo
+	if len(pos) == 1 {
+		u, err := a.stg.GetUserInfo(ctx, pos[0])
+		if err != nil {
+			return fmt.Errorf("an error occured while retrieving user %s, err: %w", pos[0], err)
+		}
+		us = append(us, u)
+	} else {
+		us, err = a.stg.ListUsers(ctx)
+		if err != nil {
+			return fmt.Errorf("an error occured while list users, err: %w", err)
+		}
+	}
+
+	views := make([]*usageView, 0, len(us))
+	for _, u := range us {
+		v, err := a.usage(ctx, u)
+		if err != nil {
+			return err
+		}
+		views = append(views, v)
+	}
+	if *asJSON {
+		return a.printJSON(views)
+	}
+
+	w := a.tabWriter()
+	fmt.Fprintln(w, "LOGIN\tRECORDS\tBYTES\tDEVICES\tLAST SYNC")
+	for _, v := range views {
+		lastSync := never
+		if v.LastSync != nil {
+			lastSync = v.LastSync.Format(timeFormat)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\n", v.Login, v.Records, v.Bytes, v.Devices, lastSync)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("an error occured while print usage, err: %w", err)
+	}
+	return nil
+}
+
+// usage - returns the usage of the storage by the user and the active devices of the user.
+func (a *Admin) usage(ctx context.Context, u *models.UserInfo) (*usageView, error) {
+	usage, err := a.stg.Usage(ctx, u.ID)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while retrieving usage of user %s, err: %w", u.Login, err)
+	}
+	ds, err := a.stg.ListDevices(ctx, u.ID)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while list devices of user %s, err: %w", u.Login, err)
+	}
+
+	v := &usageView{Login: u.Login, Records: usage.Records, Bytes: usage.Bytes}
+	for _, d := range ds {
+		if d.Revoked {
+			continue
+		}
+		v.Devices++
+		if !d.LastSync.IsZero() && (v.LastSync == nil || d.LastSync.After(*v.LastSync)) {
+			lastSync := d.LastSync
+			v.LastSync = &lastSync
+		}
+	}
+
+	return v, nil
+}
+
+func runPurge(ctx context.Context, a *Admin, args []string) error {
+	fs := newFlagSet(a, "purge")
+	olderThan := fs.Duration("older-than", a.retention, "remove the deleted records that are older than this")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if *olderThan < 0 {
+		return fmt.Errorf("%w: --older-than must not be negative", errUsage)
+	}
+
+	before := a.now().Add(-*olderThan)
+	n, err := a.stg.PurgeTombstones(ctx, before)
+	if err != nil {
+		return fmt.Errorf("an error occured while purge deleted records, err: %w", err)
+	}
+
+	if *asJSON {
+		return a.printJSON(struct {
+			Before time.Time `json:"before"`
+			Purged int       `json:"purged"`
+		}{Before: before, Purged: n})
+	}
+	fmt.Fprintf(a.stdout, "%d deleted records older than %s purged\n", n, before.Format(timeFormat))
+	return nil
+}
@@ -0,0 +1,172 @@
+// Package backup - The encrypted archive with all records of the user.
+//
+// The archive is encrypted with the key derived from the passphrase by argon2id.
+// Inside it is a CBOR sequence: the manifest, the records and the trailer with the number of the records.
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+// FormatVersion - The version of the archive content. The reader rejects archives of newer versions.
+const FormatVersion = 1
+
+// ErrCorrupted - The error is returned if the content of the decrypted archive is not valid.
+var ErrCorrupted = errors.New("the backup is corrupted")
+
+// Manifest - The description of the archive, written before the records.
+type Manifest struct {
+	// Version - the version of the archive content.
+	Version int `cbor:"version"`
+	// Created - the date of the backup.
+	Created time.Time `cbor:"created"`
+	// Login - the login of the user whose records are in the archive.
+	Login string `cbor:"login"`
+	// ClientVersion - the version of the client that made the backup.
+	ClientVersion string `cbor:"client_version"`
+}
+
+// trailer - written after the records, allows to detect the lost records.
+type trailer struct {
+	Records int `cbor:"records"`
+}
+
+// entry - an item of the CBOR sequence after the manifest. Exactly one field is set.
+type entry struct {
+	Record  *models.Record `cbor:"record,omitempty"`
+	Trailer *trailer       `cbor:"trailer,omitempty"`
+}
+
+// Writer - Writes the records into the encrypted archive.
+type Writer struct {
+	ew  *encryptWriter
+	enc *cbor.Encoder
+	n   int
+}
+
+// NewWriter - Object Constructor. Writes the header of the archive and the manifest.
+// The version and the creation date of the manifest are filled by the writer.
+func NewWriter(w io.Writer, passphrase []byte, m Manifest) (*Writer, error) {
+	ew, err := newEncryptWriter(w, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	m.Version = FormatVersion
+	m.Created = time.Now()
+
+	bw := &Writer{ew: ew, enc: cbor.NewEncoder(ew)}
+	if err := bw.enc.Encode(m); err != nil {
+		return nil, fmt.Errorf("an error occured while write manifest, err: %w", err)
+	}
+
+	return bw, nil
+}
+
+// WriteRecord - Writes the record into the archive.
+func (bw *Writer) WriteRecord(r *models.Record) error {
+	if err := bw.enc.Encode(entry{Record: r}); err != nil {
+		return fmt.Errorf("an error occured while write record %s, err: %w", r.ID, err)
+	}
+	bw.n++
+
+	return nil
+}
+
+// Count - Returns the number of the written records.
+func (bw *Writer) Count() int {
+	return bw.n
+}
+
+// Close - Writes the trailer and finishes the archive. The underlying writer is not closed.
+func (bw *Writer) Close() error {
+	if err := bw.enc.Encode(entry{Trailer: &trailer{Records: bw.n}}); err != nil {
+		return fmt.Errorf("an error occured while write trailer, err: %w", err)
+	}
+
+	return bw.ew.Close()
+}
+
+// Reader - Reads the records from the encrypted archive.
+type Reader struct {
+	dec      *cbor.Decoder
+	manifest *Manifest
+	n        int
+	done     bool
+}
+
+// NewReader - Object Constructor. Reads the header of the archive and the manifest.
+// Returns ErrDecrypt if the passphrase is wrong.
+func NewReader(r io.Reader, passphrase []byte) (*Reader, error) {
+	dr, err := newDecryptReader(r, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	br := &Reader{dec: cbor.NewDecoder(dr)}
+
+	var m Manifest
+	if err := br.dec.Decode(&m); err != nil {
+		return nil, decodeErr("manifest", err)
+	}
+	if m.Version < 1 || m.Version > FormatVersion {
+		return nil, fmt.Errorf("%w: archive version %d", ErrNotBackup, m.Version)
+	}
+	br.manifest = &m
+
+	return br, nil
+}
+
+// Manifest - Returns the manifest of the archive.
+func (br *Reader) Manifest() Manifest {
+	return *br.manifest
+}
+
+// Next - Returns the next record of the archive. The hashsum of the record is verified.
+// Returns io.EOF after the last record if the archive is complete.
+func (br *Reader) Next() (*models.Record, error) {
+	if br.done {
+		return nil, io.EOF
+	}
+
+	var e entry
+	if err := br.dec.Decode(&e); err != nil {
+		return nil, decodeErr("record", err)
+	}
+
+	switch {
+	case e.Record != nil:
+		if err := e.Record.VerifyHashsum(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCorrupted, err)
+		}
+		br.n++
+		return e.Record, nil
+	case e.Trailer != nil:
+		if e.Trailer.Records != br.n {
+			return nil, fmt.Errorf("%w: the archive contains %d records, the trailer expects %d",
+				ErrCorrupted, br.n, e.Trailer.Records)
+		}
+		br.done = true
+		return nil, io.EOF
+	default:
+		return nil, fmt.Errorf("%w: empty entry", ErrCorrupted)
+	}
+}
+
+// decodeErr - keeps the errors of decryption and converts other errors into ErrCorrupted.
+func decodeErr(item string, err error) error {
+	if errors.Is(err, ErrDecrypt) || errors.Is(err, ErrTruncated) {
+		return err
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrTruncated
+	}
+	return fmt.Errorf("%w: an error occured while decode %s, err: %w", ErrCorrupted, item, err)
+}
@@ -0,0 +1,181 @@
+package backup
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+const testPassphrase = "correct horse battery staple"
+
+func newTestRecords(t *testing.T, count int) []*models.Record {
+	t.Helper()
+
+	rs := make([]*models.Record, count)
+	for i := range rs {
+		data := make([]byte, i*1000)
+		for j := range data {
+			data[j] = byte(j)
+		}
+		r, err := models.NewRecord(uuid.NewString(), uuid.NewString(), models.BinaryType,
+			time.Now().UTC().Truncate(time.Second), time.Now().UTC().Truncate(time.Second),
+			&models.Binary{Name: "file", Data: data},
+			[]*models.Metadata{{Key: "key", Value: uuid.NewString()}}, false, int64(i+1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rs[i] = r
+	}
+	return rs
+}
+
+func writeTestBackup(t *testing.T, rs []*models.Record) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+	bw, err := NewWriter(&b, []byte(testPassphrase), Manifest{Login: "login", ClientVersion: "v1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range rs {
+		if err := bw.WriteRecord(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := bw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func readTestBackup(data []byte, passphrase string) ([]*models.Record, error) {
+	br, err := NewReader(bytes.NewReader(data), []byte(passphrase))
+	if err != nil {
+		return nil, err
+	}
+
+	var rs []*models.Record
+	for {
+		r, err := br.Next()
+		if errors.Is(err, io.EOF) {
+			return rs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		rs = append(rs, r)
+	}
+}
+
+func TestBackup_RoundTrip(t *testing.T) {
+	rs := newTestRecords(t, 200)
+	data := writeTestBackup(t, rs)
+
+	if bytes.Contains(data, rs[1].Data) {
+		t.Error("the backup contains the record data in plain text")
+	}
+
+	br, err := NewReader(bytes.NewReader(data), []byte(testPassphrase))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	if m := br.Manifest(); m.Version != FormatVersion || m.Login != "login" || m.ClientVersion != "v1" {
+		t.Errorf("Reader.Manifest() = %v", m)
+	}
+
+	got, err := readTestBackup(data, testPassphrase)
+	if err != nil {
+		t.Fatalf("Reader.Next() error = %v", err)
+	}
+	if len(got) != len(rs) {
+		t.Fatalf("Reader.Next() got %d records, want %d", len(got), len(rs))
+	}
+	for i, r := range got {
+		want := rs[i]
+		if r.ID != want.ID || r.Hashsum != want.Hashsum || !bytes.Equal(r.Data, want.Data) ||
+			!r.Created.Equal(want.Created) || !r.Modified.Equal(want.Modified) || r.Version != want.Version ||
+			!reflect.DeepEqual(r.Metadata, want.Metadata) {
+			t.Errorf("Reader.Next() record = %+v, want %+v", r, want)
+		}
+	}
+}
+
+func TestBackup_Errors(t *testing.T) {
+	data := writeTestBackup(t, newTestRecords(t, 100))
+
+	tampered := bytes.Clone(data)
+	tampered[len(tampered)/2] ^= 0xff
+
+	tests := []struct {
+		name       string
+		data       []byte
+		passphrase string
+		wantErr    error
+	}{
+		{
+			name:       "wrong passphrase",
+			data:       data,
+			passphrase: "wrong",
+			wantErr:    ErrDecrypt,
+		},
+		{
+			name:       "tampered",
+			data:       tampered,
+			passphrase: testPassphrase,
+			wantErr:    ErrDecrypt,
+		},
+		{
+			name:       "truncated",
+			data:       data[:len(data)-100],
+			passphrase: testPassphrase,
+			wantErr:    ErrTruncated,
+		},
+		{
+			name:       "last chunk removed",
+			data:       data[:headerLen+4+chunkSize+16],
+			passphrase: testPassphrase,
+			wantErr:    ErrTruncated,
+		},
+		{
+			name:       "appended data",
+			data:       append(bytes.Clone(data), 0),
+			passphrase: testPassphrase,
+			wantErr:    ErrDecrypt,
+		},
+		{
+			name:       "not a backup",
+			data:       []byte("name,url,username,password\n"),
+			passphrase: testPassphrase,
+			wantErr:    ErrNotBackup,
+		},
+		{
+			name:       "empty passphrase",
+			data:       data,
+			passphrase: "",
+			wantErr:    ErrEmptyPassphrase,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := readTestBackup(tt.data, tt.passphrase); !errors.Is(err, tt.wantErr) {
+				t.Errorf("readTestBackup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBackup_CorruptedRecord(t *testing.T) {
+	rs := newTestRecords(t, 2)
+	rs[1].Hashsum = "broken"
+
+	if _, err := readTestBackup(writeTestBackup(t, rs), testPassphrase); !errors.Is(err, ErrCorrupted) {
+		t.Errorf("readTestBackup() error = %v, wantErr %v", err, ErrCorrupted)
+	}
+}
@@ -0,0 +1,297 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// The encrypted stream is the header followed by chunks. Every chunk is sealed with AES-256-GCM,
+// the nonce contains the number of the chunk and the flag of the last chunk,
+// so chunks cannot be reordered, removed or appended. The header is authenticated with every chunk.
+//
+// Header: magic (8) | version (1) | salt (16) | argon2 time (4) | argon2 memory (4) | argon2 threads (1) |
+// nonce prefix (7).
+// Chunk: length with the flag of the last chunk in the highest bit (4) | sealed data.
+const (
+	magic         = "GKBACKUP"
+	cryptoVersion = 1
+
+	saltLen        = 16
+	noncePrefixLen = 7
+	keyLen         = 32
+	headerLen      = len(magic) + 1 + saltLen + 4 + 4 + 1 + noncePrefixLen
+
+	chunkSize = 64 * 1024
+	lastChunk = uint32(1) << 31
+
+	argonTime    = 3
+	argonMemory  = 64 * 1024
+	argonThreads = 4
+	// maxArgonMemory - the limit of the memory parameter read from the header, KiB.
+	maxArgonMemory = 1024 * 1024
+)
+
+// ErrDecrypt - The error is returned if the backup cannot be decrypted:
+// the passphrase is wrong or the backup has been changed.
+var ErrDecrypt = errors.New("wrong passphrase or the backup is corrupted")
+
+// ErrTruncated - The error is returned if the backup ends before its last chunk.
+var ErrTruncated = errors.New("the backup is truncated")
+
+// ErrNotBackup - The error is returned if the file is not a backup of gophkeeper or its version is not supported.
+var ErrNotBackup = errors.New("the file is not a gophkeeper backup or its version is not supported")
+
+// ErrEmptyPassphrase - The error is returned if the passphrase is empty.
+var ErrEmptyPassphrase = errors.New("the passphrase cannot be empty")
+
+type kdfParams struct {
+	salt    []byte
+	time    uint32
+	memory  uint32
+	threads uint8
+}
+
+func (p *kdfParams) key(passphrase []byte) []byte {
+	return argon2.IDKey(passphrase, p.salt, p.time, p.memory, p.threads, keyLen)
+}
+
+// encryptWriter - Encrypts the data written to it chunk by chunk.
+type encryptWriter struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	header []byte
+	prefix []byte
+	buf    []byte
+	out    []byte
+	n      uint32
+	closed bool
+}
+
+func newEncryptWriter(w io.Writer, passphrase []byte) (*encryptWriter, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrEmptyPassphrase
+	}
+
+	random := make([]byte, saltLen+noncePrefixLen)
+	if _, err := rand.Read(random); err != nil {
+		return nil, fmt.Errorf("an error occured while generate salt, err: %w", err)
+	}
+	p := &kdfParams{salt: random[:saltLen], time: argonTime, memory: argonMemory, threads: argonThreads}
+	prefix := random[saltLen:]
+
+	header := make([]byte, 0, headerLen)
+	header = append(header, magic...)
+	header = append(header, cryptoVersion)
+	header = append(header, p.salt...)
+	header = binary.BigEndian.AppendUint32(header, p.time)
+	header = binary.BigEndian.AppendUint32(header, p.memory)
+	header = append(header, p.threads)
+	header = append(header, prefix...)
+
+	aead, err := newAEAD(p.key(passphrase))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := w.Write(header); err != nil {
+		return nil, fmt.Errorf("an error occured while write backup header, err: %w", err)
+	}
+
+	return &encryptWriter{
+		w:      w,
+		aead:   aead,
+		header: header,
+		prefix: prefix,
+		buf:    make([]byte, 0, chunkSize),
+	}, nil
+}
+
+func (ew *encryptWriter) Write(p []byte) (int, error) {
+	if ew.closed {
+		return 0, errors.New("write to closed backup")
+	}
+
+	written := 0
+	for len(p) > 0 {
+		if len(ew.buf) == chunkSize {
+			if err := ew.flush(false); err != nil {
+				return written, err
+			}
+		}
+		n := copy(ew.buf[len(ew.buf):chunkSize], p)
+		ew.buf = ew.buf[:len(ew.buf)+n]
+		p = p[n:]
+		written += n
+	}
+
+	return written, nil
+}
+
+// Close - writes the last chunk. The underlying writer is not closed.
+func (ew *encryptWriter) Close() error {
+	if ew.closed {
+		return nil
+	}
+	ew.closed = true
+
+	return ew.flush(true)
+}
+
+func (ew *encryptWriter) flush(last bool) error {
+	ew.out = ew.aead.Seal(ew.out[:0], chunkNonce(ew.prefix, ew.n, last), ew.buf, ew.header)
+
+	l := uint32(len(ew.out))
+	if last {
+		l |= lastChunk
+	}
+	var lb [4]byte
+	binary.BigEndian.PutUint32(lb[:], l)
+
+	if _, err := ew.w.Write(lb[:]); err != nil {
+		return fmt.Errorf("an error occured while write backup, err: %w", err)
+	}
+	if _, err := ew.w.Write(ew.out); err != nil {
+		return fmt.Errorf("an error occured while write backup, err: %w", err)
+	}
+
+	ew.n++
+	ew.buf = ew.buf[:0]
+
+	return nil
+}
+
+// decryptReader - Decrypts the data encrypted by encryptWriter.
+type decryptReader struct {
+	r      *bufio.Reader
+	aead   cipher.AEAD
+	header []byte
+	prefix []byte
+	buf    []byte
+	plain  []byte
+	n      uint32
+	done   bool
+}
+
+func newDecryptReader(r io.Reader, passphrase []byte) (*decryptReader, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrEmptyPassphrase
+	}
+
+	header := make([]byte, headerLen)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, ErrNotBackup
+	}
+	if !bytes.HasPrefix(header, []byte(magic)) || header[len(magic)] != cryptoVersion {
+		return nil, ErrNotBackup
+	}
+
+	rest := header[len(magic)+1:]
+	p := &kdfParams{salt: rest[:saltLen]}
+	rest = rest[saltLen:]
+	p.time = binary.BigEndian.Uint32(rest)
+	p.memory = binary.BigEndian.Uint32(rest[4:])
+	p.threads = rest[8]
+	prefix := rest[9:]
+	if p.time == 0 || p.threads == 0 || p.memory > maxArgonMemory {
+		return nil, ErrNotBackup
+	}
+
+	aead, err := newAEAD(p.key(passphrase))
+	if err != nil {
+		return nil, err
+	}
+
+	return &decryptReader{
+		r:      bufio.NewReader(r),
+		aead:   aead,
+		header: header,
+		prefix: prefix,
+	}, nil
+}
+
+func (dr *decryptReader) Read(p []byte) (int, error) {
+	for len(dr.plain) == 0 {
+		if dr.done {
+			return 0, io.EOF
+		}
+		if err := dr.next(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, dr.plain)
+	dr.plain = dr.plain[n:]
+
+	return n, nil
+}
+
+func (dr *decryptReader) next() error {
+	var lb [4]byte
+	if _, err := io.ReadFull(dr.r, lb[:]); err != nil {
+		return ErrTruncated
+	}
+	l := binary.BigEndian.Uint32(lb[:])
+	last := l&lastChunk != 0
+	l &^= lastChunk
+	if l > chunkSize+uint32(dr.aead.Overhead()) {
+		return ErrDecrypt
+	}
+
+	if cap(dr.buf) < int(l) {
+		dr.buf = make([]byte, l)
+	}
+	dr.buf = dr.buf[:l]
+	if _, err := io.ReadFull(dr.r, dr.buf); err != nil {
+		return ErrTruncated
+	}
+
+	plain, err := dr.aead.Open(dr.buf[:0], chunkNonce(dr.prefix, dr.n, last), dr.buf, dr.header)
+	if err != nil {
+		return ErrDecrypt
+	}
+	dr.plain = plain
+	dr.n++
+
+	if last {
+		dr.done = true
+		if _, err := dr.r.Peek(1); !errors.Is(err, io.EOF) {
+			return ErrDecrypt
+		}
+	}
+
+	return nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while create cipher, err: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while create cipher, err: %w", err)
+	}
+
+	return aead, nil
+}
+
+// chunkNonce - nonce prefix (7) | number of the chunk (4) | flag of the last chunk (1).
+func chunkNonce(prefix []byte, n uint32, last bool) []byte {
+	nonce := make([]byte, 0, noncePrefixLen+4+1)
+	nonce = append(nonce, prefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, n)
+	if last {
+		return append(nonce, 1)
+	}
+	return append(nonce, 0)
+}
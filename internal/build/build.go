@@ -48,3 +48,18 @@ func (b *Build) String() string {
 func infoTemplate() string {
 	return "Build version: %s; Build date: %s; Build commit: %s;"
 }
+
+// Version - Returns the version of the assembly.
+func (b *Build) Version() string {
+	return b.buildVersion
+}
+
+// Date - Returns the date of the assembly.
+func (b *Build) Date() string {
+	return b.buildDate
+}
+
+// Commit - Returns the commit of the assembly.
+func (b *Build) Commit() string {
+	return b.buildCommit
+}
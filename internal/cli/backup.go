@@ -0,0 +1,263 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/backup"
+	"github.com/ArtemShalinFe/gophkeeper/internal/build"
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+const (
+	envBackupPassphrase = "GK_BACKUP_PASSPHRASE"
+	// exportFormatBackup - the format of the backup in the audit log.
+	exportFormatBackup = "backup"
+
+	restoreStatusRestored  = "restored"
+	restoreStatusDuplicate = "duplicate"
+)
+
+// restoreView - The representation of the restore in the command output.
+type restoreView struct {
+	Login      string             `json:"login"`
+	Records    int                `json:"records"`
+	Restored   int                `json:"restored"`
+	Duplicates int                `json:"duplicates"`
+	Failed     int                `json:"failed"`
+	Verified   bool               `json:"verified_only"`
+	Items      []*restoreItemView `json:"items,omitempty"`
+}
+
+// restoreItemView - The representation of one restored record in the command output.
+type restoreItemView struct {
+	OldID  string `json:"old_id"`
+	NewID  string `json:"new_id,omitempty"`
+	Status string `json:"status"`
+}
+
+func runBackup(ctx context.Context, c *CLI, args []string) error {
+	fs := newFlagSet(c, "backup")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return fmt.Errorf("%w: exactly one file is expected, %s to write the backup to stdout", errUsage, stdinValue)
+	}
+
+	passphrase, err := c.readSecret(envBackupPassphrase, "passphrase")
+	if err != nil {
+		return err
+	}
+
+	gkclient, err := c.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	u := c.user()
+	b := build.NewBuild()
+	count := 0
+	write := func(w io.Writer) error {
+		bw, err := backup.NewWriter(w, []byte(passphrase), backup.Manifest{Login: u.Login, ClientVersion: b.Version()})
+		if err != nil {
+			return fmt.Errorf("an error occured while create backup, err: %w", err)
+		}
+
+		for offset := 0; ; offset += models.DefaultLimit {
+			rs, err := u.GetRecords(ctx, gkclient, offset, models.DefaultLimit)
+			if err != nil {
+				return fmt.Errorf("an error occured while backup records, err: %w", err)
+			}
+			if len(rs) == 0 {
+				break
+			}
+
+			for _, r := range rs {
+				if r.Deleted {
+					continue
+				}
+				if err := bw.WriteRecord(r); err != nil {
+					return fmt.Errorf("an error occured while backup records, err: %w", err)
+				}
+			}
+		}
+
+		count = bw.Count()
+		if err := bw.Close(); err != nil {
+			return fmt.Errorf("an error occured while finish backup, err: %w", err)
+		}
+		return nil
+	}
+
+	if pos[0] == stdinValue {
+		if err := write(c.stdout); err != nil {
+			return err
+		}
+		c.reportExport(ctx, gkclient, exportFormatBackup, count)
+		return nil
+	}
+	if err := writeFile(pos[0], write); err != nil {
+		return err
+	}
+	c.reportExport(ctx, gkclient, exportFormatBackup, count)
+
+	fmt.Fprintf(c.stdout, "%d records saved to %s\n", count, pos[0])
+	return nil
+}
+
+func runRestore(ctx context.Context, c *CLI, args []string) error {
+	fs := newFlagSet(c, "restore")
+	verify := fs.Bool("verify", false, "only check that the backup can be decrypted and is not corrupted")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return fmt.Errorf("%w: exactly one file is expected", errUsage)
+	}
+
+	passphrase, err := c.readSecret(envBackupPassphrase, "passphrase")
+	if err != nil {
+		return err
+	}
+
+	// The whole backup is verified before anything is written, so a corrupted backup is not restored partially.
+	view := &restoreView{Verified: *verify}
+	err = readBackup(pos[0], passphrase, func(m backup.Manifest, r *models.Record) error {
+		view.Login = m.Login
+		view.Records++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !*verify {
+		if err := c.restore(ctx, pos[0], passphrase, view); err != nil {
+			return err
+		}
+	}
+
+	if *asJSON {
+		if err := c.printJSON(view); err != nil {
+			return err
+		}
+	} else if err := c.printRestore(view); err != nil {
+		return err
+	}
+
+	if view.Failed > 0 {
+		return fmt.Errorf("%d records were not restored", view.Failed)
+	}
+	return nil
+}
+
+func (c *CLI) restore(ctx context.Context, path string, passphrase string, view *restoreView) error {
+	gkclient, err := c.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	rr, err := c.user().NewRestorer(ctx, gkclient)
+	if err != nil {
+		return fmt.Errorf("an error occured while prepare restore, err: %w", err)
+	}
+
+	var batch []*models.Record
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		results, err := rr.Restore(ctx, batch)
+		if err != nil {
+			return fmt.Errorf("an error occured while restore records, err: %w", err)
+		}
+		batch = batch[:0]
+
+		for _, res := range results {
+			item := &restoreItemView{OldID: res.OldID, NewID: res.NewID}
+			switch {
+			case res.Err != nil:
+				item.Status = res.Err.Error()
+				view.Failed++
+			case res.Duplicate:
+				item.Status = restoreStatusDuplicate
+				view.Duplicates++
+			default:
+				item.Status = restoreStatusRestored
+				view.Restored++
+			}
+			view.Items = append(view.Items, item)
+		}
+		return nil
+	}
+
+	err = readBackup(path, passphrase, func(m backup.Manifest, r *models.Record) error {
+		batch = append(batch, r)
+		if len(batch) < models.MaxBatchSize {
+			return nil
+		}
+		return flush()
+	})
+	if err != nil {
+		return err
+	}
+
+	return flush()
+}
+
+// readBackup - decrypts the backup and calls fn for every record.
+func readBackup(path string, passphrase string, fn func(m backup.Manifest, r *models.Record) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("an error occured while open backup, err: %w", err)
+	}
+	defer f.Close()
+
+	br, err := backup.NewReader(f, []byte(passphrase))
+	if err != nil {
+		return fmt.Errorf("an error occured while read backup, err: %w", err)
+	}
+
+	for {
+		r, err := br.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("an error occured while read backup, err: %w", err)
+		}
+
+		if err := fn(br.Manifest(), r); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *CLI) printRestore(v *restoreView) error {
+	if len(v.Items) > 0 {
+		w := c.newTabWriter()
+		fmt.Fprintln(w, "OLD ID\tNEW ID\tSTATUS")
+		for _, item := range v.Items {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", item.OldID, item.NewID, item.Status)
+		}
+		if err := w.Flush(); err != nil {
+			return fmt.Errorf("an error occured while write output, err: %w", err)
+		}
+	}
+
+	if v.Verified {
+		fmt.Fprintf(c.stdout, "backup of %s is valid, records: %d\n", v.Login, v.Records)
+		return nil
+	}
+	fmt.Fprintf(c.stdout, "backup of %s, records: %d, restored: %d, duplicates: %d, failed: %d\n",
+		v.Login, v.Records, v.Restored, v.Duplicates, v.Failed)
+	return nil
+}
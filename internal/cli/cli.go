@@ -0,0 +1,256 @@
+// Package cli - Non-interactive commands of the gophkeeper client, used in scripts and CI.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/config"
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+	"github.com/ArtemShalinFe/gophkeeper/internal/server"
+)
+
+// Exit codes of the commands.
+const (
+	// ExitOK - the command was completed successfully.
+	ExitOK = 0
+	// ExitError - the command failed, for example, the server is unavailable.
+	ExitError = 1
+	// ExitUsage - the command or its arguments are not correct.
+	ExitUsage = 2
+	// ExitUnauthenticated - there is no saved session, the login failed or the device has been revoked.
+	ExitUnauthenticated = 3
+	// ExitNotFound - the record was not found.
+	ExitNotFound = 4
+	// ExitIncompatible - the client cannot work with the server, one of them must be updated.
+	ExitIncompatible = 5
+)
+
+// errUsage - An error that is returned if the command or its arguments are not correct.
+var errUsage = errors.New("usage error")
+
+// command - The description of the subcommand.
+type command struct {
+	run   func(ctx context.Context, c *CLI, args []string) error
+	usage string
+	// session - the command requires a saved session.
+	session bool
+}
+
+var commands = map[string]command{
+	"login":  {run: runLogin, usage: "login --login LOGIN [--password PASSWORD] [--register]"},
+	"logout": {run: runLogout, usage: "logout"},
+	"list":   {run: runList, usage: "list [--offset N] [--limit N] [--json]", session: true},
+	"get":    {run: runGet, usage: "get ID [--field NAME] [--json]", session: true},
+	"add": {run: runAdd, usage: "add auth|text|card|file [--description D] [--metadata KEY[#TYPE]:VALUE]... " +
+		"[--login L] [--password P] [--text T|-] [--number N] [--owner O] [--term MM/YY] [--path FILE] [--json]",
+		session: true},
+	"update": {run: runUpdate, usage: "update ID [same flags as add]", session: true},
+	"delete": {run: runDelete, usage: "delete ID... [--json]", session: true},
+	"sync":   {run: runSync, usage: "sync [--password PASSWORD] [--on-conflict report|keep-both] [--json]", session: true},
+	"import": {run: runImport, usage: "import FILE|- [--format auto|keepass|chrome|firefox|bitwarden|1password] " +
+		"[--dry-run] [--json]", session: true},
+	"export":  {run: runExport, usage: "export FILE|- [--format keepass]", session: true},
+	"backup":  {run: runBackup, usage: "backup FILE|-", session: true},
+	"restore": {run: runRestore, usage: "restore FILE [--verify] [--json]", session: true},
+	"generate": {run: runGenerate, usage: "generate [--length N] [--no-lower] [--no-upper] [--no-digits] " +
+		"[--no-symbols] [--allow-similar] | --passphrase [--words N] [--separator S] [--capitalize] [--digit] [--json]"},
+	"version": {run: runVersion, usage: "version [--server] [--json]"},
+	"profile": {run: runProfile, usage: "profile list [--json] | use NAME | remove NAME | set NAME [--address A] " +
+		"[--ca FILE] [--pin SHA256] [--sync-interval D] [--lock-timeout D] [--use]"},
+}
+
+// CLI - The object that runs one non-interactive command.
+type CLI struct {
+	stdin    io.Reader
+	stdout   io.Writer
+	stderr   io.Writer
+	log      *zap.Logger
+	cfg      *config.ClientCfg
+	gkclient *server.GKClient
+	session  *Session
+}
+
+// NewCLI - Object Constructor.
+func NewCLI(log *zap.Logger, stdin io.Reader, stdout io.Writer, stderr io.Writer) *CLI {
+	return &CLI{
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+		log:    log,
+	}
+}
+
+// SetConfig - Sets the configuration of the client, by default it is read from the environment variables
+// and the configuration file.
+func (c *CLI) SetConfig(cfg *config.ClientCfg) {
+	c.cfg = cfg
+}
+
+// Run - Runs the command and returns the exit code of the program.
+func (c *CLI) Run(ctx context.Context, args []string) int {
+	err := c.run(ctx, args)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintf(c.stderr, "gclient: %v\n", err)
+	}
+	if errors.Is(err, errUsage) {
+		c.printUsage()
+	}
+
+	return exitCode(err)
+}
+
+func (c *CLI) run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: command is not specified", errUsage)
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("%w: unknown command %q", errUsage, args[0])
+	}
+
+	if c.cfg == nil {
+		cfg, _, err := config.LoadClientCfg(nil)
+		if err != nil {
+			return fmt.Errorf("an error occured while read config, err: %w", err)
+		}
+		c.cfg = cfg
+	}
+
+	if cmd.session {
+		s, err := loadSession(c.cfg)
+		if err != nil {
+			return err
+		}
+		c.session = s
+	}
+
+	err := cmd.run(ctx, c, args[1:])
+	if errors.Is(err, models.ErrDeviceRevoked) {
+		if err := removeSession(c.cfg); err != nil {
+			c.log.Error("an error occured while remove session", zap.Error(err))
+		}
+	}
+
+	return err
+}
+
+// client - Returns the client of the server. The client is created on the first call,
+// it checks that the server is compatible with the client.
+func (c *CLI) client(ctx context.Context) (*server.GKClient, error) {
+	if c.gkclient != nil {
+		return c.gkclient, nil
+	}
+
+	gkclient, err := c.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	compat, err := models.CheckServer(ctx, gkclient)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while check server, err: %w", err)
+	}
+	c.printWarnings(compat.Warnings)
+
+	if c.session != nil {
+		gkclient.SetDeviceID(c.session.DeviceID)
+	}
+	c.gkclient = gkclient
+
+	return gkclient, nil
+}
+
+// connect - Returns the new client of the server without the compatibility check.
+func (c *CLI) connect(ctx context.Context) (*server.GKClient, error) {
+	gkclient, err := server.NewGKClient(ctx, c.cfg, c.log)
+	if err != nil {
+		return nil, fmt.Errorf("an error occure while init gk client, err: %w", err)
+	}
+	return gkclient, nil
+}
+
+// user - Returns the user of the saved session.
+func (c *CLI) user() *models.User {
+	return &models.User{
+		ID:    c.session.UserID,
+		Login: c.session.Login,
+	}
+}
+
+func (c *CLI) printUsage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(c.stderr, "Usage: gclient [--config FILE] [--profile NAME] [--address A] [--ca FILE] [--pin SHA256] "+
+		"[--sync-interval D] [--lock-timeout D] [COMMAND [ARGS]]\n\n"+
+		"Without a command the text user interface is started.\n\nCommands:")
+	for _, name := range names {
+		fmt.Fprintf(c.stderr, "  %s\n", commands[name].usage)
+	}
+}
+
+// parseArgs - parses flags that may be placed before, after or between positional arguments.
+// Returns the positional arguments.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var pos []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, flag.ErrHelp
+			}
+			return nil, fmt.Errorf("%w: %w", errUsage, err)
+		}
+		if fs.NArg() == 0 {
+			return pos, nil
+		}
+		pos = append(pos, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+func exitCode(err error) int {
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return ExitOK
+	case errors.Is(err, errUsage):
+		return ExitUsage
+	case errors.Is(err, errNoSession),
+		errors.Is(err, models.ErrDeviceRevoked),
+		errors.Is(err, models.ErrUnknowUser),
+		status.Code(err) == codes.Unauthenticated:
+		return ExitUnauthenticated
+	case errors.Is(err, models.ErrRecordNotFound),
+		status.Code(err) == codes.NotFound:
+		return ExitNotFound
+	case errors.Is(err, models.ErrIncompatibleServer):
+		return ExitIncompatible
+	default:
+		return ExitError
+	}
+}
+
+// metadataFlag - The flag that can be specified several times, every value is a KEY[#TYPE]:VALUE pair.
+type metadataFlag []string
+
+func (m *metadataFlag) String() string {
+	return strings.Join(*m, ", ")
+}
+
+func (m *metadataFlag) Set(v string) error {
+	*m = append(*m, v)
+	return nil
+}
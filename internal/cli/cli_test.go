@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/config"
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+func TestCLI_Run(t *testing.T) {
+	t.Setenv("DATA_DIR", t.TempDir())
+	t.Setenv("GK_CONFIG", filepath.Join(t.TempDir(), "client.json"))
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{
+			name: "no command",
+			args: nil,
+			want: ExitUsage,
+		},
+		{
+			name: "unknown command",
+			args: []string{"unknown"},
+			want: ExitUsage,
+		},
+		{
+			name: "not logged in",
+			args: []string{"list"},
+			want: ExitUnauthenticated,
+		},
+		{
+			name: "unknown flag",
+			args: []string{"logout", "--unknown"},
+			want: ExitUsage,
+		},
+		{
+			name: "logout without session",
+			args: []string{"logout"},
+			want: ExitOK,
+		},
+		{
+			name: "login without login",
+			args: []string{"login"},
+			want: ExitUsage,
+		},
+		{
+			name: "generate passphrase",
+			args: []string{"generate", "--passphrase", "--words", "4"},
+			want: ExitOK,
+		},
+		{
+			name: "generate too short password",
+			args: []string{"generate", "--length", "2"},
+			want: ExitUsage,
+		},
+		{
+			name: "version",
+			args: []string{"version", "--json"},
+			want: ExitOK,
+		},
+		{
+			name: "set profile",
+			args: []string{"profile", "set", "work", "--address", "localhost:8080", "--lock-timeout", "1m"},
+			want: ExitOK,
+		},
+		{
+			name: "use profile",
+			args: []string{"profile", "use", "work"},
+			want: ExitOK,
+		},
+		{
+			name: "list profiles",
+			args: []string{"profile", "list", "--json"},
+			want: ExitOK,
+		},
+		{
+			name: "use unknown profile",
+			args: []string{"profile", "use", "home"},
+			want: ExitUsage,
+		},
+		{
+			name: "set profile with invalid name",
+			args: []string{"profile", "set", "../work"},
+			want: ExitUsage,
+		},
+		{
+			name: "remove profile",
+			args: []string{"profile", "remove", "work"},
+			want: ExitOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stderr bytes.Buffer
+			c := NewCLI(zap.NewNop(), bytes.NewReader(nil), io.Discard, &stderr)
+			if got := c.Run(context.Background(), tt.args); got != tt.want {
+				t.Errorf("CLI.Run() = %v, want %v, stderr: %s", got, tt.want, stderr.String())
+			}
+		})
+	}
+}
+
+func TestSession(t *testing.T) {
+	cfg := &config.ClientCfg{DataDir: t.TempDir()}
+
+	if _, err := loadSession(cfg); !errors.Is(err, errNoSession) {
+		t.Errorf("loadSession() error = %v, want %v", err, errNoSession)
+	}
+
+	want := &Session{UserID: "user", Login: "login", DeviceID: "device"}
+	if err := saveSession(cfg, want); err != nil {
+		t.Fatalf("saveSession() error = %v", err)
+	}
+
+	got, err := loadSession(cfg)
+	if err != nil {
+		t.Fatalf("loadSession() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loadSession() = %v, want %v", got, want)
+	}
+
+	if err := removeSession(cfg); err != nil {
+		t.Fatalf("removeSession() error = %v", err)
+	}
+	if _, err := loadSession(cfg); !errors.Is(err, errNoSession) {
+		t.Errorf("loadSession() error = %v, want %v", err, errNoSession)
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantPos  []string
+		wantFlag string
+		wantErr  bool
+	}{
+		{
+			name:     "flags before positional arguments",
+			args:     []string{"--field", "login", "id1", "id2"},
+			wantPos:  []string{"id1", "id2"},
+			wantFlag: "login",
+		},
+		{
+			name:     "flags between positional arguments",
+			args:     []string{"id1", "--field", "login", "id2"},
+			wantPos:  []string{"id1", "id2"},
+			wantFlag: "login",
+		},
+		{
+			name:    "unknown flag",
+			args:    []string{"id1", "--unknown"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			field := fs.String("field", "", "")
+
+			got, err := parseArgs(fs, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, errUsage) {
+					t.Errorf("parseArgs() error = %v, want %v", err, errUsage)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.wantPos) {
+				t.Errorf("parseArgs() = %v, want %v", got, tt.wantPos)
+			}
+			if *field != tt.wantFlag {
+				t.Errorf("parseArgs() field = %v, want %v", *field, tt.wantFlag)
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "ok", err: nil, want: ExitOK},
+		{name: "help", err: flag.ErrHelp, want: ExitOK},
+		{name: "usage", err: fmt.Errorf("%w: bad flag", errUsage), want: ExitUsage},
+		{name: "no session", err: errNoSession, want: ExitUnauthenticated},
+		{name: "revoked", err: fmt.Errorf("err: %w", models.ErrDeviceRevoked), want: ExitUnauthenticated},
+		{name: "unauthenticated", err: status.Error(codes.Unauthenticated, "denied"), want: ExitUnauthenticated},
+		{name: "not found", err: fmt.Errorf("err: %w", models.ErrRecordNotFound), want: ExitNotFound},
+		{name: "grpc not found", err: status.Error(codes.NotFound, "not found"), want: ExitNotFound},
+		{name: "incompatible", err: fmt.Errorf("err: %w", models.ErrIncompatibleServer), want: ExitIncompatible},
+		{name: "other", err: errors.New("something went wrong"), want: ExitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
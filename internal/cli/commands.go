@@ -0,0 +1,674 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/build"
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+	"github.com/ArtemShalinFe/gophkeeper/internal/storage/file"
+	"github.com/ArtemShalinFe/gophkeeper/internal/storage/mem"
+	"github.com/ArtemShalinFe/gophkeeper/internal/storage/sealed"
+)
+
+const (
+	envPassword = "GK_PASSWORD"
+	termFormat  = "01/06"
+	stdinValue  = "-"
+
+	conflictReport   = "report"
+	conflictKeepBoth = "keep-both"
+)
+
+// errSyncConflicts - The error is returned by the sync command if the records are changed both locally
+// and on the server.
+var errSyncConflicts = errors.New("the records have been changed both locally and on the server, " +
+	"resolve the conflicts in the text interface")
+
+func newFlagSet(c *CLI, name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	return fs
+}
+
+func runLogin(ctx context.Context, c *CLI, args []string) error {
+	fs := newFlagSet(c, "login")
+	login := fs.String("login", "", "user login")
+	password := fs.String("password", "", "user password, by default "+envPassword+" or the first line of stdin is used")
+	register := fs.Bool("register", false, "register a new user")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	if *login == "" {
+		return fmt.Errorf("%w: login is not specified", errUsage)
+	}
+	if *password == "" {
+		p, err := c.readSecret(envPassword, "password")
+		if err != nil {
+			return err
+		}
+		*password = p
+	}
+
+	gkclient, err := c.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	udto := &models.UserDTO{Login: *login, Password: *password}
+	var u *models.User
+	if *register {
+		u, err = udto.AddUser(ctx, gkclient)
+	} else {
+		u, err = udto.GetUser(ctx, gkclient)
+	}
+	if err != nil {
+		return fmt.Errorf("an error occured while login, err: %w", err)
+	}
+
+	b := build.NewBuild()
+	device := models.NewDeviceDTO(fmt.Sprintf("%s (%s)", b.Version(), b.Commit()))
+	device.Login, device.Password = u.Login, *password
+	if _, err := u.RegisterDevice(ctx, gkclient, device); err != nil {
+		return fmt.Errorf("an error occured while register device, err: %w", err)
+	}
+
+	if err := saveSession(c.cfg, &Session{
+		UserID:   u.ID,
+		Login:    u.Login,
+		DeviceID: gkclient.DeviceID(),
+	}); err != nil {
+		return err
+	}
+	if err := c.cfg.SaveLastLogin(u.Login); err != nil {
+		c.log.Error("an error occured while save last login", zap.Error(err))
+	}
+
+	fmt.Fprintf(c.stdout, "logged in as %s\n", u.Login)
+	return nil
+}
+
+// readSecret - returns the value of the environment variable or, if it is not set, the first line of stdin.
+func (c *CLI) readSecret(env string, name string) (string, error) {
+	if p := os.Getenv(env); p != "" {
+		return p, nil
+	}
+
+	var p string
+	if _, err := fmt.Fscanln(c.stdin, &p); err != nil {
+		return "", fmt.Errorf("%w: %s is not specified", errUsage, name)
+	}
+
+	return p, nil
+}
+
+func runLogout(ctx context.Context, c *CLI, args []string) error {
+	if _, err := parseArgs(newFlagSet(c, "logout"), args); err != nil {
+		return err
+	}
+
+	return removeSession(c.cfg)
+}
+
+func runList(ctx context.Context, c *CLI, args []string) error {
+	fs := newFlagSet(c, "list")
+	offset := fs.Int("offset", 0, "number of records to skip")
+	limit := fs.Int("limit", models.DefaultLimit, "maximum number of records")
+	asJSON := fs.Bool("json", false, "print records as JSON")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	gkclient, err := c.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	rs, err := c.user().GetRecords(ctx, gkclient, *offset, *limit)
+	if err != nil {
+		return fmt.Errorf("an error occured while list records, err: %w", err)
+	}
+
+	var views []*recordView
+	for _, r := range rs {
+		if !r.Deleted {
+			views = append(views, newRecordView(r))
+		}
+	}
+
+	if *asJSON {
+		return c.printJSON(views)
+	}
+	return c.printRecords(views)
+}
+
+func runGet(ctx context.Context, c *CLI, args []string) error {
+	fs := newFlagSet(c, "get")
+	field := fs.String("field", "", "print only the value of the field (data field or metadata key)")
+	asJSON := fs.Bool("json", false, "print the record as JSON")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return fmt.Errorf("%w: exactly one record ID is expected", errUsage)
+	}
+
+	r, err := c.getRecord(ctx, pos[0])
+	if err != nil {
+		return err
+	}
+
+	if *field != "" {
+		return c.printField(r, *field)
+	}
+
+	v := newRecordView(r)
+	fields, err := r.DataFields()
+	if err != nil {
+		return fmt.Errorf("an error occured while decode record, err: %w", err)
+	}
+	v.Data = make(map[string]string, len(fields))
+	for _, f := range fields {
+		v.Data[f.Name] = f.Value
+	}
+
+	if *asJSON {
+		return c.printJSON(v)
+	}
+	return c.printRecord(v, fields)
+}
+
+func (c *CLI) getRecord(ctx context.Context, recordID string) (*models.Record, error) {
+	gkclient, err := c.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := c.user().GetRecord(ctx, gkclient, recordID)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while get record, err: %w", err)
+	}
+	if r.Deleted {
+		return nil, models.ErrRecordNotFound
+	}
+
+	return r, nil
+}
+
+// printField - prints the value of the field of the record.
+// The file of the binary record is printed as is.
+func (c *CLI) printField(r *models.Record, name string) error {
+	if strings.EqualFold(name, models.FieldDescription) {
+		fmt.Fprintln(c.stdout, r.Description)
+		return nil
+	}
+
+	d, err := r.DecodeData()
+	if err != nil {
+		return fmt.Errorf("an error occured while decode record, err: %w", err)
+	}
+	if b, ok := d.(*models.Binary); ok && strings.EqualFold(name, models.FieldFile) {
+		if _, err := c.stdout.Write(b.Data); err != nil {
+			return fmt.Errorf("an error occured while write file, err: %w", err)
+		}
+		return nil
+	}
+
+	fields, err := r.DataFields()
+	if err != nil {
+		return fmt.Errorf("an error occured while decode record, err: %w", err)
+	}
+	for _, f := range fields {
+		if strings.EqualFold(name, f.Name) {
+			fmt.Fprintln(c.stdout, f.Value)
+			return nil
+		}
+	}
+	for _, m := range r.Metadata {
+		if strings.EqualFold(name, m.Key) {
+			fmt.Fprintln(c.stdout, m.Value)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: the record has no field %q", errUsage, name)
+}
+
+// recordFlags - The flags of the add and update commands.
+type recordFlags struct {
+	description string
+	login       string
+	password    string
+	text        string
+	number      string
+	owner       string
+	term        string
+	path        string
+	metadata    metadataFlag
+	json        bool
+	// set - names of the flags specified by the user.
+	set map[string]bool
+}
+
+func newRecordFlagSet(c *CLI, name string) (*flag.FlagSet, *recordFlags) {
+	rf := &recordFlags{set: make(map[string]bool)}
+	fs := newFlagSet(c, name)
+	fs.StringVar(&rf.description, "description", "", "description of the record")
+	fs.StringVar(&rf.login, "login", "", "login of the auth record")
+	fs.StringVar(&rf.password, "password", "", "password of the auth record")
+	fs.StringVar(&rf.text, "text", "", "text of the text record, "+stdinValue+" to read the text from stdin")
+	fs.StringVar(&rf.number, "number", "", "number of the card record")
+	fs.StringVar(&rf.owner, "owner", "", "owner of the card record")
+	fs.StringVar(&rf.term, "term", "", "term of the card record in format MM/YY")
+	fs.StringVar(&rf.path, "path", "", "path to the file of the file record")
+	fs.Var(&rf.metadata, "metadata", "metadata of the record in format KEY[#TYPE]:VALUE, may be specified several times")
+	fs.BoolVar(&rf.json, "json", false, "print the result as JSON")
+	return fs, rf
+}
+
+func runAdd(ctx context.Context, c *CLI, args []string) error {
+	fs, rf := newRecordFlagSet(c, "add")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	fs.Visit(func(f *flag.Flag) { rf.set[f.Name] = true })
+
+	if len(pos) != 1 {
+		return fmt.Errorf("%w: exactly one record type is expected: auth, text, card or file", errUsage)
+	}
+
+	dt, err := parseDataType(pos[0])
+	if err != nil {
+		return err
+	}
+
+	data, err := c.applyData(dt, rf, nil)
+	if err != nil {
+		return err
+	}
+	if b, ok := data.(*models.Binary); ok && !rf.set["description"] {
+		rf.description = b.Name
+	}
+
+	mi, err := models.NewMetadataFromStringArray(rf.metadata)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+
+	rdto, err := models.NewRecordDTO(rf.description, dt, data, mi)
+	if err != nil {
+		return fmt.Errorf("an error occured while create record, err: %w", err)
+	}
+
+	gkclient, err := c.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	r, err := c.user().AddRecord(ctx, gkclient, rdto)
+	if err != nil {
+		return fmt.Errorf("an error occured while add record, err: %w", err)
+	}
+
+	if rf.json {
+		return c.printJSON(newRecordView(r))
+	}
+	fmt.Fprintln(c.stdout, r.ID)
+	return nil
+}
+
+func runUpdate(ctx context.Context, c *CLI, args []string) error {
+	fs, rf := newRecordFlagSet(c, "update")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	fs.Visit(func(f *flag.Flag) { rf.set[f.Name] = true })
+
+	if len(pos) != 1 {
+		return fmt.Errorf("%w: exactly one record ID is expected", errUsage)
+	}
+
+	r, err := c.getRecord(ctx, pos[0])
+	if err != nil {
+		return err
+	}
+
+	cur, err := r.DecodeData()
+	if err != nil {
+		return fmt.Errorf("an error occured while decode record, err: %w", err)
+	}
+
+	data, err := c.applyData(models.DataType(r.Type), rf, cur)
+	if err != nil {
+		return err
+	}
+
+	desc := r.Description
+	if rf.set["description"] {
+		desc = rf.description
+	}
+
+	mi := r.Metadata
+	if rf.set["metadata"] {
+		mi, err = models.NewMetadataFromStringArray(rf.metadata)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errUsage, err)
+		}
+	}
+
+	nr, err := models.NewRecord(r.ID, desc, models.DataType(r.Type), r.Created, time.Now(), data, mi, false,
+		r.Version+1)
+	if err != nil {
+		return fmt.Errorf("an error occured while create record, err: %w", err)
+	}
+
+	gkclient, err := c.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	nr, err = c.user().UpdateRecord(ctx, gkclient, nr)
+	if err != nil {
+		return fmt.Errorf("an error occured while update record, err: %w", err)
+	}
+
+	if rf.json {
+		return c.printJSON(newRecordView(nr))
+	}
+	fmt.Fprintln(c.stdout, nr.ID)
+	return nil
+}
+
+func parseDataType(s string) (models.DataType, error) {
+	switch strings.ToLower(s) {
+	case "auth":
+		return models.AuthType, nil
+	case "text":
+		return models.TextType, nil
+	case "card":
+		return models.CardType, nil
+	case "file":
+		return models.BinaryType, nil
+	default:
+		return "", fmt.Errorf("%w: unknown record type %q, expected auth, text, card or file", errUsage, s)
+	}
+}
+
+// applyData - applies the specified flags to the record data.
+// If cur is nil, a new record data is created.
+func (c *CLI) applyData(dt models.DataType, rf *recordFlags, cur models.RecordData) (models.RecordData, error) {
+	switch dt {
+	case models.AuthType:
+		a := &models.Auth{}
+		if v, ok := cur.(*models.Auth); ok {
+			a = v
+		}
+		if rf.set["login"] {
+			a.Login = rf.login
+		}
+		if rf.set["password"] {
+			a.Password = rf.password
+		}
+		return a, nil
+	case models.TextType:
+		t := &models.Text{}
+		if v, ok := cur.(*models.Text); ok {
+			t = v
+		}
+		if rf.set["text"] {
+			text, err := c.readText(rf.text)
+			if err != nil {
+				return nil, err
+			}
+			t.Data = text
+		}
+		return t, nil
+	case models.CardType:
+		return applyCard(rf, cur)
+	case models.BinaryType:
+		b := &models.Binary{}
+		if v, ok := cur.(*models.Binary); ok {
+			b = v
+		}
+		if rf.set["path"] {
+			nb, err := readBinary(rf.path)
+			if err != nil {
+				return nil, err
+			}
+			b = nb
+		}
+		if cur == nil && !rf.set["path"] {
+			return nil, fmt.Errorf("%w: path to the file is not specified", errUsage)
+		}
+		return b, nil
+	default:
+		return nil, models.ErrUnknowDataType
+	}
+}
+
+func applyCard(rf *recordFlags, cur models.RecordData) (models.RecordData, error) {
+	cd := &models.Card{}
+	if v, ok := cur.(*models.Card); ok {
+		cd = v
+	}
+	if rf.set["number"] {
+		cd.Number = rf.number
+	}
+	if rf.set["owner"] {
+		cd.Owner = rf.owner
+	}
+	if rf.set["term"] {
+		t, err := time.Parse(termFormat, rf.term)
+		if err != nil {
+			return nil, fmt.Errorf("%w: term must be in format MM/YY, err: %w", errUsage, err)
+		}
+		cd.Term = t
+	}
+	return cd, nil
+}
+
+func (c *CLI) readText(v string) (string, error) {
+	if v != stdinValue {
+		return v, nil
+	}
+
+	b, err := io.ReadAll(c.stdin)
+	if err != nil {
+		return "", fmt.Errorf("an error occured while read text from stdin, err: %w", err)
+	}
+
+	return string(b), nil
+}
+
+func readBinary(path string) (*models.Binary, error) {
+	s, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while read file info, err: %w", err)
+	}
+	if s.Size() > int64(models.MaxFileSize) {
+		return nil, errors.New(models.ErrLargeFile)
+	}
+
+	f, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while read file, err: %w", err)
+	}
+
+	return &models.Binary{
+		Data: f,
+		Name: s.Name(),
+		Ext:  filepath.Ext(path),
+	}, nil
+}
+
+func runDelete(ctx context.Context, c *CLI, args []string) error {
+	fs := newFlagSet(c, "delete")
+	asJSON := fs.Bool("json", false, "print the results as JSON")
+	ids, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("%w: at least one record ID is expected", errUsage)
+	}
+
+	gkclient, err := c.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	brs, err := gkclient.BatchDeleteRecords(ctx, c.session.UserID, ids)
+	if err != nil {
+		return fmt.Errorf("an error occured while delete records, err: %w", err)
+	}
+
+	views := make([]*resultView, len(brs))
+	for i, br := range brs {
+		views[i] = &resultView{ID: br.ID}
+		if br.Err != nil {
+			views[i].Error = br.Err.Error()
+		}
+	}
+
+	if *asJSON {
+		if err := c.printJSON(views); err != nil {
+			return err
+		}
+	} else {
+		c.printResults(views)
+	}
+
+	return models.BatchErr(brs)
+}
+
+func runSync(ctx context.Context, c *CLI, args []string) error {
+	fs := newFlagSet(c, "sync")
+	password := fs.String("password", "",
+		"master password of the pending changes, by default "+envPassword+" or the first line of stdin is used")
+	onConflict := fs.String("on-conflict", conflictReport,
+		"what to do with the records changed both locally and on the server: "+conflictReport+" or "+conflictKeepBoth)
+	asJSON := fs.Bool("json", false, "print the status as JSON")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	var conflicts *conflictCollector
+	switch *onConflict {
+	case conflictReport:
+		conflicts = &conflictCollector{seen: make(map[string]struct{})}
+	case conflictKeepBoth:
+	default:
+		return fmt.Errorf("%w: unknown conflict policy %q", errUsage, *onConflict)
+	}
+
+	dir, err := c.cfg.GetDataDir()
+	if err != nil {
+		return fmt.Errorf("an error occured while retrieving data dir, err: %w", err)
+	}
+
+	keys, err := c.openKeyring(dir, *password)
+	if err != nil {
+		return err
+	}
+
+	q, err := file.NewQueue(file.UserQueuePath(dir, c.session.UserID), keys)
+	if err != nil {
+		return fmt.Errorf("an error occured while open pending queue, err: %w", err)
+	}
+
+	gkclient, err := c.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	u := c.user()
+	cache := mem.NewMemStorage()
+	if err := cache.AddUserRecordStorage(u.ID); err != nil {
+		return fmt.Errorf("an error occured while create cache, err: %w", err)
+	}
+
+	var handler models.ConflictHandler
+	if conflicts != nil {
+		handler = conflicts
+	}
+	s := models.NewSynchronizer(u, cache, gkclient, q, handler, 0)
+	if err := s.Restore(ctx); err != nil {
+		return fmt.Errorf("an error occured while restore pending changes, err: %w", err)
+	}
+
+	err = s.Sync(ctx)
+	st := newStatusView(s.Status())
+	if conflicts != nil && len(conflicts.ids) > 0 {
+		st.Conflicts = conflicts.ids
+		if err == nil {
+			err = fmt.Errorf("%w: %d records", errSyncConflicts, len(conflicts.ids))
+		}
+	}
+	if *asJSON {
+		if err := c.printJSON(st); err != nil {
+			return err
+		}
+	} else {
+		c.printStatus(st)
+	}
+
+	return err
+}
+
+// conflictCollector - The conflict handler of the sync command, it notes the conflicting records.
+// The conflicting records stay in the pending queue, they are resolved in the text interface.
+type conflictCollector struct {
+	seen map[string]struct{}
+	ids  []string
+}
+
+// HandleConflict - notes the conflicting record.
+func (cc *conflictCollector) HandleConflict(ctx context.Context, conflict *models.Conflict) {
+	if _, ok := cc.seen[conflict.ID()]; ok {
+		return
+	}
+	cc.seen[conflict.ID()] = struct{}{}
+	cc.ids = append(cc.ids, conflict.ID())
+}
+
+// openKeyring - returns the keyring that seals the pending queue of the user.
+// The queue and its keyring are created by the text interface, so without the keyring the queue is empty
+// and the keyring of the command is not saved. The master password is read only to open the saved keyring.
+func (c *CLI) openKeyring(dir string, password string) (*sealed.Keyring, error) {
+	path := file.UserKeyringPath(dir, c.session.UserID)
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		keys, err := sealed.NewKeyring(password)
+		if err != nil {
+			return nil, fmt.Errorf("an error occured while create keyring, err: %w", err)
+		}
+		return keys, nil
+	}
+
+	if password == "" {
+		p, err := c.readSecret(envPassword, "password")
+		if err != nil {
+			return nil, err
+		}
+		password = p
+	}
+
+	keys, err := sealed.LoadKeyring(path, password)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while open pending queue keyring, err: %w", err)
+	}
+
+	return keys, nil
+}
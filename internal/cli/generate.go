@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+// generateView - The representation of the generated secret in the command output.
+type generateView struct {
+	Value   string  `json:"value"`
+	Entropy float64 `json:"entropy_bits"`
+}
+
+func runGenerate(_ context.Context, c *CLI, args []string) error {
+	fs := newFlagSet(c, "generate")
+	passphrase := fs.Bool("passphrase", false, "generate a passphrase of words instead of a password")
+	length := fs.Int("length", models.DefaultPasswordLength, "length of the password")
+	noLower := fs.Bool("no-lower", false, "do not use lowercase letters")
+	noUpper := fs.Bool("no-upper", false, "do not use uppercase letters")
+	noDigits := fs.Bool("no-digits", false, "do not use digits")
+	noSymbols := fs.Bool("no-symbols", false, "do not use symbols")
+	similar := fs.Bool("allow-similar", false, "allow look-alike characters such as l, 1, O and 0")
+	words := fs.Int("words", models.DefaultPassphraseWords, "number of words of the passphrase")
+	separator := fs.String("separator", models.DefaultWordSeparator, "separator of the words of the passphrase")
+	capitalize := fs.Bool("capitalize", false, "capitalize the words of the passphrase")
+	digit := fs.Bool("digit", false, "append a digit to one of the words of the passphrase")
+	asJSON := fs.Bool("json", false, "print the secret and its entropy as JSON")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	var (
+		s   *models.GeneratedSecret
+		err error
+	)
+	if *passphrase {
+		s, err = models.GeneratePassphrase(models.PassphraseOptions{
+			Words:      *words,
+			Separator:  *separator,
+			Capitalize: *capitalize,
+			Digit:      *digit,
+		})
+	} else {
+		s, err = models.GeneratePassword(models.PasswordOptions{
+			Length:         *length,
+			Lower:          !*noLower,
+			Upper:          !*noUpper,
+			Digits:         !*noDigits,
+			Symbols:        !*noSymbols,
+			ExcludeSimilar: !*similar,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+
+	if *asJSON {
+		return c.printJSON(&generateView{Value: s.Value, Entropy: s.Entropy})
+	}
+
+	// Only the secret is written to stdout, so it can be used in the scripts.
+	fmt.Fprintln(c.stdout, s.Value)
+	fmt.Fprintf(c.stderr, "entropy: %.0f bits\n", s.Entropy)
+
+	return nil
+}
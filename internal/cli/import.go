@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/importer"
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+	"github.com/ArtemShalinFe/gophkeeper/internal/server"
+)
+
+const (
+	importStatusNew       = "new"
+	importStatusDuplicate = "duplicate"
+	importStatusImported  = "imported"
+)
+
+// importView - The representation of the import in the command output.
+type importView struct {
+	Format  importer.Format   `json:"format"`
+	Records []*importItemView `json:"records"`
+	Skipped int               `json:"skipped"`
+	DryRun  bool              `json:"dry_run"`
+}
+
+// importItemView - The representation of one imported record in the command output.
+type importItemView struct {
+	ID          string `json:"id,omitempty"`
+	Description string `json:"description"`
+	Login       string `json:"login"`
+	URL         string `json:"url,omitempty"`
+	Status      string `json:"status"`
+	Error       string `json:"error,omitempty"`
+}
+
+func newImportItemView(r *models.RecordDTO, st string) *importItemView {
+	v := &importItemView{
+		Description: r.Description,
+		URL:         models.MetadataValue(r.Metadata, models.MetadataKeyURL),
+		Status:      st,
+	}
+	if d, err := r.DecodeData(); err == nil {
+		if a, ok := d.(*models.Auth); ok {
+			v.Login = a.Login
+		}
+	}
+	return v
+}
+
+func runImport(ctx context.Context, c *CLI, args []string) error {
+	fs := newFlagSet(c, "import")
+	format := fs.String("format", string(importer.FormatAuto),
+		"format of the file: auto, keepass, chrome, firefox, bitwarden, 1password")
+	dryRun := fs.Bool("dry-run", false, "only show the records that would be imported")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return fmt.Errorf("%w: exactly one file is expected, %s to read the file from stdin", errUsage, stdinValue)
+	}
+
+	f, err := importer.ParseFormat(*format)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+
+	res, err := c.parseImport(pos[0], f)
+	if err != nil {
+		return err
+	}
+
+	gkclient, err := c.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	u := c.user()
+	plan, err := u.PlanImport(ctx, gkclient, res.Records)
+	if err != nil {
+		return fmt.Errorf("an error occured while preview import, err: %w", err)
+	}
+
+	view := &importView{Format: res.Format, Skipped: res.Skipped, DryRun: *dryRun}
+	for _, r := range plan.Records {
+		view.Records = append(view.Records, newImportItemView(r, importStatusNew))
+	}
+
+	var batchErr error
+	if !*dryRun && len(plan.Records) > 0 {
+		brs, err := u.ImportRecords(ctx, gkclient, plan)
+		if err != nil {
+			return fmt.Errorf("an error occured while import records, err: %w", err)
+		}
+		for i, br := range brs {
+			view.Records[i].ID = br.ID
+			view.Records[i].Status = importStatusImported
+			if br.Err != nil {
+				view.Records[i].Error = br.Err.Error()
+			}
+		}
+		batchErr = models.BatchErr(brs)
+	}
+	for _, r := range plan.Duplicates {
+		view.Records = append(view.Records, newImportItemView(r, importStatusDuplicate))
+	}
+
+	if *asJSON {
+		if err := c.printJSON(view); err != nil {
+			return err
+		}
+	} else if err := c.printImport(view); err != nil {
+		return err
+	}
+
+	return batchErr
+}
+
+func (c *CLI) parseImport(path string, f importer.Format) (*importer.Result, error) {
+	var r io.Reader = c.stdin
+	if path != stdinValue {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("an error occured while open file, err: %w", err)
+		}
+		defer file.Close()
+		r = file
+	}
+
+	res, err := importer.Parse(r, f)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while read file, err: %w", err)
+	}
+
+	return res, nil
+}
+
+func (c *CLI) printImport(v *importView) error {
+	w := c.newTabWriter()
+	fmt.Fprintln(w, "DESCRIPTION\tLOGIN\tURL\tSTATUS")
+	for _, r := range v.Records {
+		st := r.Status
+		if r.Error != "" {
+			st = r.Error
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Description, r.Login, r.URL, st)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("an error occured while write output, err: %w", err)
+	}
+
+	fmt.Fprintf(c.stdout, "format: %s, records: %d, skipped: %d\n", v.Format, len(v.Records), v.Skipped)
+	if v.DryRun {
+		fmt.Fprintln(c.stdout, "dry run, nothing was imported")
+	}
+
+	return nil
+}
+
+func runExport(ctx context.Context, c *CLI, args []string) error {
+	fs := newFlagSet(c, "export")
+	format := fs.String("format", string(importer.FormatKeePass), "format of the file, only keepass is supported")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return fmt.Errorf("%w: exactly one file is expected, %s to write the file to stdout", errUsage, stdinValue)
+	}
+	if f, err := importer.ParseFormat(*format); err != nil || f != importer.FormatKeePass {
+		return fmt.Errorf("%w: unsupported export format %q", errUsage, *format)
+	}
+
+	gkclient, err := c.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	rs, err := c.user().GetAllRecords(ctx, gkclient)
+	if err != nil {
+		return fmt.Errorf("an error occured while export records, err: %w", err)
+	}
+
+	write := func(w io.Writer) error {
+		if err := importer.WriteKeePassXML(w, rs); err != nil {
+			return fmt.Errorf("an error occured while export records, err: %w", err)
+		}
+		return nil
+	}
+	if pos[0] == stdinValue {
+		err = write(c.stdout)
+	} else {
+		err = writeFile(pos[0], write)
+	}
+	if err != nil {
+		return err
+	}
+
+	c.reportExport(ctx, gkclient, string(importer.FormatKeePass), len(rs))
+	return nil
+}
+
+// reportExport - writes the export to the audit log of the user on the server.
+// The records have already been exported, so the error is only reported as a warning.
+func (c *CLI) reportExport(ctx context.Context, gkclient *server.GKClient, format string, records int) {
+	if err := gkclient.ReportExport(ctx, c.user().ID, format, records); err != nil {
+		c.printWarnings([]string{err.Error()})
+	}
+}
+
+// writeFile - writes the file that is readable only by the user. The file is not left half-written on error.
+func writeFile(path string, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("an error occured while create file, err: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return fmt.Errorf("an error occured while write file, err: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("an error occured while write file, err: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("an error occured while write file, err: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+const (
+	timeFormat     = time.RFC3339
+	tabMinWidth    = 0
+	tabWidth       = 4
+	tabPadding     = 2
+	tabPadChar     = ' '
+	resultOK       = "ok"
+	statusNeverRun = "never"
+)
+
+// lineEscaper - keeps the multi-line metadata values on one line of the table.
+var lineEscaper = strings.NewReplacer("\\", "\\\\", "\n", "\\n", "\r", "\\r")
+
+// recordView - The representation of the record in the command output.
+type recordView struct {
+	ID          string             `json:"id"`
+	Description string             `json:"description"`
+	Type        string             `json:"type"`
+	Created     time.Time          `json:"created"`
+	Modified    time.Time          `json:"modified"`
+	Version     int64              `json:"version"`
+	Metadata    []*models.Metadata `json:"metadata,omitempty"`
+	Data        map[string]string  `json:"data,omitempty"`
+}
+
+func newRecordView(r *models.Record) *recordView {
+	return &recordView{
+		ID:          r.ID,
+		Description: r.Description,
+		Type:        r.Type,
+		Created:     r.Created,
+		Modified:    r.Modified,
+		Version:     r.Version,
+		Metadata:    r.Metadata,
+	}
+}
+
+// resultView - The representation of the result of the batch operation in the command output.
+type resultView struct {
+	ID    string `json:"id"`
+	Error string `json:"error,omitempty"`
+}
+
+// statusView - The representation of the synchronization status in the command output.
+type statusView struct {
+	LastSync  *time.Time `json:"last_sync,omitempty"`
+	Error     string     `json:"error,omitempty"`
+	Pending   int        `json:"pending"`
+	Conflicts []string   `json:"conflicts,omitempty"`
+	Rejected  []string   `json:"rejected,omitempty"`
+}
+
+func newStatusView(st models.SyncStatus) *statusView {
+	v := &statusView{Pending: st.Pending, Rejected: st.Rejected}
+	if !st.LastSync.IsZero() {
+		v.LastSync = &st.LastSync
+	}
+	if st.LastErr != nil {
+		v.Error = st.LastErr.Error()
+	}
+	return v
+}
+
+func (c *CLI) printJSON(v any) error {
+	enc := json.NewEncoder(c.stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("an error occured while encode output, err: %w", err)
+	}
+
+	return nil
+}
+
+func (c *CLI) newTabWriter() *tabwriter.Writer {
+	return tabwriter.NewWriter(c.stdout, tabMinWidth, tabWidth, tabPadding, tabPadChar, 0)
+}
+
+func (c *CLI) printRecords(views []*recordView) error {
+	w := c.newTabWriter()
+	fmt.Fprintln(w, "ID\tDESCRIPTION\tTYPE\tMODIFIED\tVERSION")
+	for _, v := range views {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", v.ID, v.Description, v.Type, v.Modified.Format(timeFormat), v.Version)
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("an error occured while write output, err: %w", err)
+	}
+
+	return nil
+}
+
+func (c *CLI) printRecord(v *recordView, fields []models.RecordField) error {
+	w := c.newTabWriter()
+	fmt.Fprintf(w, "ID:\t%s\n", v.ID)
+	fmt.Fprintf(w, "%s:\t%s\n", models.FieldDescription, v.Description)
+	fmt.Fprintf(w, "Type:\t%s\n", v.Type)
+	fmt.Fprintf(w, "Created:\t%s\n", v.Created.Format(timeFormat))
+	fmt.Fprintf(w, "Modified:\t%s\n", v.Modified.Format(timeFormat))
+	fmt.Fprintf(w, "Version:\t%d\n", v.Version)
+	for _, f := range fields {
+		fmt.Fprintf(w, "%s:\t%s\n", f.Name, f.Value)
+	}
+	for _, m := range v.Metadata {
+		key := m.Key
+		if m.Type != "" {
+			key += " (" + string(m.Type) + ")"
+		}
+		fmt.Fprintf(w, "%s %s:\t%s\n", models.FieldMetadata, key, lineEscaper.Replace(m.Value))
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("an error occured while write output, err: %w", err)
+	}
+
+	return nil
+}
+
+func (c *CLI) printResults(views []*resultView) {
+	for _, v := range views {
+		res := resultOK
+		if v.Error != "" {
+			res = v.Error
+		}
+		fmt.Fprintf(c.stdout, "%s\t%s\n", v.ID, res)
+	}
+}
+
+func (c *CLI) printStatus(v *statusView) {
+	lastSync := statusNeverRun
+	if v.LastSync != nil {
+		lastSync = v.LastSync.Format(timeFormat)
+	}
+	fmt.Fprintf(c.stdout, "last sync: %s\npending: %d\n", lastSync, v.Pending)
+	if len(v.Conflicts) > 0 {
+		fmt.Fprintf(c.stdout, "conflicts: %s\n", strings.Join(v.Conflicts, ", "))
+	}
+	if len(v.Rejected) > 0 {
+		fmt.Fprintf(c.stdout, "rejected: %s\n", strings.Join(v.Rejected, ", "))
+	}
+}
@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/config"
+)
+
+// profileView - The representation of the profile in the command output.
+type profileView struct {
+	Name    string          `json:"name"`
+	Current bool            `json:"current"`
+	Profile *config.Profile `json:"profile"`
+}
+
+func runProfile(_ context.Context, c *CLI, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: profile subcommand is not specified", errUsage)
+	}
+
+	f, err := config.ReadClientFile(c.cfg.ConfigFile)
+	if err != nil {
+		return fmt.Errorf("an error occured while read config file, err: %w", err)
+	}
+
+	switch args[0] {
+	case "list":
+		return runProfileList(c, f, args[1:])
+	case "set":
+		return runProfileSet(c, f, args[1:])
+	case "use":
+		return runProfileUse(c, f, args[1:])
+	case "remove":
+		return runProfileRemove(c, f, args[1:])
+	default:
+		return fmt.Errorf("%w: unknown profile subcommand %q", errUsage, args[0])
+	}
+}
+
+func runProfileList(c *CLI, f *config.ClientFile, args []string) error {
+	fs := newFlagSet(c, "profile list")
+	asJSON := fs.Bool("json", false, "print the profiles as JSON")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	views := make([]*profileView, 0, len(f.Profiles))
+	for _, name := range f.Names() {
+		views = append(views, &profileView{Name: name, Current: name == f.Current, Profile: f.Profiles[name]})
+	}
+
+	if *asJSON {
+		return c.printJSON(views)
+	}
+
+	w := c.newTabWriter()
+	fmt.Fprintln(w, "CURRENT\tNAME\tADDRESS\tLAST LOGIN")
+	for _, v := range views {
+		current := ""
+		if v.Current {
+			current = "*"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", current, v.Name, v.Profile.Address, v.Profile.LastLogin)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("an error occured while write output, err: %w", err)
+	}
+
+	return nil
+}
+
+func runProfileSet(c *CLI, f *config.ClientFile, args []string) error {
+	fs := newFlagSet(c, "profile set")
+	address := fs.String("address", "", "address of the server")
+	caFile := fs.String("ca", "", "path to the certificate that the server certificate is verified with")
+	certPin := fs.String("pin", "", "SHA-256 fingerprint of the server certificate in hex")
+	syncInterval := fs.Duration("sync-interval", 0, "interval between the synchronizations")
+	lockTimeout := fs.Duration("lock-timeout", 0, "the session is locked after this time without input")
+	use := fs.Bool("use", false, "make the profile current")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	name, err := profileName(pos)
+	if err != nil {
+		return err
+	}
+
+	p, ok := f.Profiles[name]
+	if !ok {
+		p = &config.Profile{}
+		f.Profiles[name] = p
+	}
+	fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "address":
+			p.Address = *address
+		case "ca":
+			p.CAFile = *caFile
+		case "pin":
+			p.CertPin = *certPin
+		case "sync-interval":
+			p.SyncInterval = config.Duration(*syncInterval)
+		case "lock-timeout":
+			p.LockTimeout = config.Duration(*lockTimeout)
+		}
+	})
+	if *use || f.Current == "" {
+		return switchProfile(c, f, name)
+	}
+
+	return saveClientFile(c, f)
+}
+
+func runProfileUse(c *CLI, f *config.ClientFile, args []string) error {
+	fs := newFlagSet(c, "profile use")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	name, err := profileName(pos)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := f.Profiles[name]; !ok {
+		return fmt.Errorf("%w: %w: %q", errUsage, config.ErrUnknownProfile, name)
+	}
+
+	return switchProfile(c, f, name)
+}
+
+// switchProfile - makes the profile current. The first current profile takes over the data
+// of the client without profiles, so the session and the pending changes are kept.
+func switchProfile(c *CLI, f *config.ClientFile, name string) error {
+	adopt := f.Current == ""
+	f.Current = name
+	if err := saveClientFile(c, f); err != nil {
+		return err
+	}
+
+	if adopt {
+		if err := c.cfg.AdoptDataDir(name); err != nil {
+			return fmt.Errorf("an error occured while move data to profile %q, err: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func runProfileRemove(c *CLI, f *config.ClientFile, args []string) error {
+	fs := newFlagSet(c, "profile remove")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	name, err := profileName(pos)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := f.Profiles[name]; !ok {
+		return fmt.Errorf("%w: %w: %q", errUsage, config.ErrUnknownProfile, name)
+	}
+	delete(f.Profiles, name)
+	if f.Current == name {
+		f.Current = ""
+	}
+
+	return saveClientFile(c, f)
+}
+
+// profileName - returns the name of the profile that is the only positional argument.
+func profileName(pos []string) (string, error) {
+	if len(pos) != 1 {
+		return "", fmt.Errorf("%w: exactly one profile name must be specified", errUsage)
+	}
+	if err := config.CheckProfileName(pos[0]); err != nil {
+		return "", fmt.Errorf("%w: %w", errUsage, err)
+	}
+	return pos[0], nil
+}
+
+func saveClientFile(c *CLI, f *config.ClientFile) error {
+	if err := f.Save(c.cfg.ConfigFile); err != nil {
+		return fmt.Errorf("an error occured while save config file, err: %w", err)
+	}
+	return nil
+}
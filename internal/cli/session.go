@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/config"
+)
+
+const (
+	sessionFile     = "session.json"
+	sessionDirMode  = 0700
+	sessionFileMode = 0600
+)
+
+// errNoSession - An error that is returned if the command requires a session, but the user is not logged in.
+var errNoSession = errors.New("not logged in, run `gclient login` first")
+
+// Session - The saved login of the user. The session allows commands to run without entering the password.
+// The password is not saved.
+type Session struct {
+	// UserID - id of the logged in user.
+	UserID string `json:"user_id"`
+	// Login - login of the logged in user.
+	Login string `json:"login"`
+	// DeviceID - id of the device registered at login.
+	DeviceID string `json:"device_id"`
+}
+
+func sessionPath(cfg *config.ClientCfg) (string, error) {
+	dir, err := cfg.GetDataDir()
+	if err != nil {
+		return "", fmt.Errorf("an error occured while retrieving data dir, err: %w", err)
+	}
+
+	return filepath.Join(dir, sessionFile), nil
+}
+
+func loadSession(cfg *config.ClientCfg) (*Session, error) {
+	path, err := sessionPath(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, errNoSession
+		}
+		return nil, fmt.Errorf("an error occured while read session, err: %w", err)
+	}
+
+	var s Session
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("an error occured while decode session, err: %w", err)
+	}
+	if s.UserID == "" || s.DeviceID == "" {
+		return nil, errNoSession
+	}
+
+	return &s, nil
+}
+
+func saveSession(cfg *config.ClientCfg, s *Session) error {
+	path, err := sessionPath(cfg)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("an error occured while encode session, err: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), sessionDirMode); err != nil {
+		return fmt.Errorf("an error occured while create session dir, err: %w", err)
+	}
+
+	if err := os.WriteFile(path, b, sessionFileMode); err != nil {
+		return fmt.Errorf("an error occured while write session, err: %w", err)
+	}
+
+	return nil
+}
+
+func removeSession(cfg *config.ClientCfg) error {
+	path, err := sessionPath(cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("an error occured while remove session, err: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/build"
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+// buildView - The representation of the build of the client or the server in the command output.
+type buildView struct {
+	Version            string   `json:"version"`
+	Date               string   `json:"date"`
+	Commit             string   `json:"commit"`
+	ProtocolVersion    int      `json:"protocol_version"`
+	MinProtocolVersion int      `json:"min_protocol_version"`
+	Features           []string `json:"features,omitempty"`
+}
+
+// versionView - The representation of the versions in the command output.
+type versionView struct {
+	Client   *buildView `json:"client"`
+	Server   *buildView `json:"server,omitempty"`
+	Warnings []string   `json:"warnings,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+func runVersion(ctx context.Context, c *CLI, args []string) error {
+	fs := newFlagSet(c, "version")
+	withServer := fs.Bool("server", false, "also print the version of the server and check the compatibility")
+	asJSON := fs.Bool("json", false, "print the versions as JSON")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	b := build.NewBuild()
+	v := &versionView{
+		Client: &buildView{
+			Version:            b.Version(),
+			Date:               b.Date(),
+			Commit:             b.Commit(),
+			ProtocolVersion:    models.ProtocolVersion,
+			MinProtocolVersion: models.MinProtocolVersion,
+		},
+	}
+
+	var checkErr error
+	if *withServer {
+		gkclient, err := c.connect(ctx)
+		if err != nil {
+			return err
+		}
+		compat, err := models.CheckServer(ctx, gkclient)
+		if err != nil && !errors.Is(err, models.ErrIncompatibleServer) {
+			return fmt.Errorf("an error occured while check server, err: %w", err)
+		}
+		checkErr = err
+		if compat.Info != nil {
+			v.Server = &buildView{
+				Version:            compat.Info.Version,
+				Date:               compat.Info.Date,
+				Commit:             compat.Info.Commit,
+				ProtocolVersion:    compat.Info.ProtocolVersion,
+				MinProtocolVersion: compat.Info.MinProtocolVersion,
+				Features:           compat.Info.Features,
+			}
+		}
+		v.Warnings = compat.Warnings
+		if checkErr != nil {
+			v.Error = checkErr.Error()
+		}
+	}
+
+	if *asJSON {
+		if err := c.printJSON(v); err != nil {
+			return err
+		}
+		return checkErr
+	}
+
+	c.printBuild("Client", v.Client)
+	if v.Server != nil {
+		c.printBuild("Server", v.Server)
+	}
+	c.printWarnings(v.Warnings)
+
+	return checkErr
+}
+
+func (c *CLI) printBuild(name string, v *buildView) {
+	fmt.Fprintf(c.stdout, "%s version: %s\n", name, v.Version)
+	fmt.Fprintf(c.stdout, "%s build date: %s\n", name, v.Date)
+	fmt.Fprintf(c.stdout, "%s build commit: %s\n", name, v.Commit)
+	fmt.Fprintf(c.stdout, "%s protocol: %d (supports %d-%d)\n",
+		name, v.ProtocolVersion, v.MinProtocolVersion, v.ProtocolVersion)
+	if len(v.Features) > 0 {
+		fmt.Fprintf(c.stdout, "%s features: %s\n", name, strings.Join(v.Features, ", "))
+	}
+}
+
+func (c *CLI) printWarnings(warnings []string) {
+	for _, w := range warnings {
+		fmt.Fprintf(c.stderr, "gclient: warning: %s\n", w)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"log"
 
 	"go.uber.org/zap"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/config"
 )
 
 // App - The object that is used to launch the TUI interface.
@@ -16,9 +18,9 @@ type App struct {
 }
 
 // NewApp - Object Constructor.
-func NewApp(log *zap.Logger) *App {
+func NewApp(log *zap.Logger, cfg *config.ClientCfg) *App {
 	return &App{
-		ui:  &TUI{},
+		ui:  &TUI{cfg: cfg},
 		log: log,
 	}
 }
@@ -0,0 +1,16 @@
+//go:build !windows
+
+package client
+
+import (
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+// suspendProcess - stops the client as the shell does on Ctrl+Z, the terminal is restored when it is continued.
+func suspendProcess() {
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGTSTP); err != nil {
+		zap.L().Warn("an error occured while suspend client", zap.Error(err))
+	}
+}
@@ -0,0 +1,6 @@
+//go:build windows
+
+package client
+
+// suspendProcess - the console of Windows cannot suspend the process, the session is only locked.
+func suspendProcess() {}
@@ -3,14 +3,19 @@ package client
 import (
 	"context"
 	"fmt"
+	"sync"
+	"sync/atomic"
 
 	"github.com/rivo/tview"
 	"go.uber.org/zap"
 
+	"github.com/ArtemShalinFe/gophkeeper/internal/clipboard"
 	"github.com/ArtemShalinFe/gophkeeper/internal/config"
 	"github.com/ArtemShalinFe/gophkeeper/internal/models"
 	"github.com/ArtemShalinFe/gophkeeper/internal/server"
+	"github.com/ArtemShalinFe/gophkeeper/internal/storage/file"
 	"github.com/ArtemShalinFe/gophkeeper/internal/storage/mem"
+	"github.com/ArtemShalinFe/gophkeeper/internal/storage/sealed"
 )
 
 const (
@@ -23,13 +28,37 @@ const (
 
 // TUI - An object that contains everything necessary for the text user interface to work correctly.
 type TUI struct {
-	app        *tview.Application
-	pages      *tview.Pages
-	gkclient   *server.GKClient
-	authUser   *models.User
-	cache      *mem.MemStorage
+	app      *tview.Application
+	pages    *tview.Pages
+	gkclient *server.GKClient
+	authUser *models.User
+	cache    *mem.MemStorage
+	// vault - the cache of the logged in user that seals the records while the session is locked.
+	vault *sealed.Storage
+	cfg   *config.ClientCfg
+	// compat - the compatibility of the server of the profile, nil until it is checked at the login.
+	compat     *models.Compatibility
 	syncStatus *tview.TextView
-	recLimit   int
+	// syncer - synchronizer of the logged in user, local - the cache whose changes are queued for sync.
+	syncer *models.Synchronizer
+	local  models.RecordStorage
+	// stopSync - stops the synchronizer and waits until it returns, see runSync.
+	stopSync func()
+	// queue - the pending changes of the logged in user, they are wiped from memory while the session is locked.
+	queue *file.Queue
+	// conflicts - IDs of records whose conflicts are displayed to the user now.
+	conflicts   map[string]struct{}
+	conflictsMu sync.Mutex
+	recLimit    int
+	// list - the displayed record list, nil if the list is not displayed.
+	list *recordList
+	clip *clipboard.Clipboard
+	// stopClipClear - stops the countdown of the clipboard clearing, nil if nothing is waiting to be cleared.
+	// It is accessed only from the event loop.
+	stopClipClear context.CancelFunc
+	// lastInput - the time of the last input in Unix nanoseconds, the session is locked after the idle period.
+	lastInput    atomic.Int64
+	stopIdleLock context.CancelFunc
 }
 
 // Start - starts graphical text user interface.
@@ -37,19 +66,31 @@ func (ui *TUI) Start(ctx context.Context) error {
 	ui.app = tview.NewApplication()
 	ui.pages = tview.NewPages()
 	ui.recLimit = models.DefaultLimit
+	ui.conflicts = make(map[string]struct{})
 	ui.syncStatus = tview.NewTextView().SetTextAlign(tview.AlignCenter)
 
 	log := zap.L()
-	cfg := config.NewClientCfg()
-	if err := config.ReadEnvClientCfg(cfg); err != nil {
-		return fmt.Errorf("an error occured while read config, err: %w", err)
+	cfg := ui.cfg
+	if cfg == nil {
+		c, _, err := config.LoadClientCfg(nil)
+		if err != nil {
+			return fmt.Errorf("an error occured while read config, err: %w", err)
+		}
+		cfg = c
 	}
 	gkclient, err := server.NewGKClient(ctx, cfg, log)
 	if err != nil {
 		return fmt.Errorf("an error occure while init gk client, err: %w", err)
 	}
 
+	clip, err := clipboard.New(cfg.Clipboard)
+	if err != nil {
+		return fmt.Errorf("an error occured while init clipboard, err: %w", err)
+	}
+
 	ui.gkclient = gkclient
+	ui.clip = clip
+	ui.cfg = cfg
 	ui.cache = mem.NewMemStorage()
 	ui.displayUserLoginPage(ctx)
 	appStopCh := make(chan error)
@@ -64,24 +105,22 @@ func (ui *TUI) Start(ctx context.Context) error {
 			AddItem(statusFlex, 1, 1, false), 0, 1, true)
 
 	go func() {
-		appStopCh <- ui.app.SetRoot(flex, true).EnableMouse(true).SetFocus(flex).Run()
+		appStopCh <- ui.app.SetRoot(flex, true).
+			SetInputCapture(ui.captureKey(ctx)).
+			SetMouseCapture(ui.captureMouse).
+			EnableMouse(true).SetFocus(flex).Run()
 	}()
 
+	var runErr error
 	select {
 	case <-ctx.Done():
 		ui.app.Stop()
-		return <-appStopCh
-	case err := <-appStopCh:
-		return err
+		runErr = <-appStopCh
+	case runErr = <-appStopCh:
 	}
-}
 
-// Sync - Synchronizes the client storage cache and the server storage using the version vector mechanism.
-func (ui *TUI) Sync(ctx context.Context) {
-	if ui.authUser == nil {
-		return
-	}
-	if err := ui.authUser.SyncRecords(ctx, ui.cache, ui.gkclient, defaulTickSync); err != nil {
-		ui.statusSetup("sync with server was failed", defaultStatusTime)
-	}
+	// The copied secret must not stay in the clipboard after the exit.
+	ui.clearClipboard()
+
+	return runErr
 }
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/build"
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+const (
+	pageAbout = "About"
+
+	buttonAboutDesc = "About"
+)
+
+// checkServer - checks once that the server of the profile is compatible with the client.
+// The warnings are displayed in the status line, the incompatible server is returned as an error.
+func (ui *TUI) checkServer(ctx context.Context) error {
+	if ui.compat != nil {
+		return nil
+	}
+
+	compat, err := models.CheckServer(ctx, ui.gkclient)
+	if err != nil {
+		return fmt.Errorf("an error occured while check server, err: %w", err)
+	}
+	ui.compat = compat
+
+	if len(compat.Warnings) > 0 {
+		ui.statusSetup("Warning: "+strings.Join(compat.Warnings, "; "), defaultStatusTime)
+	}
+
+	return nil
+}
+
+// displayAbout - displays the builds of the client and the server and the result of the compatibility check.
+func (ui *TUI) displayAbout(ctx context.Context) {
+	var sb strings.Builder
+	b := build.NewBuild()
+	fmt.Fprintf(&sb, "Client version: %s\nClient build date: %s\nClient build commit: %s\n",
+		b.Version(), b.Date(), b.Commit())
+	fmt.Fprintf(&sb, "Client protocol: %d (supports %d-%d)\n\n",
+		models.ProtocolVersion, models.MinProtocolVersion, models.ProtocolVersion)
+
+	fmt.Fprintf(&sb, "Server: %s\n", ui.cfg.GKeeper)
+	if ui.cfg.Profile != "" {
+		fmt.Fprintf(&sb, "Profile: %s\n", ui.cfg.Profile)
+	}
+
+	compat, err := models.CheckServer(ctx, ui.gkclient)
+	if compat != nil && compat.Info != nil {
+		info := compat.Info
+		fmt.Fprintf(&sb, "Server version: %s\nServer build date: %s\nServer build commit: %s\n",
+			info.Version, info.Date, info.Commit)
+		fmt.Fprintf(&sb, "Server protocol: %d (supports %d-%d)\nServer features: %s\n",
+			info.ProtocolVersion, info.MinProtocolVersion, info.ProtocolVersion, strings.Join(info.Features, ", "))
+	}
+	if compat != nil {
+		for _, w := range compat.Warnings {
+			fmt.Fprintf(&sb, "Warning: %s\n", w)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(&sb, "Error: %v\n", err)
+	}
+
+	text := tview.NewTextView().SetText(sb.String())
+
+	buttons := tview.NewForm().
+		AddButton(buttonCancelDesc, func() { ui.pages.RemovePage(pageAbout) })
+	buttons.SetButtonsAlign(tview.AlignLeft).SetBorderPadding(0, 0, 0, 0)
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(text, 0, 1, false).
+		AddItem(buttons, 1, 1, true)
+
+	flex.SetBorder(true).SetTitle(" About ").SetTitleAlign(tview.AlignLeft)
+
+	ui.pages.AddPage(pageAbout, flex, true, true)
+}
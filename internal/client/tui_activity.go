@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rivo/tview"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+const pageActivity = "Activity"
+
+const activityUnknownDevice = "unknown"
+
+const (
+	colActivityTime = iota
+	colActivityAction
+	colActivityDevice
+	colActivityIP
+	colActivityRecord
+	colActivityDetails
+)
+
+// displayActivity - displays the page of the audit log of the user from the offset, the latest events come first.
+func (ui *TUI) displayActivity(ctx context.Context, offset int) {
+	es, err := ui.authUser.GetAuditEvents(ctx, ui.gkclient, offset, ui.recLimit)
+	if err != nil {
+		ui.displayErr(fmt.Sprintf("an error occured while retrieving activity, err: %v", err))
+		return
+	}
+
+	table := tview.NewTable()
+
+	table.SetCell(0, colActivityTime, addTableHeaderCell("TIME"))
+	table.SetCell(0, colActivityAction, addTableHeaderCell("ACTION"))
+	table.SetCell(0, colActivityDevice, addTableHeaderCell("DEVICE"))
+	table.SetCell(0, colActivityIP, addTableHeaderCell("IP"))
+	table.SetCell(0, colActivityRecord, addTableHeaderCell("RECORD"))
+	table.SetCell(0, colActivityDetails, addTableHeaderCell("DETAILS"))
+
+	for i, e := range es {
+		rn := i + 1
+
+		table.SetCell(rn, colActivityTime, addTableCell(e.Time.Local().Format(fnDateFormat)))
+		table.SetCell(rn, colActivityAction, addTableCell(string(e.Action)))
+		table.SetCell(rn, colActivityDevice, addTableCell(ui.activityDevice(e)))
+		table.SetCell(rn, colActivityIP, addTableCell(e.PeerIP))
+		table.SetCell(rn, colActivityRecord, addTableCell(ui.activityRecord(ctx, e)))
+		table.SetCell(rn, colActivityDetails, addTableCell(e.Details))
+	}
+	table.SetSelectable(true, false).
+		SetFixed(1, 0)
+
+	redisplay := func(offset int) {
+		ui.pages.RemovePage(pageActivity)
+		ui.displayActivity(ctx, offset)
+	}
+	buttons := tview.NewForm().
+		AddButton("<", func() {
+			if offset > 0 {
+				redisplay(max(0, offset-ui.recLimit))
+			}
+		}).
+		AddButton("Refresh", func() { redisplay(offset) }).
+		AddButton(">", func() {
+			if len(es) == ui.recLimit {
+				redisplay(offset + ui.recLimit)
+			}
+		}).
+		AddButton("Back to list", func() {
+			ui.pages.RemovePage(pageActivity)
+		})
+	buttons.SetButtonsAlign(tview.AlignLeft).SetBorderPadding(0, 0, 0, 0)
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(buttons, 1, 1, false)
+
+	flex.SetBorder(true).
+		SetTitle(fmt.Sprintf(" %s: events %d-%d ", pageActivity, offset+1, offset+len(es))).
+		SetTitleAlign(tview.AlignLeft)
+
+	ui.pages.AddPage(pageActivity, flex, true, true)
+}
+
+// activityDevice - returns the name of the device of the event, the ID is shown if the device has no name.
+func (ui *TUI) activityDevice(e *models.AuditEvent) string {
+	name := e.DeviceName
+	if name == "" {
+		name = e.DeviceID
+	}
+	if name == "" {
+		return activityUnknownDevice
+	}
+	if e.DeviceID == ui.gkclient.DeviceID() {
+		name += deviceThis
+	}
+	return name
+}
+
+// activityRecord - returns the description of the record of the event from the cache,
+// the ID is shown if the record is not in the cache, for example, it has been deleted.
+func (ui *TUI) activityRecord(ctx context.Context, e *models.AuditEvent) string {
+	if e.RecordID == "" {
+		return ""
+	}
+	r, err := ui.cache.GetRecord(ctx, ui.authUser.ID, e.RecordID)
+	if err != nil || r.Deleted {
+		return e.RecordID
+	}
+	return r.Description
+}
@@ -0,0 +1,175 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+// copyField - The value of the record that can be copied to the clipboard.
+type copyField string
+
+const (
+	copyLogin    copyField = "Login"
+	copyPassword copyField = "Password"
+	copyNumber   copyField = "Card number"
+	copyOTP      copyField = "OTP"
+)
+
+// copyKeys - The key bindings of the record list that copy the value of the selected record.
+var copyKeys = map[rune]copyField{
+	'u': copyLogin,
+	'p': copyPassword,
+	'n': copyNumber,
+	'o': copyOTP,
+}
+
+const copyKeysHint = "u: copy login, p: copy password, n: copy card number, o: copy OTP"
+
+var errNoCopyValue = errors.New("the record has no such value")
+
+// copyRecordKey - copies the value of the record bound to the key of the event.
+// Returns nil if the event has been handled.
+func (ui *TUI) copyRecordKey(ctx context.Context, recordID string, event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() != tcell.KeyRune {
+		return event
+	}
+	f, ok := copyKeys[event.Rune()]
+	if !ok {
+		return event
+	}
+
+	r, err := ui.authUser.GetRecord(ctx, ui.cache, recordID)
+	if err != nil {
+		ui.displayErr(err.Error())
+		return nil
+	}
+	ui.copyRecordField(r, f)
+
+	return nil
+}
+
+// copyRecordField - copies the value of the record to the clipboard.
+func (ui *TUI) copyRecordField(r *models.Record, f copyField) {
+	v, err := recordFieldValue(r, f)
+	if err != nil {
+		ui.displayErr(fmt.Sprintf("%s cannot be copied, err: %v", f, err))
+		return
+	}
+
+	ui.copyToClipboard(string(f), v)
+}
+
+// recordFieldValue - returns the value of the record, the current one-time password is generated by the secret.
+func recordFieldValue(r *models.Record, f copyField) (string, error) {
+	if f == copyOTP {
+		secret := models.MetadataValue(r.Metadata, models.MetadataKeyTOTP)
+		if secret == "" {
+			return "", errNoCopyValue
+		}
+		totp, err := models.NewTOTP(secret)
+		if err != nil {
+			return "", fmt.Errorf("an error occured while generate OTP, err: %w", err)
+		}
+		code, _ := totp.Code(time.Now())
+		return code, nil
+	}
+
+	d, err := r.DecodeData()
+	if err != nil {
+		return "", fmt.Errorf("an error occured while decode record, err: %w", err)
+	}
+
+	switch d := d.(type) {
+	case *models.Auth:
+		switch f {
+		case copyLogin:
+			return d.Login, nil
+		case copyPassword:
+			return d.Password, nil
+		}
+	case *models.Card:
+		if f == copyNumber {
+			return d.Number, nil
+		}
+	}
+
+	return "", errNoCopyValue
+}
+
+// copyToClipboard - copies the value and starts the countdown of the clipboard clearing.
+// Must be called from the event loop, as the escape sequence is written to the terminal.
+func (ui *TUI) copyToClipboard(what string, value string) {
+	if err := ui.clip.Copy(value); err != nil {
+		ui.displayErr(err.Error())
+		return
+	}
+
+	if ui.stopClipClear != nil {
+		ui.stopClipClear()
+		ui.stopClipClear = nil
+	}
+
+	timeout := ui.cfg.ClipboardTimeout
+	if timeout <= 0 {
+		ui.statusSetup(what+" copied to clipboard", defaultStatusTime)
+		return
+	}
+
+	cctx, cancel := context.WithCancel(context.Background())
+	ui.stopClipClear = cancel
+	deadline := time.Now().Add(timeout)
+	ui.statusSetup(clipboardCountdown(what, timeout), 0)
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-cctx.Done():
+				return
+			case now := <-ticker.C:
+				left := deadline.Sub(now)
+				ui.app.QueueUpdateDraw(func() {
+					// The countdown has been replaced by a newer copy.
+					if cctx.Err() != nil {
+						return
+					}
+					if left > 0 {
+						ui.statusSetup(clipboardCountdown(what, left), 0)
+						return
+					}
+					ui.clearClipboard()
+				})
+				if left <= 0 {
+					return
+				}
+			}
+		}
+	}()
+}
+
+// clearClipboard - clears the clipboard if the copied value has not been cleared yet.
+func (ui *TUI) clearClipboard() {
+	if ui.stopClipClear == nil {
+		return
+	}
+	ui.stopClipClear()
+	ui.stopClipClear = nil
+
+	if err := ui.clip.Clear(); err != nil {
+		ui.statusSetup(fmt.Sprintf("an error occured while clear clipboard, err: %v", err), defaultStatusTime)
+		return
+	}
+	ui.statusSetup("Clipboard cleared", defaultStatusTime)
+}
+
+func clipboardCountdown(what string, left time.Duration) string {
+	return fmt.Sprintf("%s copied, clipboard will be cleared in %ds", what, int(left.Round(time.Second)/time.Second))
+}
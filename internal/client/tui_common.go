@@ -13,6 +13,7 @@ const (
 	buttonLoginDesc    = "Login"
 	buttonOkDesc       = "Ok"
 	buttonUpdate       = "Update"
+	buttonDeleteDesc   = "Delete"
 )
 
 func (ui *TUI) displayQuitModal() {
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rivo/tview"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+const (
+	pageConflict = "Conflict"
+
+	buttonApplyDesc    = "Apply"
+	buttonKeepBothDesc = "Keep both"
+
+	sideLocalDesc  = "Local"
+	sideRemoteDesc = "Remote"
+)
+
+const (
+	colConflictField = iota
+	colConflictLocal
+	colConflictRemote
+)
+
+// HandleConflict - Implements models.ConflictHandler.
+// Shows the conflict resolution page, if the conflict of the record is not shown yet.
+func (ui *TUI) HandleConflict(ctx context.Context, c *models.Conflict) {
+	ui.conflictsMu.Lock()
+	defer ui.conflictsMu.Unlock()
+
+	if _, ok := ui.conflicts[c.ID()]; ok {
+		return
+	}
+	ui.conflicts[c.ID()] = struct{}{}
+
+	// The handler is called by the synchronizer that the event loop may wait for, so the update is not waited for.
+	go ui.app.QueueUpdateDraw(func() {
+		// The conflict is found again by the first synchronization after the unlock.
+		if ui.locked() {
+			ui.conflictDone(c)
+			return
+		}
+		ui.displayConflict(ctx, c)
+	})
+}
+
+func (ui *TUI) conflictDone(c *models.Conflict) {
+	ui.conflictsMu.Lock()
+	defer ui.conflictsMu.Unlock()
+
+	delete(ui.conflicts, c.ID())
+}
+
+func (ui *TUI) displayConflict(ctx context.Context, c *models.Conflict) {
+	name := fmt.Sprintf("%s %s", pageConflict, c.ID())
+
+	fields, err := c.Fields()
+	if err != nil {
+		ui.conflictDone(c)
+		ui.displayErr(fmt.Sprintf("an error occured while retrieving conflict fields, err: %v", err))
+		return
+	}
+
+	table := tview.NewTable().SetBorders(true)
+	table.SetCell(0, colConflictField, addTableHeaderCell("FIELD"))
+	table.SetCell(0, colConflictLocal, addTableHeaderCell("LOCAL"))
+	table.SetCell(0, colConflictRemote, addTableHeaderCell("REMOTE"))
+
+	choices := make(map[string]models.ConflictSide, len(fields))
+	form := tview.NewForm()
+	for i, f := range fields {
+		table.SetCell(i+1, colConflictField, addTableHeaderCell(f.Name))
+		table.SetCell(i+1, colConflictLocal, addTableCell(f.Local))
+		table.SetCell(i+1, colConflictRemote, addTableCell(f.Remote))
+
+		field := f.Name
+		form.AddDropDown(field, []string{sideLocalDesc, sideRemoteDesc}, int(models.LocalSide),
+			func(option string, optionIndex int) {
+				choices[field] = models.ConflictSide(optionIndex)
+			})
+	}
+
+	done := func() {
+		ui.conflictDone(c)
+		ui.pages.RemovePage(name)
+	}
+
+	buttons := tview.NewForm().
+		AddButton(buttonApplyDesc, func() {
+			r, err := c.Resolve(choices)
+			if err != nil {
+				ui.displayErr(err.Error())
+				return
+			}
+
+			if _, err := ui.authUser.UpdateRecord(ctx, ui.local, r); err != nil {
+				ui.displayErr(err.Error())
+				return
+			}
+
+			done()
+		}).
+		AddButton(buttonKeepBothDesc, func() {
+			remote, local := c.KeepBoth()
+
+			if _, err := ui.authUser.UpdateRecord(ctx, ui.local, remote); err != nil {
+				ui.displayErr(err.Error())
+				return
+			}
+
+			if _, err := ui.authUser.UpdateRecord(ctx, ui.local, local); err != nil {
+				ui.displayErr(err.Error())
+				return
+			}
+
+			done()
+		})
+
+	buttons.SetButtonsAlign(tview.AlignLeft).SetBorderPadding(0, 0, 0, 0)
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, false).
+		AddItem(form, 0, 1, true).
+		AddItem(buttons, 1, 1, false)
+
+	flex.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Conflict: %s ", c.Local.Description)).
+		SetTitleAlign(tview.AlignLeft)
+
+	ui.pages.AddPage(name, flex, true, true)
+}
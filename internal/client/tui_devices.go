@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rivo/tview"
+)
+
+const (
+	pageListDevices  = "List devices"
+	pageRevokeDevice = "Revoke device"
+
+	buttonRevokeDesc = "Revoke"
+
+	deviceStatusActive  = "active"
+	deviceStatusRevoked = "revoked"
+	deviceThis          = " (this device)"
+	deviceNeverSynced   = "never"
+)
+
+const (
+	colDeviceID = iota
+	colDeviceName
+	colDevicePlatform
+	colDeviceVersion
+	colDeviceCreated
+	colDeviceLastSeen
+	colDeviceLastSync
+	colDeviceStatus
+)
+
+func (ui *TUI) displayDevices(ctx context.Context) {
+	ds, err := ui.authUser.GetDevices(ctx, ui.gkclient)
+	if err != nil {
+		ui.displayErr(fmt.Sprintf("an error occured while retrieving device list, err: %v", err))
+		return
+	}
+
+	table := tview.NewTable()
+
+	table.SetCell(0, colDeviceID, addTableHeaderCell("ID"))
+	table.SetCell(0, colDeviceName, addTableHeaderCell("NAME"))
+	table.SetCell(0, colDevicePlatform, addTableHeaderCell("PLATFORM"))
+	table.SetCell(0, colDeviceVersion, addTableHeaderCell("VERSION"))
+	table.SetCell(0, colDeviceCreated, addTableHeaderCell("CREATED"))
+	table.SetCell(0, colDeviceLastSeen, addTableHeaderCell("LAST SEEN"))
+	table.SetCell(0, colDeviceLastSync, addTableHeaderCell("LAST SYNC"))
+	table.SetCell(0, colDeviceStatus, addTableHeaderCell("STATUS"))
+
+	for i, d := range ds {
+		rn := i + 1
+
+		name := d.Name
+		if d.ID == ui.gkclient.DeviceID() {
+			name += deviceThis
+		}
+
+		lastSync := deviceNeverSynced
+		if !d.LastSync.IsZero() {
+			lastSync = d.LastSync.Format(fnDateFormat)
+		}
+
+		st := deviceStatusActive
+		if d.Revoked {
+			st = deviceStatusRevoked
+		}
+
+		table.SetCell(rn, colDeviceID, addTableCell(d.ID))
+		table.SetCell(rn, colDeviceName, addTableCell(name))
+		table.SetCell(rn, colDevicePlatform, addTableCell(d.Platform))
+		table.SetCell(rn, colDeviceVersion, addTableCell(d.Version))
+		table.SetCell(rn, colDeviceCreated, addTableCell(d.Created.Format(fnDateFormat)))
+		table.SetCell(rn, colDeviceLastSeen, addTableCell(d.LastSeen.Format(fnDateFormat)))
+		table.SetCell(rn, colDeviceLastSync, addTableCell(lastSync))
+		table.SetCell(rn, colDeviceStatus, addTableCell(st))
+	}
+	table.SetSelectable(true, false)
+
+	table.SetSelectedFunc(func(row int, column int) {
+		if row == 0 || table.GetCell(row, colDeviceStatus).Text == deviceStatusRevoked {
+			return
+		}
+		ui.displayRevokeDeviceModal(ctx, table.GetCell(row, colDeviceID).Text, table.GetCell(row, colDeviceName).Text)
+	})
+
+	buttons := tview.NewForm().
+		AddButton("Refresh", func() {
+			ui.pages.RemovePage(pageListDevices)
+			ui.displayDevices(ctx)
+		}).
+		AddButton("Back to list", func() {
+			ui.pages.RemovePage(pageListDevices)
+		})
+	buttons.SetButtonsAlign(tview.AlignLeft).SetBorderPadding(0, 0, 0, 0)
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(buttons, 1, 1, false)
+
+	flex.SetBorder(true).SetTitle(" Devices ").SetTitleAlign(tview.AlignLeft)
+
+	ui.pages.AddPage(pageListDevices, flex, true, true)
+}
+
+func (ui *TUI) displayRevokeDeviceModal(ctx context.Context, deviceID string, name string) {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Do you want to revoke the device %s? Its requests will be rejected.", name)).
+		AddButtons([]string{buttonCancelDesc, buttonRevokeDesc}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			ui.pages.RemovePage(pageRevokeDevice)
+			if buttonLabel != buttonRevokeDesc {
+				return
+			}
+
+			if err := ui.authUser.RevokeDevice(ctx, ui.gkclient, deviceID); err != nil {
+				ui.displayErr(err.Error())
+				return
+			}
+
+			ui.pages.RemovePage(pageListDevices)
+			ui.displayDevices(ctx)
+		})
+
+	ui.pages.AddPage(pageRevokeDevice, modal, true, true)
+}
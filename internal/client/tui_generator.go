@@ -0,0 +1,103 @@
+package client
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/rivo/tview"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+const (
+	pageGenerator = "Generate password"
+
+	buttonGenerateDesc = "Generate"
+
+	generatorPassword   = "Password"
+	generatorPassphrase = "Passphrase"
+)
+
+// displayGenerator - displays the password generator, use is called with the accepted password.
+func (ui *TUI) displayGenerator(use func(password string)) {
+	passphrase := false
+	pwdOpts := models.DefaultPasswordOptions()
+	phraseOpts := models.DefaultPassphraseOptions()
+
+	result := tview.NewTextView()
+	var generated *models.GeneratedSecret
+	generate := func() {
+		var err error
+		if passphrase {
+			generated, err = models.GeneratePassphrase(phraseOpts)
+		} else {
+			generated, err = models.GeneratePassword(pwdOpts)
+		}
+		if err != nil {
+			generated = nil
+			result.SetText(err.Error())
+			return
+		}
+		result.SetText(fmt.Sprintf("%s\nEntropy: %.0f bits", generated.Value, generated.Entropy))
+	}
+
+	number := func(v string, set func(n int)) {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return
+		}
+		set(n)
+	}
+
+	form := tview.NewForm().
+		AddDropDown("Type", []string{generatorPassword, generatorPassphrase}, 0, func(option string, _ int) {
+			passphrase = option == generatorPassphrase
+		}).
+		AddInputField("Length", strconv.Itoa(pwdOpts.Length), defaultFieldWidth, tview.InputFieldInteger,
+			func(v string) { number(v, func(n int) { pwdOpts.Length = n }) }).
+		AddCheckbox("Lowercase", pwdOpts.Lower, func(checked bool) { pwdOpts.Lower = checked }).
+		AddCheckbox("Uppercase", pwdOpts.Upper, func(checked bool) { pwdOpts.Upper = checked }).
+		AddCheckbox("Digits", pwdOpts.Digits, func(checked bool) { pwdOpts.Digits = checked }).
+		AddCheckbox("Symbols", pwdOpts.Symbols, func(checked bool) { pwdOpts.Symbols = checked }).
+		AddCheckbox("Exclude look-alike", pwdOpts.ExcludeSimilar, func(checked bool) { pwdOpts.ExcludeSimilar = checked }).
+		AddInputField("Words", strconv.Itoa(phraseOpts.Words), defaultFieldWidth, tview.InputFieldInteger,
+			func(v string) { number(v, func(n int) { phraseOpts.Words = n }) }).
+		AddInputField("Separator", phraseOpts.Separator, defaultFieldWidth, nil,
+			func(v string) { phraseOpts.Separator = v }).
+		AddCheckbox("Capitalize", phraseOpts.Capitalize, func(checked bool) { phraseOpts.Capitalize = checked }).
+		AddCheckbox("Add digit", phraseOpts.Digit, func(checked bool) { phraseOpts.Digit = checked })
+
+	form.SetTitle(pageGenerator).
+		SetTitleAlign(tview.AlignLeft)
+
+	buttons := tview.NewForm().
+		AddButton(buttonGenerateDesc, generate).
+		AddButton("Use", func() {
+			if generated == nil {
+				return
+			}
+			use(generated.Value)
+			ui.pages.RemovePage(pageGenerator)
+		}).
+		AddButton(buttonCancelDesc, func() { ui.pages.RemovePage(pageGenerator) })
+
+	buttons.SetButtonsAlign(tview.AlignLeft).SetBorderPadding(0, 0, 0, 0)
+
+	const resultHeight = 2
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(form, 0, 1, true).
+		AddItem(result, resultHeight, 1, false).
+		AddItem(buttons, 1, 1, false)
+
+	generate()
+	ui.pages.AddPage(pageGenerator, flex, true, true)
+}
+
+// setPasswordField - sets the generated password into the password field of the form.
+func setPasswordField(form *tview.Form) func(password string) {
+	return func(password string) {
+		if f, ok := form.GetFormItemByLabel(fnPassword).(*tview.InputField); ok {
+			f.SetText(password)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/rivo/tview"
+	"go.uber.org/zap"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/importer"
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+const (
+	pageImport        = "Import records"
+	pageImportPreview = "Import preview"
+	pageExport        = "Export records"
+
+	fnFormat = "Format"
+
+	importStatusNew       = "new"
+	importStatusDuplicate = "duplicate"
+)
+
+const (
+	colImportDesc = iota
+	colImportLogin
+	colImportURL
+	colImportStatus
+)
+
+func (ui *TUI) displayImport(ctx context.Context) {
+	formats := []string{string(importer.FormatAuto)}
+	for _, f := range importer.Formats {
+		formats = append(formats, string(f))
+	}
+
+	var path string
+	format := importer.FormatAuto
+	form := tview.NewForm().
+		AddInputField(fnPath, "", defaultFieldWidth, nil, func(v string) {
+			path = v
+		}).
+		AddDropDown(fnFormat, formats, 0, func(option string, optionIndex int) {
+			format = importer.Format(option)
+		})
+
+	form.SetTitle(pageImport).
+		SetTitleAlign(tview.AlignLeft)
+
+	buttons := tview.NewForm().
+		AddButton("Preview", func() {
+			f, err := os.Open(path)
+			if err != nil {
+				ui.displayErr(err.Error())
+				return
+			}
+			defer f.Close()
+
+			res, err := importer.Parse(f, format)
+			if err != nil {
+				ui.displayErr(err.Error())
+				return
+			}
+
+			plan, err := ui.authUser.PlanImport(ctx, ui.local, res.Records)
+			if err != nil {
+				ui.displayErr(err.Error())
+				return
+			}
+
+			ui.displayImportPreview(ctx, res, plan)
+		}).
+		AddButton(buttonCancelDesc, func() { ui.pages.RemovePage(pageImport) })
+
+	buttons.SetButtonsAlign(tview.AlignLeft).SetBorderPadding(0, 0, 0, 0)
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(form, 0, 1, true).
+		AddItem(buttons, 1, 1, false)
+
+	ui.pages.AddPage(pageImport, flex, true, true)
+}
+
+func (ui *TUI) displayImportPreview(ctx context.Context, res *importer.Result, plan *models.ImportPlan) {
+	table := tview.NewTable()
+
+	table.SetCell(0, colImportDesc, addTableHeaderCell("DESCRIPTION"))
+	table.SetCell(0, colImportLogin, addTableHeaderCell("LOGIN"))
+	table.SetCell(0, colImportURL, addTableHeaderCell("URL"))
+	table.SetCell(0, colImportStatus, addTableHeaderCell("STATUS"))
+
+	rn := 1
+	addRow := func(r *models.RecordDTO, st string) {
+		var login string
+		if d, err := r.DecodeData(); err == nil {
+			if a, ok := d.(*models.Auth); ok {
+				login = a.Login
+			}
+		}
+
+		table.SetCell(rn, colImportDesc, addTableCell(r.Description))
+		table.SetCell(rn, colImportLogin, addTableCell(login))
+		table.SetCell(rn, colImportURL, addTableCell(models.MetadataValue(r.Metadata, models.MetadataKeyURL)))
+		table.SetCell(rn, colImportStatus, addTableCell(st))
+		rn++
+	}
+	for _, r := range plan.Records {
+		addRow(r, importStatusNew)
+	}
+	for _, r := range plan.Duplicates {
+		addRow(r, importStatusDuplicate)
+	}
+
+	summary := tview.NewTextView().SetText(fmt.Sprintf("Format: %s, new: %d, duplicates: %d, skipped: %d",
+		res.Format, len(plan.Records), len(plan.Duplicates), res.Skipped))
+
+	buttons := tview.NewForm().
+		AddButton(fmt.Sprintf("Import %d records", len(plan.Records)), func() {
+			brs, err := ui.authUser.ImportRecords(ctx, ui.local, plan)
+			if err != nil {
+				ui.displayErr(err.Error())
+				return
+			}
+
+			ui.pages.RemovePage(pageImportPreview)
+			ui.pages.RemovePage(pageImport)
+			ui.refreshRecords(ctx)
+			ui.syncer.SyncNow()
+
+			if err := models.BatchErr(brs); err != nil {
+				ui.displayErr(err.Error())
+			}
+		}).
+		AddButton("Back", func() { ui.pages.RemovePage(pageImportPreview) })
+
+	buttons.SetButtonsAlign(tview.AlignLeft).SetBorderPadding(0, 0, 0, 0)
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(summary, 1, 1, false).
+		AddItem(table, 0, 1, false).
+		AddItem(buttons, 1, 1, true)
+
+	flex.SetBorder(true).SetTitle(" " + pageImportPreview + " ").SetTitleAlign(tview.AlignLeft)
+
+	ui.pages.AddPage(pageImportPreview, flex, true, true)
+}
+
+func (ui *TUI) displayExport(ctx context.Context) {
+	var path string
+	form := tview.NewForm().
+		AddInputField(fnPath, "", defaultFieldWidth, nil, func(v string) {
+			path = v
+		})
+
+	form.SetTitle(pageExport + " to KeePass XML").
+		SetTitleAlign(tview.AlignLeft)
+
+	buttons := tview.NewForm().
+		AddButton(buttonOkDesc, func() {
+			rs, err := ui.authUser.GetAllRecords(ctx, ui.local)
+			if err != nil {
+				ui.displayErr(err.Error())
+				return
+			}
+
+			var b bytes.Buffer
+			if err := importer.WriteKeePassXML(&b, rs); err != nil {
+				ui.displayErr(err.Error())
+				return
+			}
+
+			if err := os.WriteFile(path, b.Bytes(), defFileMode); err != nil {
+				ui.displayErr(err.Error())
+				return
+			}
+
+			ui.pages.RemovePage(pageExport)
+			ui.statusSetup(fmt.Sprintf("%d records exported to %s", len(rs), path), defaultStatusTime)
+			go ui.reportExport(ctx, string(importer.FormatKeePass), len(rs))
+		}).
+		AddButton(buttonCancelDesc, func() { ui.pages.RemovePage(pageExport) })
+
+	buttons.SetButtonsAlign(tview.AlignLeft).SetBorderPadding(0, 0, 0, 0)
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(form, 0, 1, true).
+		AddItem(buttons, 1, 1, false)
+
+	ui.pages.AddPage(pageExport, flex, true, true)
+}
+
+// reportExport - writes the export to the audit log of the user on the server.
+// The records have already been exported, so the error is only logged.
+func (ui *TUI) reportExport(ctx context.Context, format string, records int) {
+	if err := ui.gkclient.ReportExport(ctx, ui.authUser.ID, format, records); err != nil {
+		zap.L().Warn("an error occured while report export", zap.Error(err))
+	}
+}
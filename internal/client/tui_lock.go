@@ -0,0 +1,149 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	pageUnlock = "Unlock"
+
+	buttonLockDesc   = "Lock"
+	buttonUnlockDesc = "Unlock"
+	buttonLogoutDesc = "Logout"
+
+	// idleCheckInterval - how often the idle time is checked.
+	idleCheckInterval = time.Second
+)
+
+// touch - notes the activity of the user, it postpones the idle lock.
+func (ui *TUI) touch() {
+	ui.lastInput.Store(time.Now().UnixNano())
+}
+
+// captureKey - the input capture of the application. Every key postpones the idle lock,
+// Ctrl+Z locks the session and suspends the client like the shell does.
+func (ui *TUI) captureKey(ctx context.Context) func(event *tcell.EventKey) *tcell.EventKey {
+	return func(event *tcell.EventKey) *tcell.EventKey {
+		ui.touch()
+
+		if event.Key() != tcell.KeyCtrlZ {
+			return event
+		}
+		ui.lockSession(ctx)
+		ui.app.Suspend(suspendProcess)
+
+		return nil
+	}
+}
+
+func (ui *TUI) captureMouse(event *tcell.EventMouse, action tview.MouseAction) (*tcell.EventMouse, tview.MouseAction) {
+	ui.touch()
+	return event, action
+}
+
+// watchIdle - locks the session when there has been no input for the lock timeout, until the context is done.
+func (ui *TUI) watchIdle(ctx context.Context) {
+	timeout := ui.cfg.LockTimeout
+	if timeout <= 0 {
+		return
+	}
+	vault := ui.vault
+	ui.touch()
+
+	go func() {
+		ticker := time.NewTicker(idleCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				idle := time.Since(time.Unix(0, ui.lastInput.Load()))
+				if idle < timeout || vault.Locked() {
+					continue
+				}
+				ui.app.QueueUpdateDraw(func() {
+					// The user may have logged out while the update was queued.
+					if ctx.Err() == nil {
+						ui.lockSession(ctx)
+					}
+				})
+			}
+		}
+	}()
+}
+
+// locked - reports whether the session of the logged in user is locked.
+func (ui *TUI) locked() bool {
+	return ui.vault != nil && ui.vault.Locked()
+}
+
+// lockSession - seals the records, wipes the pending changes from memory,
+// closes the pages that show them and displays the unlock page.
+// The synchronization is stopped before the lock, so a push in progress does not read the wiped records,
+// then it is started again and keeps pulling the records, they are sealed until the session is unlocked,
+// local changes are kept only in the sealed queue file and are pushed after the unlock.
+func (ui *TUI) lockSession(ctx context.Context) {
+	if ui.vault == nil || ui.vault.Locked() {
+		return
+	}
+
+	ui.stopSync()
+	ui.syncer.SetPullOnly(true)
+	lockErr := ui.vault.Lock(ui.authUser.ID)
+	ui.queue.Lock()
+	ui.runSync(ctx)
+
+	ui.clearClipboard()
+	ui.list = nil
+	ui.closePages()
+	ui.displayUnlock(ctx)
+
+	if lockErr != nil {
+		ui.displayErr(lockErr.Error())
+	}
+}
+
+func (ui *TUI) unlockSession(ctx context.Context, password string) {
+	if err := ui.vault.Unlock(ui.authUser.ID, password); err != nil {
+		ui.displayErr(err.Error())
+		return
+	}
+	// The records are opened, so the failed queue only delays the push of the local changes.
+	queueErr := ui.queue.Unlock()
+
+	ui.syncer.SetPullOnly(false)
+	ui.syncer.SyncNow()
+	ui.touch()
+
+	ui.pages.RemovePage(pageUnlock)
+	ui.displayRecords(ctx)
+
+	if queueErr != nil {
+		ui.displayErr(queueErr.Error())
+	}
+}
+
+// displayUnlock - displays the page that unlocks the session by the master password without a new login.
+func (ui *TUI) displayUnlock(ctx context.Context) {
+	var password string
+	form := tview.NewForm().
+		AddTextView(fnUsername, ui.authUser.Login, defaultFieldWidth, 1, true, false).
+		AddPasswordField(fnPassword, "", defaultFieldWidth, '*', func(v string) {
+			password = v
+		})
+	form.AddButton(buttonUnlockDesc, func() { ui.unlockSession(ctx, password) }).
+		AddButton(buttonLogoutDesc, ui.logout).
+		AddButton(buttonQuinDesc, ui.displayQuitModal)
+
+	form.SetBorder(true).
+		SetTitle(" Session locked ").
+		SetTitleAlign(tview.AlignLeft)
+
+	ui.pages.AddPage(pageUnlock, form, true, true)
+}
@@ -0,0 +1,213 @@
+package client
+
+import (
+	"strings"
+
+	"github.com/rivo/tview"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+const (
+	pageMetadataItem = "Metadata field"
+
+	fnKey   = "Key"
+	fnType  = "Type"
+	fnValue = "Value"
+
+	hiddenValue = "********"
+	// metadataHeight - the height of the metadata editor in the record forms.
+	metadataHeight = 8
+)
+
+const (
+	colMetadataKey = iota
+	colMetadataType
+	colMetadataValue
+)
+
+// metadataEditor - The table of the metadata fields of the record with the buttons to add, edit and remove them.
+// The order of the fields is kept.
+type metadataEditor struct {
+	ui    *TUI
+	items []*models.Metadata
+	table *tview.Table
+	flex  *tview.Flex
+}
+
+func (ui *TUI) newMetadataEditor(mi []*models.Metadata) *metadataEditor {
+	me := &metadataEditor{ui: ui, table: tview.NewTable()}
+	for _, m := range mi {
+		me.items = append(me.items, &models.Metadata{Key: m.Key, Value: m.Value, Type: m.Type})
+	}
+
+	me.table.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetSelectedFunc(func(row int, column int) { me.displayItem(row - 1) })
+
+	buttons := tview.NewForm().
+		AddButton("Add field", func() { me.displayItem(len(me.items)) }).
+		AddButton("Edit field", func() { me.displayItem(me.selected()) }).
+		AddButton("Remove field", func() {
+			if i := me.selected(); i >= 0 {
+				me.items = append(me.items[:i], me.items[i+1:]...)
+				me.draw()
+			}
+		}).
+		AddButton("Up", func() { me.move(-1) }).
+		AddButton("Down", func() { me.move(1) })
+	buttons.SetButtonsAlign(tview.AlignLeft).SetBorderPadding(0, 0, 0, 0)
+
+	me.flex = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(me.table, 0, 1, false).
+		AddItem(buttons, 1, 1, false)
+	me.flex.SetBorder(true).SetTitle(" " + fnMetadata + " ").SetTitleAlign(tview.AlignLeft)
+
+	me.draw()
+
+	return me
+}
+
+// Metadata - Returns the edited metadata fields.
+func (me *metadataEditor) Metadata() []*models.Metadata {
+	return me.items
+}
+
+// selected - returns the index of the selected field, -1 if there is none.
+func (me *metadataEditor) selected() int {
+	row, _ := me.table.GetSelection()
+	if row < 1 || row > len(me.items) {
+		return -1
+	}
+	return row - 1
+}
+
+func (me *metadataEditor) move(delta int) {
+	i := me.selected()
+	j := i + delta
+	if i < 0 || j < 0 || j >= len(me.items) {
+		return
+	}
+	me.items[i], me.items[j] = me.items[j], me.items[i]
+	me.draw()
+	me.table.Select(j+1, 0)
+}
+
+func (me *metadataEditor) draw() {
+	row, _ := me.table.GetSelection()
+
+	me.table.Clear()
+	me.table.SetCell(0, colMetadataKey, addTableHeaderCell(strings.ToUpper(fnKey)))
+	me.table.SetCell(0, colMetadataType, addTableHeaderCell(strings.ToUpper(fnType)))
+	me.table.SetCell(0, colMetadataValue, addTableHeaderCell(strings.ToUpper(fnValue)))
+
+	for i, m := range me.items {
+		me.table.SetCell(i+1, colMetadataKey, addTableCell(m.Key))
+		me.table.SetCell(i+1, colMetadataType, addTableCell(string(m.GetType())))
+		me.table.SetCell(i+1, colMetadataValue, addTableCell(metadataPreview(m)))
+	}
+
+	me.table.Select(min(max(row, 1), len(me.items)), 0)
+}
+
+// metadataPreview - secrets are hidden, only the first line of the multi-line text is shown.
+func metadataPreview(m *models.Metadata) string {
+	switch m.GetType() {
+	case models.MetadataSecret:
+		return hiddenValue
+	case models.MetadataMultiline:
+		if first, _, ok := strings.Cut(m.Value, "\n"); ok {
+			return first + " ..."
+		}
+	}
+	return m.Value
+}
+
+// displayItem - displays the form of the field with the index, the index equal to the number of fields adds a new one.
+func (me *metadataEditor) displayItem(index int) {
+	if index < 0 || index > len(me.items) {
+		return
+	}
+
+	draft := &models.Metadata{Type: models.MetadataText}
+	if index < len(me.items) {
+		m := me.items[index]
+		draft = &models.Metadata{Key: m.Key, Value: m.Value, Type: m.GetType()}
+	}
+
+	me.displayDraft(index, draft)
+}
+
+// displayDraft - the value field depends on the type, so the form is rebuilt when the type is changed.
+func (me *metadataEditor) displayDraft(index int, draft *models.Metadata) {
+	ui := me.ui
+
+	types := make([]string, len(models.MetadataTypes))
+	current := 0
+	for i, t := range models.MetadataTypes {
+		types[i] = string(t)
+		if t == draft.Type {
+			current = i
+		}
+	}
+
+	form := tview.NewForm().
+		AddInputField(fnKey, draft.Key, defaultFieldWidth, nil, func(v string) {
+			draft.Key = v
+		}).
+		AddDropDown(fnType, types, current, func(option string, _ int) {
+			t := models.MetadataType(option)
+			if t == draft.Type {
+				return
+			}
+			draft.Type = t
+			ui.pages.RemovePage(pageMetadataItem)
+			me.displayDraft(index, draft)
+		})
+
+	setValue := func(v string) { draft.Value = v }
+	switch draft.Type {
+	case models.MetadataMultiline:
+		form.AddTextArea(fnValue, draft.Value, defaultFieldWidth, 0, 0, setValue)
+	case models.MetadataSecret:
+		form.AddPasswordField(fnValue, draft.Value, defaultFieldWidth, '*', setValue)
+	case models.MetadataDate:
+		form.AddInputField(fnValue, draft.Value, defaultFieldWidth, nil, setValue).
+			AddTextView("Format", "YYYY-MM-DD", defaultFieldWidth, 1, true, false)
+	default:
+		form.AddInputField(fnValue, draft.Value, defaultFieldWidth, nil, setValue)
+	}
+
+	form.SetTitle(pageMetadataItem).
+		SetTitleAlign(tview.AlignLeft)
+
+	buttons := tview.NewForm().
+		AddButton(buttonOkDesc, func() {
+			m := &models.Metadata{Key: draft.Key, Value: draft.Value}
+			if draft.Type != models.MetadataText {
+				m.Type = draft.Type
+			}
+			if err := m.Validate(); err != nil {
+				ui.displayErr(err.Error())
+				return
+			}
+
+			if index < len(me.items) {
+				me.items[index] = m
+			} else {
+				me.items = append(me.items, m)
+			}
+			me.draw()
+			me.table.Select(index+1, 0)
+			ui.pages.RemovePage(pageMetadataItem)
+		}).
+		AddButton(buttonCancelDesc, func() { ui.pages.RemovePage(pageMetadataItem) })
+
+	buttons.SetButtonsAlign(tview.AlignLeft).SetBorderPadding(0, 0, 0, 0)
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(form, 0, 1, true).
+		AddItem(buttons, 1, 1, false)
+
+	ui.pages.AddPage(pageMetadataItem, flex, true, true)
+}
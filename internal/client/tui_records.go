@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -38,6 +39,7 @@ const (
 	pageUpdateBinaryRecord = "Update binary record"
 	pageAddCardRecord      = "New card record"
 	pageUpdateCardRecord   = "Update card record"
+	pageDeleteRecord       = "Delete record"
 	loginPage              = "Login page"
 )
 
@@ -68,48 +70,91 @@ const (
 
 const defFileMode = 0600
 
-func (ui *TUI) displayRecords(ctx context.Context, offset int, limit int) {
-	curOst := offset
-	curLt := limit
-
-	rs, err := ui.authUser.GetRecords(ctx, ui.cache, curOst, curLt)
-	if err != nil {
-		ui.displayErr(fmt.Sprintf("an error occured while retrieving record list, err: %v", err))
-		return
-	}
+// recordList - The state of the displayed record list. It is kept across refreshes and sync updates.
+type recordList struct {
+	table *tview.Table
+	info  *tview.TextView
+	index *models.RecordIndex
+	// shown - the filtered and sorted records.
+	shown  []*models.Record
+	filter models.RecordFilter
+	offset int
+	// selected - the ID of the selected record, the selection follows the record when the list changes.
+	selected string
+}
 
-	table := tview.NewTable()
+const recordListHint = "/: filter, f: types and sorting, " + copyKeysHint
 
-	table.SetCell(0, colID, addTableHeaderCell("ID"))
-	table.SetCell(0, colDesc, addTableHeaderCell(strings.ToUpper(fnDescription)))
-	table.SetCell(0, colCreated, addTableHeaderCell("CREATED"))
-	table.SetCell(0, colModified, addTableHeaderCell("MODIFIED"))
-	table.SetCell(0, colType, addTableHeaderCell("TYPE"))
-	table.SetCell(0, colHash, addTableHeaderCell("HASHSUM"))
-	table.SetCell(0, colVersion, addTableHeaderCell("VERSION"))
+func (ui *TUI) displayRecords(ctx context.Context) {
+	list := &recordList{
+		table:  tview.NewTable(),
+		info:   tview.NewTextView(),
+		filter: models.RecordFilter{SortBy: models.SortRelevance},
+	}
+	ui.list = list
+	table := list.table
+
+	query := tview.NewInputField().
+		SetLabel("Filter: ").
+		SetChangedFunc(func(v string) {
+			list.filter.Query = v
+			list.offset = 0
+			ui.drawRecords()
+		})
+	query.SetDoneFunc(func(tcell.Key) { ui.app.SetFocus(table) })
 
-	for r := curOst; r < len(rs); r++ {
-		record := rs[r]
-		rn := r + 1
+	sortFields := make([]string, len(models.SortFields))
+	for i, f := range models.SortFields {
+		sortFields[i] = string(f)
+	}
+	options := tview.NewForm().SetHorizontal(true)
+	for _, dt := range models.DataTypes {
+		dt := dt
+		options.AddCheckbox(string(dt), false, func(checked bool) {
+			list.filter.Types = toggleType(list.filter.Types, dt, checked)
+			list.offset = 0
+			ui.drawRecords()
+		})
+	}
+	options.AddDropDown("Sort", sortFields, 0, func(option string, _ int) {
+		list.filter.SortBy = models.SortField(option)
+		ui.drawRecords()
+	}).
+		AddCheckbox("Desc", false, func(checked bool) {
+			list.filter.Desc = checked
+			ui.drawRecords()
+		}).
+		SetCancelFunc(func() { ui.app.SetFocus(table) }).
+		SetBorderPadding(0, 0, 0, 0)
 
-		table.SetCell(rn, colID, addTableCell(record.ID))
-		table.SetCell(rn, colDesc, addTableHeaderCell(record.Description))
-		table.SetCell(rn, colCreated, addTableHeaderCell(record.Created.Format(fnDateFormat)))
-		table.SetCell(rn, colModified, addTableHeaderCell(record.Modified.Format(fnDateFormat)))
-		table.SetCell(rn, colType, addTableHeaderCell(record.Type))
-		table.SetCell(rn, colHash, addTableHeaderCell(record.Hashsum))
-		table.SetCell(rn, colVersion, addTableHeaderCell(strconv.FormatInt(record.GetVersion(), 10)))
+	table.SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetSelectionChangedFunc(func(row int, column int) {
+		if row > 0 {
+			list.selected = table.GetCell(row, colID).Text
+		}
+	})
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case '/':
+			ui.app.SetFocus(query)
+			return nil
+		case 'f':
+			ui.app.SetFocus(options)
+			return nil
+		}
 
-		if rn >= curOst+ui.recLimit {
-			break
+		row, _ := table.GetSelection()
+		recordID := table.GetCell(row, colID).Text
+		if row == 0 || strings.TrimSpace(recordID) == "" {
+			return event
 		}
-	}
-	table.SetSelectable(true, false)
+		return ui.copyRecordKey(ctx, recordID, event)
+	})
 
 	table.SetSelectedFunc(func(row int, column int) {
 		recordID := table.GetCell(row, colID).Text
-		if strings.TrimSpace(recordID) == "" {
-			ui.displayErr("record id is empty")
+		if row == 0 || strings.TrimSpace(recordID) == "" {
 			return
 		}
 
@@ -130,22 +175,20 @@ func (ui *TUI) displayRecords(ctx context.Context, offset int, limit int) {
 
 	buttonsManageList := tview.NewForm().
 		AddButton("<", func() {
-			curOst := max(0, curOst-curLt)
-
-			ui.pages.RemovePage(pageListRecords)
-			ui.displayRecords(ctx, curOst, curLt)
+			list.offset = max(0, list.offset-ui.recLimit)
+			ui.drawRecords()
 		}).
 		AddButton("Refresh", func() {
-			ui.pages.RemovePage(pageListRecords)
-			ui.displayRecords(ctx, curOst, curLt)
+			ui.loadRecords(ctx)
 		}).
 		AddButton(">", func() {
-			curOst := curOst + curLt
-
-			ui.pages.RemovePage(pageListRecords)
-			ui.displayRecords(ctx, curOst, curLt)
+			if list.offset+ui.recLimit < len(list.shown) {
+				list.offset += ui.recLimit
+			}
+			ui.drawRecords()
 		}).
 		AddButton("Back to menu", func() {
+			ui.list = nil
 			ui.pages.RemovePage(pageListRecords)
 		})
 	buttonsManageList.SetButtonsAlign(tview.AlignLeft).
@@ -155,26 +198,136 @@ func (ui *TUI) displayRecords(ctx context.Context, offset int, limit int) {
 		AddButton("Add auth", func() { ui.displayCreateAuth(ctx) }).
 		AddButton("Add text", func() { ui.displayCreateText(ctx) }).
 		AddButton("Add file", func() { ui.displayCreateBinary(ctx) }).
-		AddButton("Add card", func() { ui.displayCreateCard(ctx) })
+		AddButton("Add card", func() { ui.displayCreateCard(ctx) }).
+		AddButton("Import", func() { ui.displayImport(ctx) }).
+		AddButton("Export", func() { ui.displayExport(ctx) }).
+		AddButton("Devices", func() { ui.displayDevices(ctx) }).
+		AddButton(pageActivity, func() { ui.displayActivity(ctx, 0) }).
+		AddButton("Sync status", ui.displaySyncStatus).
+		AddButton(buttonLockDesc, func() { ui.lockSession(ctx) }).
+		AddButton(buttonAboutDesc, func() { ui.displayAbout(ctx) })
 
 	buttons.SetButtonsAlign(tview.AlignLeft).SetBorderPadding(0, 0, 0, 0)
 
 	flex := tview.NewFlex().SetDirection(tview.FlexRow).
 		AddItem(buttons, 1, 1, false).
+		AddItem(query, 1, 1, false).
+		AddItem(options, 1, 1, false).
 		AddItem(table, 0, 1, true).
+		AddItem(list.info, 1, 1, false).
+		AddItem(tview.NewTextView().SetText(recordListHint), 1, 1, false).
 		AddItem(buttonsManageList, 1, 1, false)
 
 	flex.SetBorder(true)
 
 	ui.pages.AddPage(pageListRecords, flex, true, true)
+	ui.loadRecords(ctx)
+}
+
+// loadRecords - reads the records from the cache and redraws the list keeping the filter and the selection.
+func (ui *TUI) loadRecords(ctx context.Context) {
+	if ui.list == nil {
+		return
+	}
+
+	// The cache is read at once, the synchronization may change it between the pages.
+	rs, err := ui.cache.AllRecords(ui.authUser.ID)
+	if err != nil {
+		ui.displayErr(fmt.Sprintf("an error occured while retrieving record list, err: %v", err))
+		return
+	}
+	// Deleted records are kept in the storage only to propagate the deletion during synchronization.
+	rs = slices.DeleteFunc(rs, func(r *models.Record) bool { return r.Deleted })
+
+	ui.list.index = models.NewRecordIndex(rs)
+	ui.drawRecords()
+}
+
+// drawRecords - filters the loaded records and redraws the current page of the table.
+func (ui *TUI) drawRecords() {
+	list := ui.list
+	if list == nil || list.index == nil {
+		return
+	}
+
+	list.shown = list.index.Filter(list.filter)
+	if list.offset >= len(list.shown) {
+		list.offset = max(0, len(list.shown)-1) / ui.recLimit * ui.recLimit
+	}
+	selected := list.selected
+
+	table := list.table
+	table.Clear()
+
+	headers := []struct {
+		col   int
+		name  string
+		field models.SortField
+	}{
+		{col: colID, name: "ID"},
+		{col: colDesc, name: strings.ToUpper(fnDescription), field: models.SortDescription},
+		{col: colCreated, name: "CREATED", field: models.SortCreated},
+		{col: colModified, name: "MODIFIED", field: models.SortModified},
+		{col: colType, name: "TYPE", field: models.SortType},
+		{col: colHash, name: "HASHSUM"},
+		{col: colVersion, name: "VERSION", field: models.SortVersion},
+	}
+	for _, h := range headers {
+		name := h.name
+		if h.field != "" && h.field == list.filter.SortBy {
+			name += sortMark(list.filter.Desc)
+		}
+		table.SetCell(0, h.col, addTableHeaderCell(name))
+	}
+
+	selectedRow := 1
+	end := min(len(list.shown), list.offset+ui.recLimit)
+	for i, record := range list.shown[list.offset:end] {
+		rn := i + 1
+
+		table.SetCell(rn, colID, addTableCell(record.ID))
+		table.SetCell(rn, colDesc, addTableHeaderCell(record.Description))
+		table.SetCell(rn, colCreated, addTableHeaderCell(record.Created.Format(fnDateFormat)))
+		table.SetCell(rn, colModified, addTableHeaderCell(record.Modified.Format(fnDateFormat)))
+		table.SetCell(rn, colType, addTableHeaderCell(record.Type))
+		table.SetCell(rn, colHash, addTableHeaderCell(record.Hashsum))
+		table.SetCell(rn, colVersion, addTableHeaderCell(strconv.FormatInt(record.GetVersion(), 10)))
+
+		if record.ID == selected {
+			selectedRow = rn
+		}
+	}
+	table.Select(selectedRow, 0)
+
+	list.info.SetText(fmt.Sprintf("%d-%d of %d records, %d total",
+		min(list.offset+1, end), end, len(list.shown), list.index.Len()))
+}
+
+func sortMark(desc bool) string {
+	if desc {
+		return " ▼"
+	}
+	return " ▲"
+}
+
+func toggleType(types []models.DataType, dt models.DataType, checked bool) []models.DataType {
+	var res []models.DataType
+	for _, t := range types {
+		if t != dt {
+			res = append(res, t)
+		}
+	}
+	if checked {
+		res = append(res, dt)
+	}
+	return res
 }
 
 func (ui *TUI) displayCreateAuth(ctx context.Context) {
 	var desc string
 	var login string
 	var pass string
-	var metadata []*models.Metadata
-	mit := ""
+	md := ui.newMetadataEditor(nil)
 	form := tview.NewForm().
 		AddInputField(fnDescription, "", defaultFieldWidth, nil, func(v string) {
 			desc = v
@@ -184,9 +337,6 @@ func (ui *TUI) displayCreateAuth(ctx context.Context) {
 		}).
 		AddInputField(fnPassword, "", defaultFieldWidth, nil, func(v string) {
 			pass = v
-		}).
-		AddTextArea(fnMetadata, mit, defaultFieldWidth, 0, 0, func(text string) {
-			mit = text
 		})
 
 	form.SetTitle(pageAddAuthRecord).
@@ -202,7 +352,7 @@ func (ui *TUI) displayCreateAuth(ctx context.Context) {
 				desc,
 				models.AuthType,
 				auth,
-				metadata,
+				md.Metadata(),
 			)
 
 			if err != nil {
@@ -210,15 +360,7 @@ func (ui *TUI) displayCreateAuth(ctx context.Context) {
 				return
 			}
 
-			rows := splitMetadata(mit)
-			m, err := models.NewMetadataFromStringArray(rows)
-			if err != nil {
-				ui.displayErr(err.Error())
-				return
-			}
-			rdto.Metadata = m
-
-			_, err = ui.authUser.AddRecord(ctx, ui.cache, rdto)
+			_, err = ui.authUser.AddRecord(ctx, ui.local, rdto)
 			if err != nil {
 				ui.displayErr(err.Error())
 				return
@@ -226,12 +368,14 @@ func (ui *TUI) displayCreateAuth(ctx context.Context) {
 
 			ui.pages.RemovePage(pageAddAuthRecord)
 		}).
+		AddButton(buttonGenerateDesc, func() { ui.displayGenerator(setPasswordField(form)) }).
 		AddButton(buttonCancelDesc, func() { ui.pages.RemovePage(pageAddAuthRecord) })
 
 	buttons.SetButtonsAlign(tview.AlignLeft).SetBorderPadding(0, 0, 0, 0)
 
 	flex := tview.NewFlex().SetDirection(tview.FlexRow).
 		AddItem(form, 0, 1, true).
+		AddItem(md.flex, metadataHeight, 1, false).
 		AddItem(buttons, 1, 1, false)
 
 	ui.pages.AddPage(pageAddAuthRecord, flex, true, true)
@@ -252,8 +396,7 @@ func (ui *TUI) displayUpdateAuth(ctx context.Context, recordID string) {
 	desc := r.Description
 	login := a.Login
 	pass := a.Password
-	metadata := r.Metadata
-	mit := convertMetadataToString(r.Metadata)
+	md := ui.newMetadataEditor(r.Metadata)
 	form := tview.NewForm().
 		AddInputField(fnDescription, r.Description, defaultFieldWidth, nil, func(v string) {
 			desc = v
@@ -263,9 +406,6 @@ func (ui *TUI) displayUpdateAuth(ctx context.Context, recordID string) {
 		}).
 		AddInputField(fnPassword, a.Password, defaultFieldWidth, nil, func(v string) {
 			pass = v
-		}).
-		AddTextArea(fnMetadata, mit, defaultFieldWidth, 0, 0, func(text string) {
-			mit = text
 		})
 
 	form.SetTitle(pageUpdateAuthRecord).
@@ -285,7 +425,7 @@ func (ui *TUI) displayUpdateAuth(ctx context.Context, recordID string) {
 				r.Created,
 				time.Now(),
 				auth,
-				metadata,
+				md.Metadata(),
 				false,
 				r.Version,
 			)
@@ -294,16 +434,8 @@ func (ui *TUI) displayUpdateAuth(ctx context.Context, recordID string) {
 				return
 			}
 
-			rows := splitMetadata(mit)
-			m, err := models.NewMetadataFromStringArray(rows)
-			if err != nil {
-				ui.displayErr(err.Error())
-				return
-			}
-			r.Metadata = m
-
 			r.Version++
-			_, err = ui.authUser.UpdateRecord(ctx, ui.cache, r)
+			_, err = ui.authUser.UpdateRecord(ctx, ui.local, r)
 			if err != nil {
 				ui.displayErr(err.Error())
 				return
@@ -311,12 +443,19 @@ func (ui *TUI) displayUpdateAuth(ctx context.Context, recordID string) {
 
 			ui.pages.RemovePage(pageUpdateAuthRecord)
 		}).
+		AddButton(buttonGenerateDesc, func() { ui.displayGenerator(setPasswordField(form)) }).
+		AddButton("Copy login", func() { ui.copyToClipboard(string(copyLogin), login) }).
+		AddButton("Copy password", func() { ui.copyToClipboard(string(copyPassword), pass) }).
+		AddButton("Copy OTP", func() { ui.copyRecordField(r, copyOTP) }).
+		AddButton(buttonDeleteDesc, func() { ui.displayDeleteRecordModal(ctx, r.ID, pageUpdateAuthRecord) }).
 		AddButton(buttonCancelDesc, func() { ui.pages.RemovePage(pageUpdateAuthRecord) })
 
 	buttons.SetButtonsAlign(tview.AlignLeft).SetBorderPadding(0, 0, 0, 0)
 
 	flex := tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(form, 0, 1, true).AddItem(buttons, 1, 1, false)
+		AddItem(form, 0, 1, true).
+		AddItem(md.flex, metadataHeight, 1, false).
+		AddItem(buttons, 1, 1, false)
 
 	ui.pages.AddPage(pageUpdateAuthRecord, flex, true, true)
 }
@@ -324,17 +463,13 @@ func (ui *TUI) displayUpdateAuth(ctx context.Context, recordID string) {
 func (ui *TUI) displayCreateText(ctx context.Context) {
 	var text string
 	var desc string
-	var metadata []*models.Metadata
-	mit := ""
+	md := ui.newMetadataEditor(nil)
 	form := tview.NewForm().
 		AddInputField(fnDescription, "", defaultFieldWidth, nil, func(v string) {
 			desc = v
 		}).
 		AddInputField(fnText, "", defaultFieldWidth, nil, func(v string) {
 			text = v
-		}).
-		AddTextArea(fnMetadata, mit, defaultFieldWidth, 0, 0, func(text string) {
-			mit = text
 		})
 	form.SetTitle(pageAddTextRecord).
 		SetTitleAlign(tview.AlignLeft)
@@ -348,7 +483,7 @@ func (ui *TUI) displayCreateText(ctx context.Context) {
 				desc,
 				models.TextType,
 				textType,
-				metadata,
+				md.Metadata(),
 			)
 
 			if err != nil {
@@ -356,15 +491,7 @@ func (ui *TUI) displayCreateText(ctx context.Context) {
 				return
 			}
 
-			rows := splitMetadata(mit)
-			m, err := models.NewMetadataFromStringArray(rows)
-			if err != nil {
-				ui.displayErr(err.Error())
-				return
-			}
-			rdto.Metadata = m
-
-			_, err = ui.authUser.AddRecord(ctx, ui.cache, rdto)
+			_, err = ui.authUser.AddRecord(ctx, ui.local, rdto)
 			if err != nil {
 				ui.displayErr(err.Error())
 				return
@@ -377,7 +504,9 @@ func (ui *TUI) displayCreateText(ctx context.Context) {
 	buttons.SetButtonsAlign(tview.AlignLeft).SetBorderPadding(0, 0, 0, 0)
 
 	flex := tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(form, 0, 1, true).AddItem(buttons, 1, 1, false)
+		AddItem(form, 0, 1, true).
+		AddItem(md.flex, metadataHeight, 1, false).
+		AddItem(buttons, 1, 1, false)
 
 	ui.pages.AddPage(pageAddTextRecord, flex, true, true)
 }
@@ -396,8 +525,7 @@ func (ui *TUI) displayUpdateText(ctx context.Context, recordID string) {
 
 	desc := r.Description
 	text := t.Data
-	metadata := r.Metadata
-	mit := convertMetadataToString(r.Metadata)
+	md := ui.newMetadataEditor(r.Metadata)
 
 	form := tview.NewForm().
 		AddInputField(fnDescription, r.Description, defaultFieldWidth, nil, func(v string) {
@@ -405,9 +533,6 @@ func (ui *TUI) displayUpdateText(ctx context.Context, recordID string) {
 		}).
 		AddInputField(fnText, t.Data, defaultFieldWidth, nil, func(v string) {
 			text = v
-		}).
-		AddTextArea(fnMetadata, mit, defaultFieldWidth, 0, 0, func(text string) {
-			mit = text
 		})
 	form.SetTitle(pageUpdateTextRecord).
 		SetTitleAlign(tview.AlignLeft)
@@ -425,7 +550,7 @@ func (ui *TUI) displayUpdateText(ctx context.Context, recordID string) {
 				r.Created,
 				time.Now(),
 				textType,
-				metadata,
+				md.Metadata(),
 				false,
 				r.Version,
 			)
@@ -433,17 +558,9 @@ func (ui *TUI) displayUpdateText(ctx context.Context, recordID string) {
 				ui.displayErr(err.Error())
 			}
 
-			rows := splitMetadata(mit)
-			m, err := models.NewMetadataFromStringArray(rows)
-			if err != nil {
-				ui.displayErr(err.Error())
-				return
-			}
-			r.Metadata = m
-
 			r.Version++
 
-			_, err = ui.authUser.UpdateRecord(ctx, ui.cache, r)
+			_, err = ui.authUser.UpdateRecord(ctx, ui.local, r)
 			if err != nil {
 				ui.displayErr(err.Error())
 				return
@@ -451,12 +568,15 @@ func (ui *TUI) displayUpdateText(ctx context.Context, recordID string) {
 
 			ui.pages.RemovePage(pageUpdateTextRecord)
 		}).
+		AddButton(buttonDeleteDesc, func() { ui.displayDeleteRecordModal(ctx, r.ID, pageUpdateTextRecord) }).
 		AddButton(buttonCancelDesc, func() { ui.pages.RemovePage(pageUpdateTextRecord) })
 
 	buttons.SetButtonsAlign(tview.AlignLeft).SetBorderPadding(0, 0, 0, 0)
 
 	flex := tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(form, 0, 1, true).AddItem(buttons, 1, 1, false)
+		AddItem(form, 0, 1, true).
+		AddItem(md.flex, metadataHeight, 1, false).
+		AddItem(buttons, 1, 1, false)
 
 	ui.pages.AddPage(pageUpdateTextRecord, flex, true, true)
 }
@@ -464,17 +584,13 @@ func (ui *TUI) displayUpdateText(ctx context.Context, recordID string) {
 func (ui *TUI) displayCreateBinary(ctx context.Context) {
 	var path string
 	var desc string
-	var metadata []*models.Metadata
-	mit := ""
+	md := ui.newMetadataEditor(nil)
 	form := tview.NewForm().
 		AddInputField(fnDescription, "", defaultFieldWidth, nil, func(v string) {
 			desc = v
 		}).
 		AddInputField(fnPath, "", defaultFieldWidth, nil, func(v string) {
 			path = v
-		}).
-		AddTextArea(fnMetadata, mit, defaultFieldWidth, 0, 0, func(text string) {
-			mit = text
 		})
 	form.SetTitle(pageAddBinaryRecord).
 		SetTitleAlign(tview.AlignLeft)
@@ -508,7 +624,7 @@ func (ui *TUI) displayCreateBinary(ctx context.Context) {
 				desc,
 				models.BinaryType,
 				binaryType,
-				metadata,
+				md.Metadata(),
 			)
 
 			if err != nil {
@@ -516,15 +632,7 @@ func (ui *TUI) displayCreateBinary(ctx context.Context) {
 				return
 			}
 
-			rows := splitMetadata(mit)
-			m, err := models.NewMetadataFromStringArray(rows)
-			if err != nil {
-				ui.displayErr(err.Error())
-				return
-			}
-			rdto.Metadata = m
-
-			_, err = ui.authUser.AddRecord(ctx, ui.cache, rdto)
+			_, err = ui.authUser.AddRecord(ctx, ui.local, rdto)
 			if err != nil {
 				ui.displayErr(err.Error())
 				return
@@ -537,7 +645,9 @@ func (ui *TUI) displayCreateBinary(ctx context.Context) {
 	buttons.SetButtonsAlign(tview.AlignLeft).SetBorderPadding(0, 0, 0, 0)
 
 	flex := tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(form, 0, 1, true).AddItem(buttons, 1, 1, false)
+		AddItem(form, 0, 1, true).
+		AddItem(md.flex, metadataHeight, 1, false).
+		AddItem(buttons, 1, 1, false)
 
 	ui.pages.AddPage(pageAddBinaryRecord, flex, true, true)
 }
@@ -556,8 +666,7 @@ func (ui *TUI) displayUpdateBinary(ctx context.Context, recordID string) {
 
 	desc := r.Description
 	path := "Enter new path for file here..."
-	metadata := r.Metadata
-	mit := convertMetadataToString(r.Metadata)
+	md := ui.newMetadataEditor(r.Metadata)
 
 	form := tview.NewForm().
 		AddInputField(fnDescription, r.Description, defaultFieldWidth, nil, func(v string) {
@@ -574,9 +683,6 @@ func (ui *TUI) displayUpdateBinary(ctx context.Context, recordID string) {
 		}).
 		AddInputField(fnPath, "", defaultFieldWidth, nil, func(v string) {
 			path = v
-		}).
-		AddTextArea(fnMetadata, mit, defaultFieldWidth, 0, 0, func(text string) {
-			mit = text
 		})
 	form.SetTitle(pageUpdateBinaryRecord).
 		SetTitleAlign(tview.AlignLeft)
@@ -613,7 +719,7 @@ func (ui *TUI) displayUpdateBinary(ctx context.Context, recordID string) {
 				r.Created,
 				time.Now(),
 				binaryType,
-				metadata,
+				md.Metadata(),
 				false,
 				r.Version,
 			)
@@ -621,16 +727,9 @@ func (ui *TUI) displayUpdateBinary(ctx context.Context, recordID string) {
 				ui.displayErr(err.Error())
 				return
 			}
-			rows := splitMetadata(mit)
-			m, err := models.NewMetadataFromStringArray(rows)
-			if err != nil {
-				ui.displayErr(err.Error())
-				return
-			}
-			r.Metadata = m
 			r.Version++
 
-			_, err = ui.authUser.UpdateRecord(ctx, ui.cache, r)
+			_, err = ui.authUser.UpdateRecord(ctx, ui.local, r)
 			if err != nil {
 				ui.displayErr(err.Error())
 				return
@@ -638,12 +737,15 @@ func (ui *TUI) displayUpdateBinary(ctx context.Context, recordID string) {
 
 			ui.pages.RemovePage(pageUpdateBinaryRecord)
 		}).
+		AddButton(buttonDeleteDesc, func() { ui.displayDeleteRecordModal(ctx, r.ID, pageUpdateBinaryRecord) }).
 		AddButton(buttonCancelDesc, func() { ui.pages.RemovePage(pageUpdateBinaryRecord) })
 
 	buttons.SetButtonsAlign(tview.AlignLeft).SetBorderPadding(0, 0, 0, 0)
 
 	flex := tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(form, 0, 1, true).AddItem(buttons, 1, 1, false)
+		AddItem(form, 0, 1, true).
+		AddItem(md.flex, metadataHeight, 1, false).
+		AddItem(buttons, 1, 1, false)
 
 	ui.pages.AddPage(pageUpdateBinaryRecord, flex, true, true)
 }
@@ -655,8 +757,7 @@ func (ui *TUI) displayCreateCard(ctx context.Context) {
 	var number string
 	var term time.Time
 	var owner string
-	var metadata []*models.Metadata
-	mit := ""
+	md := ui.newMetadataEditor(nil)
 	form := tview.NewForm().
 		AddInputField(fnDescription, "", defaultFieldWidth, nil, func(v string) {
 			desc = v
@@ -677,10 +778,7 @@ func (ui *TUI) displayCreateCard(ctx context.Context) {
 			}
 			term = t
 		}).
-		AddTextView(fnTemplateTermDesc, fnTemplateHintTermDesc, defaultFieldWidth, 0, true, true).
-		AddTextArea(fnMetadata, mit, defaultFieldWidth, 0, 0, func(text string) {
-			mit = text
-		})
+		AddTextView(fnTemplateTermDesc, fnTemplateHintTermDesc, defaultFieldWidth, 0, true, true)
 
 	form.SetTitle(pageAddCardRecord).
 		SetTitleAlign(tview.AlignLeft)
@@ -696,7 +794,7 @@ func (ui *TUI) displayCreateCard(ctx context.Context) {
 				desc,
 				models.CardType,
 				cardType,
-				metadata,
+				md.Metadata(),
 			)
 
 			if err != nil {
@@ -704,15 +802,7 @@ func (ui *TUI) displayCreateCard(ctx context.Context) {
 				return
 			}
 
-			rows := splitMetadata(mit)
-			m, err := models.NewMetadataFromStringArray(rows)
-			if err != nil {
-				ui.displayErr(err.Error())
-				return
-			}
-			rdto.Metadata = m
-
-			_, err = ui.authUser.AddRecord(ctx, ui.cache, rdto)
+			_, err = ui.authUser.AddRecord(ctx, ui.local, rdto)
 			if err != nil {
 				ui.displayErr(err.Error())
 				return
@@ -724,7 +814,9 @@ func (ui *TUI) displayCreateCard(ctx context.Context) {
 	buttons.SetButtonsAlign(tview.AlignLeft).SetBorderPadding(0, 0, 0, 0)
 
 	flex := tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(form, 0, 1, true).AddItem(buttons, 1, 1, false)
+		AddItem(form, 0, 1, true).
+		AddItem(md.flex, metadataHeight, 1, false).
+		AddItem(buttons, 1, 1, false)
 
 	ui.pages.AddPage(pageAddCardRecord, flex, true, true)
 }
@@ -745,8 +837,7 @@ func (ui *TUI) displayUpdateCard(ctx context.Context, recordID string) {
 	number := c.Number
 	term := c.Term
 	owner := c.Owner
-	metadata := r.Metadata
-	mit := convertMetadataToString(r.Metadata)
+	md := ui.newMetadataEditor(r.Metadata)
 
 	form := tview.NewForm().
 		AddInputField(fnDescription, desc, defaultFieldWidth, nil, func(v string) {
@@ -768,10 +859,7 @@ func (ui *TUI) displayUpdateCard(ctx context.Context, recordID string) {
 			}
 			term = t
 		}).
-		AddTextView(fnTemplateTermDesc, fnTemplateHintTermDesc, defaultFieldWidth, 0, true, true).
-		AddTextArea(fnMetadata, mit, defaultFieldWidth, 0, 0, func(text string) {
-			mit = text
-		})
+		AddTextView(fnTemplateTermDesc, fnTemplateHintTermDesc, defaultFieldWidth, 0, true, true)
 
 	form.SetTitle(pageUpdateCardRecord).
 		SetTitleAlign(tview.AlignLeft)
@@ -791,7 +879,7 @@ func (ui *TUI) displayUpdateCard(ctx context.Context, recordID string) {
 				r.Created,
 				time.Now(),
 				cardType,
-				metadata,
+				md.Metadata(),
 				false,
 				r.Version,
 			)
@@ -800,17 +888,9 @@ func (ui *TUI) displayUpdateCard(ctx context.Context, recordID string) {
 				return
 			}
 
-			rows := splitMetadata(mit)
-			m, err := models.NewMetadataFromStringArray(rows)
-			if err != nil {
-				ui.displayErr(err.Error())
-				return
-			}
-			r.Metadata = m
-
 			r.Version++
 
-			_, err = ui.authUser.UpdateRecord(ctx, ui.cache, r)
+			_, err = ui.authUser.UpdateRecord(ctx, ui.local, r)
 			if err != nil {
 				ui.displayErr(err.Error())
 				return
@@ -818,26 +898,37 @@ func (ui *TUI) displayUpdateCard(ctx context.Context, recordID string) {
 
 			ui.pages.RemovePage(pageUpdateCardRecord)
 		}).
+		AddButton("Copy number", func() { ui.copyToClipboard(string(copyNumber), number) }).
+		AddButton(buttonDeleteDesc, func() { ui.displayDeleteRecordModal(ctx, r.ID, pageUpdateCardRecord) }).
 		AddButton(buttonCancelDesc, func() { ui.pages.RemovePage(pageUpdateCardRecord) })
 
 	buttons.SetButtonsAlign(tview.AlignLeft).SetBorderPadding(0, 0, 0, 0)
 
 	flex := tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(form, 0, 1, true).AddItem(buttons, 1, 1, false)
+		AddItem(form, 0, 1, true).
+		AddItem(md.flex, metadataHeight, 1, false).
+		AddItem(buttons, 1, 1, false)
 
 	ui.pages.AddPage(pageUpdateCardRecord, flex, true, true)
 }
 
-func splitMetadata(md string) []string {
-	return strings.Split(md, "\n")
-}
+func (ui *TUI) displayDeleteRecordModal(ctx context.Context, recordID string, page string) {
+	modal := tview.NewModal().
+		SetText("Do you want to delete the record? The deletion will be synchronized to all devices.").
+		AddButtons([]string{buttonCancelDesc, buttonDeleteDesc}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			ui.pages.RemovePage(pageDeleteRecord)
+			if buttonLabel != buttonDeleteDesc {
+				return
+			}
 
-func convertMetadataToString(md []*models.Metadata) string {
-	mit := ""
+			if err := ui.authUser.DeleteRecord(ctx, ui.local, recordID); err != nil {
+				ui.displayErr(err.Error())
+				return
+			}
 
-	for _, m := range md {
-		mit += fmt.Sprintf("%s:%s\n", m.Key, m.Value)
-	}
+			ui.pages.RemovePage(page)
+		})
 
-	return mit
+	ui.pages.AddPage(pageDeleteRecord, modal, true, true)
 }
@@ -0,0 +1,169 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+	"github.com/ArtemShalinFe/gophkeeper/internal/storage/file"
+	"github.com/ArtemShalinFe/gophkeeper/internal/storage/sealed"
+)
+
+const (
+	pageSyncStatus = "Sync status"
+
+	buttonSyncNowDesc = "Sync now"
+
+	syncTimeFormat = "15:04:05"
+)
+
+// startSync - restores the pending changes of the user and starts the synchronization in the background.
+// The pending queue is sealed by the keyring of the session.
+func (ui *TUI) startSync(ctx context.Context, u *models.User, keys *sealed.Keyring) error {
+	dir, err := ui.cfg.GetDataDir()
+	if err != nil {
+		return fmt.Errorf("an error occured while retrieving data dir, err: %w", err)
+	}
+
+	q, err := file.NewQueue(file.UserQueuePath(dir, u.ID), keys)
+	if err != nil {
+		return fmt.Errorf("an error occured while open pending queue, err: %w", err)
+	}
+
+	interval := ui.cfg.SyncInterval
+	if interval <= 0 {
+		interval = time.Second * defaulTickSync
+	}
+	s := models.NewSynchronizer(u, ui.vault, ui.gkclient, q, ui, interval)
+	if err := s.Restore(ctx); err != nil {
+		return fmt.Errorf("an error occured while restore pending changes, err: %w", err)
+	}
+
+	// last - the status of the last update of the record list, it is accessed only from the event loop.
+	var last models.SyncStatus
+	s.OnChange(func(st models.SyncStatus) {
+		// OnChange may be called from the event loop, so the update must not be waited for.
+		go ui.app.QueueUpdateDraw(func() {
+			ui.statusSetup(syncStatusLine(st), 0)
+
+			// The records have been pulled from the server or changed locally.
+			if !st.LastSync.Equal(last.LastSync) || st.Pending != last.Pending {
+				ui.refreshRecords(ctx)
+			}
+			last = st
+		})
+	})
+
+	ui.syncer = s
+	ui.local = s.Local()
+	ui.queue = q
+	ui.runSync(ctx)
+
+	return nil
+}
+
+// runSync - runs the synchronizer in the background, stopSync stops it and waits until it returns,
+// so no records are written by the synchronizer after stopSync.
+func (ui *TUI) runSync(ctx context.Context) {
+	sctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	ui.stopSync = func() {
+		cancel()
+		<-done
+	}
+
+	// Run syncs at once, the records are refreshed by the status callback after the first attempt.
+	s := ui.syncer
+	go func() {
+		err := s.Run(sctx)
+		// The event loop may wait for the synchronizer to stop, so it is notified before the update is queued.
+		close(done)
+		if errors.Is(err, models.ErrDeviceRevoked) {
+			ui.app.QueueUpdateDraw(func() {
+				ui.logout()
+				ui.displayErr("This device has been revoked, please log in again")
+			})
+		}
+	}()
+}
+
+// refreshRecords - reloads the record list in place, if it is displayed.
+// The list may be covered by other pages, it is up to date when they are closed.
+func (ui *TUI) refreshRecords(ctx context.Context) {
+	if !ui.pages.HasPage(pageListRecords) {
+		return
+	}
+	ui.loadRecords(ctx)
+}
+
+func (ui *TUI) displaySyncStatus() {
+	if ui.syncer == nil {
+		return
+	}
+
+	text := tview.NewTextView().SetText(syncStatusDetails(ui.syncer.Status()))
+
+	buttons := tview.NewForm().
+		AddButton("Refresh", func() {
+			text.SetText(syncStatusDetails(ui.syncer.Status()))
+		}).
+		AddButton(buttonSyncNowDesc, func() {
+			ui.syncer.SyncNow()
+		}).
+		AddButton("Back to list", func() {
+			ui.pages.RemovePage(pageSyncStatus)
+		})
+	buttons.SetButtonsAlign(tview.AlignLeft).SetBorderPadding(0, 0, 0, 0)
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(text, 0, 1, false).
+		AddItem(buttons, 1, 1, true)
+
+	flex.SetBorder(true).SetTitle(" Sync status ").SetTitleAlign(tview.AlignLeft)
+
+	ui.pages.AddPage(pageSyncStatus, flex, true, true)
+}
+
+// syncStatusLine - returns a short description of the synchronization status for the status line.
+func syncStatusLine(st models.SyncStatus) string {
+	parts := []string{"last sync: " + formatSyncTime(st.LastSync)}
+	if st.Pending > 0 {
+		parts = append(parts, fmt.Sprintf("pending: %d", st.Pending))
+	}
+	if len(st.Rejected) > 0 {
+		parts = append(parts, fmt.Sprintf("rejected: %d", len(st.Rejected)))
+	}
+	if st.LastErr != nil {
+		parts = append(parts, fmt.Sprintf("sync failed, retry at %s", formatSyncTime(st.NextRetry)))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// syncStatusDetails - returns the full description of the synchronization status.
+func syncStatusDetails(st models.SyncStatus) string {
+	lastErr := "none"
+	if st.LastErr != nil {
+		lastErr = st.LastErr.Error()
+	}
+
+	rejected := "none"
+	if len(st.Rejected) > 0 {
+		rejected = strings.Join(st.Rejected, ", ")
+	}
+
+	return fmt.Sprintf("Last successful sync: %s\nPending local changes: %d\nNext attempt: %s\n"+
+		"Failed attempts in a row: %d\nLast error: %s\nRejected by the server as invalid: %s",
+		formatSyncTime(st.LastSync), st.Pending, formatSyncTime(st.NextRetry), st.Failures, lastErr, rejected)
+}
+
+func formatSyncTime(t time.Time) string {
+	if t.IsZero() {
+		return deviceNeverSynced
+	}
+	return t.Format(syncTimeFormat)
+}
@@ -2,40 +2,78 @@ package client
 
 import (
 	"context"
-	"time"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
 
 	"github.com/rivo/tview"
+	"go.uber.org/zap"
 
+	"github.com/ArtemShalinFe/gophkeeper/internal/build"
+	"github.com/ArtemShalinFe/gophkeeper/internal/config"
 	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+	"github.com/ArtemShalinFe/gophkeeper/internal/server"
+	"github.com/ArtemShalinFe/gophkeeper/internal/storage/file"
+	"github.com/ArtemShalinFe/gophkeeper/internal/storage/sealed"
 )
 
-const defaulTickSync = 5
+const (
+	defaulTickSync = 5
+
+	fnProfile = "Profile"
+)
 
 func (ui *TUI) displayUserLoginPage(ctx context.Context) {
 	var userDTO models.UserDTO
-	form := tview.NewForm().
-		AddInputField(fnUsername, "", defaultFieldWidth, nil, func(v string) {
-			userDTO.Login = v
-		}).
+	var form *tview.Form
+	// login - the password is not kept in the form after the login, the session is unlocked by the keyring.
+	login := func(u *models.User) {
+		password := userDTO.Password
+		if f, ok := form.GetFormItemByLabel(fnPassword).(*tview.InputField); ok {
+			f.SetText("")
+		}
+		if err := ui.cfg.SaveLastLogin(u.Login); err != nil {
+			ui.displayErr(err.Error())
+		}
+		ui.runSyncAndDisplayRecords(ctx, u, password)
+	}
+
+	form = tview.NewForm()
+	ui.addProfileDropDown(ctx, form)
+
+	userDTO.Login = ui.cfg.LastLogin
+	form.AddInputField(fnUsername, userDTO.Login, defaultFieldWidth, nil, func(v string) {
+		userDTO.Login = v
+	}).
 		AddPasswordField(fnPassword, "", defaultFieldWidth, '*', func(v string) {
 			userDTO.Password = v
 		}).
 		AddButton(buttonLoginDesc, func() {
+			if err := ui.checkServer(ctx); err != nil {
+				ui.displayErr(err.Error())
+				return
+			}
 			u, err := userDTO.GetUser(ctx, ui.gkclient)
 			if err != nil {
 				ui.displayErr(err.Error())
 				return
 			}
-			ui.runSyncAndDisplayRecords(ctx, u)
+			login(u)
 		}).
 		AddButton(buttonRegisterDesc, func() {
+			if err := ui.checkServer(ctx); err != nil {
+				ui.displayErr(err.Error())
+				return
+			}
 			u, err := userDTO.AddUser(ctx, ui.gkclient)
 			if err != nil {
 				ui.displayErr(err.Error())
 				return
 			}
-			ui.runSyncAndDisplayRecords(ctx, u)
+			login(u)
 		}).
+		AddButton(buttonAboutDesc, func() { ui.displayAbout(ctx) }).
 		AddButton(buttonQuinDesc, ui.displayQuitModal)
 
 	form.SetBorder(true).SetTitle(" GophKeeper ").
@@ -44,19 +82,145 @@ func (ui *TUI) displayUserLoginPage(ctx context.Context) {
 	ui.pages.AddPage(loginPage, form, true, true)
 }
 
-func (ui *TUI) runSyncAndDisplayRecords(ctx context.Context, u *models.User) {
+// addProfileDropDown - adds the list of the profiles of the configuration file to the login form,
+// the list is not added if the file has no profiles.
+func (ui *TUI) addProfileDropDown(ctx context.Context, form *tview.Form) {
+	f, err := config.ReadClientFile(ui.cfg.ConfigFile)
+	if err != nil {
+		ui.displayErr(err.Error())
+		return
+	}
+	names := f.Names()
+	if len(names) == 0 {
+		return
+	}
+
+	current := slices.Index(names, ui.cfg.Profile)
+	form.AddDropDown(fnProfile, names, current, func(name string, index int) {
+		// The drop-down calls the handler when it is added to the form, the profile is already in use.
+		if index < 0 || name == ui.cfg.Profile {
+			return
+		}
+		ui.switchProfile(ctx, name)
+	})
+}
+
+// switchProfile - connects to the server of the other profile and redisplays the login page.
+func (ui *TUI) switchProfile(ctx context.Context, name string) {
+	cfg, err := ui.cfg.SwitchProfile(name)
+	if err != nil {
+		ui.displayErr(err.Error())
+		return
+	}
+	gkclient, err := server.NewGKClient(ctx, cfg, zap.L())
+	if err != nil {
+		ui.displayErr(err.Error())
+		return
+	}
+	if err := ui.gkclient.Close(); err != nil {
+		zap.L().Error("an error occured while close gk client", zap.Error(err))
+	}
+
+	ui.cfg = cfg
+	ui.gkclient = gkclient
+	ui.compat = nil
+
+	ui.pages.RemovePage(loginPage)
+	ui.displayUserLoginPage(ctx)
+}
+
+func (ui *TUI) runSyncAndDisplayRecords(ctx context.Context, u *models.User, password string) {
+	b := build.NewBuild()
+	device := models.NewDeviceDTO(fmt.Sprintf("%s (%s)", b.Version(), b.Commit()))
+	device.Login, device.Password = u.Login, password
+	if _, err := u.RegisterDevice(ctx, ui.gkclient, device); err != nil {
+		ui.displayErr(err.Error())
+		return
+	}
+
+	keys, err := ui.openKeyring(u, password)
+	if err != nil {
+		ui.displayErr(err.Error())
+		return
+	}
+
 	ui.authUser = u
 
 	if err := ui.cache.AddUserRecordStorage(u.ID); err != nil {
 		ui.displayErr(err.Error())
 	}
+	ui.vault = sealed.NewStorage(ui.cache, keys)
+
+	if err := ui.startSync(ctx, u, keys); err != nil {
+		ui.displayErr(err.Error())
+		return
+	}
+
+	ictx, cancel := context.WithCancel(ctx)
+	ui.stopIdleLock = cancel
+	ui.watchIdle(ictx)
+
+	ui.displayRecords(ctx)
+}
+
+// openKeyring - returns the keyring of the user that seals the records of the session and the pending queue.
+// The keyring is created at the first login of the user on the device.
+func (ui *TUI) openKeyring(u *models.User, password string) (*sealed.Keyring, error) {
+	dir, err := ui.cfg.GetDataDir()
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while retrieving data dir, err: %w", err)
+	}
 
-	ctxT, cancel := context.WithTimeout(ctx, time.Second*defaulTickSync)
-	defer cancel()
+	path := file.UserKeyringPath(dir, u.ID)
+	keys, err := sealed.LoadKeyring(path, password)
+	if !errors.Is(err, os.ErrNotExist) {
+		return keys, err
+	}
 
-	ui.Sync(ctxT)
+	keys, err = sealed.NewKeyring(password)
+	if err != nil {
+		return nil, err
+	}
+	if err := keys.Save(path); err != nil {
+		return nil, err
+	}
 
-	ui.displayRecords(ctx, 0, ui.recLimit)
+	return keys, nil
+}
 
-	go func() { ui.Sync(ctx) }()
+// logout - removes the user storage from cache and returns to the login page.
+func (ui *TUI) logout() {
+	if ui.stopSync != nil {
+		ui.stopSync()
+	}
+	if ui.stopIdleLock != nil {
+		ui.stopIdleLock()
+	}
+	ui.syncer = nil
+	ui.local = nil
+	ui.queue = nil
+	ui.stopSync = nil
+	ui.stopIdleLock = nil
+
+	if ui.authUser != nil {
+		if err := ui.cache.RemoveUserRecordStorage(ui.authUser.ID); err != nil {
+			ui.displayErr(err.Error())
+		}
+	}
+	ui.authUser = nil
+	ui.vault = nil
+	ui.list = nil
+
+	ui.closePages()
+}
+
+// closePages - closes all pages above the login page.
+func (ui *TUI) closePages() {
+	for {
+		name, _ := ui.pages.GetFrontPage()
+		if name == "" || name == loginPage {
+			break
+		}
+		ui.pages.RemovePage(name)
+	}
 }
@@ -0,0 +1,174 @@
+// Package clipboard - Copies the text to the system clipboard.
+//
+// The text is sent to the terminal with the OSC 52 escape sequence, so copying works over SSH as well.
+// If the terminal cannot be used, the text is passed to one of the common Linux clipboard tools.
+package clipboard
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Mode - The way the text is copied to the clipboard.
+type Mode string
+
+const (
+	// ModeAuto - OSC 52 if the terminal is available, otherwise a clipboard tool.
+	ModeAuto Mode = "auto"
+	// ModeOSC52 - only the OSC 52 escape sequence.
+	ModeOSC52 Mode = "osc52"
+	// ModeCommand - only the clipboard tools, for terminals that ignore OSC 52.
+	ModeCommand Mode = "command"
+)
+
+const ttyPath = "/dev/tty"
+
+// ErrUnavailable - The error is returned if no way to reach the clipboard has been found.
+var ErrUnavailable = errors.New("the clipboard is not available, " +
+	"the terminal does not support OSC 52 and none of wl-copy, xclip, xsel is found")
+
+// ErrUnknownMode - The error is returned if the mode of the clipboard is not supported.
+var ErrUnknownMode = errors.New("unknown clipboard mode")
+
+// commands - The clipboard tools in the order of preference. The text is written to the standard input.
+var commands = []struct {
+	// display - the environment variable of the graphical session the tool works in.
+	display string
+	args    []string
+}{
+	{display: "WAYLAND_DISPLAY", args: []string{"wl-copy"}},
+	{display: "DISPLAY", args: []string{"xclip", "-selection", "clipboard"}},
+	{display: "DISPLAY", args: []string{"xsel", "--clipboard", "--input"}},
+}
+
+// Clipboard - Copies the text to the system clipboard.
+type Clipboard struct {
+	mode Mode
+	// openTTY - opens the terminal the escape sequences are written to.
+	openTTY func() (io.WriteCloser, error)
+	// run - runs the clipboard tool with the text on the standard input.
+	run      func(text string, args []string) error
+	lookPath func(file string) (string, error)
+	getenv   func(key string) string
+}
+
+// New - Object Constructor. An empty mode means ModeAuto.
+func New(mode string) (*Clipboard, error) {
+	m := Mode(strings.ToLower(strings.TrimSpace(mode)))
+	switch m {
+	case "":
+		m = ModeAuto
+	case ModeAuto, ModeOSC52, ModeCommand:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownMode, mode)
+	}
+
+	return &Clipboard{
+		mode:     m,
+		openTTY:  openTTY,
+		run:      runCommand,
+		lookPath: exec.LookPath,
+		getenv:   os.Getenv,
+	}, nil
+}
+
+// Copy - Copies the text to the clipboard.
+func (c *Clipboard) Copy(text string) error {
+	if c.mode != ModeCommand && c.osc52Supported() {
+		err := c.copyOSC52(text)
+		if err == nil || c.mode == ModeOSC52 {
+			return err
+		}
+	}
+	if c.mode == ModeOSC52 {
+		return ErrUnavailable
+	}
+
+	return c.copyCommand(text)
+}
+
+// Clear - Replaces the content of the clipboard with the empty string.
+func (c *Clipboard) Clear() error {
+	return c.Copy("")
+}
+
+// osc52Supported - the Linux console and dumb terminals do not handle OSC 52.
+func (c *Clipboard) osc52Supported() bool {
+	switch c.getenv("TERM") {
+	case "", "dumb", "linux":
+		return false
+	default:
+		return true
+	}
+}
+
+func (c *Clipboard) copyOSC52(text string) error {
+	tty, err := c.openTTY()
+	if err != nil {
+		return err
+	}
+	defer tty.Close()
+
+	if _, err := io.WriteString(tty, c.osc52(text)); err != nil {
+		return fmt.Errorf("an error occured while write to terminal, err: %w", err)
+	}
+
+	return nil
+}
+
+// osc52 - returns the escape sequence that sets the clipboard.
+// Inside tmux and screen the sequence is wrapped so that it is passed to the outer terminal.
+func (c *Clipboard) osc52(text string) string {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+
+	switch {
+	case c.getenv("TMUX") != "":
+		return "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	case strings.HasPrefix(c.getenv("TERM"), "screen"):
+		return "\x1bP" + seq + "\x1b\\"
+	default:
+		return seq
+	}
+}
+
+func (c *Clipboard) copyCommand(text string) error {
+	for _, cmd := range commands {
+		if c.getenv(cmd.display) == "" {
+			continue
+		}
+		if _, err := c.lookPath(cmd.args[0]); err != nil {
+			continue
+		}
+
+		return c.run(text, cmd.args)
+	}
+
+	return ErrUnavailable
+}
+
+func openTTY() (io.WriteCloser, error) {
+	tty, err := os.OpenFile(ttyPath, os.O_WRONLY, 0)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while open terminal, err: %w", err)
+	}
+
+	return tty, nil
+}
+
+// runCommand - the output of the tool is not read: xclip and xsel stay in the background
+// to serve the clipboard and would keep the output pipe open.
+func runCommand(text string, args []string) error {
+	cmd := exec.Command(args[0], args[1:]...) //nolint:gosec // The command is one of the known clipboard tools.
+	cmd.Stdin = strings.NewReader(text)
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("an error occured while run %s, err: %w", args[0], err)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package clipboard
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+)
+
+type nopCloser struct {
+	*bytes.Buffer
+}
+
+func (nopCloser) Close() error { return nil }
+
+func TestClipboard_Copy(t *testing.T) {
+	tests := []struct {
+		env     map[string]string
+		tools   map[string]bool
+		name    string
+		mode    Mode
+		noTTY   bool
+		wantTTY string
+		wantRun []string
+		wantErr error
+	}{
+		{
+			name:    "osc52",
+			mode:    ModeAuto,
+			env:     map[string]string{"TERM": "xterm-256color", "DISPLAY": ":0"},
+			tools:   map[string]bool{"xclip": true},
+			wantTTY: "\x1b]52;c;c2VjcmV0\a",
+		},
+		{
+			name:    "osc52 inside tmux",
+			mode:    ModeAuto,
+			env:     map[string]string{"TERM": "screen", "TMUX": "/tmp/tmux"},
+			wantTTY: "\x1bPtmux;\x1b\x1b]52;c;c2VjcmV0\a\x1b\\",
+		},
+		{
+			name:    "linux console falls back to the tool",
+			mode:    ModeAuto,
+			env:     map[string]string{"TERM": "linux", "DISPLAY": ":0"},
+			tools:   map[string]bool{"xsel": true},
+			wantRun: []string{"xsel", "--clipboard", "--input"},
+		},
+		{
+			name:    "no terminal falls back to the tool",
+			mode:    ModeAuto,
+			env:     map[string]string{"TERM": "xterm", "WAYLAND_DISPLAY": "wayland-0"},
+			tools:   map[string]bool{"wl-copy": true, "xclip": true},
+			noTTY:   true,
+			wantRun: []string{"wl-copy"},
+		},
+		{
+			name:    "command mode",
+			mode:    ModeCommand,
+			env:     map[string]string{"TERM": "xterm", "DISPLAY": ":0"},
+			tools:   map[string]bool{"xclip": true},
+			wantRun: []string{"xclip", "-selection", "clipboard"},
+		},
+		{
+			name:    "tool without graphical session",
+			mode:    ModeAuto,
+			env:     map[string]string{"TERM": "dumb"},
+			tools:   map[string]bool{"xclip": true},
+			wantErr: ErrUnavailable,
+		},
+		{
+			name:    "osc52 mode on linux console",
+			mode:    ModeOSC52,
+			env:     map[string]string{"TERM": "linux", "DISPLAY": ":0"},
+			tools:   map[string]bool{"xclip": true},
+			wantErr: ErrUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(string(tt.mode))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var tty bytes.Buffer
+			var run []string
+			c.getenv = func(key string) string { return tt.env[key] }
+			c.openTTY = func() (io.WriteCloser, error) {
+				if tt.noTTY {
+					return nil, os.ErrNotExist
+				}
+				return nopCloser{&tty}, nil
+			}
+			c.lookPath = func(file string) (string, error) {
+				if tt.tools[file] {
+					return "/usr/bin/" + file, nil
+				}
+				return "", os.ErrNotExist
+			}
+			c.run = func(text string, args []string) error {
+				if text != "secret" {
+					t.Errorf("Clipboard.Copy() runs the tool with %q", text)
+				}
+				run = args
+				return nil
+			}
+
+			if err := c.Copy("secret"); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Clipboard.Copy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tty.String() != tt.wantTTY {
+				t.Errorf("Clipboard.Copy() writes %q to the terminal, want %q", tty.String(), tt.wantTTY)
+			}
+			if !reflect.DeepEqual(run, tt.wantRun) {
+				t.Errorf("Clipboard.Copy() runs %v, want %v", run, tt.wantRun)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New("clipboard"); !errors.Is(err, ErrUnknownMode) {
+		t.Errorf("New() error = %v, wantErr %v", err, ErrUnknownMode)
+	}
+	c, err := New("")
+	if err != nil || c.mode != ModeAuto {
+		t.Errorf("New() = %v, %v, want the auto mode", c, err)
+	}
+}
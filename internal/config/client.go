@@ -1,18 +1,64 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/caarlos0/env"
 )
 
+// The environment variables of the settings that can be set by the profile.
+const (
+	envAddress      = "GKS_ADDRESS"
+	envCertificate  = "CERTIFICATE"
+	envCertPin      = "CERTIFICATE_PIN"
+	envSyncInterval = "SYNC_INTERVAL"
+	envLockTimeout  = "LOCK_TIMEOUT"
+)
+
+const (
+	// profilesDir - the directory of the data of the profiles in the data directory of the client.
+	profilesDir = "profiles"
+	dataDirMode = 0700
+)
+
 // ClientCfg - An object that implements the application configuration.
+//
+// The settings of the connection may be stored in the profiles of the configuration file, see LoadClientCfg.
 type ClientCfg struct {
 	// GKeeper - The address of the server running the gophkeeper service.
 	GKeeper string `env:"GKS_ADDRESS" json:"gkeeper_address"`
 	// CertFilePath - The path to the certificate file.
 	CertFilePath string `env:"CERTIFICATE" json:"agent_certificate"`
+	// CertPin - The SHA-256 fingerprint of the certificate of the server in hex.
+	// If it is set, the connection is established only with the server that has this certificate.
+	CertPin string `env:"CERTIFICATE_PIN" json:"certificate_pin"`
+	// SyncInterval - The interval between the synchronizations of the text interface.
+	SyncInterval time.Duration `env:"SYNC_INTERVAL" envDefault:"5s" json:"sync_interval"`
+	// DataDir - The directory where the client keeps its data between runs.
+	// By default, the gophkeeper directory in the user cache directory is used.
+	DataDir string `env:"DATA_DIR" json:"data_dir"`
+	// Clipboard - The way the values are copied to the clipboard: auto, osc52 or command.
+	Clipboard string `env:"CLIPBOARD" envDefault:"auto" json:"clipboard"`
+	// ClipboardTimeout - The clipboard is cleared after this time since the copy. Zero disables the clearing.
+	ClipboardTimeout time.Duration `env:"CLIPBOARD_TIMEOUT" envDefault:"30s" json:"clipboard_timeout"`
+	// LockTimeout - The session of the text interface is locked after this time without input. Zero disables the lock.
+	LockTimeout time.Duration `env:"LOCK_TIMEOUT" envDefault:"5m" json:"lock_timeout"`
+	// TracesExporter - The exporter of the traces: none, otlp or stdout.
+	// The stdout exporter writes the spans to the standard error, so they do not mix with the output of the commands.
+	TracesExporter string `env:"TRACES_EXPORTER" envDefault:"none" json:"traces_exporter"`
+	// ConfigFile - The path to the configuration file with the profiles.
+	ConfigFile string `json:"-"`
+	// Profile - The name of the profile in use, empty if no profile is used.
+	Profile string `json:"-"`
+	// LastLogin - The login of the user who logged in with the profile last time.
+	LastLogin string `json:"-"`
+	// overrides - the settings of the flags, they are applied again when the profile is switched.
+	overrides []func(cfg *ClientCfg)
 }
 
 // NewClientCfg - Object Constructor.
@@ -31,3 +77,68 @@ func ReadEnvClientCfg(cfg *ClientCfg) error {
 	}
 	return nil
 }
+
+// GetDataDir - Returns the directory where the client keeps its data between runs.
+// Every profile has its own directory, so the sessions and the pending changes of different servers are not mixed.
+func (cfg *ClientCfg) GetDataDir() (string, error) {
+	dir, err := cfg.baseDataDir()
+	if err != nil {
+		return "", err
+	}
+
+	if cfg.Profile != "" {
+		return filepath.Join(dir, profilesDir, cfg.Profile), nil
+	}
+	return dir, nil
+}
+
+// AdoptDataDir - Moves the data of the client without profiles to the directory of the profile.
+// It is used when the first profile becomes current, so the session and the pending changes are not left behind.
+// The data is not moved if the profile already has its own data.
+func (cfg *ClientCfg) AdoptDataDir(profile string) error {
+	dir, err := cfg.baseDataDir()
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("an error occured while read data dir, err: %w", err)
+	}
+
+	dst := filepath.Join(dir, profilesDir, profile)
+	if _, err := os.Stat(dst); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(dst, dataDirMode); err != nil {
+		return fmt.Errorf("an error occured while create profile data dir, err: %w", err)
+	}
+
+	for _, e := range entries {
+		if e.Name() == profilesDir {
+			continue
+		}
+		if err := os.Rename(filepath.Join(dir, e.Name()), filepath.Join(dst, e.Name())); err != nil {
+			return fmt.Errorf("an error occured while move data to profile dir, err: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// baseDataDir - returns the data directory of the client without profiles.
+func (cfg *ClientCfg) baseDataDir() (string, error) {
+	dir := strings.TrimSpace(cfg.DataDir)
+	if dir != "" {
+		return dir, nil
+	}
+
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("an error occured while retrieving user cache dir, err: %w", err)
+	}
+	return filepath.Join(cache, "gophkeeper"), nil
+}
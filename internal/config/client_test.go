@@ -3,6 +3,7 @@ package config
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestReadEnvClientCfg(t *testing.T) {
@@ -21,8 +22,13 @@ func TestReadEnvClientCfg(t *testing.T) {
 			name: "check reading env",
 			cfg:  NewClientCfg(),
 			want: &ClientCfg{
-				GKeeper:      testString,
-				CertFilePath: testString,
+				GKeeper:          testString,
+				CertFilePath:     testString,
+				Clipboard:        "auto",
+				ClipboardTimeout: 30 * time.Second,
+				LockTimeout:      5 * time.Minute,
+				SyncInterval:     5 * time.Second,
+				TracesExporter:   "none",
 			},
 			keyword: testString,
 			wantErr: false,
@@ -31,8 +37,13 @@ func TestReadEnvClientCfg(t *testing.T) {
 			name: "check reading env",
 			cfg:  NewClientCfg(),
 			want: &ClientCfg{
-				GKeeper:      testString,
-				CertFilePath: testString,
+				GKeeper:          testString,
+				CertFilePath:     testString,
+				Clipboard:        "auto",
+				ClipboardTimeout: 30 * time.Second,
+				LockTimeout:      5 * time.Minute,
+				SyncInterval:     5 * time.Second,
+				TracesExporter:   "none",
 			},
 			wantErr: false,
 		},
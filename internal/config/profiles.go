@@ -0,0 +1,282 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+const (
+	clientFileName = "client.json"
+	clientDirMode  = 0700
+	clientFileMode = 0600
+)
+
+// The environment variables that select the configuration file and the profile.
+const (
+	envConfigFile = "GK_CONFIG"
+	envProfile    = "GK_PROFILE"
+)
+
+// ErrUnknownProfile - The error is returned if the profile is not found in the configuration file.
+var ErrUnknownProfile = errors.New("unknown profile")
+
+// ErrInvalidProfileName - The error is returned if the name of the profile contains characters other than
+// letters, digits, dots, dashes and underscores. The name is a part of the path to the data of the profile.
+var ErrInvalidProfileName = errors.New("invalid profile name")
+
+var profileNameRe = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9._-]*$`)
+
+// CheckProfileName - Returns ErrInvalidProfileName if the name cannot be used as the name of the profile.
+func CheckProfileName(name string) error {
+	if !profileNameRe.MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidProfileName, name)
+	}
+	return nil
+}
+
+// Duration - The duration that is written to the configuration file as a string, for example 5m.
+type Duration time.Duration
+
+// MarshalText - Implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText - Implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(b []byte) error {
+	v, err := time.ParseDuration(string(b))
+	if err != nil {
+		return fmt.Errorf("an error occured while parse duration, err: %w", err)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Profile - The named settings of the connection to the server.
+type Profile struct {
+	// Address - The address of the server.
+	Address string `json:"gkeeper_address,omitempty"`
+	// CAFile - The path to the certificate that the certificate of the server is verified with.
+	CAFile string `json:"ca_file,omitempty"`
+	// CertPin - The SHA-256 fingerprint of the certificate of the server in hex.
+	CertPin string `json:"certificate_pin,omitempty"`
+	// SyncInterval - The interval between the synchronizations.
+	SyncInterval Duration `json:"sync_interval,omitempty"`
+	// LockTimeout - The session of the text interface is locked after this time without input.
+	LockTimeout Duration `json:"lock_timeout,omitempty"`
+	// LastLogin - The login of the user who logged in with the profile last time.
+	LastLogin string `json:"last_login,omitempty"`
+}
+
+// ClientFile - The configuration file of the client.
+type ClientFile struct {
+	// Current - The profile that is used if no profile is specified.
+	Current  string              `json:"current,omitempty"`
+	Profiles map[string]*Profile `json:"profiles,omitempty"`
+}
+
+// DefaultClientFilePath - Returns the path to the configuration file in the user config directory.
+func DefaultClientFilePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("an error occured while retrieving user config dir, err: %w", err)
+	}
+
+	return filepath.Join(dir, "gophkeeper", clientFileName), nil
+}
+
+// ReadClientFile - Reads the configuration file, the file that does not exist is empty.
+func ReadClientFile(path string) (*ClientFile, error) {
+	f := &ClientFile{Profiles: make(map[string]*Profile)}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return f, nil
+		}
+		return nil, fmt.Errorf("an error occured while read config file, err: %w", err)
+	}
+
+	if err := json.Unmarshal(b, f); err != nil {
+		return nil, fmt.Errorf("an error occured while decode config file %s, err: %w", path, err)
+	}
+	if f.Profiles == nil {
+		f.Profiles = make(map[string]*Profile)
+	}
+
+	return f, nil
+}
+
+// Save - Writes the configuration file. The file is replaced at once, so it is never left half-written.
+func (f *ClientFile) Save(path string) error {
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("an error occured while encode config file, err: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), clientDirMode); err != nil {
+		return fmt.Errorf("an error occured while create config dir, err: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, clientFileMode); err != nil {
+		return fmt.Errorf("an error occured while write config file, err: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("an error occured while replace config file, err: %w", err)
+	}
+
+	return nil
+}
+
+// Names - Returns the sorted names of the profiles.
+func (f *ClientFile) Names() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// LoadClientCfg - Reads the configuration of the client from the flags at the beginning of the arguments,
+// the environment variables and the profile of the configuration file.
+//
+// The settings are taken in the order of precedence: the flags, the environment variables, the profile
+// and the defaults. The profile is selected by the --profile flag, the GK_PROFILE variable
+// or the current profile of the file. Returns the arguments that follow the flags.
+func LoadClientCfg(args []string) (*ClientCfg, []string, error) {
+	fs := flag.NewFlagSet("gclient", flag.ContinueOnError)
+	configFile := fs.String("config", "", "path to the configuration file")
+	profile := fs.String("profile", "", "name of the profile of the configuration file")
+	address := fs.String("address", "", "address of the server")
+	caFile := fs.String("ca", "", "path to the certificate that the server certificate is verified with")
+	certPin := fs.String("pin", "", "SHA-256 fingerprint of the server certificate in hex")
+	syncInterval := fs.Duration("sync-interval", 0, "interval between the synchronizations")
+	lockTimeout := fs.Duration("lock-timeout", 0, "the session is locked after this time without input, 0 disables it")
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, fmt.Errorf("an error occured while parse flags, err: %w", err)
+	}
+
+	var overrides []func(cfg *ClientCfg)
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "address":
+			overrides = append(overrides, func(cfg *ClientCfg) { cfg.GKeeper = *address })
+		case "ca":
+			overrides = append(overrides, func(cfg *ClientCfg) { cfg.CertFilePath = *caFile })
+		case "pin":
+			overrides = append(overrides, func(cfg *ClientCfg) { cfg.CertPin = *certPin })
+		case "sync-interval":
+			overrides = append(overrides, func(cfg *ClientCfg) { cfg.SyncInterval = *syncInterval })
+		case "lock-timeout":
+			overrides = append(overrides, func(cfg *ClientCfg) { cfg.LockTimeout = *lockTimeout })
+		}
+	})
+
+	path := firstNonEmpty(*configFile, os.Getenv(envConfigFile))
+	if path == "" {
+		p, err := DefaultClientFilePath()
+		if err != nil {
+			return nil, nil, err
+		}
+		path = p
+	}
+
+	cfg, err := buildClientCfg(path, firstNonEmpty(*profile, os.Getenv(envProfile)), overrides)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cfg, fs.Args(), nil
+}
+
+// SwitchProfile - Returns the configuration with the other profile of the configuration file.
+// The flags and the environment variables still take precedence over the profile.
+func (cfg *ClientCfg) SwitchProfile(name string) (*ClientCfg, error) {
+	return buildClientCfg(cfg.ConfigFile, name, cfg.overrides)
+}
+
+// SaveLastLogin - Remembers the login in the profile of the configuration, if a profile is used.
+func (cfg *ClientCfg) SaveLastLogin(login string) error {
+	if cfg.Profile == "" || cfg.ConfigFile == "" {
+		return nil
+	}
+
+	f, err := ReadClientFile(cfg.ConfigFile)
+	if err != nil {
+		return err
+	}
+	p, ok := f.Profiles[cfg.Profile]
+	if !ok || p.LastLogin == login {
+		return nil
+	}
+	p.LastLogin = login
+
+	return f.Save(cfg.ConfigFile)
+}
+
+func buildClientCfg(path string, profile string, overrides []func(cfg *ClientCfg)) (*ClientCfg, error) {
+	cfg := NewClientCfg()
+	if err := ReadEnvClientCfg(cfg); err != nil {
+		return nil, err
+	}
+	cfg.ConfigFile = path
+	cfg.overrides = overrides
+
+	f, err := ReadClientFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Profile = firstNonEmpty(profile, f.Current)
+	if cfg.Profile != "" {
+		if err := CheckProfileName(cfg.Profile); err != nil {
+			return nil, err
+		}
+		p, ok := f.Profiles[cfg.Profile]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q is not found in %s", ErrUnknownProfile, cfg.Profile, path)
+		}
+		cfg.applyProfile(p)
+	}
+
+	for _, o := range overrides {
+		o(cfg)
+	}
+
+	return cfg, nil
+}
+
+// applyProfile - takes the settings of the profile that are not set by the environment variables.
+func (cfg *ClientCfg) applyProfile(p *Profile) {
+	apply := func(env string, ok bool, set func()) {
+		if _, found := os.LookupEnv(env); !found && ok {
+			set()
+		}
+	}
+
+	apply(envAddress, p.Address != "", func() { cfg.GKeeper = p.Address })
+	apply(envCertificate, p.CAFile != "", func() { cfg.CertFilePath = p.CAFile })
+	apply(envCertPin, p.CertPin != "", func() { cfg.CertPin = p.CertPin })
+	apply(envSyncInterval, p.SyncInterval != 0, func() { cfg.SyncInterval = time.Duration(p.SyncInterval) })
+	apply(envLockTimeout, p.LockTimeout != 0, func() { cfg.LockTimeout = time.Duration(p.LockTimeout) })
+	cfg.LastLogin = p.LastLogin
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
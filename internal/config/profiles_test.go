@@ -0,0 +1,205 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// unsetEnv - unsets the environment variables for the test, they are restored after the test.
+func unsetEnv(t *testing.T, keys ...string) {
+	t.Helper()
+
+	for _, k := range keys {
+		t.Setenv(k, "")
+		if err := os.Unsetenv(k); err != nil {
+			t.Fatalf("os.Unsetenv() error = %v", err)
+		}
+	}
+}
+
+func writeTestClientFile(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), clientFileName)
+	f := &ClientFile{
+		Current: "work",
+		Profiles: map[string]*Profile{
+			"work": {
+				Address:     "work:8080",
+				CAFile:      "work.crt",
+				LockTimeout: Duration(time.Minute),
+				LastLogin:   "worker",
+			},
+			"home": {
+				Address:      "home:8080",
+				SyncInterval: Duration(time.Minute),
+			},
+		},
+	}
+	if err := f.Save(path); err != nil {
+		t.Fatalf("ClientFile.Save() error = %v", err)
+	}
+	return path
+}
+
+func TestLoadClientCfg(t *testing.T) {
+	path := writeTestClientFile(t)
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		args     []string
+		want     *ClientCfg
+		wantArgs []string
+		wantErr  error
+	}{
+		{
+			name: "current profile",
+			args: []string{"--config", path, "list"},
+			want: &ClientCfg{GKeeper: "work:8080", CertFilePath: "work.crt", LockTimeout: time.Minute,
+				SyncInterval: 5 * time.Second, Profile: "work", LastLogin: "worker"},
+			wantArgs: []string{"list"},
+		},
+		{
+			name: "profile from the environment",
+			env:  map[string]string{envConfigFile: path, envProfile: "home"},
+			want: &ClientCfg{GKeeper: "home:8080", LockTimeout: 5 * time.Minute,
+				SyncInterval: time.Minute, Profile: "home"},
+		},
+		{
+			name: "environment takes precedence over the profile",
+			env:  map[string]string{envConfigFile: path, envAddress: "env:8080"},
+			want: &ClientCfg{GKeeper: "env:8080", CertFilePath: "work.crt", LockTimeout: time.Minute,
+				SyncInterval: 5 * time.Second, Profile: "work", LastLogin: "worker"},
+		},
+		{
+			name: "flags take precedence over the environment",
+			env:  map[string]string{envConfigFile: path, envAddress: "env:8080"},
+			args: []string{"--profile", "home", "--address", "flag:8080", "--lock-timeout", "0s"},
+			want: &ClientCfg{GKeeper: "flag:8080", SyncInterval: time.Minute, Profile: "home"},
+		},
+		{
+			name: "no configuration file",
+			args: []string{"--config", filepath.Join(t.TempDir(), clientFileName)},
+			want: &ClientCfg{GKeeper: "localhost:6085", LockTimeout: 5 * time.Minute, SyncInterval: 5 * time.Second},
+		},
+		{
+			name:    "unknown profile",
+			args:    []string{"--config", path, "--profile", "office"},
+			wantErr: ErrUnknownProfile,
+		},
+		{
+			name:    "invalid profile name",
+			args:    []string{"--config", path, "--profile", "../work"},
+			wantErr: ErrInvalidProfileName,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			unsetEnv(t, envConfigFile, envProfile, envAddress, envCertificate, envCertPin, envSyncInterval, envLockTimeout)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			got, args, err := LoadClientCfg(tt.args)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LoadClientCfg() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if got.GKeeper != tt.want.GKeeper || got.CertFilePath != tt.want.CertFilePath ||
+				got.LockTimeout != tt.want.LockTimeout || got.SyncInterval != tt.want.SyncInterval ||
+				got.Profile != tt.want.Profile || got.LastLogin != tt.want.LastLogin {
+				t.Errorf("LoadClientCfg() = %+v, want %+v", got, tt.want)
+			}
+			if len(args) != len(tt.wantArgs) || (len(args) > 0 && args[0] != tt.wantArgs[0]) {
+				t.Errorf("LoadClientCfg() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestClientCfg_SwitchProfile(t *testing.T) {
+	unsetEnv(t, envProfile, envAddress, envCertificate, envCertPin, envSyncInterval, envLockTimeout)
+	path := writeTestClientFile(t)
+
+	cfg, _, err := LoadClientCfg([]string{"--config", path, "--ca", "flag.crt"})
+	if err != nil {
+		t.Fatalf("LoadClientCfg() error = %v", err)
+	}
+
+	got, err := cfg.SwitchProfile("home")
+	if err != nil {
+		t.Fatalf("ClientCfg.SwitchProfile() error = %v", err)
+	}
+	if got.GKeeper != "home:8080" || got.CertFilePath != "flag.crt" || got.Profile != "home" {
+		t.Errorf("ClientCfg.SwitchProfile() = %+v, want the home profile with the flag certificate", got)
+	}
+
+	if err := got.SaveLastLogin("homer"); err != nil {
+		t.Fatalf("ClientCfg.SaveLastLogin() error = %v", err)
+	}
+	f, err := ReadClientFile(path)
+	if err != nil {
+		t.Fatalf("ReadClientFile() error = %v", err)
+	}
+	if f.Profiles["home"].LastLogin != "homer" || f.Profiles["home"].SyncInterval != Duration(time.Minute) {
+		t.Errorf("ReadClientFile() home profile = %+v, want the saved last login", f.Profiles["home"])
+	}
+
+	if _, err := cfg.SwitchProfile("office"); !errors.Is(err, ErrUnknownProfile) {
+		t.Errorf("ClientCfg.SwitchProfile() error = %v, want %v", err, ErrUnknownProfile)
+	}
+}
+
+func TestClientCfg_AdoptDataDir(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &ClientCfg{DataDir: dir}
+
+	session := filepath.Join(dir, "session.json")
+	if err := os.WriteFile(session, []byte("{}"), 0600); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	pending := filepath.Join(dir, "user", "pending.cbor")
+	if err := os.MkdirAll(filepath.Dir(pending), 0700); err != nil {
+		t.Fatalf("os.MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(pending, []byte("queue"), 0600); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	if err := cfg.AdoptDataDir("work"); err != nil {
+		t.Fatalf("ClientCfg.AdoptDataDir() error = %v", err)
+	}
+
+	cfg.Profile = "work"
+	got, err := cfg.GetDataDir()
+	if err != nil {
+		t.Fatalf("ClientCfg.GetDataDir() error = %v", err)
+	}
+	for _, name := range []string{"session.json", filepath.Join("user", "pending.cbor")} {
+		if _, err := os.Stat(filepath.Join(got, name)); err != nil {
+			t.Errorf("the %s is not moved to the profile data dir, err: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(session); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("the session is left in the data dir, err: %v", err)
+	}
+
+	// The profile that already has the data is not overwritten.
+	if err := os.WriteFile(session, []byte("{}"), 0600); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if err := cfg.AdoptDataDir("work"); err != nil {
+		t.Fatalf("ClientCfg.AdoptDataDir() error = %v", err)
+	}
+	if _, err := os.Stat(session); err != nil {
+		t.Errorf("the data is moved to the profile that has its own data, err: %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/tracing"
+)
+
+// envServerConfigFile - The environment variable with the path to the configuration file of the server.
+const envServerConfigFile = "GKS_CONFIG"
+
+// redacted - replaces the secrets in the printed configuration.
+const redacted = "xxxxx"
+
+// ErrInvalidServerCfg - The error is returned if the settings of the server are not valid.
+var ErrInvalidServerCfg = errors.New("invalid server config")
+
+// LoadServerCfg - Reads the configuration of the server from the file, the environment variables and the flags.
+//
+// The settings are taken in the order of precedence: the flags, the environment variables,
+// the configuration file and the defaults. The file is selected by the --config flag or the GKS_CONFIG
+// variable, it is YAML or JSON. The configuration is validated before it is returned.
+// Returns the arguments that follow the flags, for example, the administrative command.
+func LoadServerCfg(args []string) (*ServerCfg, []string, error) {
+	// The flags are parsed twice: at first to find the configuration file,
+	// then on top of the file and the environment, so only the flags that are set take precedence.
+	fs := flag.NewFlagSet("gserver", flag.ContinueOnError)
+	configFile := fs.String("config", "", "path to the configuration file in YAML or JSON")
+	printConfig := fs.Bool("print-config", false, "print the configuration with the hidden secrets and exit")
+	bindServerFlags(fs, NewServerCfg())
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, fmt.Errorf("an error occured while parse flags, err: %w", err)
+	}
+
+	cfg := NewServerCfg()
+	cfg.ConfigFile = firstNonEmpty(*configFile, os.Getenv(envServerConfigFile))
+	cfg.PrintConfig = *printConfig
+	if cfg.ConfigFile != "" {
+		if err := readServerFile(cfg.ConfigFile, cfg); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := ReadEnvServerCfg(cfg); err != nil {
+		return nil, nil, err
+	}
+
+	fs = flag.NewFlagSet("gserver", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.String("config", "", "")
+	fs.Bool("print-config", false, "")
+	bindServerFlags(fs, cfg)
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, fmt.Errorf("an error occured while parse flags, err: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	return cfg, fs.Args(), nil
+}
+
+// bindServerFlags - adds the flags of the settings, the values of the flags are stored in the configuration.
+func bindServerFlags(fs *flag.FlagSet, cfg *ServerCfg) {
+	fs.StringVar(&cfg.Addr, "address", cfg.Addr, "address of the gRPC listener in the format host:port")
+	fs.StringVar(&cfg.DSN, "dsn", cfg.DSN, "data source name of the PostgreSQL database")
+	fs.StringVar(&cfg.CertFilePath, "tls-cert", cfg.CertFilePath, "path to the TLS certificate")
+	fs.StringVar(&cfg.PrivateCryptoKey, "tls-key", cfg.PrivateCryptoKey, "path to the private key of the TLS certificate")
+	fs.StringVar(&cfg.HealthAddr, "health-address", cfg.HealthAddr, "address of the /healthz and /readyz endpoints")
+	fs.StringVar(&cfg.MetricsAddr, "metrics-address", cfg.MetricsAddr, "address of the /metrics endpoint")
+	fs.StringVar(&cfg.GatewayAddr, "gateway-address", cfg.GatewayAddr, "address of the REST/JSON gateway")
+	fs.DurationVar(&cfg.HealthInterval, "health-interval", cfg.HealthInterval, "interval between the checks of the database")
+	fs.StringVar(&cfg.TracesExporter, "traces-exporter", cfg.TracesExporter, "exporter of the traces: none, otlp or stdout")
+	fs.IntVar(&cfg.DBMaxConns, "db-max-conns", cfg.DBMaxConns, "maximum size of the pool of the database connections")
+	fs.IntVar(&cfg.DBMinConns, "db-min-conns", cfg.DBMinConns, "number of the idle database connections kept open")
+	fs.DurationVar(&cfg.DBMaxConnLifetime, "db-max-conn-lifetime", cfg.DBMaxConnLifetime,
+		"the database connection is closed after this time")
+	fs.DurationVar(&cfg.DBMaxConnIdleTime, "db-max-conn-idle-time", cfg.DBMaxConnIdleTime,
+		"the idle database connection is closed after this time")
+	fs.IntVar(&cfg.MaxMsgSize, "max-message-size", cfg.MaxMsgSize, "maximum size of the gRPC message in bytes")
+	fs.StringVar(&cfg.LogLevel, "log-level", cfg.LogLevel, "level of the log: debug, info, warn or error")
+	fs.IntVar(&cfg.QuotaRecords, "quota-records", cfg.QuotaRecords, "maximum number of the records of the user, 0 is no limit")
+	fs.IntVar(&cfg.QuotaRecordSize, "quota-record-size", cfg.QuotaRecordSize,
+		"maximum size of the record data in bytes, 0 is no limit")
+	fs.DurationVar(&cfg.TombstoneRetention, "tombstone-retention", cfg.TombstoneRetention,
+		"the deleted records are purged after this time")
+	fs.Float64Var(&cfg.RateLimit, "rate-limit", cfg.RateLimit,
+		"requests per second of the IP address or the user, 0 is no limit")
+	fs.IntVar(&cfg.RateBurst, "rate-burst", cfg.RateBurst,
+		"requests of the IP address or the user at once above the rate limit")
+	fs.DurationVar(&cfg.RequestTimeout, "request-timeout", cfg.RequestTimeout,
+		"maximum time of processing of the request, 0 is no limit")
+	fs.DurationVar(&cfg.ConnIdleTimeout, "conn-idle-timeout", cfg.ConnIdleTimeout,
+		"the idle connection of the client is closed after this time, 0 is no limit")
+	fs.DurationVar(&cfg.ConnMaxAge, "conn-max-age", cfg.ConnMaxAge,
+		"the connection of the client is closed after this time, 0 is no limit")
+}
+
+// readServerFile - reads the settings of the file on top of the configuration.
+// The unknown settings are reported, so a misspelled setting does not silently keep its default.
+func readServerFile(path string, cfg *ServerCfg) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("an error occured while read config file, err: %w", err)
+	}
+
+	// YAML is a superset of JSON, so the JSON files are decoded by the same decoder.
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("an error occured while decode config file %s, err: %w", path, err)
+	}
+
+	return nil
+}
+
+// Validate - Checks the settings, all the invalid settings are reported at once.
+func (cfg *ServerCfg) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	_, _, err := net.SplitHostPort(cfg.Addr)
+	check(err == nil, "address %q must be in the format host:port", cfg.Addr)
+	for name, addr := range map[string]string{
+		"health address":  cfg.HealthAddr,
+		"metrics address": cfg.MetricsAddr,
+		"gateway address": cfg.GatewayAddr,
+	} {
+		if addr != "" {
+			_, _, err := net.SplitHostPort(addr)
+			check(err == nil, "%s %q must be in the format host:port", name, addr)
+		}
+	}
+	check(cfg.DSN != "", "dsn must be set")
+	check((cfg.CertFilePath == "") == (cfg.PrivateCryptoKey == ""),
+		"TLS certificate and private key must be set together")
+	for _, path := range []string{cfg.CertFilePath, cfg.PrivateCryptoKey} {
+		if path != "" {
+			_, err := os.Stat(path)
+			check(err == nil, "TLS file %s is not available: %v", path, err)
+		}
+	}
+
+	check(cfg.HealthInterval >= 0, "health interval must not be negative")
+	switch cfg.TracesExporter {
+	case "", tracing.ExporterNone, tracing.ExporterOTLP, tracing.ExporterStdout:
+	default:
+		check(false, "traces exporter %q must be one of %s, %s, %s",
+			cfg.TracesExporter, tracing.ExporterNone, tracing.ExporterOTLP, tracing.ExporterStdout)
+	}
+
+	check(cfg.DBMaxConns > 0, "db max conns must be positive")
+	check(cfg.DBMinConns >= 0 && cfg.DBMinConns <= cfg.DBMaxConns, "db min conns must be between 0 and db max conns")
+	check(cfg.DBMaxConnLifetime >= 0, "db max conn lifetime must not be negative")
+	check(cfg.DBMaxConnIdleTime >= 0, "db max conn idle time must not be negative")
+
+	check(cfg.MaxMsgSize > 0, "max message size must be positive")
+	check(cfg.QuotaRecordSize >= 0, "quota record size must not be negative")
+	check(cfg.QuotaRecordSize < cfg.MaxMsgSize,
+		"max message size %d must be greater than quota record size %d", cfg.MaxMsgSize, cfg.QuotaRecordSize)
+	check(cfg.QuotaRecords >= 0, "quota records must not be negative")
+
+	_, err = zapcore.ParseLevel(cfg.LogLevel)
+	check(err == nil, "log level %q must be one of debug, info, warn or error", cfg.LogLevel)
+
+	check(cfg.TombstoneRetention >= 0, "tombstone retention must not be negative")
+	check(cfg.RateLimit >= 0, "rate limit must not be negative")
+	check(cfg.RateLimit == 0 || cfg.RateBurst > 0, "rate burst must be positive if the rate limit is set")
+	check(cfg.RequestTimeout >= 0, "request timeout must not be negative")
+	check(cfg.ConnIdleTimeout >= 0, "connection idle timeout must not be negative")
+	check(cfg.ConnMaxAge >= 0, "connection max age must not be negative")
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidServerCfg, err)
+	}
+	return nil
+}
+
+// Print - Writes the configuration in YAML, the secrets are hidden.
+func (cfg *ServerCfg) Print(w io.Writer) error {
+	c := *cfg
+	c.DSN = RedactDSN(c.DSN)
+
+	b, err := yaml.Marshal(&c)
+	if err != nil {
+		return fmt.Errorf("an error occured while encode config, err: %w", err)
+	}
+	if _, err := w.Write(b); err != nil {
+		return fmt.Errorf("an error occured while print config, err: %w", err)
+	}
+
+	return nil
+}
+
+var dsnPasswordRe = regexp.MustCompile(`(password\s*=\s*)('(?:[^'\\]|\\.)*'|\S+)`)
+
+// RedactDSN - Returns the data source name with the hidden password.
+// Both the URL and the key=value formats of PostgreSQL are supported.
+func RedactDSN(dsn string) string {
+	u, err := url.Parse(dsn)
+	if err == nil && u.Scheme != "" && u.Host != "" {
+		q := u.Query()
+		if q.Has("password") {
+			q.Set("password", redacted)
+			u.RawQuery = q.Encode()
+		}
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), redacted)
+		}
+		return u.String()
+	}
+
+	return dsnPasswordRe.ReplaceAllString(dsn, "${1}"+redacted)
+}
@@ -0,0 +1,235 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+// utf8BOM - the byte order mark that some applications write at the start of the file.
+const utf8BOM = '\uFEFF'
+
+// errSkipRow - The row is not a login or has neither a login nor a password.
+var errSkipRow = errors.New("the row is skipped")
+
+// columns - The names of the columns of the export that are mapped on the record fields.
+// Columns are compared in lower case. The columns that are not mapped and not ignored go into the metadata.
+type columns struct {
+	// detect - the columns that identify the format.
+	detect      []string
+	description string
+	login       string
+	password    string
+	url         string
+	notes       string
+	totp        string
+	// kind, kindLogin - the column with the type of the item and its value for logins.
+	// Items of other types are skipped.
+	kind      string
+	kindLogin string
+	ignored   []string
+}
+
+var formats = map[Format]columns{
+	FormatChrome: {
+		detect:      []string{"name", "url", "username", "password"},
+		description: "name",
+		login:       "username",
+		password:    "password",
+		url:         "url",
+		notes:       "note",
+	},
+	FormatFirefox: {
+		detect:   []string{"url", "username", "password", "guid"},
+		login:    "username",
+		password: "password",
+		url:      "url",
+		ignored: []string{"httprealm", "formactionorigin", "guid",
+			"timecreated", "timelastused", "timepasswordchanged"},
+	},
+	FormatBitwarden: {
+		detect:      []string{"login_username", "login_password"},
+		description: "name",
+		login:       "login_username",
+		password:    "login_password",
+		url:         "login_uri",
+		notes:       "notes",
+		totp:        "login_totp",
+		kind:        "type",
+		kindLogin:   "login",
+		ignored:     []string{"favorite", "reprompt"},
+	},
+	Format1Password: {
+		detect:      []string{"title", "username", "password"},
+		description: "title",
+		login:       "username",
+		password:    "password",
+		url:         "url",
+		notes:       "notes",
+		totp:        "otpauth",
+		ignored:     []string{"favorite", "archived"},
+	},
+}
+
+// DetectFormat - Detects the format of the CSV export by the header of the file.
+func DetectFormat(header []string) (Format, error) {
+	idx := headerIndex(header)
+	for _, f := range csvFormats {
+		if hasColumns(idx, formats[f].detect) {
+			return f, nil
+		}
+	}
+
+	return "", ErrUnknownFormat
+}
+
+// ParseCSV - Reads the export and converts the logins into auth records.
+// The address, the notes, the one-time password secret and the extra columns are saved into the metadata.
+func ParseCSV(r io.Reader, format Format) (*Result, error) {
+	br := bufio.NewReader(r)
+	if ch, _, err := br.ReadRune(); err == nil && ch != utf8BOM {
+		if err := br.UnreadRune(); err != nil {
+			return nil, fmt.Errorf("an error occured while read file, err: %w", err)
+		}
+	}
+
+	cr := csv.NewReader(br)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrEmptyFile
+		}
+		return nil, fmt.Errorf("an error occured while read header, err: %w", err)
+	}
+
+	if format == FormatAuto {
+		format, err = DetectFormat(header)
+		if err != nil {
+			return nil, err
+		}
+	}
+	cols, ok := formats[format]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+	idx := headerIndex(header)
+	if !hasColumns(idx, cols.detect) {
+		return nil, fmt.Errorf("the header does not match the %s format, expected columns: %s",
+			format, strings.Join(cols.detect, ", "))
+	}
+
+	res := &Result{Format: format}
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("an error occured while read row, err: %w", err)
+		}
+
+		rdto, err := cols.record(header, idx, row)
+		if errors.Is(err, errSkipRow) {
+			res.Skipped++
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		res.Records = append(res.Records, rdto)
+	}
+
+	return res, nil
+}
+
+// record - converts the row into the auth record. Returns errSkipRow if the row must be skipped.
+func (c columns) record(header []string, idx map[string]int, row []string) (*models.RecordDTO, error) {
+	get := func(col string) string {
+		i, ok := idx[col]
+		if col == "" || !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	if c.kind != "" && !strings.EqualFold(get(c.kind), c.kindLogin) {
+		return nil, errSkipRow
+	}
+
+	auth := &models.Auth{
+		Login:    get(c.login),
+		Password: get(c.password),
+	}
+	if auth.Login == "" && auth.Password == "" {
+		return nil, errSkipRow
+	}
+
+	var mi []*models.Metadata
+	add := func(key string, value string) {
+		if value != "" {
+			mi = append(mi, &models.Metadata{Key: key, Value: value})
+		}
+	}
+	add(models.MetadataKeyURL, get(c.url))
+	add(models.MetadataKeyNotes, get(c.notes))
+	add(models.MetadataKeyTOTP, get(c.totp))
+
+	mapped := map[string]bool{c.description: true, c.login: true, c.password: true,
+		c.url: true, c.notes: true, c.totp: true, c.kind: true}
+	for _, col := range c.ignored {
+		mapped[col] = true
+	}
+	for i, name := range header {
+		if !mapped[strings.ToLower(strings.TrimSpace(name))] && i < len(row) {
+			add(strings.TrimSpace(name), strings.TrimSpace(row[i]))
+		}
+	}
+
+	desc := get(c.description)
+	if desc == "" {
+		desc = hostname(get(c.url))
+	}
+	if desc == "" {
+		desc = auth.Login
+	}
+
+	rdto, err := models.NewRecordDTO(desc, models.AuthType, auth, mi)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while create record, err: %w", err)
+	}
+
+	return rdto, nil
+}
+
+func headerIndex(header []string) map[string]int {
+	idx := make(map[string]int, len(header))
+	for i, name := range header {
+		idx[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	return idx
+}
+
+func hasColumns(idx map[string]int, cols []string) bool {
+	for _, col := range cols {
+		if _, ok := idx[col]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func hostname(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
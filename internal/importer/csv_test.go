@@ -0,0 +1,146 @@
+package importer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		format      Format
+		wantFormat  Format
+		wantDesc    []string
+		wantLogin   []string
+		wantMeta    map[string]string
+		wantSkipped int
+		wantErr     error
+	}{
+		{
+			name: "chrome",
+			data: "name,url,username,password,note\n" +
+				"example.com,https://example.com/login,user,secret,my note\n",
+			format:     FormatAuto,
+			wantFormat: FormatChrome,
+			wantDesc:   []string{"example.com"},
+			wantLogin:  []string{"user"},
+			wantMeta: map[string]string{
+				models.MetadataKeyURL:   "https://example.com/login",
+				models.MetadataKeyNotes: "my note",
+			},
+		},
+		{
+			name: "firefox",
+			data: string(utf8BOM) + "\"url\",\"username\",\"password\",\"httpRealm\",\"formActionOrigin\",\"guid\"," +
+				"\"timeCreated\",\"timeLastUsed\",\"timePasswordChanged\"\n" +
+				"\"https://mail.example.org\",\"user\",\"secret\",,\"\",\"{1}\",\"1\",\"2\",\"3\"\n",
+			format:     FormatAuto,
+			wantFormat: FormatFirefox,
+			wantDesc:   []string{"mail.example.org"},
+			wantLogin:  []string{"user"},
+			wantMeta:   map[string]string{models.MetadataKeyURL: "https://mail.example.org"},
+		},
+		{
+			name: "bitwarden skips items that are not logins",
+			data: "folder,favorite,type,name,notes,fields,reprompt,login_uri,login_username,login_password,login_totp\n" +
+				"work,1,login,Mail,,key: value,0,https://mail.example.org,user,secret,JBSWY3DPEHPK3PXP\n" +
+				"work,,note,Note,text,,0,,,,\n",
+			format:      FormatAuto,
+			wantFormat:  FormatBitwarden,
+			wantDesc:    []string{"Mail"},
+			wantLogin:   []string{"user"},
+			wantSkipped: 1,
+			wantMeta: map[string]string{
+				models.MetadataKeyURL:  "https://mail.example.org",
+				models.MetadataKeyTOTP: "JBSWY3DPEHPK3PXP",
+				"folder":               "work",
+				"fields":               "key: value",
+			},
+		},
+		{
+			name: "1password",
+			data: "Title,Url,Username,Password,OTPAuth,Favorite,Archived,Tags,Notes\n" +
+				"Bank,https://bank.example.com,user,secret,,false,false,finance,\n" +
+				"Empty,,,,,false,false,,\n",
+			format:      FormatAuto,
+			wantFormat:  Format1Password,
+			wantDesc:    []string{"Bank"},
+			wantLogin:   []string{"user"},
+			wantSkipped: 1,
+			wantMeta: map[string]string{
+				models.MetadataKeyURL: "https://bank.example.com",
+				"Tags":                "finance",
+			},
+		},
+		{
+			name:    "unknown format",
+			data:    "a,b,c\n1,2,3\n",
+			format:  FormatAuto,
+			wantErr: ErrUnknownFormat,
+		},
+		{
+			name:    "empty file",
+			data:    "",
+			format:  FormatAuto,
+			wantErr: ErrEmptyFile,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := ParseCSV(strings.NewReader(tt.data), tt.format)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseCSV() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCSV() error = %v", err)
+			}
+
+			if res.Format != tt.wantFormat {
+				t.Errorf("ParseCSV() format = %v, want %v", res.Format, tt.wantFormat)
+			}
+			if res.Skipped != tt.wantSkipped {
+				t.Errorf("ParseCSV() skipped = %v, want %v", res.Skipped, tt.wantSkipped)
+			}
+			if len(res.Records) != len(tt.wantDesc) {
+				t.Fatalf("ParseCSV() got %d records, want %d", len(res.Records), len(tt.wantDesc))
+			}
+
+			for i, r := range res.Records {
+				if r.Description != tt.wantDesc[i] {
+					t.Errorf("ParseCSV() description = %v, want %v", r.Description, tt.wantDesc[i])
+				}
+
+				d, err := r.DecodeData()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if a, ok := d.(*models.Auth); !ok || a.Login != tt.wantLogin[i] {
+					t.Errorf("ParseCSV() data = %v, want login %v", d, tt.wantLogin[i])
+				}
+
+				if len(r.Metadata) != len(tt.wantMeta) {
+					t.Errorf("ParseCSV() metadata = %v, want %v", r.Metadata, tt.wantMeta)
+				}
+				for k, v := range tt.wantMeta {
+					if got := models.MetadataValue(r.Metadata, k); got != v {
+						t.Errorf("ParseCSV() metadata %s = %v, want %v", k, got, v)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParseCSV_FormatMismatch(t *testing.T) {
+	_, err := ParseCSV(strings.NewReader("name,url,username,password\n"), FormatBitwarden)
+	if err == nil {
+		t.Error("ParseCSV() error = nil, want error")
+	}
+}
@@ -0,0 +1,95 @@
+// Package importer - Converts the exports of browsers and password managers into gophkeeper records
+// and writes the records back in the KeePass format.
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+// Format - The name of the application that made the export.
+type Format string
+
+const (
+	// FormatAuto - the format is detected by the content of the file.
+	FormatAuto Format = "auto"
+	// FormatChrome - the export of Google Chrome and other Chromium based browsers.
+	FormatChrome Format = "chrome"
+	// FormatFirefox - the export of Mozilla Firefox.
+	FormatFirefox Format = "firefox"
+	// FormatBitwarden - the CSV export of Bitwarden.
+	FormatBitwarden Format = "bitwarden"
+	// Format1Password - the CSV export of 1Password.
+	Format1Password Format = "1password"
+	// FormatKeePass - the XML export of KeePass 2.x and KeePassXC.
+	FormatKeePass Format = "keepass"
+)
+
+// Formats - The formats that can be imported.
+var Formats = []Format{FormatKeePass, FormatBitwarden, Format1Password, FormatFirefox, FormatChrome}
+
+// csvFormats - The CSV formats in the order they are detected.
+var csvFormats = []Format{FormatBitwarden, Format1Password, FormatFirefox, FormatChrome}
+
+// ErrUnknownFormat - The error is returned if the format of the file is not supported or cannot be detected.
+var ErrUnknownFormat = errors.New("unknown import format")
+
+// ErrEmptyFile - The error is returned if the file has no header.
+var ErrEmptyFile = errors.New("the file is empty")
+
+// Result - The records read from the export.
+type Result struct {
+	// Format - the format of the file, detected if FormatAuto was requested.
+	Format Format
+	// Records - the records of the export.
+	Records []*models.RecordDTO
+	// Skipped - the number of the items that are not logins or have neither a login nor a password.
+	Skipped int
+}
+
+// ParseFormat - Converts the name of the format into the Format.
+func ParseFormat(s string) (Format, error) {
+	f := Format(strings.ToLower(strings.TrimSpace(s)))
+	if f == "" || f == FormatAuto {
+		return FormatAuto, nil
+	}
+	for _, known := range Formats {
+		if f == known {
+			return f, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrUnknownFormat, s)
+}
+
+// Parse - Reads the export in the format. If the format is FormatAuto,
+// XML files are read as KeePass exports and other files as CSV exports.
+func Parse(r io.Reader, format Format) (*Result, error) {
+	br := bufio.NewReader(r)
+	if format == FormatAuto && isXML(br) {
+		format = FormatKeePass
+	}
+
+	if format == FormatKeePass {
+		return ParseKeePassXML(br)
+	}
+
+	return ParseCSV(br, format)
+}
+
+// isXML - checks that the file starts with the XML declaration or an element.
+func isXML(br *bufio.Reader) bool {
+	const sniffLen = 512
+
+	b, _ := br.Peek(sniffLen)
+	b = bytes.TrimPrefix(b, []byte(string(utf8BOM)))
+	b = bytes.TrimLeft(b, " \t\r\n")
+
+	return bytes.HasPrefix(b, []byte("<"))
+}
@@ -0,0 +1,556 @@
+package importer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+// Names of the standard fields of the KeePass entry.
+const (
+	kpTitle    = "Title"
+	kpUserName = "UserName"
+	kpPassword = "Password"
+	kpURL      = "URL"
+	kpNotes    = "Notes"
+	kpOTP      = "otp"
+	kpNumber   = "Number"
+	kpOwner    = "Owner"
+	kpTerm     = "Term"
+	// kpType - the custom field that keeps the type of records that are not logins.
+	kpType = "gophkeeper_type"
+)
+
+const (
+	// metadataKeyKeePassUUID - the metadata key that keeps the UUID of the KeePass entry.
+	// Attachments of the entry are linked to it by the UUID.
+	metadataKeyKeePassUUID = "keepass_uuid"
+	// keepassRootGroup - the name of the root group if the records have different top-level folders.
+	keepassRootGroup  = "gophkeeper"
+	keepassGenerator  = "gophkeeper"
+	keepassTermFormat = "01/06"
+	folderSeparator   = "/"
+	repeatedKeyFormat = "%s (%d)"
+)
+
+var repeatedKeyRe = regexp.MustCompile(`^(.+) \((\d+)\)$`)
+
+// kpFile - The KeePass 2.x XML file. Elements that have no place in the records, for example times,
+// icons and history, are not read.
+type kpFile struct {
+	XMLName xml.Name `xml:"KeePassFile"`
+	Meta    kpMeta   `xml:"Meta"`
+	Root    kpRoot   `xml:"Root"`
+}
+
+type kpMeta struct {
+	Generator string      `xml:"Generator,omitempty"`
+	Binaries  []*kpBinary `xml:"Binaries>Binary"`
+}
+
+// kpBinary - the attachment from the pool of the file, the entries refer to it by the ID.
+type kpBinary struct {
+	ID         string `xml:"ID,attr"`
+	Compressed bool   `xml:"Compressed,attr,omitempty"`
+	Data       string `xml:",chardata"`
+}
+
+type kpRoot struct {
+	Groups []*kpGroup `xml:"Group"`
+}
+
+type kpGroup struct {
+	UUID    string     `xml:"UUID"`
+	Name    string     `xml:"Name"`
+	Entries []*kpEntry `xml:"Entry"`
+	Groups  []*kpGroup `xml:"Group"`
+}
+
+type kpEntry struct {
+	UUID     string           `xml:"UUID"`
+	Strings  []*kpString      `xml:"String"`
+	Binaries []*kpEntryBinary `xml:"Binary"`
+}
+
+type kpString struct {
+	Key   string  `xml:"Key"`
+	Value kpValue `xml:"Value"`
+}
+
+type kpValue struct {
+	Protected bool   `xml:"ProtectInMemory,attr,omitempty"`
+	Value     string `xml:",chardata"`
+}
+
+// kpEntryBinary - the attachment of the entry. The data is either in the pool of the file or inline.
+type kpEntryBinary struct {
+	Key   string        `xml:"Key"`
+	Value kpBinaryValue `xml:"Value"`
+}
+
+type kpBinaryValue struct {
+	Ref  string `xml:"Ref,attr,omitempty"`
+	Data string `xml:",chardata"`
+}
+
+// ParseKeePassXML - Reads the KeePass 2.x XML export.
+// Entries are converted into auth records, attachments into binary records and custom fields into the metadata.
+// The path of the group is saved into the folder metadata.
+func ParseKeePassXML(r io.Reader) (*Result, error) {
+	var f kpFile
+	if err := xml.NewDecoder(r).Decode(&f); err != nil {
+		return nil, fmt.Errorf("%w: an error occured while decode KeePass XML, err: %w", ErrUnknownFormat, err)
+	}
+
+	pool := make(map[string]*kpBinary, len(f.Meta.Binaries))
+	for _, b := range f.Meta.Binaries {
+		pool[b.ID] = b
+	}
+
+	p := &kpParser{pool: pool, res: &Result{Format: FormatKeePass}}
+	for _, g := range f.Root.Groups {
+		if err := p.group(g, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return p.res, nil
+}
+
+type kpParser struct {
+	pool map[string]*kpBinary
+	res  *Result
+}
+
+func (p *kpParser) group(g *kpGroup, path []string) error {
+	path = append(append([]string{}, path...), escapeFolder(g.Name))
+	folder := strings.Join(path, folderSeparator)
+
+	for _, e := range g.Entries {
+		if err := p.entry(e, folder); err != nil {
+			return err
+		}
+	}
+	for _, sub := range g.Groups {
+		if err := p.group(sub, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *kpParser) entry(e *kpEntry, folder string) error {
+	fields := make(map[string]string, len(e.Strings))
+	for _, s := range e.Strings {
+		fields[s.Key] = s.Value.Value
+	}
+
+	link := []*models.Metadata{
+		{Key: models.MetadataKeyFolder, Value: folder},
+		{Key: metadataKeyKeePassUUID, Value: e.UUID},
+	}
+
+	dt := models.DataType(fields[kpType])
+	if dt == "" {
+		dt = models.AuthType
+	}
+
+	if dt != models.BinaryType {
+		if err := p.record(dt, fields, e.Strings, link); err != nil {
+			return err
+		}
+	}
+
+	for _, b := range e.Binaries {
+		data, err := p.binary(b)
+		if err != nil {
+			return fmt.Errorf("an error occured while read attachment %q of entry %q, err: %w", b.Key, fields[kpTitle], err)
+		}
+
+		bin := &models.Binary{Name: b.Key, Ext: filepath.Ext(b.Key), Data: data}
+		rdto, err := models.NewRecordDTO(b.Key, models.BinaryType, bin, link)
+		if err != nil {
+			return fmt.Errorf("an error occured while create record, err: %w", err)
+		}
+		p.res.Records = append(p.res.Records, rdto)
+	}
+
+	return nil
+}
+
+// record - creates the record of the entry, the fields that are not used by the type go into the metadata.
+func (p *kpParser) record(dt models.DataType,
+	fields map[string]string,
+	strs []*kpString,
+	link []*models.Metadata) error {
+	used := map[string]bool{kpTitle: true, kpType: true}
+	mi := append([]*models.Metadata{}, link...)
+	meta := func(field string, key string) {
+		used[field] = true
+		if v := fields[field]; v != "" {
+			mi = append(mi, &models.Metadata{Key: key, Value: v})
+		}
+	}
+
+	var data models.RecordData
+	switch dt {
+	case models.AuthType:
+		used[kpUserName], used[kpPassword] = true, true
+		data = &models.Auth{Login: fields[kpUserName], Password: fields[kpPassword]}
+		meta(kpURL, models.MetadataKeyURL)
+		meta(kpNotes, models.MetadataKeyNotes)
+		meta(kpOTP, models.MetadataKeyTOTP)
+	case models.TextType:
+		used[kpNotes] = true
+		data = &models.Text{Data: fields[kpNotes]}
+	case models.CardType:
+		used[kpNumber], used[kpOwner], used[kpTerm] = true, true, true
+		card := &models.Card{Number: fields[kpNumber], Owner: fields[kpOwner]}
+		if v := fields[kpTerm]; v != "" {
+			t, err := time.Parse(keepassTermFormat, v)
+			if err != nil {
+				return fmt.Errorf("an error occured while parse term of entry %q, err: %w", fields[kpTitle], err)
+			}
+			card.Term = t
+		}
+		data = card
+	default:
+		return fmt.Errorf("entry %q, err: %w", fields[kpTitle], models.ErrUnknowDataType)
+	}
+
+	for _, s := range strs {
+		if !used[s.Key] && s.Value.Value != "" {
+			mi = append(mi, &models.Metadata{Key: repeatedKey(s.Key, fields), Value: s.Value.Value})
+		}
+	}
+
+	rdto, err := models.NewRecordDTO(fields[kpTitle], dt, data, mi)
+	if err != nil {
+		return fmt.Errorf("an error occured while create record, err: %w", err)
+	}
+	p.res.Records = append(p.res.Records, rdto)
+
+	return nil
+}
+
+func (p *kpParser) binary(b *kpEntryBinary) ([]byte, error) {
+	raw := b.Value.Data
+	compressed := false
+	if b.Value.Ref != "" {
+		pb, ok := p.pool[b.Value.Ref]
+		if !ok {
+			return nil, fmt.Errorf("the attachment %q is not found", b.Value.Ref)
+		}
+		raw, compressed = pb.Data, pb.Compressed
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while decode attachment, err: %w", err)
+	}
+	if !compressed {
+		return data, nil
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while decompress attachment, err: %w", err)
+	}
+	defer zr.Close()
+
+	data, err = io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while decompress attachment, err: %w", err)
+	}
+
+	return data, nil
+}
+
+// WriteKeePassXML - Writes the records in the KeePass 2.x XML format.
+// Records are placed into the groups by the folder metadata, binary records become attachments of the entry
+// they were imported from or of a separate entry.
+func WriteKeePassXML(w io.Writer, records []*models.Record) error {
+	root := &kpGroup{UUID: newKeePassUUID(""), Name: commonRootFolder(records)}
+	wr := &kpWriter{
+		root:    root,
+		groups:  map[string]*kpGroup{"": root},
+		entries: make(map[string]*kpEntry),
+		file:    &kpFile{Meta: kpMeta{Generator: keepassGenerator}, Root: kpRoot{Groups: []*kpGroup{root}}},
+	}
+
+	for _, r := range records {
+		if r.Type != string(models.BinaryType) {
+			if err := wr.record(r); err != nil {
+				return err
+			}
+		}
+	}
+	for _, r := range records {
+		if r.Type == string(models.BinaryType) {
+			if err := wr.attachment(r); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("an error occured while write KeePass XML, err: %w", err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "\t")
+	if err := enc.Encode(wr.file); err != nil {
+		return fmt.Errorf("an error occured while write KeePass XML, err: %w", err)
+	}
+
+	return nil
+}
+
+type kpWriter struct {
+	root    *kpGroup
+	groups  map[string]*kpGroup
+	entries map[string]*kpEntry
+	file    *kpFile
+}
+
+// group - returns the group of the folder, the missing groups are created.
+func (wr *kpWriter) group(folder string) *kpGroup {
+	names := splitFolder(folder)
+	if len(names) > 0 && names[0] == wr.root.Name {
+		names = names[1:]
+	}
+
+	g := wr.root
+	path := ""
+	for _, name := range names {
+		path += folderSeparator + escapeFolder(name)
+		sub, ok := wr.groups[path]
+		if !ok {
+			sub = &kpGroup{UUID: newKeePassUUID(""), Name: name}
+			g.Groups = append(g.Groups, sub)
+			wr.groups[path] = sub
+		}
+		g = sub
+	}
+
+	return g
+}
+
+// entry - creates the entry of the record in the group of its folder.
+func (wr *kpWriter) entry(r *models.Record) *kpEntry {
+	id := models.MetadataValue(r.Metadata, metadataKeyKeePassUUID)
+	if id == "" {
+		id = newKeePassUUID(r.ID)
+	}
+
+	e := &kpEntry{UUID: id}
+	g := wr.group(models.MetadataValue(r.Metadata, models.MetadataKeyFolder))
+	g.Entries = append(g.Entries, e)
+	wr.entries[id] = e
+
+	return e
+}
+
+func (wr *kpWriter) record(r *models.Record) error {
+	d, err := r.DecodeData()
+	if err != nil {
+		return fmt.Errorf("an error occured while decode record %s, err: %w", r.ID, err)
+	}
+
+	e := wr.entry(r)
+	e.addString(kpTitle, r.Description, false)
+
+	// consumed - metadata keys whose first value is written into the standard field of the entry.
+	var consumed []string
+	switch v := d.(type) {
+	case *models.Auth:
+		e.addString(kpUserName, v.Login, false)
+		e.addString(kpPassword, v.Password, true)
+		e.addString(kpURL, models.MetadataValue(r.Metadata, models.MetadataKeyURL), false)
+		e.addString(kpNotes, models.MetadataValue(r.Metadata, models.MetadataKeyNotes), false)
+		if totp := models.MetadataValue(r.Metadata, models.MetadataKeyTOTP); totp != "" {
+			e.addString(kpOTP, totp, true)
+		}
+		consumed = []string{models.MetadataKeyURL, models.MetadataKeyNotes, models.MetadataKeyTOTP}
+	case *models.Text:
+		e.addString(kpType, r.Type, false)
+		e.addString(kpNotes, v.Data, false)
+	case *models.Card:
+		e.addString(kpType, r.Type, false)
+		e.addString(kpNumber, v.Number, true)
+		e.addString(kpOwner, v.Owner, false)
+		if !v.Term.IsZero() {
+			e.addString(kpTerm, v.Term.Format(keepassTermFormat), false)
+		}
+	default:
+		return fmt.Errorf("record %s, err: %w", r.ID, models.ErrUnknowDataType)
+	}
+
+	skip := map[string]bool{models.MetadataKeyFolder: true, metadataKeyKeePassUUID: true}
+	for _, key := range consumed {
+		skip[key] = true
+	}
+	for _, m := range r.Metadata {
+		key := strings.ToLower(m.Key)
+		if skip[key] {
+			// Only the first value is consumed, the repeated keys are written as custom fields.
+			skip[key] = key == models.MetadataKeyFolder || key == metadataKeyKeePassUUID
+			continue
+		}
+		e.addString(m.Key, m.Value, false)
+	}
+
+	return nil
+}
+
+// attachment - adds the file to the entry the record was imported from.
+// If there is no such entry, a separate entry is created.
+func (wr *kpWriter) attachment(r *models.Record) error {
+	d, err := r.DecodeData()
+	if err != nil {
+		return fmt.Errorf("an error occured while decode record %s, err: %w", r.ID, err)
+	}
+	b, ok := d.(*models.Binary)
+	if !ok {
+		return fmt.Errorf("record %s, err: %w", r.ID, models.ErrUnknowDataType)
+	}
+
+	e, ok := wr.entries[models.MetadataValue(r.Metadata, metadataKeyKeePassUUID)]
+	if !ok {
+		e = wr.entry(r)
+		e.addString(kpTitle, r.Description, false)
+		e.addString(kpType, r.Type, false)
+	}
+
+	name := b.Name
+	if name == "" {
+		name = r.Description
+	}
+	id := strconv.Itoa(len(wr.file.Meta.Binaries))
+	wr.file.Meta.Binaries = append(wr.file.Meta.Binaries, &kpBinary{
+		ID:   id,
+		Data: base64.StdEncoding.EncodeToString(b.Data),
+	})
+	e.Binaries = append(e.Binaries, &kpEntryBinary{Key: e.uniqueBinaryKey(name), Value: kpBinaryValue{Ref: id}})
+
+	return nil
+}
+
+// addString - adds the field to the entry. The names of the fields must be unique,
+// so a number is added to the name of the repeated field.
+func (e *kpEntry) addString(key string, value string, protected bool) {
+	name := key
+	for i := 2; e.hasString(name); i++ {
+		name = fmt.Sprintf(repeatedKeyFormat, key, i)
+	}
+	e.Strings = append(e.Strings, &kpString{Key: name, Value: kpValue{Protected: protected, Value: value}})
+}
+
+func (e *kpEntry) hasString(key string) bool {
+	for _, s := range e.Strings {
+		if s.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *kpEntry) uniqueBinaryKey(key string) string {
+	name := key
+	for i := 2; ; i++ {
+		unique := true
+		for _, b := range e.Binaries {
+			if b.Key == name {
+				unique = false
+				break
+			}
+		}
+		if unique {
+			return name
+		}
+		name = fmt.Sprintf(repeatedKeyFormat, key, i)
+	}
+}
+
+// repeatedKey - returns the original key of the field that was renamed by addString.
+func repeatedKey(key string, fields map[string]string) string {
+	m := repeatedKeyRe.FindStringSubmatch(key)
+	if m == nil {
+		return key
+	}
+	if _, ok := fields[m[1]]; !ok {
+		return key
+	}
+	return m[1]
+}
+
+// commonRootFolder - returns the top-level folder of the records if all the records have the same one.
+// Otherwise returns the name of the new root group.
+func commonRootFolder(records []*models.Record) string {
+	root := ""
+	for _, r := range records {
+		names := splitFolder(models.MetadataValue(r.Metadata, models.MetadataKeyFolder))
+		if len(names) == 0 || (root != "" && names[0] != root) {
+			return keepassRootGroup
+		}
+		root = names[0]
+	}
+	if root == "" {
+		return keepassRootGroup
+	}
+
+	return root
+}
+
+// newKeePassUUID - returns the UUID in the KeePass format. The ID of the record is used if it is a UUID.
+func newKeePassUUID(id string) string {
+	u, err := uuid.Parse(id)
+	if err != nil {
+		u = uuid.New()
+	}
+	return base64.StdEncoding.EncodeToString(u[:])
+}
+
+// escapeFolder - escapes the separator in the name of the folder.
+func escapeFolder(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, `\`, `\\`), folderSeparator, `\`+folderSeparator)
+}
+
+// splitFolder - splits the folder into the names of the nested folders.
+func splitFolder(folder string) []string {
+	if folder == "" {
+		return nil
+	}
+
+	var names []string
+	var name strings.Builder
+	escaped := false
+	for _, ch := range folder {
+		switch {
+		case escaped:
+			name.WriteRune(ch)
+			escaped = false
+		case ch == '\\':
+			escaped = true
+		case string(ch) == folderSeparator:
+			names = append(names, name.String())
+			name.Reset()
+		default:
+			name.WriteRune(ch)
+		}
+	}
+
+	return append(names, name.String())
+}
@@ -0,0 +1,228 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+const testKeePassXML = `<?xml version="1.0" encoding="utf-8" standalone="yes"?>
+<KeePassFile>
+	<Meta>
+		<Generator>KeePass</Generator>
+		<Binaries>
+			<Binary ID="0" Compressed="True">H4sIABY/1WoC/8tIzcnJV0gsKUlMzshNzSsBADcSvqMQAAAA</Binary>
+		</Binaries>
+	</Meta>
+	<Root>
+		<Group>
+			<UUID>AAAAAAAAAAAAAAAAAAAAAA==</UUID>
+			<Name>Database</Name>
+			<Entry>
+				<UUID>AQEBAQEBAQEBAQEBAQEBAQ==</UUID>
+				<Times><CreationTime>2023-01-01T00:00:00Z</CreationTime></Times>
+				<String><Key>Title</Key><Value>Mail</Value></String>
+				<String><Key>UserName</Key><Value>user</Value></String>
+				<String><Key>Password</Key><Value ProtectInMemory="True">secret</Value></String>
+				<String><Key>URL</Key><Value>https://mail.example.org</Value></String>
+				<String><Key>Notes</Key><Value></Value></String>
+				<String><Key>PIN</Key><Value>1234</Value></String>
+				<Binary><Key>readme.txt</Key><Value Ref="0"/></Binary>
+			</Entry>
+			<Group>
+				<UUID>AgICAgICAgICAgICAgICAg==</UUID>
+				<Name>Work/Projects</Name>
+				<Entry>
+					<UUID>AwMDAwMDAwMDAwMDAwMDAw==</UUID>
+					<String><Key>Title</Key><Value>VPN</Value></String>
+					<String><Key>UserName</Key><Value>admin</Value></String>
+					<String><Key>Password</Key><Value>pa55</Value></String>
+					<Binary><Key>cert.pem</Key><Value>aW5saW5l</Value></Binary>
+				</Entry>
+			</Group>
+		</Group>
+	</Root>
+</KeePassFile>`
+
+func TestParseKeePassXML(t *testing.T) {
+	res, err := Parse(strings.NewReader(testKeePassXML), FormatAuto)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if res.Format != FormatKeePass {
+		t.Errorf("Parse() format = %v, want %v", res.Format, FormatKeePass)
+	}
+
+	type want struct {
+		desc   string
+		data   models.RecordData
+		folder string
+		meta   map[string]string
+	}
+	wants := []want{
+		{
+			desc:   "Mail",
+			data:   &models.Auth{Login: "user", Password: "secret"},
+			folder: "Database",
+			meta:   map[string]string{models.MetadataKeyURL: "https://mail.example.org", "PIN": "1234"},
+		},
+		{
+			desc:   "readme.txt",
+			data:   &models.Binary{Name: "readme.txt", Ext: ".txt", Data: []byte("hello attachment")},
+			folder: "Database",
+		},
+		{
+			desc:   "VPN",
+			data:   &models.Auth{Login: "admin", Password: "pa55"},
+			folder: `Database/Work\/Projects`,
+		},
+		{
+			desc:   "cert.pem",
+			data:   &models.Binary{Name: "cert.pem", Ext: ".pem", Data: []byte("inline")},
+			folder: `Database/Work\/Projects`,
+		},
+	}
+	if len(res.Records) != len(wants) {
+		t.Fatalf("Parse() got %d records, want %d", len(res.Records), len(wants))
+	}
+
+	for i, w := range wants {
+		r := res.Records[i]
+		if r.Description != w.desc {
+			t.Errorf("Parse() description = %v, want %v", r.Description, w.desc)
+		}
+		d, err := r.DecodeData()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(d, w.data) {
+			t.Errorf("Parse() data = %v, want %v", d, w.data)
+		}
+		if got := models.MetadataValue(r.Metadata, models.MetadataKeyFolder); got != w.folder {
+			t.Errorf("Parse() folder = %v, want %v", got, w.folder)
+		}
+		for k, v := range w.meta {
+			if got := models.MetadataValue(r.Metadata, k); got != v {
+				t.Errorf("Parse() metadata %s = %v, want %v", k, got, v)
+			}
+		}
+	}
+}
+
+func TestKeePassXML_RoundTrip(t *testing.T) {
+	const entries = 3000
+
+	var rs []*models.Record
+	add := func(desc string, dt models.DataType, data models.RecordData, mi []*models.Metadata) {
+		rdto, err := models.NewRecordDTO(desc, dt, data, mi)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rs = append(rs, &models.Record{
+			ID:          uuid.NewString(),
+			Description: rdto.Description,
+			Type:        rdto.Type,
+			Data:        rdto.Data,
+			Hashsum:     rdto.Hashsum,
+			Metadata:    rdto.Metadata,
+			Version:     1,
+		})
+	}
+
+	for i := 0; i < entries; i++ {
+		folder := fmt.Sprintf("Root/Group %d/Sub %d", i%10, i%3)
+		add(fmt.Sprintf("entry %d", i), models.AuthType,
+			&models.Auth{Login: fmt.Sprintf("user%d", i), Password: uuid.NewString()},
+			[]*models.Metadata{
+				{Key: models.MetadataKeyFolder, Value: folder},
+				{Key: models.MetadataKeyURL, Value: fmt.Sprintf("https://site%d.example.com", i)},
+				{Key: models.MetadataKeyTOTP, Value: "JBSWY3DPEHPK3PXP"},
+				{Key: "tag", Value: "a"},
+				{Key: "tag", Value: "b"},
+			})
+	}
+	add("note", models.TextType, &models.Text{Data: "some\nlines"},
+		[]*models.Metadata{{Key: models.MetadataKeyFolder, Value: "Root"}})
+	add("card", models.CardType, &models.Card{Number: "4111", Owner: "OWNER", Term: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)},
+		[]*models.Metadata{{Key: models.MetadataKeyFolder, Value: "Root/Cards"}})
+	add("file.bin", models.BinaryType, &models.Binary{Name: "file.bin", Ext: ".bin", Data: []byte{0, 1, 2}},
+		[]*models.Metadata{{Key: models.MetadataKeyFolder, Value: "Root"}})
+
+	var b bytes.Buffer
+	if err := WriteKeePassXML(&b, rs); err != nil {
+		t.Fatalf("WriteKeePassXML() error = %v", err)
+	}
+
+	first, err := ParseKeePassXML(bytes.NewReader(b.Bytes()))
+	if err != nil {
+		t.Fatalf("ParseKeePassXML() error = %v", err)
+	}
+	if len(first.Records) != len(rs) {
+		t.Fatalf("ParseKeePassXML() got %d records, want %d", len(first.Records), len(rs))
+	}
+	if got, want := recordKeys(t, first.Records), recordKeys(t, rs); !reflect.DeepEqual(got, want) {
+		for i := range got {
+			if got[i] != want[i] {
+				t.Errorf("ParseKeePassXML() record = %v, want %v", got[i], want[i])
+				break
+			}
+		}
+	}
+
+	// The second round trip must not change anything, including the KeePass UUIDs.
+	rs2 := make([]*models.Record, len(first.Records))
+	for i, r := range first.Records {
+		rs2[i] = &models.Record{ID: uuid.NewString(), Description: r.Description, Type: r.Type,
+			Data: r.Data, Hashsum: r.Hashsum, Metadata: r.Metadata}
+	}
+	var b2 bytes.Buffer
+	if err := WriteKeePassXML(&b2, rs2); err != nil {
+		t.Fatalf("WriteKeePassXML() error = %v", err)
+	}
+	second, err := ParseKeePassXML(bytes.NewReader(b2.Bytes()))
+	if err != nil {
+		t.Fatalf("ParseKeePassXML() error = %v", err)
+	}
+	if !reflect.DeepEqual(second.Records, first.Records) {
+		t.Errorf("ParseKeePassXML() second round trip differs from the first one")
+	}
+}
+
+// recordKeys - returns the comparable representation of the records without the KeePass UUIDs.
+func recordKeys(t *testing.T, rs any) []string {
+	t.Helper()
+
+	var keys []string
+	add := func(desc string, typ string, hashsum string, mi []*models.Metadata) {
+		var ms []string
+		for _, m := range mi {
+			if m.Key != metadataKeyKeePassUUID {
+				ms = append(ms, m.Key+"="+m.Value)
+			}
+		}
+		sort.Strings(ms)
+		keys = append(keys, strings.Join(append([]string{desc, typ, hashsum}, ms...), "|"))
+	}
+
+	switch v := rs.(type) {
+	case []*models.Record:
+		for _, r := range v {
+			add(r.Description, r.Type, r.Hashsum, r.Metadata)
+		}
+	case []*models.RecordDTO:
+		for _, r := range v {
+			add(r.Description, r.Type, r.Hashsum, r.Metadata)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrUserDisabled - The error is returned if the user has been disabled by the administrator.
+var ErrUserDisabled = errors.New("user is disabled")
+
+// UserInfo - The account of the user as it is seen by the administrator of the server.
+type UserInfo struct {
+	// ID - uuid of the user.
+	ID string
+	// Login - the login of the user.
+	Login string
+	// Created - the date of the registration.
+	Created time.Time
+	// Disabled - the user cannot log in.
+	Disabled bool
+}
+
+// AdminStorage - The interface that the repository should implement for the administration of the server.
+type AdminStorage interface {
+	UsageReporter
+	// ListUsers - returns all the users sorted by the login.
+	ListUsers(ctx context.Context) ([]*UserInfo, error)
+	// GetUserInfo - returns the user by the login, ErrUnknowUser if the user is not found.
+	GetUserInfo(ctx context.Context, login string) (*UserInfo, error)
+	// SetUserDisabled - disables or enables the login of the user.
+	SetUserDisabled(ctx context.Context, userID string, disabled bool) error
+	// SetUserPassword - replaces the hash of the password of the user.
+	SetUserPassword(ctx context.Context, userID string, passwordHash string) error
+	// PurgeTombstones - removes the deleted records that have not been changed since the time
+	// and returns the number of the removed records.
+	PurgeTombstones(ctx context.Context, before time.Time) (int, error)
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// AuditAction - The action of the user that is written to the audit log.
+type AuditAction string
+
+const (
+	// AuditLogin - the user has logged in.
+	AuditLogin AuditAction = "login"
+	// AuditLoginFailed - the login of the user has been rejected, for example, the password is wrong.
+	AuditLoginFailed AuditAction = "login_failed"
+	// AuditRecordRead - the record has been read.
+	AuditRecordRead AuditAction = "record_read"
+	// AuditRecordCreate - the record has been created.
+	AuditRecordCreate AuditAction = "record_create"
+	// AuditRecordUpdate - the record has been updated.
+	AuditRecordUpdate AuditAction = "record_update"
+	// AuditRecordDelete - the record has been deleted.
+	AuditRecordDelete AuditAction = "record_delete"
+	// AuditExport - the records have been exported by the client.
+	AuditExport AuditAction = "export"
+	// AuditDeviceRegister - the device has been registered.
+	AuditDeviceRegister AuditAction = "device_register"
+	// AuditDeviceRevoke - the device has been revoked.
+	AuditDeviceRevoke AuditAction = "device_revoke"
+	// AuditUserDisable - the user has been disabled by the administrator.
+	AuditUserDisable AuditAction = "user_disable"
+	// AuditUserEnable - the user has been enabled by the administrator.
+	AuditUserEnable AuditAction = "user_enable"
+	// AuditPasswordReset - the password of the user has been reset by the administrator.
+	AuditPasswordReset AuditAction = "password_reset"
+)
+
+// AuditEvent - The action of the user in the audit log. The event never contains the secret contents of the records.
+type AuditEvent struct {
+	// ID - the sequence number of the event, the later events have the greater numbers.
+	ID int64
+	// UserID - uuid of the user.
+	UserID string
+	// Action - the action of the user.
+	Action AuditAction
+	// Time - indicates the date when the action was done.
+	Time time.Time
+	// DeviceID - uuid of the device that did the action, empty if the device is unknown.
+	DeviceID string
+	// DeviceName - the name of the device, it is filled in by the storage.
+	DeviceName string
+	// PeerIP - IP address of the client.
+	PeerIP string
+	// RecordID - uuid of the record for the actions with the records.
+	RecordID string
+	// Details - the additional information, for example, the reason of the failed login.
+	Details string
+}
+
+// AuditReader - The interface of the source of the audit log, the client of the server implements it as well.
+type AuditReader interface {
+	// ListAuditEvents - used to retrieving the events of the user, the latest events come first.
+	ListAuditEvents(ctx context.Context, userID string, offset int, limit int) ([]*AuditEvent, error)
+}
+
+// AuditStorage - The interface that the repository should implement for the audit log.
+// The log is append-only: the events are never changed or removed.
+type AuditStorage interface {
+	AuditReader
+	// AddAuditEvents - appends the events to the audit log.
+	AddAuditEvents(ctx context.Context, events []*AuditEvent) error
+}
+
+// GetAuditEvents - The method is used to get a page of the audit log of the user, the latest events come first.
+func (u *User) GetAuditEvents(ctx context.Context, db AuditReader, offset int, limit int) ([]*AuditEvent, error) {
+	es, err := db.ListAuditEvents(ctx, u.ID, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while retrieving audit events, err: %w", err)
+	}
+
+	return es, nil
+}
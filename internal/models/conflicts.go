@@ -0,0 +1,308 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/google/uuid"
+)
+
+// ConflictSide - Identifies which version of the record is chosen when the conflict is resolved.
+type ConflictSide int
+
+const (
+	// LocalSide - the version of the record from the client storage.
+	LocalSide ConflictSide = iota
+	// RemoteSide - the version of the record from the server storage.
+	RemoteSide
+)
+
+const (
+	// FieldDescription - the name of the description field of the record.
+	FieldDescription = "Description"
+	// FieldMetadata - the name of the metadata field of the record.
+	FieldMetadata = "Metadata"
+	// FieldData - the name of the field that contains the whole record data.
+	// Used when the versions of the record have different types.
+	FieldData = "Data"
+	// FieldLogin - the name of the login field of the Auth record.
+	FieldLogin = "Login"
+	// FieldPassword - the name of the password field of the Auth record.
+	FieldPassword = "Password"
+	// FieldText - the name of the text field of the Text record.
+	FieldText = "Text"
+	// FieldFile - the name of the file field of the Binary record.
+	FieldFile = "File"
+	// FieldNumber - the name of the number field of the Card record.
+	FieldNumber = "Number"
+	// FieldOwner - the name of the owner field of the Card record.
+	FieldOwner = "Owner"
+	// FieldTerm - the name of the term field of the Card record.
+	FieldTerm = "Term"
+	// FieldDeleted - the name of the field that indicates that the record has been deleted.
+	// Present only when one version of the record is deleted and the other is not.
+	FieldDeleted = "Deleted"
+)
+
+const cardTermFormat = "01/06"
+
+// ErrUnknowDataType - The error is returned if the record contains a data type unknown to the service.
+var ErrUnknowDataType = errors.New("unknow data type")
+
+// Conflict - Two versions of the same record that cannot be ordered by the version vector.
+type Conflict struct {
+	// Local - the version of the record from the client storage.
+	Local *Record
+	// Remote - the version of the record from the server storage.
+	Remote *Record
+}
+
+// ConflictField - One field of the record that differs or may differ between versions.
+type ConflictField struct {
+	// Name - the name of the field.
+	Name string
+	// Local - the printable value of the field in the local version of the record.
+	Local string
+	// Remote - the printable value of the field in the remote version of the record.
+	Remote string
+}
+
+// ConflictHandler - The interface that is notified about conflicts found during synchronization.
+type ConflictHandler interface {
+	// HandleConflict - called for every conflict found during synchronization.
+	// The handler must not block, the conflict is resolved later through the normal update path.
+	HandleConflict(ctx context.Context, c *Conflict)
+}
+
+// ID - Returns the ID of the conflicting record.
+func (c *Conflict) ID() string {
+	return c.Local.ID
+}
+
+// Fields - Returns the fields of the record that can be chosen separately when resolving the conflict.
+func (c *Conflict) Fields() ([]*ConflictField, error) {
+	var fs []*ConflictField
+	if c.Local.Deleted != c.Remote.Deleted {
+		fs = append(fs, &ConflictField{
+			Name:   FieldDeleted,
+			Local:  deletedString(c.Local.Deleted),
+			Remote: deletedString(c.Remote.Deleted),
+		})
+	}
+
+	fs = append(fs, &ConflictField{
+		Name:   FieldDescription,
+		Local:  c.Local.Description,
+		Remote: c.Remote.Description,
+	})
+
+	lds, err := dataFields(c.Local)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while retrieving fields of local record, err: %w", err)
+	}
+	rds, err := dataFields(c.Remote)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while retrieving fields of remote record, err: %w", err)
+	}
+
+	if c.Local.Type != c.Remote.Type {
+		fs = append(fs, &ConflictField{
+			Name:   FieldData,
+			Local:  c.Local.Type,
+			Remote: c.Remote.Type,
+		})
+	} else {
+		for i := range lds {
+			fs = append(fs, &ConflictField{
+				Name:   lds[i][0],
+				Local:  lds[i][1],
+				Remote: rds[i][1],
+			})
+		}
+	}
+
+	fs = append(fs, &ConflictField{
+		Name:   FieldMetadata,
+		Local:  metadataString(c.Local.Metadata),
+		Remote: metadataString(c.Remote.Metadata),
+	})
+
+	return fs, nil
+}
+
+// Resolve - Merges two versions of the record according to the chosen sides.
+// Fields that are not present in choices are taken from the local version.
+// The version of the resulting record is higher than both versions, so it wins during next synchronization.
+func (c *Conflict) Resolve(choices map[string]ConflictSide) (*Record, error) {
+	pick := func(field string) *Record {
+		if choices[field] == RemoteSide {
+			return c.Remote
+		}
+		return c.Local
+	}
+
+	var data RecordData
+	var dataType DataType
+	if c.Local.Type != c.Remote.Type {
+		src := pick(FieldData)
+		d, err := src.DecodeData()
+		if err != nil {
+			return nil, fmt.Errorf("an error occured while decode record data, err: %w", err)
+		}
+		data = d
+		dataType = DataType(src.Type)
+	} else {
+		d, err := mergeData(c.Local, c.Remote, pick)
+		if err != nil {
+			return nil, fmt.Errorf("an error occured while merge record data, err: %w", err)
+		}
+		data = d
+		dataType = DataType(c.Local.Type)
+	}
+
+	created := c.Local.Created
+	if c.Remote.Created.Before(created) {
+		created = c.Remote.Created
+	}
+
+	r, err := NewRecord(c.Local.ID,
+		pick(FieldDescription).Description,
+		dataType,
+		created,
+		time.Now(),
+		data,
+		pick(FieldMetadata).Metadata,
+		pick(FieldDeleted).Deleted,
+		max(c.Local.Version, c.Remote.Version)+1,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while create resolved record, err: %w", err)
+	}
+	r.Owner = c.Local.Owner
+
+	return r, nil
+}
+
+// KeepBoth - Resolves the conflict by keeping both versions of the record.
+// The remote version keeps the ID of the record, the local version is returned as a copy with a new ID.
+func (c *Conflict) KeepBoth() (*Record, *Record) {
+	cp := *c.Local
+	cp.ID = uuid.NewString()
+	cp.Description = fmt.Sprintf("(COPY) %s", c.Local.Description)
+
+	return c.Remote, &cp
+}
+
+func mergeData(local *Record, remote *Record, pick func(field string) *Record) (RecordData, error) {
+	decode := func(field string, v any) error {
+		if err := cbor.Unmarshal(pick(field).Data, v); err != nil {
+			return fmt.Errorf("an error occured while decode field %s, err: %w", field, err)
+		}
+		return nil
+	}
+
+	switch local.Type {
+	case string(AuthType):
+		var l, p Auth
+		if err := decode(FieldLogin, &l); err != nil {
+			return nil, err
+		}
+		if err := decode(FieldPassword, &p); err != nil {
+			return nil, err
+		}
+		return &Auth{Login: l.Login, Password: p.Password}, nil
+	case string(TextType):
+		var t Text
+		if err := decode(FieldText, &t); err != nil {
+			return nil, err
+		}
+		return &t, nil
+	case string(BinaryType):
+		var b Binary
+		if err := decode(FieldFile, &b); err != nil {
+			return nil, err
+		}
+		return &b, nil
+	case string(CardType):
+		var n, o, t Card
+		if err := decode(FieldNumber, &n); err != nil {
+			return nil, err
+		}
+		if err := decode(FieldOwner, &o); err != nil {
+			return nil, err
+		}
+		if err := decode(FieldTerm, &t); err != nil {
+			return nil, err
+		}
+		return &Card{Number: n.Number, Owner: o.Owner, Term: t.Term}, nil
+	default:
+		return nil, ErrUnknowDataType
+	}
+}
+
+// RecordField - A named printable value of the record data.
+type RecordField struct {
+	// Name - the name of the field, one of the Field constants.
+	Name string
+	// Value - the printable value of the field.
+	Value string
+}
+
+// DataFields - Returns the fields of the record data with their printable values.
+func (r *Record) DataFields() ([]RecordField, error) {
+	ds, err := dataFields(r)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while retrieving fields of record, err: %w", err)
+	}
+
+	fs := make([]RecordField, len(ds))
+	for i, d := range ds {
+		fs[i] = RecordField{Name: d[0], Value: d[1]}
+	}
+
+	return fs, nil
+}
+
+// dataFields - returns pairs of field name and printable field value of the record data.
+func dataFields(r *Record) ([][2]string, error) {
+	d, err := r.DecodeData()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := d.(type) {
+	case *Auth:
+		return [][2]string{{FieldLogin, v.Login}, {FieldPassword, v.Password}}, nil
+	case *Text:
+		return [][2]string{{FieldText, v.Data}}, nil
+	case *Binary:
+		return [][2]string{{FieldFile, fmt.Sprintf("%s (%d bytes)", v.Name, len(v.Data))}}, nil
+	case *Card:
+		return [][2]string{
+			{FieldNumber, v.Number},
+			{FieldOwner, v.Owner},
+			{FieldTerm, v.Term.Format(cardTermFormat)},
+		}, nil
+	default:
+		return nil, ErrUnknowDataType
+	}
+}
+
+func deletedString(deleted bool) string {
+	if deleted {
+		return "yes"
+	}
+	return "no"
+}
+
+func metadataString(mis []*Metadata) string {
+	ss := make([]string, len(mis))
+	for i, mi := range mis {
+		ss[i] = fmt.Sprintf("%s:%s", mi.Key, mi.Value)
+	}
+	return strings.Join(ss, "; ")
+}
@@ -0,0 +1,255 @@
+package models
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+type conflictRecorder struct {
+	mu        sync.Mutex
+	conflicts []*Conflict
+}
+
+func (cr *conflictRecorder) HandleConflict(ctx context.Context, c *Conflict) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	cr.conflicts = append(cr.conflicts, c)
+}
+
+func newAuthConflict(t *testing.T) *Conflict {
+	t.Helper()
+
+	local := generateAuthRecord(t)
+	remote, err := NewRecord(local.ID, uuid.NewString(), AuthType, local.Created, time.Now(),
+		&Auth{Login: uuid.NewString(), Password: uuid.NewString()}, nil, false, local.Version)
+	if err != nil {
+		t.Errorf("an error occured while generating remote record, err: %v", err)
+	}
+
+	return &Conflict{Local: local, Remote: remote}
+}
+
+func TestConflict_Fields(t *testing.T) {
+	c := newAuthConflict(t)
+
+	fs, err := c.Fields()
+	if err != nil {
+		t.Errorf("Conflict.Fields() error = %v", err)
+		return
+	}
+
+	var names []string
+	for _, f := range fs {
+		names = append(names, f.Name)
+	}
+	want := []string{FieldDescription, FieldLogin, FieldPassword, FieldMetadata}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Conflict.Fields() = %v, want %v", names, want)
+	}
+	if fs[0].Local != c.Local.Description || fs[0].Remote != c.Remote.Description {
+		t.Errorf("Conflict.Fields() description = %v, want %s/%s", fs[0], c.Local.Description, c.Remote.Description)
+	}
+}
+
+func TestConflict_Resolve(t *testing.T) {
+	c := newAuthConflict(t)
+
+	la, err := c.Local.DecodeData()
+	if err != nil {
+		t.Errorf("an error occured while decode local data, err: %v", err)
+	}
+	ra, err := c.Remote.DecodeData()
+	if err != nil {
+		t.Errorf("an error occured while decode remote data, err: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		choices  map[string]ConflictSide
+		wantDesc string
+		wantAuth *Auth
+		wantMeta []*Metadata
+	}{
+		{
+			name:     "all fields from local version",
+			choices:  map[string]ConflictSide{},
+			wantDesc: c.Local.Description,
+			wantAuth: la.(*Auth),
+			wantMeta: c.Local.Metadata,
+		},
+		{
+			name: "mixed fields",
+			choices: map[string]ConflictSide{
+				FieldDescription: RemoteSide,
+				FieldPassword:    RemoteSide,
+			},
+			wantDesc: c.Remote.Description,
+			wantAuth: &Auth{Login: la.(*Auth).Login, Password: ra.(*Auth).Password},
+			wantMeta: c.Local.Metadata,
+		},
+		{
+			name: "all fields from remote version",
+			choices: map[string]ConflictSide{
+				FieldDescription: RemoteSide,
+				FieldLogin:       RemoteSide,
+				FieldPassword:    RemoteSide,
+				FieldMetadata:    RemoteSide,
+			},
+			wantDesc: c.Remote.Description,
+			wantAuth: ra.(*Auth),
+			wantMeta: c.Remote.Metadata,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.Resolve(tt.choices)
+			if err != nil {
+				t.Errorf("Conflict.Resolve() error = %v", err)
+				return
+			}
+			if got.ID != c.Local.ID {
+				t.Errorf("Conflict.Resolve() ID = %s, want %s", got.ID, c.Local.ID)
+			}
+			if got.Version <= c.Local.Version || got.Version <= c.Remote.Version {
+				t.Errorf("Conflict.Resolve() version = %d must be higher than both versions", got.Version)
+			}
+			if got.Description != tt.wantDesc {
+				t.Errorf("Conflict.Resolve() description = %s, want %s", got.Description, tt.wantDesc)
+			}
+			d, err := got.DecodeData()
+			if err != nil {
+				t.Errorf("an error occured while decode resolved data, err: %v", err)
+			}
+			if !reflect.DeepEqual(d, tt.wantAuth) {
+				t.Errorf("Conflict.Resolve() data = %v, want %v", d, tt.wantAuth)
+			}
+			if !reflect.DeepEqual(got.Metadata, tt.wantMeta) {
+				t.Errorf("Conflict.Resolve() metadata = %v, want %v", got.Metadata, tt.wantMeta)
+			}
+		})
+	}
+}
+
+func TestConflict_KeepBoth(t *testing.T) {
+	c := newAuthConflict(t)
+
+	remote, local := c.KeepBoth()
+	if remote != c.Remote {
+		t.Errorf("Conflict.KeepBoth() remote = %v, want %v", remote, c.Remote)
+	}
+	if local.ID == c.Local.ID {
+		t.Errorf("Conflict.KeepBoth() the copy of local record must have a new ID")
+	}
+	if local.Hashsum != c.Local.Hashsum {
+		t.Errorf("Conflict.KeepBoth() hashsum = %s, want %s", local.Hashsum, c.Local.Hashsum)
+	}
+}
+
+func TestUser_SyncRecords(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	local := NewMockRecordStorage(ctrl)
+	remote := NewMockRecordStorage(ctrl)
+
+	u := &User{ID: uuid.NewString()}
+	c := newAuthConflict(t)
+
+	local.EXPECT().ListRecords(gomock.Any(), u.ID, 0, DefaultLimit).Return([]*Record{c.Local}, nil)
+	local.EXPECT().ListRecords(gomock.Any(), u.ID, DefaultLimit, DefaultLimit).Return(nil, nil)
+	remote.EXPECT().ListRecords(gomock.Any(), u.ID, 0, DefaultLimit).Return([]*Record{c.Remote}, nil)
+	remote.EXPECT().ListRecords(gomock.Any(), u.ID, DefaultLimit, DefaultLimit).Return(nil, nil)
+	local.EXPECT().BatchGetRecords(gomock.Any(), u.ID, []string{c.ID()}).
+		Return([]*BatchResult{{ID: c.ID(), Record: c.Local}}, nil)
+	remote.EXPECT().BatchGetRecords(gomock.Any(), u.ID, []string{c.ID()}).
+		Return([]*BatchResult{{ID: c.ID(), Record: c.Remote}}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cr := &conflictRecorder{}
+	if err := u.SyncRecords(ctx, local, remote, 1, cr); err != nil {
+		t.Errorf("User.SyncRecords() error = %v", err)
+		return
+	}
+
+	if len(cr.conflicts) != 2 {
+		t.Errorf("User.SyncRecords() reported %d conflicts, want 2", len(cr.conflicts))
+		return
+	}
+	for _, got := range cr.conflicts {
+		if got.Local != c.Local || got.Remote != c.Remote {
+			t.Errorf("User.SyncRecords() conflict = %v, want %v", got, c)
+		}
+	}
+}
+
+func TestUser_SyncRecordsDeleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	local := NewMockRecordStorage(ctrl)
+	remote := NewMockRecordStorage(ctrl)
+
+	u := &User{ID: uuid.NewString()}
+	c := newAuthConflict(t)
+
+	lr := *c.Local
+	lr.Version = 1
+	rr := lr
+	rr.MarkDeleted()
+
+	local.EXPECT().ListRecords(gomock.Any(), u.ID, 0, DefaultLimit).Return([]*Record{&rr}, nil)
+	local.EXPECT().ListRecords(gomock.Any(), u.ID, DefaultLimit, DefaultLimit).Return(nil, nil)
+	remote.EXPECT().ListRecords(gomock.Any(), u.ID, 0, DefaultLimit).Return([]*Record{&rr}, nil)
+	remote.EXPECT().ListRecords(gomock.Any(), u.ID, DefaultLimit, DefaultLimit).Return(nil, nil)
+	local.EXPECT().BatchGetRecords(gomock.Any(), u.ID, []string{lr.ID}).
+		Return([]*BatchResult{{ID: lr.ID, Record: &lr}}, nil)
+	local.EXPECT().BatchUpsertRecords(gomock.Any(), u.ID, []*Record{&rr}).
+		Return([]*BatchResult{{ID: rr.ID}}, nil)
+	remote.EXPECT().BatchGetRecords(gomock.Any(), u.ID, []string{rr.ID}).
+		Return([]*BatchResult{{ID: rr.ID, Record: &rr}}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cr := &conflictRecorder{}
+	if err := u.SyncRecords(ctx, local, remote, 1, cr); err != nil {
+		t.Errorf("User.SyncRecords() error = %v", err)
+		return
+	}
+
+	if len(cr.conflicts) != 0 {
+		t.Errorf("User.SyncRecords() reported %d conflicts, want 0", len(cr.conflicts))
+	}
+}
+
+func TestConflict_ResolveDeleted(t *testing.T) {
+	c := newAuthConflict(t)
+	c.Remote.Deleted = true
+
+	fs, err := c.Fields()
+	if err != nil {
+		t.Errorf("Conflict.Fields() error = %v", err)
+		return
+	}
+	if fs[0].Name != FieldDeleted {
+		t.Errorf("Conflict.Fields() first field = %s, want %s", fs[0].Name, FieldDeleted)
+	}
+
+	for _, tt := range []struct {
+		side ConflictSide
+		want bool
+	}{{LocalSide, false}, {RemoteSide, true}} {
+		got, err := c.Resolve(map[string]ConflictSide{FieldDeleted: tt.side})
+		if err != nil {
+			t.Errorf("Conflict.Resolve() error = %v", err)
+			return
+		}
+		if got.Deleted != tt.want {
+			t.Errorf("Conflict.Resolve() deleted = %v, want %v", got.Deleted, tt.want)
+		}
+	}
+}
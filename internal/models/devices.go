@@ -0,0 +1,187 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+)
+
+// ErrDeviceNotFound - An error that is returned in case when device not found in database.
+var ErrDeviceNotFound = errors.New("device not found")
+
+// ErrDeviceRevoked - An error that is returned in case when the device has been revoked by the user.
+var ErrDeviceRevoked = errors.New("device has been revoked")
+
+// DeviceStorage - The interface that the repository should implement for the registry of user devices.
+type DeviceStorage interface {
+	// AddDevice - registers the device of the user.
+	// If the user already has an active device with the same name, this device is returned.
+	AddDevice(ctx context.Context, userID string, device *DeviceDTO) (*Device, error)
+	// GetDevice - used to retrieving device.
+	GetDevice(ctx context.Context, userID string, deviceID string) (*Device, error)
+	// ListDevices - used to retrieving user devices.
+	ListDevices(ctx context.Context, userID string) ([]*Device, error)
+	// RevokeDevice - mark device as revoked.
+	RevokeDevice(ctx context.Context, userID string, deviceID string) error
+	// TouchDevice - updates the date of the last request from the device
+	// and, if synced is true, the date of the last synchronization.
+	TouchDevice(ctx context.Context, userID string, deviceID string, synced bool) error
+}
+
+// UserStatusReader - The interface that the repository may implement to reject the devices of the users
+// that have been disabled by the administrator.
+type UserStatusReader interface {
+	// UserDisabled - returns true if the user has been disabled, ErrUnknowUser if the user is not found.
+	UserDisabled(ctx context.Context, userID string) (bool, error)
+}
+
+// DeviceDTO - Data transfer object for Device.
+type DeviceDTO struct {
+	// Name - the name of the device, by default the hostname of the client.
+	Name string `cbor:"name"`
+	// Platform - operating system and architecture of the client.
+	Platform string `cbor:"platform"`
+	// Version - build information of the client.
+	Version string `cbor:"version"`
+	// Login, Password - the credentials of the user, the server registers the device only if they are correct.
+	// They are not stored.
+	Login    string `cbor:"-"`
+	Password string `cbor:"-"`
+}
+
+// NewDeviceDTO - Object Constructor. The device name is the hostname of the client,
+// the platform is the operating system and architecture of the client.
+func NewDeviceDTO(version string) *DeviceDTO {
+	name, err := os.Hostname()
+	if err != nil || name == "" {
+		name = "unknown"
+	}
+
+	return &DeviceDTO{
+		Name:     name,
+		Platform: fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+		Version:  version,
+	}
+}
+
+// Device - The object contains information about the client application registered by the user.
+type Device struct {
+	// ID - uuid device in database storage.
+	ID string `cbor:"uuid"`
+	// Owner - id of the user owner of the device in the database.
+	Owner string `cbor:"user"`
+	// Name - the name of the device, by default the hostname of the client.
+	Name string `cbor:"name"`
+	// Platform - operating system and architecture of the client.
+	Platform string `cbor:"platform"`
+	// Version - build information of the client.
+	Version string `cbor:"version"`
+	// Created - indicates the date when the device was registered.
+	Created time.Time `cbor:"created"`
+	// LastSeen - indicates the date of the last request from the device.
+	LastSeen time.Time `cbor:"last_seen"`
+	// LastSync - indicates the date of the last successful synchronization of the device.
+	LastSync time.Time `cbor:"last_sync"`
+	// Revoked - this flag indicates that the device has been revoked and its requests are rejected.
+	Revoked bool `cbor:"revoked"`
+}
+
+// RegisterDevice - This method is used to register the device of the user.
+func (u *User) RegisterDevice(ctx context.Context, db DeviceStorage, device *DeviceDTO) (*Device, error) {
+	d, err := db.AddDevice(ctx, u.ID, device)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while register device, err: %w", err)
+	}
+
+	return d, nil
+}
+
+// CheckDeviceName - This method checks that the user has not revoked a device with the same name.
+// The revoked device must not be registered again, otherwise the revocation is bypassed by the new registration.
+func (u *User) CheckDeviceName(ctx context.Context, db DeviceStorage, name string) error {
+	ds, err := db.ListDevices(ctx, u.ID)
+	if err != nil {
+		return fmt.Errorf("an error occured while retrieving devices, err: %w", err)
+	}
+
+	for _, d := range ds {
+		if d.Name == name && d.Revoked {
+			return ErrDeviceRevoked
+		}
+	}
+
+	return nil
+}
+
+// GetDevices - The method is used to get a list of user devices from the storage.
+func (u *User) GetDevices(ctx context.Context, db DeviceStorage) ([]*Device, error) {
+	ds, err := db.ListDevices(ctx, u.ID)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while retrieving devices, err: %w", err)
+	}
+
+	return ds, nil
+}
+
+// RevokeDevice - This method is used to revoke the device of the user.
+// All subsequent requests from the device are rejected.
+func (u *User) RevokeDevice(ctx context.Context, db DeviceStorage, deviceID string) error {
+	if deviceID == "" {
+		return ErrDeviceNotFound
+	}
+	if err := db.RevokeDevice(ctx, u.ID, deviceID); err != nil {
+		return fmt.Errorf("an error occured while revoke device, err: %w", err)
+	}
+
+	return nil
+}
+
+// CheckEnabled - This method returns ErrUserDisabled if the user has been disabled by the administrator.
+// The user is checked only if the storage implements UserStatusReader.
+func (u *User) CheckEnabled(ctx context.Context, db DeviceStorage) error {
+	sr, ok := db.(UserStatusReader)
+	if !ok {
+		return nil
+	}
+
+	disabled, err := sr.UserDisabled(ctx, u.ID)
+	if err != nil {
+		return fmt.Errorf("an error occured while retrieving user status, err: %w", err)
+	}
+	if disabled {
+		return ErrUserDisabled
+	}
+
+	return nil
+}
+
+// CheckDevice - This method checks that the user is not disabled and the device belongs to the user
+// and has not been revoked.
+// The date of the last request from the device is updated.
+func (u *User) CheckDevice(ctx context.Context, db DeviceStorage, deviceID string, synced bool) error {
+	if deviceID == "" {
+		return ErrDeviceNotFound
+	}
+
+	if err := u.CheckEnabled(ctx, db); err != nil {
+		return err
+	}
+
+	d, err := db.GetDevice(ctx, u.ID, deviceID)
+	if err != nil {
+		return fmt.Errorf("an error occured while retrieving device, err: %w", err)
+	}
+
+	if d.Revoked {
+		return ErrDeviceRevoked
+	}
+
+	if err := db.TouchDevice(ctx, u.ID, deviceID, synced); err != nil {
+		return fmt.Errorf("an error occured while touch device, err: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,255 @@
+package models
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SortField - The field the record list is sorted by.
+type SortField string
+
+const (
+	// SortRelevance - the best matches of the query first, the order of the storage without the query.
+	SortRelevance   SortField = "relevance"
+	SortDescription SortField = "description"
+	SortType        SortField = "type"
+	SortCreated     SortField = "created"
+	SortModified    SortField = "modified"
+	SortVersion     SortField = "version"
+)
+
+// SortFields - The fields the record list can be sorted by.
+var SortFields = []SortField{SortRelevance, SortDescription, SortType, SortCreated, SortModified, SortVersion}
+
+// DataTypes - All types of the records.
+var DataTypes = []DataType{AuthType, TextType, BinaryType, CardType}
+
+// RecordFilter - The filter of the record list.
+type RecordFilter struct {
+	// Query - the words that must all be found in the description, type, login or metadata of the record.
+	// The characters of a word may be separated by other characters, closer matches are ranked higher.
+	Query string
+	// Types - the types of the records to show, all types if empty.
+	Types  []DataType
+	SortBy SortField
+	// Desc - sort in descending order.
+	Desc bool
+}
+
+// The weights of the fields in the ranking.
+const (
+	weightDescription = 3
+	weightLogin       = 2
+	weightOther       = 1
+)
+
+// The score of the fuzzy match.
+const (
+	scoreChar        = 1
+	scoreConsecutive = 4
+	scoreWordStart   = 6
+	scoreSubstring   = 20
+	scorePrefix      = 10
+	penaltyGap       = 1
+)
+
+type indexField struct {
+	text   string
+	weight int
+}
+
+type indexedRecord struct {
+	record *Record
+	fields []indexField
+}
+
+// RecordIndex - The list of the records prepared for filtering. The data of the records is decoded once.
+type RecordIndex struct {
+	records []indexedRecord
+}
+
+// NewRecordIndex - Object Constructor.
+func NewRecordIndex(rs []*Record) *RecordIndex {
+	idx := &RecordIndex{records: make([]indexedRecord, len(rs))}
+	for i, r := range rs {
+		fields := []indexField{
+			{text: strings.ToLower(r.Description), weight: weightDescription},
+			{text: strings.ToLower(r.Type), weight: weightOther},
+		}
+		if d, err := r.DecodeData(); err == nil {
+			if a, ok := d.(*Auth); ok {
+				fields = append(fields, indexField{text: strings.ToLower(a.Login), weight: weightLogin})
+			}
+		}
+		for _, m := range r.Metadata {
+			fields = append(fields,
+				indexField{text: strings.ToLower(m.Key), weight: weightOther},
+				indexField{text: strings.ToLower(m.Value), weight: weightOther})
+		}
+		idx.records[i] = indexedRecord{record: r, fields: fields}
+	}
+	return idx
+}
+
+// Len - Returns the number of the records of the index.
+func (idx *RecordIndex) Len() int {
+	return len(idx.records)
+}
+
+// Filter - Returns the records that match the filter in the order of the filter.
+func (idx *RecordIndex) Filter(f RecordFilter) []*Record {
+	words := strings.Fields(strings.ToLower(f.Query))
+
+	type match struct {
+		record *Record
+		score  int
+	}
+	var ms []match
+	for _, ir := range idx.records {
+		if !typeSelected(ir.record.Type, f.Types) {
+			continue
+		}
+		score, ok := ir.score(words)
+		if !ok {
+			continue
+		}
+		ms = append(ms, match{record: ir.record, score: score})
+	}
+
+	if f.SortBy == SortRelevance || f.SortBy == "" {
+		sort.SliceStable(ms, func(i, j int) bool { return ms[i].score > ms[j].score })
+	}
+
+	rs := make([]*Record, len(ms))
+	for i, m := range ms {
+		rs[i] = m.record
+	}
+	SortRecords(rs, f.SortBy, f.Desc)
+
+	return rs
+}
+
+// score - every word must match at least one field, the best match of every word is counted.
+func (ir *indexedRecord) score(words []string) (int, bool) {
+	total := 0
+	for _, w := range words {
+		best := 0
+		for _, f := range ir.fields {
+			if s, ok := fuzzyScore(w, f.text); ok && s*f.weight > best {
+				best = s * f.weight
+			}
+		}
+		if best == 0 {
+			return 0, false
+		}
+		total += best
+	}
+	return total, true
+}
+
+func typeSelected(t string, types []DataType) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, dt := range types {
+		if string(dt) == t {
+			return true
+		}
+	}
+	return false
+}
+
+// fuzzyScore - matches the characters of the pattern in the same order in the text.
+// Substrings, prefixes, consecutive characters and characters at the start of words score higher.
+func fuzzyScore(pattern string, text string) (int, bool) {
+	if pattern == "" {
+		return scoreChar, true
+	}
+
+	if i := strings.Index(text, pattern); i >= 0 {
+		score := scoreSubstring + utf8.RuneCountInString(pattern)*(scoreChar+scoreConsecutive)
+		if i == 0 {
+			score += scorePrefix
+		}
+		if isWordStart(text, i) {
+			score += scoreWordStart
+		}
+		return score, true
+	}
+
+	score := 0
+	ti := 0
+	prev := -1
+	for _, pr := range pattern {
+		found := false
+		for ti < len(text) {
+			tr, size := utf8.DecodeRuneInString(text[ti:])
+			pos := ti
+			ti += size
+			if tr != pr {
+				continue
+			}
+
+			score += scoreChar
+			switch {
+			case pos == prev:
+				score += scoreConsecutive
+			case prev >= 0:
+				score -= penaltyGap
+			}
+			if isWordStart(text, pos) {
+				score += scoreWordStart
+			}
+			prev = ti
+			found = true
+			break
+		}
+		if !found {
+			return 0, false
+		}
+	}
+
+	return max(score, scoreChar), true
+}
+
+// isWordStart - the character at the position starts the text or follows a non-alphanumeric character.
+func isWordStart(text string, pos int) bool {
+	if pos == 0 {
+		return true
+	}
+	r, _ := utf8.DecodeLastRuneInString(text[:pos])
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// SortRecords - Sorts the records by the field. The records with equal fields keep their order.
+func SortRecords(rs []*Record, by SortField, desc bool) {
+	var less func(a, b *Record) bool
+	switch by {
+	case SortDescription:
+		less = func(a, b *Record) bool { return strings.ToLower(a.Description) < strings.ToLower(b.Description) }
+	case SortType:
+		less = func(a, b *Record) bool { return a.Type < b.Type }
+	case SortCreated:
+		less = func(a, b *Record) bool { return a.Created.Before(b.Created) }
+	case SortModified:
+		less = func(a, b *Record) bool { return a.Modified.Before(b.Modified) }
+	case SortVersion:
+		less = func(a, b *Record) bool { return a.Version < b.Version }
+	default:
+		if desc {
+			for i, j := 0, len(rs)-1; i < j; i, j = i+1, j-1 {
+				rs[i], rs[j] = rs[j], rs[i]
+			}
+		}
+		return
+	}
+
+	sort.SliceStable(rs, func(i, j int) bool {
+		if desc {
+			return less(rs[j], rs[i])
+		}
+		return less(rs[i], rs[j])
+	})
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRecordIndex_Filter(t *testing.T) {
+	newRecord := func(desc string, dt DataType, data RecordData, mi []*Metadata, created time.Time) *Record {
+		r, err := NewRecord(desc, desc, dt, created, created, data, mi, false, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	day := func(d int) time.Time { return time.Date(2023, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	rs := []*Record{
+		newRecord("Mail", AuthType, &Auth{Login: "john@example.org", Password: "p"},
+			[]*Metadata{{Key: MetadataKeyURL, Value: "https://mail.example.org"}}, day(3)),
+		newRecord("Github", AuthType, &Auth{Login: "octocat", Password: "p"}, nil, day(1)),
+		newRecord("Grocery list", TextType, &Text{Data: "milk"}, nil, day(2)),
+		newRecord("Visa", CardType, &Card{Number: "4111", Owner: "JOHN"},
+			[]*Metadata{{Key: "bank", Value: "Example bank"}}, day(4)),
+	}
+	idx := NewRecordIndex(rs)
+
+	tests := []struct {
+		name string
+		f    RecordFilter
+		want []string
+	}{
+		{
+			name: "empty",
+			f:    RecordFilter{},
+			want: []string{"Mail", "Github", "Grocery list", "Visa"},
+		},
+		{
+			name: "description prefix ranked first",
+			f:    RecordFilter{Query: "g"},
+			want: []string{"Github", "Grocery list", "Mail"},
+		},
+		{
+			name: "fuzzy",
+			f:    RecordFilter{Query: "gthb"},
+			want: []string{"Github"},
+		},
+		{
+			name: "login",
+			f:    RecordFilter{Query: "octo"},
+			want: []string{"Github"},
+		},
+		{
+			name: "metadata and all words",
+			f:    RecordFilter{Query: "example john"},
+			want: []string{"Mail"},
+		},
+		{
+			name: "type",
+			f:    RecordFilter{Query: "card"},
+			want: []string{"Visa"},
+		},
+		{
+			name: "types",
+			f:    RecordFilter{Types: []DataType{TextType, CardType}},
+			want: []string{"Grocery list", "Visa"},
+		},
+		{
+			name: "no match",
+			f:    RecordFilter{Query: "zzz"},
+			want: nil,
+		},
+		{
+			name: "sort by description",
+			f:    RecordFilter{SortBy: SortDescription},
+			want: []string{"Github", "Grocery list", "Mail", "Visa"},
+		},
+		{
+			name: "sort by created descending",
+			f:    RecordFilter{Query: "example", SortBy: SortCreated, Desc: true},
+			want: []string{"Visa", "Mail"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, r := range idx.Filter(tt.f) {
+				got = append(got, r.Description)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RecordIndex.Filter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		pattern string
+		better  string
+		worse   string
+	}{
+		{pattern: "mail", better: "mail", worse: "gmail"},
+		{pattern: "gh", better: "ghost", worse: "laugh"},
+		{pattern: "abc", better: "abxc", worse: "axbxxxxc"},
+	}
+	for _, tt := range tests {
+		b, okb := fuzzyScore(tt.pattern, tt.better)
+		w, okw := fuzzyScore(tt.pattern, tt.worse)
+		if !okb || !okw || b <= w {
+			t.Errorf("fuzzyScore(%q) = %v for %q and %v for %q", tt.pattern, b, tt.better, w, tt.worse)
+		}
+	}
+	if _, ok := fuzzyScore("ba", "abc"); ok {
+		t.Errorf("fuzzyScore() matches characters out of order")
+	}
+}
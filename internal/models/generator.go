@@ -0,0 +1,225 @@
+package models
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The limits and the defaults of the generator.
+const (
+	DefaultPasswordLength  = 20
+	MaxPasswordLength      = 128
+	DefaultPassphraseWords = 6
+	MaxPassphraseWords     = 32
+	DefaultWordSeparator   = "-"
+)
+
+// The character classes of the generated passwords.
+const (
+	lowerChars  = "abcdefghijklmnopqrstuvwxyz"
+	upperChars  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars  = "0123456789"
+	symbolChars = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+	// similarChars - the characters that are easily confused with each other.
+	similarChars = "Il1|O0o`'\""
+)
+
+// effWordList - EFF's large word list for the passphrases (CC BY 3.0),
+// https://www.eff.org/deeplinks/2016/07/new-wordlists-random-passphrases.
+//
+//go:embed eff_large_wordlist.txt
+var effWordList string
+
+var wordList = strings.Fields(effWordList)
+
+// ErrGeneratorOptions - The error is returned if the secret cannot be generated with the options.
+var ErrGeneratorOptions = errors.New("invalid generator options")
+
+// PasswordOptions - The options of the password generator.
+// The password contains at least one character of every selected class.
+type PasswordOptions struct {
+	Length  int
+	Lower   bool
+	Upper   bool
+	Digits  bool
+	Symbols bool
+	// ExcludeSimilar - do not use look-alike characters such as l, 1, O and 0.
+	ExcludeSimilar bool
+}
+
+// DefaultPasswordOptions - Returns the options of the password with all character classes.
+func DefaultPasswordOptions() PasswordOptions {
+	return PasswordOptions{
+		Length:         DefaultPasswordLength,
+		Lower:          true,
+		Upper:          true,
+		Digits:         true,
+		Symbols:        true,
+		ExcludeSimilar: true,
+	}
+}
+
+// PassphraseOptions - The options of the diceware-style passphrase generator.
+type PassphraseOptions struct {
+	Words     int
+	Separator string
+	// Capitalize - capitalize the first letter of every word.
+	Capitalize bool
+	// Digit - append a random digit to one of the words.
+	Digit bool
+}
+
+// DefaultPassphraseOptions - Returns the options of the passphrase of six words.
+func DefaultPassphraseOptions() PassphraseOptions {
+	return PassphraseOptions{
+		Words:     DefaultPassphraseWords,
+		Separator: DefaultWordSeparator,
+	}
+}
+
+// GeneratedSecret - The generated password or passphrase.
+type GeneratedSecret struct {
+	Value string
+	// Entropy - The entropy of the generator in bits.
+	Entropy float64
+}
+
+// GeneratePassword - Generates the random password.
+func GeneratePassword(opts PasswordOptions) (*GeneratedSecret, error) {
+	classes := opts.classes()
+	if len(classes) == 0 {
+		return nil, fmt.Errorf("%w: at least one character class is required", ErrGeneratorOptions)
+	}
+	if opts.Length < len(classes) || opts.Length > MaxPasswordLength {
+		return nil, fmt.Errorf("%w: the length must be from %d to %d", ErrGeneratorOptions, len(classes), MaxPasswordLength)
+	}
+
+	alphabet := strings.Join(classes, "")
+
+	// Passwords without some of the classes are rejected, so every valid password is equally likely.
+	b := make([]byte, opts.Length)
+	for {
+		for i := range b {
+			n, err := randomInt(len(alphabet))
+			if err != nil {
+				return nil, err
+			}
+			b[i] = alphabet[n]
+		}
+		if containsAll(string(b), classes) {
+			break
+		}
+	}
+
+	return &GeneratedSecret{Value: string(b), Entropy: passwordEntropy(opts.Length, classes)}, nil
+}
+
+func (opts PasswordOptions) classes() []string {
+	var classes []string
+	add := func(selected bool, chars string) {
+		if !selected {
+			return
+		}
+		if opts.ExcludeSimilar {
+			chars = strings.Map(func(r rune) rune {
+				if strings.ContainsRune(similarChars, r) {
+					return -1
+				}
+				return r
+			}, chars)
+		}
+		classes = append(classes, chars)
+	}
+
+	add(opts.Lower, lowerChars)
+	add(opts.Upper, upperChars)
+	add(opts.Digits, digitChars)
+	add(opts.Symbols, symbolChars)
+
+	return classes
+}
+
+func containsAll(s string, classes []string) bool {
+	for _, c := range classes {
+		if !strings.ContainsAny(s, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// passwordEntropy - log2 of the number of the passwords that contain every class,
+// counted by the inclusion-exclusion principle.
+func passwordEntropy(length int, classes []string) float64 {
+	total := 0
+	for _, c := range classes {
+		total += len(c)
+	}
+
+	// share - the share of the passwords of the alphabet that contain every class.
+	share := 0.0
+	for mask := 0; mask < 1<<len(classes); mask++ {
+		missing, sign := 0, 1.0
+		for i, c := range classes {
+			if mask&(1<<i) != 0 {
+				missing += len(c)
+				sign = -sign
+			}
+		}
+		share += sign * math.Pow(float64(total-missing)/float64(total), float64(length))
+	}
+
+	return float64(length)*math.Log2(float64(total)) + math.Log2(share)
+}
+
+// GeneratePassphrase - Generates the passphrase of the words of EFF's large word list.
+func GeneratePassphrase(opts PassphraseOptions) (*GeneratedSecret, error) {
+	if opts.Words < 1 || opts.Words > MaxPassphraseWords {
+		return nil, fmt.Errorf("%w: the number of words must be from 1 to %d", ErrGeneratorOptions, MaxPassphraseWords)
+	}
+
+	words := make([]string, opts.Words)
+	for i := range words {
+		n, err := randomInt(len(wordList))
+		if err != nil {
+			return nil, err
+		}
+		words[i] = wordList[n]
+		if opts.Capitalize {
+			r, size := utf8.DecodeRuneInString(words[i])
+			words[i] = string(unicode.ToUpper(r)) + words[i][size:]
+		}
+	}
+	entropy := float64(opts.Words) * math.Log2(float64(len(wordList)))
+
+	if opts.Digit {
+		w, err := randomInt(len(words))
+		if err != nil {
+			return nil, err
+		}
+		d, err := randomInt(len(digitChars))
+		if err != nil {
+			return nil, err
+		}
+		words[w] += string(digitChars[d])
+		entropy += math.Log2(float64(len(words) * len(digitChars)))
+	}
+
+	return &GeneratedSecret{Value: strings.Join(words, opts.Separator), Entropy: entropy}, nil
+}
+
+// randomInt - returns the uniform random number in [0, n) from the cryptographically secure source.
+func randomInt(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("an error occured while generate random number, err: %w", err)
+	}
+	return int(v.Int64()), nil
+}
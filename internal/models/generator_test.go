@@ -0,0 +1,171 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestGeneratePassword(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        PasswordOptions
+		wantEntropy float64
+		wantErr     error
+	}{
+		{
+			name:        "default",
+			opts:        DefaultPasswordOptions(),
+			wantEntropy: 127.6,
+		},
+		{
+			name:        "digits and lowercase letters",
+			opts:        PasswordOptions{Length: 2, Lower: true, Digits: true},
+			wantEntropy: math.Log2(2 * 10 * 26),
+		},
+		{
+			name:        "digits only",
+			opts:        PasswordOptions{Length: 10, Digits: true, ExcludeSimilar: true},
+			wantEntropy: 10 * math.Log2(8),
+		},
+		{
+			name:    "no classes",
+			opts:    PasswordOptions{Length: 10},
+			wantErr: ErrGeneratorOptions,
+		},
+		{
+			name:    "too short",
+			opts:    PasswordOptions{Length: 3, Lower: true, Upper: true, Digits: true, Symbols: true},
+			wantErr: ErrGeneratorOptions,
+		},
+		{
+			name:    "too long",
+			opts:    PasswordOptions{Length: MaxPasswordLength + 1, Lower: true},
+			wantErr: ErrGeneratorOptions,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				got, err := GeneratePassword(tt.opts)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GeneratePassword() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if err != nil {
+					return
+				}
+
+				if len(got.Value) != tt.opts.Length {
+					t.Errorf("GeneratePassword() = %q, want length %d", got.Value, tt.opts.Length)
+				}
+				if math.Abs(got.Entropy-tt.wantEntropy) > 0.1 {
+					t.Errorf("GeneratePassword() entropy = %v, want %v", got.Entropy, tt.wantEntropy)
+				}
+				checkClass := func(selected bool, chars string) {
+					if selected != strings.ContainsAny(got.Value, chars) {
+						t.Errorf("GeneratePassword() = %q, the class %q is selected: %v", got.Value, chars, selected)
+					}
+				}
+				checkClass(tt.opts.Lower, lowerChars)
+				checkClass(tt.opts.Upper, upperChars)
+				checkClass(tt.opts.Digits, digitChars)
+				checkClass(tt.opts.Symbols, symbolChars)
+				if tt.opts.ExcludeSimilar && strings.ContainsAny(got.Value, similarChars) {
+					t.Errorf("GeneratePassword() = %q contains look-alike characters", got.Value)
+				}
+			}
+		})
+	}
+}
+
+func TestGeneratePassphrase(t *testing.T) {
+	// The EFF large word list contains 7776 words, one for every roll of five dice.
+	if len(wordList) != 7776 {
+		t.Fatalf("the word list contains %d words, want 7776", len(wordList))
+	}
+	words := make(map[string]bool, len(wordList))
+	for _, w := range wordList {
+		if words[w] {
+			t.Errorf("the word list contains the duplicate %q", w)
+		}
+		words[w] = true
+	}
+
+	tests := []struct {
+		name        string
+		opts        PassphraseOptions
+		wantEntropy float64
+		wantErr     error
+	}{
+		{
+			name:        "default",
+			opts:        DefaultPassphraseOptions(),
+			wantEntropy: 77.5,
+		},
+		{
+			name:        "capitalized with digit",
+			opts:        PassphraseOptions{Words: 4, Separator: " ", Capitalize: true, Digit: true},
+			wantEntropy: 4*math.Log2(7776) + math.Log2(40),
+		},
+		{
+			name:    "no words",
+			opts:    PassphraseOptions{Words: 0},
+			wantErr: ErrGeneratorOptions,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GeneratePassphrase(tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GeneratePassphrase() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if math.Abs(got.Entropy-tt.wantEntropy) > 0.1 {
+				t.Errorf("GeneratePassphrase() entropy = %v, want %v", got.Entropy, tt.wantEntropy)
+			}
+
+			parts := splitPassphrase(got.Value, tt.opts.Separator, words)
+			if len(parts) != tt.opts.Words {
+				t.Fatalf("GeneratePassphrase() = %q, want %d words", got.Value, tt.opts.Words)
+			}
+			digits := 0
+			for _, p := range parts {
+				if tt.opts.Capitalize && !unicode.IsUpper(rune(p[0])) {
+					t.Errorf("GeneratePassphrase() word %q is not capitalized", p)
+				}
+				if w := strings.TrimRight(p, digitChars); len(w) != len(p) {
+					digits++
+					p = w
+				}
+				if !words[strings.ToLower(p)] {
+					t.Errorf("GeneratePassphrase() word %q is not in the word list", p)
+				}
+			}
+			if tt.opts.Digit && digits != 1 {
+				t.Errorf("GeneratePassphrase() = %q, want one digit", got.Value)
+			}
+		})
+	}
+}
+
+// splitPassphrase - splits the passphrase into the words, the words of the list that contain the separator,
+// such as "yo-yo", are kept whole.
+func splitPassphrase(value string, sep string, words map[string]bool) []string {
+	var parts []string
+	for _, p := range strings.Split(value, sep) {
+		if n := len(parts); n > 0 {
+			joined := parts[n-1] + sep + p
+			if words[strings.ToLower(strings.TrimRight(joined, digitChars))] {
+				parts[n-1] = joined
+				continue
+			}
+		}
+		parts = append(parts, p)
+	}
+	return parts
+}
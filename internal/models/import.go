@@ -0,0 +1,107 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImportPlan - The preview of the import. Nothing is written to the storage until the plan is applied.
+type ImportPlan struct {
+	// Records - the records that will be imported.
+	Records []*RecordDTO
+	// Duplicates - the records whose data is already in the storage or repeats an earlier record of the import.
+	// Duplicates are not imported.
+	Duplicates []*RecordDTO
+}
+
+// PlanImport - The method is used to preview the import of the records into the storage.
+// Records are compared by the type and the hashsum of the data, deleted records are ignored.
+func (u *User) PlanImport(ctx context.Context, db RecordStorage, records []*RecordDTO) (*ImportPlan, error) {
+	seen, err := u.storedKeys(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &ImportPlan{}
+	for _, r := range records {
+		key := importKey(r.Type, r.Hashsum)
+		if _, ok := seen[key]; ok {
+			plan.Duplicates = append(plan.Duplicates, r)
+			continue
+		}
+		seen[key] = struct{}{}
+		plan.Records = append(plan.Records, r)
+	}
+
+	return plan, nil
+}
+
+// GetAllRecords - The method is used to get all user records from the storage page by page.
+// Deleted records are not returned.
+func (u *User) GetAllRecords(ctx context.Context, db RecordStorage) ([]*Record, error) {
+	var all []*Record
+	for offset := 0; ; offset += DefaultLimit {
+		rs, err := db.ListRecords(ctx, u.ID, offset, DefaultLimit)
+		if err != nil {
+			return nil, fmt.Errorf("an error occured while retrieving records, err: %w", err)
+		}
+		if len(rs) == 0 {
+			return all, nil
+		}
+
+		for _, r := range rs {
+			if !r.Deleted {
+				all = append(all, r)
+			}
+		}
+	}
+}
+
+// ImportRecords - The method is used to write the records of the import plan into the storage with one batch.
+func (u *User) ImportRecords(ctx context.Context, db RecordStorage, plan *ImportPlan) ([]*BatchResult, error) {
+	now := time.Now()
+	rs := make([]*Record, len(plan.Records))
+	for i, r := range plan.Records {
+		rs[i] = &Record{
+			ID:          uuid.NewString(),
+			Owner:       u.ID,
+			Description: r.Description,
+			Type:        r.Type,
+			Created:     now,
+			Modified:    now,
+			Data:        r.Data,
+			Hashsum:     r.Hashsum,
+			Metadata:    r.Metadata,
+			Version:     1,
+		}
+	}
+
+	brs, err := db.BatchUpsertRecords(ctx, u.ID, rs)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while import records, err: %w", err)
+	}
+
+	return brs, nil
+}
+
+// storedKeys - returns the import keys of the records that are already in the storage.
+func (u *User) storedKeys(ctx context.Context, db RecordStorage) (map[string]struct{}, error) {
+	rs, err := u.GetAllRecords(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{}, len(rs))
+	for _, r := range rs {
+		seen[importKey(r.Type, r.Hashsum)] = struct{}{}
+	}
+
+	return seen, nil
+}
+
+func importKey(dataType string, hashsum string) string {
+	return dataType + ":" + hashsum
+}
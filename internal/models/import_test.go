@@ -0,0 +1,74 @@
+package models
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func newImportRecord(t *testing.T, login string) *RecordDTO {
+	t.Helper()
+
+	rdto, err := NewRecordDTO(login, AuthType, &Auth{Login: login, Password: login}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rdto
+}
+
+func TestUser_PlanImport(t *testing.T) {
+	ctx := context.Background()
+	u := &User{ID: uuid.NewString()}
+
+	stored := newImportRecord(t, "stored")
+	deleted := newImportRecord(t, "deleted")
+	fresh := newImportRecord(t, "fresh")
+
+	stg := NewMockRecordStorage(gomock.NewController(t))
+	stg.EXPECT().ListRecords(gomock.Any(), u.ID, 0, DefaultLimit).Return([]*Record{
+		{ID: uuid.NewString(), Type: stored.Type, Hashsum: stored.Hashsum},
+		{ID: uuid.NewString(), Type: deleted.Type, Hashsum: deleted.Hashsum, Deleted: true},
+	}, nil)
+	stg.EXPECT().ListRecords(gomock.Any(), u.ID, DefaultLimit, DefaultLimit).Return(nil, nil)
+
+	plan, err := u.PlanImport(ctx, stg, []*RecordDTO{stored, deleted, fresh, fresh})
+	if err != nil {
+		t.Fatalf("User.PlanImport() error = %v", err)
+	}
+
+	if len(plan.Records) != 2 || plan.Records[0] != deleted || plan.Records[1] != fresh {
+		t.Errorf("User.PlanImport() records = %v, want %v", plan.Records, []*RecordDTO{deleted, fresh})
+	}
+	if len(plan.Duplicates) != 2 || plan.Duplicates[0] != stored || plan.Duplicates[1] != fresh {
+		t.Errorf("User.PlanImport() duplicates = %v, want %v", plan.Duplicates, []*RecordDTO{stored, fresh})
+	}
+}
+
+func TestUser_ImportRecords(t *testing.T) {
+	ctx := context.Background()
+	u := &User{ID: uuid.NewString()}
+	plan := &ImportPlan{Records: []*RecordDTO{newImportRecord(t, "one"), newImportRecord(t, "two")}}
+
+	stg := NewMockRecordStorage(gomock.NewController(t))
+	stg.EXPECT().BatchUpsertRecords(gomock.Any(), u.ID, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, userID string, rs []*Record) ([]*BatchResult, error) {
+			brs := make([]*BatchResult, len(rs))
+			for i, r := range rs {
+				if r.ID == "" || r.Version != 1 || r.Hashsum != plan.Records[i].Hashsum {
+					t.Errorf("User.ImportRecords() record = %v, want new record from %v", r, plan.Records[i])
+				}
+				brs[i] = &BatchResult{ID: r.ID, Record: r}
+			}
+			return brs, nil
+		})
+
+	brs, err := u.ImportRecords(ctx, stg, plan)
+	if err != nil {
+		t.Fatalf("User.ImportRecords() error = %v", err)
+	}
+	if len(brs) != len(plan.Records) {
+		t.Errorf("User.ImportRecords() got %d results, want %d", len(brs), len(plan.Records))
+	}
+}
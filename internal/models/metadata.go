@@ -1,10 +1,51 @@
 package models
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
+// Well-known metadata keys. They are filled, for example, when records are imported from other password managers.
+const (
+	// MetadataKeyURL - the address of the site the record belongs to.
+	MetadataKeyURL = "url"
+	// MetadataKeyNotes - the free-form notes of the record.
+	MetadataKeyNotes = "notes"
+	// MetadataKeyTOTP - the secret or the otpauth:// URI of the one-time password generator.
+	MetadataKeyTOTP = "totp"
+	// MetadataKeyFolder - the folder of the record, nested folders are separated by a slash.
+	MetadataKeyFolder = "folder"
+)
+
+// MetadataType - The type of the metadata value, it defines how the value is edited and displayed.
+type MetadataType string
+
+const (
+	// MetadataText - a single line of text. The empty type is the text as well.
+	MetadataText MetadataType = "text"
+	// MetadataSecret - the value is hidden until it is revealed or copied.
+	MetadataSecret MetadataType = "secret"
+	// MetadataURL - an absolute address, for example https://example.org.
+	MetadataURL MetadataType = "url"
+	// MetadataDate - a date in the MetadataDateFormat format.
+	MetadataDate MetadataType = "date"
+	// MetadataMultiline - a text of several lines.
+	MetadataMultiline MetadataType = "multiline"
+)
+
+// MetadataDateFormat - The format of the values of the MetadataDate type.
+const MetadataDateFormat = "2006-01-02"
+
+// MetadataTypes - All types of the metadata values.
+var MetadataTypes = []MetadataType{MetadataText, MetadataSecret, MetadataURL, MetadataDate, MetadataMultiline}
+
+// ErrInvalidMetadata - The error is returned if the metadata item cannot be parsed or its value does not match its type.
+var ErrInvalidMetadata = errors.New("invalid metadata")
+
 // Metadata - for storing arbitrary textual meta-information
 // (data belonging to a website, an individual or a bank, lists of one-time activation codes, etc.).
 type Metadata struct {
@@ -12,37 +53,197 @@ type Metadata struct {
 	Key string `cbor:"key"`
 	// Value - The text value of the record.
 	Value string `cbor:"value"`
+	// Type - The type of the value, empty for the text.
+	Type MetadataType `cbor:"type,omitempty"`
+}
+
+// GetType - Returns the type of the value, MetadataText if the type is not set.
+func (m *Metadata) GetType() MetadataType {
+	if m.Type == "" {
+		return MetadataText
+	}
+	return m.Type
+}
+
+// Validate - Checks that the value matches the type.
+func (m *Metadata) Validate() error {
+	switch m.GetType() {
+	case MetadataText, MetadataSecret:
+		if strings.ContainsAny(m.Value, "\r\n") {
+			return fmt.Errorf("%w: the value of %s must be a single line", ErrInvalidMetadata, m.Key)
+		}
+	case MetadataMultiline:
+	case MetadataURL:
+		u, err := url.Parse(m.Value)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("%w: the value of %s is not an absolute URL", ErrInvalidMetadata, m.Key)
+		}
+	case MetadataDate:
+		if _, err := time.Parse(MetadataDateFormat, m.Value); err != nil {
+			return fmt.Errorf("%w: the value of %s is not a date in the YYYY-MM-DD format", ErrInvalidMetadata, m.Key)
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %s", ErrInvalidMetadata, m.Type)
+	}
+	return nil
+}
+
+// ParseMetadataType - Returns the type by its name, the empty name is the text.
+func ParseMetadataType(s string) (MetadataType, error) {
+	if s == "" {
+		return MetadataText, nil
+	}
+	for _, t := range MetadataTypes {
+		if strings.EqualFold(s, string(t)) {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("%w: unknown type %s", ErrInvalidMetadata, s)
+}
+
+// The reserved characters of the text format of the metadata.
+const (
+	metadataEscape    = '\\'
+	metadataSeparator = ':'
+	metadataTypeMark  = '#'
+)
+
+// FormatMetadataLine - Returns the metadata item in the text format KEY[#TYPE]:VALUE.
+// The backslash, the line breaks and, in the key, the colon and the hash sign are escaped with the backslash,
+// so any key and value can be written in one line. The type is omitted for the text.
+func FormatMetadataLine(m *Metadata) string {
+	var b strings.Builder
+	escapeMetadata(&b, m.Key, true)
+	if m.GetType() != MetadataText {
+		b.WriteRune(metadataTypeMark)
+		b.WriteString(string(m.Type))
+	}
+	b.WriteRune(metadataSeparator)
+	escapeMetadata(&b, m.Value, false)
+	return b.String()
+}
+
+func escapeMetadata(b *strings.Builder, s string, key bool) {
+	for _, r := range s {
+		switch {
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == metadataEscape || key && (r == metadataSeparator || r == metadataTypeMark):
+			b.WriteRune(metadataEscape)
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+}
+
+// ParseMetadataLine - Parses the metadata item written by FormatMetadataLine and validates its value.
+// The value is everything after the first unescaped colon, so it may contain colons, for example URLs.
+func ParseMetadataLine(s string) (*Metadata, error) {
+	var key, typ strings.Builder
+	cur := &key
+	rs := []rune(s)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case r == metadataEscape && i+1 < len(rs):
+			i++
+			cur.WriteString(unescapeMetadata(rs[i]))
+		case r == metadataTypeMark && cur == &key:
+			cur = &typ
+		case r == metadataSeparator:
+			t, err := ParseMetadataType(typ.String())
+			if err != nil {
+				return nil, err
+			}
+			m := &Metadata{Key: key.String(), Value: unescapeMetadataValue(rs[i+1:])}
+			if t != MetadataText {
+				m.Type = t
+			}
+			if err := m.Validate(); err != nil {
+				return nil, err
+			}
+			return m, nil
+		default:
+			cur.WriteRune(r)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: the separator %q is not found in %q", ErrInvalidMetadata, metadataSeparator, s)
+}
+
+func unescapeMetadataValue(rs []rune) string {
+	var b strings.Builder
+	for i := 0; i < len(rs); i++ {
+		if rs[i] == metadataEscape && i+1 < len(rs) {
+			i++
+			b.WriteString(unescapeMetadata(rs[i]))
+			continue
+		}
+		b.WriteRune(rs[i])
+	}
+	return b.String()
+}
+
+// unescapeMetadata - unknown escape sequences are kept as they are.
+func unescapeMetadata(r rune) string {
+	switch r {
+	case 'n':
+		return "\n"
+	case 'r':
+		return "\r"
+	case metadataEscape, metadataSeparator, metadataTypeMark:
+		return string(r)
+	default:
+		return string(metadataEscape) + string(r)
+	}
+}
+
+// FormatMetadata - Returns the metadata in the text format, one item per line.
+func FormatMetadata(mi []*Metadata) string {
+	lines := make([]string, len(mi))
+	for i, m := range mi {
+		lines[i] = FormatMetadataLine(m)
+	}
+	return strings.Join(lines, "\n")
 }
 
 // NewMetadataFromStringArray - The function converts an array of strings into an object Metadata.
+// Every string is parsed by ParseMetadataLine, empty strings are skipped.
 // Example strings:
 //   - "key1:value1" <- A key value pair separated by a colon.
+//   - "url#url:https://example.org" <- A key value pair with the type of the value.
 //   - "keyvalue" <- Just a string, in this case a sequence number will be added to the key.
 func NewMetadataFromStringArray(ss []string) ([]*Metadata, error) {
 	var mi []*Metadata
-	if len(ss) == 0 {
-		return mi, nil
-	}
-
-	const kvCount = 2
-
 	for i, s := range ss {
-		sp := strings.Split(s, ":")
-		switch len(sp) {
-		case kvCount:
-			mi = append(mi, &Metadata{
-				Key:   sp[0],
-				Value: sp[1],
-			})
-		case 1:
-			mi = append(mi, &Metadata{
-				Key:   strconv.Itoa(i),
-				Value: sp[0],
-			})
-		default:
+		if strings.TrimSpace(s) == "" {
 			continue
 		}
+		if !strings.ContainsRune(s, metadataSeparator) {
+			mi = append(mi, &Metadata{Key: strconv.Itoa(i), Value: s})
+			continue
+		}
+
+		m, err := ParseMetadataLine(s)
+		if err != nil {
+			return nil, err
+		}
+		mi = append(mi, m)
 	}
 
 	return mi, nil
 }
+
+// MetadataValue - Returns the value of the first metadata item with the key, the keys are compared case-insensitively.
+// Returns an empty string if there is no such item.
+func MetadataValue(mi []*Metadata, key string) string {
+	for _, m := range mi {
+		if strings.EqualFold(m.Key, key) {
+			return m.Value
+		}
+	}
+	return ""
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
@@ -45,7 +46,7 @@ func TestNewMetadataFromStringArray(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "positive case three elements",
+			name: "positive case value with colons",
 			args: args{
 				ss: []string{fmt.Sprintf("%s:%s", s1, s1),
 					fmt.Sprintf("%s:%s", s2, s2),
@@ -53,6 +54,7 @@ func TestNewMetadataFromStringArray(t *testing.T) {
 			},
 			want: []*Metadata{{Key: s1, Value: s1},
 				{Key: s2, Value: s2},
+				{Key: s3, Value: s3 + ":" + s3},
 			},
 			wantErr: false,
 		},
@@ -84,3 +86,63 @@ func TestNewMetadataFromStringArray(t *testing.T) {
 		})
 	}
 }
+
+func TestMetadataLine_RoundTrip(t *testing.T) {
+	tests := []struct {
+		m    *Metadata
+		line string
+	}{
+		{
+			m:    &Metadata{Key: "site", Value: "https://example.org:8443/path", Type: MetadataURL},
+			line: "site#url:https://example.org:8443/path",
+		},
+		{
+			m:    &Metadata{Key: "time", Value: "12:30"},
+			line: "time:12:30",
+		},
+		{
+			m:    &Metadata{Key: `a:b#c\d`, Value: `x\y`},
+			line: `a\:b\#c\\d:x\\y`,
+		},
+		{
+			m:    &Metadata{Key: "notes", Value: "first\nsecond", Type: MetadataMultiline},
+			line: `notes#multiline:first\nsecond`,
+		},
+		{
+			m:    &Metadata{Key: "pin", Value: "1234", Type: MetadataSecret},
+			line: "pin#secret:1234",
+		},
+		{
+			m:    &Metadata{Key: "expires", Value: "2030-01-31", Type: MetadataDate},
+			line: "expires#date:2030-01-31",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if got := FormatMetadataLine(tt.m); got != tt.line {
+				t.Errorf("FormatMetadataLine() = %v, want %v", got, tt.line)
+			}
+			got, err := ParseMetadataLine(tt.line)
+			if err != nil {
+				t.Fatalf("ParseMetadataLine() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.m) {
+				t.Errorf("ParseMetadataLine() = %v, want %v", got, tt.m)
+			}
+		})
+	}
+}
+
+func TestParseMetadataLine_Invalid(t *testing.T) {
+	for _, line := range []string{
+		"no separator",
+		"key#unknown:value",
+		"site#url:example.org",
+		"expires#date:31/01/2030",
+		`key:first\nsecond`,
+	} {
+		if _, err := ParseMetadataLine(line); !errors.Is(err, ErrInvalidMetadata) {
+			t.Errorf("ParseMetadataLine(%q) error = %v, wantErr %v", line, err, ErrInvalidMetadata)
+		}
+	}
+}
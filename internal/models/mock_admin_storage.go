@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/models/admin.go
+
+// Package models is a generated GoMock package.
+package models
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAdminStorage is a mock of AdminStorage interface.
+type MockAdminStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminStorageMockRecorder
+}
+
+// MockAdminStorageMockRecorder is the mock recorder for MockAdminStorage.
+type MockAdminStorageMockRecorder struct {
+	mock *MockAdminStorage
+}
+
+// NewMockAdminStorage creates a new mock instance.
+func NewMockAdminStorage(ctrl *gomock.Controller) *MockAdminStorage {
+	mock := &MockAdminStorage{ctrl: ctrl}
+	mock.recorder = &MockAdminStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdminStorage) EXPECT() *MockAdminStorageMockRecorder {
+	return m.recorder
+}
+
+// GetUserInfo mocks base method.
+func (m *MockAdminStorage) GetUserInfo(ctx context.Context, login string) (*UserInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserInfo", ctx, login)
+	ret0, _ := ret[0].(*UserInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserInfo indicates an expected call of GetUserInfo.
+func (mr *MockAdminStorageMockRecorder) GetUserInfo(ctx, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserInfo", reflect.TypeOf((*MockAdminStorage)(nil).GetUserInfo), ctx, login)
+}
+
+// ListUsers mocks base method.
+func (m *MockAdminStorage) ListUsers(ctx context.Context) ([]*UserInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", ctx)
+	ret0, _ := ret[0].([]*UserInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockAdminStorageMockRecorder) ListUsers(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockAdminStorage)(nil).ListUsers), ctx)
+}
+
+// PurgeTombstones mocks base method.
+func (m *MockAdminStorage) PurgeTombstones(ctx context.Context, before time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTombstones", ctx, before)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTombstones indicates an expected call of PurgeTombstones.
+func (mr *MockAdminStorageMockRecorder) PurgeTombstones(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTombstones", reflect.TypeOf((*MockAdminStorage)(nil).PurgeTombstones), ctx, before)
+}
+
+// SetUserDisabled mocks base method.
+func (m *MockAdminStorage) SetUserDisabled(ctx context.Context, userID string, disabled bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserDisabled", ctx, userID, disabled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserDisabled indicates an expected call of SetUserDisabled.
+func (mr *MockAdminStorageMockRecorder) SetUserDisabled(ctx, userID, disabled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserDisabled", reflect.TypeOf((*MockAdminStorage)(nil).SetUserDisabled), ctx, userID, disabled)
+}
+
+// SetUserPassword mocks base method.
+func (m *MockAdminStorage) SetUserPassword(ctx context.Context, userID, passwordHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserPassword", ctx, userID, passwordHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserPassword indicates an expected call of SetUserPassword.
+func (mr *MockAdminStorageMockRecorder) SetUserPassword(ctx, userID, passwordHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserPassword", reflect.TypeOf((*MockAdminStorage)(nil).SetUserPassword), ctx, userID, passwordHash)
+}
+
+// Usage mocks base method.
+func (m *MockAdminStorage) Usage(ctx context.Context, userID string) (*Usage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Usage", ctx, userID)
+	ret0, _ := ret[0].(*Usage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Usage indicates an expected call of Usage.
+func (mr *MockAdminStorageMockRecorder) Usage(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Usage", reflect.TypeOf((*MockAdminStorage)(nil).Usage), ctx, userID)
+}
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/models/audit.go
+
+// Package models is a generated GoMock package.
+package models
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAuditReader is a mock of AuditReader interface.
+type MockAuditReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditReaderMockRecorder
+}
+
+// MockAuditReaderMockRecorder is the mock recorder for MockAuditReader.
+type MockAuditReaderMockRecorder struct {
+	mock *MockAuditReader
+}
+
+// NewMockAuditReader creates a new mock instance.
+func NewMockAuditReader(ctrl *gomock.Controller) *MockAuditReader {
+	mock := &MockAuditReader{ctrl: ctrl}
+	mock.recorder = &MockAuditReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditReader) EXPECT() *MockAuditReaderMockRecorder {
+	return m.recorder
+}
+
+// ListAuditEvents mocks base method.
+func (m *MockAuditReader) ListAuditEvents(ctx context.Context, userID string, offset, limit int) ([]*AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditEvents", ctx, userID, offset, limit)
+	ret0, _ := ret[0].([]*AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditEvents indicates an expected call of ListAuditEvents.
+func (mr *MockAuditReaderMockRecorder) ListAuditEvents(ctx, userID, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEvents", reflect.TypeOf((*MockAuditReader)(nil).ListAuditEvents), ctx, userID, offset, limit)
+}
+
+// MockAuditStorage is a mock of AuditStorage interface.
+type MockAuditStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditStorageMockRecorder
+}
+
+// MockAuditStorageMockRecorder is the mock recorder for MockAuditStorage.
+type MockAuditStorageMockRecorder struct {
+	mock *MockAuditStorage
+}
+
+// NewMockAuditStorage creates a new mock instance.
+func NewMockAuditStorage(ctrl *gomock.Controller) *MockAuditStorage {
+	mock := &MockAuditStorage{ctrl: ctrl}
+	mock.recorder = &MockAuditStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditStorage) EXPECT() *MockAuditStorageMockRecorder {
+	return m.recorder
+}
+
+// AddAuditEvents mocks base method.
+func (m *MockAuditStorage) AddAuditEvents(ctx context.Context, events []*AuditEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAuditEvents", ctx, events)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAuditEvents indicates an expected call of AddAuditEvents.
+func (mr *MockAuditStorageMockRecorder) AddAuditEvents(ctx, events interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAuditEvents", reflect.TypeOf((*MockAuditStorage)(nil).AddAuditEvents), ctx, events)
+}
+
+// ListAuditEvents mocks base method.
+func (m *MockAuditStorage) ListAuditEvents(ctx context.Context, userID string, offset, limit int) ([]*AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditEvents", ctx, userID, offset, limit)
+	ret0, _ := ret[0].([]*AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditEvents indicates an expected call of ListAuditEvents.
+func (mr *MockAuditStorageMockRecorder) ListAuditEvents(ctx, userID, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEvents", reflect.TypeOf((*MockAuditStorage)(nil).ListAuditEvents), ctx, userID, offset, limit)
+}
@@ -0,0 +1,98 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/models/synchronizer.go
+
+// Package models is a generated GoMock package.
+package models
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPendingQueue is a mock of PendingQueue interface.
+type MockPendingQueue struct {
+	ctrl     *gomock.Controller
+	recorder *MockPendingQueueMockRecorder
+}
+
+// MockPendingQueueMockRecorder is the mock recorder for MockPendingQueue.
+type MockPendingQueueMockRecorder struct {
+	mock *MockPendingQueue
+}
+
+// NewMockPendingQueue creates a new mock instance.
+func NewMockPendingQueue(ctrl *gomock.Controller) *MockPendingQueue {
+	mock := &MockPendingQueue{ctrl: ctrl}
+	mock.recorder = &MockPendingQueueMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPendingQueue) EXPECT() *MockPendingQueueMockRecorder {
+	return m.recorder
+}
+
+// Ack mocks base method.
+func (m *MockPendingQueue) Ack(records ...*Record) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range records {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Ack", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ack indicates an expected call of Ack.
+func (mr *MockPendingQueueMockRecorder) Ack(records ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ack", reflect.TypeOf((*MockPendingQueue)(nil).Ack), records...)
+}
+
+// Len mocks base method.
+func (m *MockPendingQueue) Len() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Len")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// Len indicates an expected call of Len.
+func (mr *MockPendingQueueMockRecorder) Len() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Len", reflect.TypeOf((*MockPendingQueue)(nil).Len))
+}
+
+// Push mocks base method.
+func (m *MockPendingQueue) Push(records ...*Record) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range records {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Push", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Push indicates an expected call of Push.
+func (mr *MockPendingQueueMockRecorder) Push(records ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Push", reflect.TypeOf((*MockPendingQueue)(nil).Push), records...)
+}
+
+// Records mocks base method.
+func (m *MockPendingQueue) Records() []*Record {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Records")
+	ret0, _ := ret[0].([]*Record)
+	return ret0
+}
+
+// Records indicates an expected call of Records.
+func (mr *MockPendingQueueMockRecorder) Records() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Records", reflect.TypeOf((*MockPendingQueue)(nil).Records))
+}
@@ -25,12 +25,16 @@ var ErrEmptyID = errors.New("field ID cannot be empty")
 // ErrRecordNotFound - An error that is returned in case when record not found in database.
 var ErrRecordNotFound = errors.New("record not found")
 
+// ErrHashsumMismatch - An error that is returned when the hashsum of the record does not match its data.
+var ErrHashsumMismatch = errors.New("the hashsum does not match the record data")
+
 // ErrUserStorageNotFound - An error that is returned when the user is logged in,
 // but the client has not created a storage for him.
 var ErrUserStorageNotFound = errors.New("user cache not found")
 
 type RecordStorage interface {
 	// ListRecords - used to retrieving user records.
+	// The records must be listed in the stable order, so the pages do not overlap and no record is skipped.
 	ListRecords(ctx context.Context, userID string, offset int, limit int) ([]*Record, error)
 	// GetRecord - used to retrieving record.
 	GetRecord(ctx context.Context, userID string, recordID string) (*Record, error)
@@ -40,6 +44,70 @@ type RecordStorage interface {
 	AddRecord(ctx context.Context, userID string, record *RecordDTO) (*Record, error)
 	// UpdateRecord - update record to the storage.
 	UpdateRecord(ctx context.Context, userID string, record *Record) (*Record, error)
+	// BatchGetRecords - used to retrieving several records at once.
+	// The results are returned in the order of recordIDs.
+	BatchGetRecords(ctx context.Context, userID string, recordIDs []string) ([]*BatchResult, error)
+	// BatchUpsertRecords - add or update several records at once.
+	// The results are returned in the order of records.
+	BatchUpsertRecords(ctx context.Context, userID string, records []*Record) ([]*BatchResult, error)
+	// BatchDeleteRecords - mark several records as deleted at once.
+	// The results are returned in the order of recordIDs.
+	BatchDeleteRecords(ctx context.Context, userID string, recordIDs []string) ([]*BatchResult, error)
+}
+
+// MaxBatchSize - The maximum number of records that the service can accept in one batch request.
+const MaxBatchSize = 100
+
+// ErrBatchTooLarge - An error that is returned in case of an attempt to transfer more records in one batch
+// than set in the constant MaxBatchSize.
+var ErrBatchTooLarge = fmt.Errorf("the batch should not contain more than %d records", MaxBatchSize)
+
+// BatchResult - The result of the batch operation for one record.
+type BatchResult struct {
+	// ID - uuid of the record.
+	ID string
+	// Record - the record, returned only by BatchGetRecords.
+	Record *Record
+	// Replaced - the version of the record that has been replaced by BatchUpsertRecords, nil if the record is new.
+	// Only the ID, version, hashsum and deleted flag of the replaced version are set.
+	Replaced *Record
+	// Err - an error that occurred while processing the record, nil on success.
+	// If the record is not found, ErrRecordNotFound is returned.
+	Err error
+}
+
+// BatchErr - Returns the joined errors of the failed records, nil if all records have been processed.
+func BatchErr(brs []*BatchResult) error {
+	var errs []error
+	for _, br := range brs {
+		if br.Err != nil {
+			errs = append(errs, fmt.Errorf("record(ID=%s), err: %w", br.ID, br.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ErrInvalidRecord - The error is returned if the record violates the rules of the storage,
+// for example, the rules of the requests of the server. Such records are skipped by the synchronization.
+var ErrInvalidRecord = errors.New("record is invalid")
+
+// ErrQuotaExceeded - The error is returned if the user has reached the limit of the records
+// or the record is larger than the server accepts.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// Usage - The amount of the data that the user keeps in the storage, the deleted records are not counted.
+type Usage struct {
+	// Records - the number of the records.
+	Records int
+	// Bytes - the size of the data of the records.
+	Bytes int64
+}
+
+// UsageReporter - An optional interface of the storage that reports the usage of the user.
+// It is used to check the quota of the records.
+type UsageReporter interface {
+	// Usage - returns the amount of the data of the user.
+	Usage(ctx context.Context, userID string) (*Usage, error)
 }
 
 // DataType - the object contains the data directly related to the record.
@@ -180,6 +248,65 @@ func (r *Record) GetHashsum() string {
 	return r.Hashsum
 }
 
+// IsDeleted - Returns true if the record has been deleted.
+func (r *Record) IsDeleted() bool {
+	return r.Deleted
+}
+
+// MarkDeleted - Turns the record into a deletion mark.
+// The version is incremented, so the deletion wins over older versions during synchronization
+// and conflicts with concurrent changes like any other edit.
+func (r *Record) MarkDeleted() {
+	r.Deleted = true
+	r.Modified = time.Now()
+	r.Version++
+}
+
+// VerifyHashsum - Checks that the data of the record has not been changed since the hashsum was calculated.
+func (r *Record) VerifyHashsum() error {
+	hs, err := hashsum(r.Data)
+	if err != nil {
+		return fmt.Errorf("an error occured while verify record %s, err: %w", r.ID, err)
+	}
+	if hs != r.Hashsum {
+		return fmt.Errorf("record %s, err: %w", r.ID, ErrHashsumMismatch)
+	}
+
+	return nil
+}
+
+// DecodeData - Decodes the record data according to the record type.
+func (r *Record) DecodeData() (RecordData, error) {
+	return decodeData(r.Type, r.Data)
+}
+
+// DecodeData - Decodes the record data according to the record type.
+func (r *RecordDTO) DecodeData() (RecordData, error) {
+	return decodeData(r.Type, r.Data)
+}
+
+func decodeData(dataType string, data []byte) (RecordData, error) {
+	var d RecordData
+	switch dataType {
+	case string(AuthType):
+		d = &Auth{}
+	case string(TextType):
+		d = &Text{}
+	case string(BinaryType):
+		d = &Binary{}
+	case string(CardType):
+		d = &Card{}
+	default:
+		return nil, ErrUnknowDataType
+	}
+
+	if err := cbor.Unmarshal(data, d); err != nil {
+		return nil, fmt.Errorf("an error occured while decode %s record data, err: %w", dataType, err)
+	}
+
+	return d, nil
+}
+
 func hashsum(b []byte) (string, error) {
 	h := sha256.New()
 	f := bytes.NewReader(b)
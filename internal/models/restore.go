@@ -0,0 +1,105 @@
+package models
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// RestoredRecord - The result of restoring one record from a backup.
+type RestoredRecord struct {
+	// OldID - the ID of the record in the backup.
+	OldID string
+	// NewID - the ID of the restored record, empty if the record was not restored.
+	NewID string
+	// Duplicate - the data of the record is already in the storage, so the record was not restored.
+	Duplicate bool
+	// Err - the error of restoring the record.
+	Err error
+}
+
+// Restorer - Writes the records from a backup into the storage of the user.
+// The records get new IDs, so a backup can be restored into the same or into another account.
+type Restorer struct {
+	user *User
+	db   RecordStorage
+	// seen - the import keys of the records that are in the storage or have been restored.
+	seen map[string]struct{}
+}
+
+// NewRestorer - Object Constructor. Reads the records that are already in the storage,
+// their duplicates in the backup are not restored.
+func (u *User) NewRestorer(ctx context.Context, db RecordStorage) (*Restorer, error) {
+	seen, err := u.storedKeys(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Restorer{user: u, db: db, seen: seen}, nil
+}
+
+// Restore - Writes the records into the storage with one batch.
+// The results are returned in the order of records.
+func (rr *Restorer) Restore(ctx context.Context, records []*Record) ([]*RestoredRecord, error) {
+	results := make([]*RestoredRecord, len(records))
+	var batch []*Record
+	var idx []int
+	for i, r := range records {
+		results[i] = &RestoredRecord{OldID: r.ID}
+
+		if err := r.VerifyHashsum(); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		key := importKey(r.Type, r.Hashsum)
+		if _, ok := rr.seen[key]; ok {
+			results[i].Duplicate = true
+			continue
+		}
+		rr.seen[key] = struct{}{}
+
+		batch = append(batch, &Record{
+			ID:          uuid.NewString(),
+			Owner:       rr.user.ID,
+			Description: r.Description,
+			Type:        r.Type,
+			Created:     r.Created,
+			Modified:    r.Modified,
+			Data:        r.Data,
+			Hashsum:     r.Hashsum,
+			Metadata:    r.Metadata,
+			Version:     1,
+		})
+		idx = append(idx, i)
+	}
+
+	if len(batch) == 0 {
+		return results, nil
+	}
+
+	brs, err := rr.db.BatchUpsertRecords(ctx, rr.user.ID, batch)
+	if err != nil {
+		for _, r := range batch {
+			delete(rr.seen, importKey(r.Type, r.Hashsum))
+		}
+		return nil, fmt.Errorf("an error occured while restore records, err: %w", err)
+	}
+	if len(brs) != len(batch) {
+		return nil, fmt.Errorf("an error occured while restore records, got %d results for %d records",
+			len(brs), len(batch))
+	}
+
+	for j, br := range brs {
+		res := results[idx[j]]
+		if br.Err != nil {
+			res.Err = br.Err
+			delete(rr.seen, importKey(batch[j].Type, batch[j].Hashsum))
+			continue
+		}
+		res.NewID = batch[j].ID
+	}
+
+	return results, nil
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestRestorer_Restore(t *testing.T) {
+	ctx := context.Background()
+	u := &User{ID: uuid.NewString()}
+
+	newRecord := func(login string) *Record {
+		r, err := NewRecord(uuid.NewString(), login, AuthType, time.Now(), time.Now(),
+			&Auth{Login: login, Password: login}, nil, false, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	stored := newRecord("stored")
+	fresh := newRecord("fresh")
+	again := newRecord("fresh")
+	broken := newRecord("broken")
+	broken.Data = []byte("changed")
+
+	stg := NewMockRecordStorage(gomock.NewController(t))
+	stg.EXPECT().ListRecords(gomock.Any(), u.ID, 0, DefaultLimit).Return([]*Record{stored}, nil)
+	stg.EXPECT().ListRecords(gomock.Any(), u.ID, DefaultLimit, DefaultLimit).Return(nil, nil)
+	stg.EXPECT().BatchUpsertRecords(gomock.Any(), u.ID, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, userID string, rs []*Record) ([]*BatchResult, error) {
+			if len(rs) != 1 || rs[0].ID == fresh.ID || rs[0].Hashsum != fresh.Hashsum || rs[0].Version != 1 {
+				t.Errorf("Restorer.Restore() upserts %v, want a copy of %v with a new ID", rs, fresh)
+			}
+			return []*BatchResult{{ID: rs[0].ID, Record: rs[0]}}, nil
+		})
+
+	rr, err := u.NewRestorer(ctx, stg)
+	if err != nil {
+		t.Fatalf("User.NewRestorer() error = %v", err)
+	}
+
+	results, err := rr.Restore(ctx, []*Record{stored, fresh, again, broken})
+	if err != nil {
+		t.Fatalf("Restorer.Restore() error = %v", err)
+	}
+
+	if !results[0].Duplicate || results[0].NewID != "" {
+		t.Errorf("Restorer.Restore() stored record = %v, want duplicate", results[0])
+	}
+	if results[1].NewID == "" || results[1].NewID == fresh.ID || results[1].Err != nil {
+		t.Errorf("Restorer.Restore() fresh record = %v, want restored with a new ID", results[1])
+	}
+	if !results[2].Duplicate {
+		t.Errorf("Restorer.Restore() repeated record = %v, want duplicate", results[2])
+	}
+	if !errors.Is(results[3].Err, ErrHashsumMismatch) {
+		t.Errorf("Restorer.Restore() broken record error = %v, want %v", results[3].Err, ErrHashsumMismatch)
+	}
+}
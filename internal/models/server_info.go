@@ -0,0 +1,115 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ProtocolVersion - The version of the protocol between the client and the server.
+// It is increased when the client and the server stop understanding each other without an update.
+const ProtocolVersion = 1
+
+// MinProtocolVersion - The oldest version of the protocol that is still supported by this build.
+const MinProtocolVersion = 1
+
+// Features of the server that the client may rely on.
+const (
+	// FeatureDevices - the devices of the user are registered and can be revoked.
+	FeatureDevices = "devices"
+	// FeatureBatch - the records are synchronized by the batch requests.
+	FeatureBatch = "batch"
+	// FeatureVersionVectors - the conflicts of the records are detected by the version vectors.
+	FeatureVersionVectors = "version-vectors"
+	// FeatureTypedMetadata - the types of the metadata fields are stored.
+	FeatureTypedMetadata = "typed-metadata"
+	// FeatureAudit - the actions of the user are written to the audit log.
+	FeatureAudit = "audit"
+)
+
+// ServerFeatures - The features that are supported by this build of the server.
+var ServerFeatures = []string{FeatureDevices, FeatureBatch, FeatureVersionVectors, FeatureTypedMetadata, FeatureAudit}
+
+// requiredFeatures - the client does not work with the server without these features.
+var requiredFeatures = []string{FeatureDevices, FeatureBatch}
+
+// ErrIncompatibleServer - The error is returned if the client cannot work with the server.
+var ErrIncompatibleServer = errors.New("incompatible server")
+
+// ErrServerInfoUnavailable - The error is returned if the server does not report the information about itself,
+// for example, it has been built before the information was added.
+var ErrServerInfoUnavailable = errors.New("server info is unavailable")
+
+// ServerInfoProvider - The interface of the source of the information about the server.
+type ServerInfoProvider interface {
+	// GetServerInfo - used to retrieving the build, the protocol and the features of the server.
+	GetServerInfo(ctx context.Context) (*ServerInfo, error)
+}
+
+// ServerInfo - The information about the server that the client checks the compatibility with.
+type ServerInfo struct {
+	// Version - the version of the build of the server.
+	Version string
+	// Date - the date of the build of the server.
+	Date string
+	// Commit - the commit of the build of the server.
+	Commit string
+	// ProtocolVersion - the newest version of the protocol supported by the server.
+	ProtocolVersion int
+	// MinProtocolVersion - the oldest version of the protocol supported by the server.
+	MinProtocolVersion int
+	// Features - the features supported by the server.
+	Features []string
+}
+
+// Compatibility - The result of the check of the server.
+type Compatibility struct {
+	// Info - the information about the server, nil if the server does not report it.
+	Info *ServerInfo
+	// Warnings - the problems that do not prevent the client from working with the server.
+	Warnings []string
+}
+
+// CheckServer - Retrieves the information about the server and checks that the client can work with it.
+// Returns ErrIncompatibleServer if it cannot, the other problems are returned as warnings.
+func CheckServer(ctx context.Context, p ServerInfoProvider) (*Compatibility, error) {
+	info, err := p.GetServerInfo(ctx)
+	if err != nil {
+		if errors.Is(err, ErrServerInfoUnavailable) {
+			return &Compatibility{
+				Warnings: []string{"the server does not report its version, it may be outdated"},
+			}, nil
+		}
+		return nil, fmt.Errorf("an error occured while retrieving server info, err: %w", err)
+	}
+
+	c := &Compatibility{Info: info}
+	switch {
+	case ProtocolVersion < info.MinProtocolVersion:
+		return c, fmt.Errorf("%w: the server supports protocol %d-%d, the client uses %d, update the client",
+			ErrIncompatibleServer, info.MinProtocolVersion, info.ProtocolVersion, ProtocolVersion)
+	case info.ProtocolVersion < MinProtocolVersion:
+		return c, fmt.Errorf("%w: the server supports protocol %d-%d, the client requires %d or newer",
+			ErrIncompatibleServer, info.MinProtocolVersion, info.ProtocolVersion, MinProtocolVersion)
+	case info.ProtocolVersion > ProtocolVersion:
+		c.Warnings = append(c.Warnings, fmt.Sprintf("the server uses the newer protocol %d, consider updating the client",
+			info.ProtocolVersion))
+	case info.ProtocolVersion < ProtocolVersion:
+		c.Warnings = append(c.Warnings, fmt.Sprintf("the server uses the older protocol %d, consider updating the server",
+			info.ProtocolVersion))
+	}
+
+	for _, f := range requiredFeatures {
+		if !info.HasFeature(f) {
+			return c, fmt.Errorf("%w: the server does not support %q", ErrIncompatibleServer, f)
+		}
+	}
+
+	return c, nil
+}
+
+// HasFeature - Reports whether the server supports the feature.
+func (si *ServerInfo) HasFeature(feature string) bool {
+	return slices.Contains(si.Features, feature)
+}
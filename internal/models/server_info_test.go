@@ -0,0 +1,82 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type testInfoProvider struct {
+	info *ServerInfo
+	err  error
+}
+
+func (p *testInfoProvider) GetServerInfo(ctx context.Context) (*ServerInfo, error) {
+	return p.info, p.err
+}
+
+func TestCheckServer(t *testing.T) {
+	tests := []struct {
+		name         string
+		provider     *testInfoProvider
+		wantErr      error
+		wantWarnings int
+	}{
+		{
+			name: "compatible server",
+			provider: &testInfoProvider{info: &ServerInfo{
+				ProtocolVersion: ProtocolVersion, MinProtocolVersion: MinProtocolVersion, Features: ServerFeatures,
+			}},
+		},
+		{
+			name: "newer compatible server",
+			provider: &testInfoProvider{info: &ServerInfo{
+				ProtocolVersion: ProtocolVersion + 1, MinProtocolVersion: ProtocolVersion, Features: ServerFeatures,
+			}},
+			wantWarnings: 1,
+		},
+		{
+			name: "server does not support the client protocol",
+			provider: &testInfoProvider{info: &ServerInfo{
+				ProtocolVersion: ProtocolVersion + 2, MinProtocolVersion: ProtocolVersion + 1, Features: ServerFeatures,
+			}},
+			wantErr: ErrIncompatibleServer,
+		},
+		{
+			name: "server is too old",
+			provider: &testInfoProvider{info: &ServerInfo{
+				ProtocolVersion: MinProtocolVersion - 1, MinProtocolVersion: MinProtocolVersion - 1, Features: ServerFeatures,
+			}},
+			wantErr: ErrIncompatibleServer,
+		},
+		{
+			name: "server without required feature",
+			provider: &testInfoProvider{info: &ServerInfo{
+				ProtocolVersion: ProtocolVersion, MinProtocolVersion: MinProtocolVersion, Features: []string{FeatureDevices},
+			}},
+			wantErr: ErrIncompatibleServer,
+		},
+		{
+			name:         "server does not report info",
+			provider:     &testInfoProvider{err: ErrServerInfoUnavailable},
+			wantWarnings: 1,
+		},
+		{
+			name:     "server is unavailable",
+			provider: &testInfoProvider{err: errSomethingWentWrong},
+			wantErr:  errSomethingWentWrong,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CheckServer(context.Background(), tt.provider)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CheckServer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != nil && len(got.Warnings) != tt.wantWarnings {
+				t.Errorf("CheckServer() warnings = %v, want %d", got.Warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,411 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MaxSyncBackoff - The maximum delay between failed synchronization attempts.
+const MaxSyncBackoff = 5 * time.Minute
+
+// PendingQueue - The interface of the durable queue of local changes that have not yet been written
+// to the remote storage. The queue must survive the restart of the client.
+type PendingQueue interface {
+	// Push - adds records to the queue. A record that is already in the queue is replaced.
+	Push(records ...*Record) error
+	// Records - returns copies of the queued records.
+	Records() []*Record
+	// Ack - removes records from the queue if they have not been changed since the copies were taken.
+	Ack(records ...*Record) error
+	// Len - returns the number of the queued records.
+	Len() int
+}
+
+// SyncStatus - The state of the synchronization between the local and the remote storage.
+type SyncStatus struct {
+	// LastSync - the date of the last successful synchronization, zero if there was none.
+	LastSync time.Time
+	// NextRetry - the date of the next synchronization attempt.
+	NextRetry time.Time
+	// LastErr - the error of the last synchronization attempt, nil if the attempt was successful.
+	LastErr error
+	// Pending - the number of local changes that have not yet been written to the remote storage.
+	Pending int
+	// Failures - the number of consecutive failed synchronization attempts.
+	Failures int
+	// Rejected - IDs of the records that the remote storage has rejected as invalid during the last attempt.
+	// They stay in the pending queue until they are corrected, the other records are synchronized.
+	Rejected []string
+}
+
+// Synchronizer - Synchronizes user records between the local and the remote storage in the background.
+//
+// Local changes made through the storage returned by Local are written to the pending queue
+// and stay there until they have been written to the remote storage.
+// Failed attempts are retried with exponential backoff.
+type Synchronizer struct {
+	user     *User
+	local    RecordStorage
+	remote   RecordStorage
+	queue    PendingQueue
+	handler  ConflictHandler
+	trigger  chan struct{}
+	onChange func(SyncStatus)
+	// conflicted - IDs of records that were in conflict during the current attempt.
+	conflicted map[string]struct{}
+	// rejected - IDs of records that were rejected as invalid during the current attempt.
+	rejected map[string]struct{}
+	status   SyncStatus
+	tick     time.Duration
+	// pullOnly - local changes are not pushed and conflicts are not resolved, see SetPullOnly.
+	pullOnly bool
+	mu       sync.Mutex
+}
+
+// NewSynchronizer - Object Constructor.
+// If the handler is nil, both versions of the conflicting record are kept.
+func NewSynchronizer(u *User,
+	local RecordStorage,
+	remote RecordStorage,
+	queue PendingQueue,
+	handler ConflictHandler,
+	tick time.Duration) *Synchronizer {
+	return &Synchronizer{
+		user:       u,
+		local:      local,
+		remote:     remote,
+		queue:      queue,
+		handler:    handler,
+		tick:       tick,
+		trigger:    make(chan struct{}, 1),
+		conflicted: make(map[string]struct{}),
+		rejected:   make(map[string]struct{}),
+		status:     SyncStatus{Pending: queue.Len()},
+	}
+}
+
+// OnChange - Sets the function that is called every time the synchronization status changes.
+// The function must not block.
+func (s *Synchronizer) OnChange(fn func(SyncStatus)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onChange = fn
+}
+
+// Status - Returns the current synchronization status.
+func (s *Synchronizer) Status() SyncStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.status
+}
+
+// Local - Returns the local storage whose changes are written to the pending queue.
+// All user changes must be made through this storage.
+func (s *Synchronizer) Local() RecordStorage {
+	return &trackedStorage{RecordStorage: s.local, s: s}
+}
+
+// Restore - Puts the records from the pending queue into the local storage.
+// Used at startup, so that the changes that were not written to the remote storage are not lost.
+func (s *Synchronizer) Restore(ctx context.Context) error {
+	rs := s.queue.Records()
+	if len(rs) == 0 {
+		return nil
+	}
+
+	brs, err := s.local.BatchUpsertRecords(ctx, s.user.ID, rs)
+	if err != nil {
+		return fmt.Errorf("an error occured while restore pending records, err: %w", err)
+	}
+	if err := BatchErr(brs); err != nil {
+		return fmt.Errorf("an error occured while restore pending records, err: %w", err)
+	}
+
+	return nil
+}
+
+// SetPullOnly - Switches the synchronizer to the mode in which records are only pulled from the remote storage.
+// Local changes stay in the pending queue and conflicting records are skipped until the mode is switched off.
+// Used while the local records cannot be read, for example while the session is locked.
+func (s *Synchronizer) SetPullOnly(pullOnly bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pullOnly = pullOnly
+}
+
+// SyncNow - Requests the immediate synchronization without waiting for the next attempt.
+func (s *Synchronizer) SyncNow() {
+	select {
+	case s.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Run - Synchronizes the storages until the context is done.
+// The method returns only when the context is done or the device has been revoked.
+func (s *Synchronizer) Run(ctx context.Context) error {
+	for {
+		if err := s.Sync(ctx); errors.Is(err, ErrDeviceRevoked) {
+			return err
+		}
+
+		delay := s.delay()
+		s.update(func(st *SyncStatus) {
+			st.NextRetry = time.Now().Add(delay)
+		})
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-s.trigger:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// Sync - Makes one synchronization attempt and updates the synchronization status.
+func (s *Synchronizer) Sync(ctx context.Context) error {
+	pending := s.queue.Records()
+
+	s.mu.Lock()
+	s.conflicted = make(map[string]struct{})
+	s.rejected = make(map[string]struct{})
+	pullOnly := s.pullOnly
+	s.mu.Unlock()
+
+	var err error
+	if pullOnly {
+		err = s.pull(ctx)
+	} else {
+		pull, push := s.user.conflictFuncs(s.local, s.remote, s.handler)
+		if s.handler != nil {
+			// Conflicts reported to the handler stay unresolved, so their records must stay in the queue.
+			pull, push = s.noteConflict(pull), s.noteConflict(push)
+		}
+		err = s.user.syncOnce(ctx, s.local, s.remote, pull, push, s.reject)
+		if err == nil {
+			err = s.ack(pending)
+		}
+	}
+
+	if ctx.Err() != nil {
+		return err
+	}
+
+	s.update(func(st *SyncStatus) {
+		st.Rejected = s.rejectedIDs()
+		st.LastErr = err
+		if err != nil {
+			st.Failures++
+			return
+		}
+		st.Failures = 0
+		st.LastSync = time.Now()
+	})
+
+	return err
+}
+
+// pull - writes the newer remote records to the local storage, conflicting records are left as they are.
+func (s *Synchronizer) pull(ctx context.Context) error {
+	skip := func(ctx context.Context, dst *Record, src *Record) error { return nil }
+	if err := s.user.syncStorages(ctx, s.local, s.remote, skip, s.reject); err != nil {
+		return fmt.Errorf("an error occured while pull records from %T, err: %w", s.remote, err)
+	}
+
+	if err := reportSync(ctx, s.user.ID, s.remote); err != nil {
+		return fmt.Errorf("an error occured while report sync, err: %w", err)
+	}
+
+	return nil
+}
+
+// ack - removes from the queue the records that have been written to the remote storage.
+// Records in conflict stay in the queue until the conflict is resolved, rejected records - until they are corrected.
+func (s *Synchronizer) ack(pending []*Record) error {
+	s.mu.Lock()
+	done := make([]*Record, 0, len(pending))
+	for _, r := range pending {
+		_, conflicted := s.conflicted[r.ID]
+		_, rejected := s.rejected[r.ID]
+		if !conflicted && !rejected {
+			done = append(done, r)
+		}
+	}
+	s.mu.Unlock()
+
+	if err := s.queue.Ack(done...); err != nil {
+		return fmt.Errorf("an error occured while remove records from pending queue, err: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Synchronizer) noteConflict(conflict conflictFunc) conflictFunc {
+	return func(ctx context.Context, dst *Record, src *Record) error {
+		s.mu.Lock()
+		s.conflicted[dst.ID] = struct{}{}
+		s.mu.Unlock()
+
+		return conflict(ctx, dst, src)
+	}
+}
+
+// reject - notes the record that has been rejected as invalid, so it stays in the pending queue.
+func (s *Synchronizer) reject(_ context.Context, r *Record, _ error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rejected[r.ID] = struct{}{}
+}
+
+// rejectedIDs - returns the sorted IDs of the records rejected during the current attempt, nil if there are none.
+// The caller must hold the mutex.
+func (s *Synchronizer) rejectedIDs() []string {
+	if len(s.rejected) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(s.rejected))
+	for id := range s.rejected {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+// delay - returns the delay before the next attempt.
+// After failed attempts the delay is doubled up to MaxSyncBackoff.
+func (s *Synchronizer) delay() time.Duration {
+	s.mu.Lock()
+	failures := s.status.Failures
+	s.mu.Unlock()
+
+	d := s.tick
+	for i := 0; i < failures && d < MaxSyncBackoff; i++ {
+		d *= 2
+	}
+
+	return min(d, MaxSyncBackoff)
+}
+
+func (s *Synchronizer) update(fn func(st *SyncStatus)) {
+	s.mu.Lock()
+	fn(&s.status)
+	s.status.Pending = s.queue.Len()
+	st := s.status
+	onChange := s.onChange
+	s.mu.Unlock()
+
+	if onChange != nil {
+		onChange(st)
+	}
+}
+
+func (s *Synchronizer) enqueue(rs ...*Record) error {
+	if err := s.queue.Push(rs...); err != nil {
+		return fmt.Errorf("the change is saved locally, but not queued for sync, err: %w", err)
+	}
+	s.update(func(st *SyncStatus) {})
+
+	return nil
+}
+
+// trackedStorage - The local storage that writes every change to the pending queue of the synchronizer.
+type trackedStorage struct {
+	RecordStorage
+	s *Synchronizer
+}
+
+// AddRecord - add new record to the storage.
+func (ts *trackedStorage) AddRecord(ctx context.Context, userID string, record *RecordDTO) (*Record, error) {
+	r, err := ts.RecordStorage.AddRecord(ctx, userID, record)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while add record, err: %w", err)
+	}
+
+	return r, ts.s.enqueue(r)
+}
+
+// UpdateRecord - update record to the storage.
+func (ts *trackedStorage) UpdateRecord(ctx context.Context, userID string, record *Record) (*Record, error) {
+	r, err := ts.RecordStorage.UpdateRecord(ctx, userID, record)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while update record, err: %w", err)
+	}
+
+	return r, ts.s.enqueue(r)
+}
+
+// DeleteRecord - mark records as deleted.
+func (ts *trackedStorage) DeleteRecord(ctx context.Context, userID string, recordID string) error {
+	if err := ts.RecordStorage.DeleteRecord(ctx, userID, recordID); err != nil {
+		return fmt.Errorf("an error occured while delete record, err: %w", err)
+	}
+
+	r, err := ts.RecordStorage.GetRecord(ctx, userID, recordID)
+	if err != nil {
+		return fmt.Errorf("an error occured while retrieving deleted record, err: %w", err)
+	}
+
+	return ts.s.enqueue(r)
+}
+
+// BatchUpsertRecords - add or update several records at once.
+func (ts *trackedStorage) BatchUpsertRecords(ctx context.Context,
+	userID string, records []*Record) ([]*BatchResult, error) {
+	brs, err := ts.RecordStorage.BatchUpsertRecords(ctx, userID, records)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while update records, err: %w", err)
+	}
+
+	var rs []*Record
+	for i, br := range brs {
+		if br.Err == nil {
+			rs = append(rs, records[i])
+		}
+	}
+
+	return brs, ts.s.enqueue(rs...)
+}
+
+// BatchDeleteRecords - mark several records as deleted at once.
+func (ts *trackedStorage) BatchDeleteRecords(ctx context.Context,
+	userID string, recordIDs []string) ([]*BatchResult, error) {
+	brs, err := ts.RecordStorage.BatchDeleteRecords(ctx, userID, recordIDs)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while delete records, err: %w", err)
+	}
+
+	var ids []string
+	for _, br := range brs {
+		if br.Err == nil {
+			ids = append(ids, br.ID)
+		}
+	}
+
+	deleted, err := ts.RecordStorage.BatchGetRecords(ctx, userID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while retrieving deleted records, err: %w", err)
+	}
+
+	var rs []*Record
+	for _, br := range deleted {
+		if br.Err == nil {
+			rs = append(rs, br.Record)
+		}
+	}
+
+	return brs, ts.s.enqueue(rs...)
+}
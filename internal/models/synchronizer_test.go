@@ -0,0 +1,210 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestSynchronizer_Sync(t *testing.T) {
+	ctx := context.Background()
+	errSync := errors.New("server is unavailable")
+
+	tests := []struct {
+		name         string
+		remoteErr    error
+		wantFailures int
+		wantAck      bool
+	}{
+		{
+			name:         "positive case pending record is pushed and acked",
+			remoteErr:    nil,
+			wantFailures: 0,
+			wantAck:      true,
+		},
+		{
+			name:         "negative case remote storage is unavailable",
+			remoteErr:    errSync,
+			wantFailures: 1,
+			wantAck:      false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			local := NewMockRecordStorage(ctrl)
+			remote := NewMockRecordStorage(ctrl)
+			queue := NewMockPendingQueue(ctrl)
+
+			u := &User{ID: uuid.NewString()}
+			r := generateAuthRecord(t)
+
+			queue.EXPECT().Len().AnyTimes().Return(1)
+			queue.EXPECT().Records().Return([]*Record{r})
+
+			if tt.remoteErr != nil {
+				remote.EXPECT().ListRecords(gomock.Any(), u.ID, 0, DefaultLimit).Return(nil, tt.remoteErr)
+			} else {
+				remote.EXPECT().ListRecords(gomock.Any(), u.ID, 0, DefaultLimit).Return(nil, nil)
+				local.EXPECT().ListRecords(gomock.Any(), u.ID, 0, DefaultLimit).Return([]*Record{r}, nil)
+				local.EXPECT().ListRecords(gomock.Any(), u.ID, DefaultLimit, DefaultLimit).Return(nil, nil)
+				remote.EXPECT().BatchGetRecords(gomock.Any(), u.ID, []string{r.ID}).
+					Return([]*BatchResult{{ID: r.ID, Err: ErrRecordNotFound}}, nil)
+				remote.EXPECT().BatchUpsertRecords(gomock.Any(), u.ID, []*Record{r}).
+					Return([]*BatchResult{{ID: r.ID}}, nil)
+			}
+			if tt.wantAck {
+				queue.EXPECT().Ack(r).Return(nil)
+			}
+
+			s := NewSynchronizer(u, local, remote, queue, nil, time.Second)
+			err := s.Sync(ctx)
+			if (err != nil) != (tt.remoteErr != nil) {
+				t.Errorf("Synchronizer.Sync() error = %v, wantErr %v", err, tt.remoteErr)
+				return
+			}
+
+			st := s.Status()
+			if st.Failures != tt.wantFailures {
+				t.Errorf("Synchronizer.Status() failures = %d, want %d", st.Failures, tt.wantFailures)
+			}
+			if !errors.Is(st.LastErr, tt.remoteErr) {
+				t.Errorf("Synchronizer.Status() last error = %v, want %v", st.LastErr, tt.remoteErr)
+			}
+			if st.LastSync.IsZero() != (tt.remoteErr != nil) {
+				t.Errorf("Synchronizer.Status() last sync = %v", st.LastSync)
+			}
+		})
+	}
+}
+
+func TestSynchronizer_SyncRejected(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	local := NewMockRecordStorage(ctrl)
+	remote := NewMockRecordStorage(ctrl)
+	queue := NewMockPendingQueue(ctrl)
+
+	u := &User{ID: uuid.NewString()}
+	valid := generateAuthRecord(t)
+	invalid := generateAuthRecord(t)
+	errInvalid := fmt.Errorf("%w: invalid request: description: value length must be at most 300 characters",
+		ErrInvalidRecord)
+
+	queue.EXPECT().Len().AnyTimes().Return(2)
+	queue.EXPECT().Records().Return([]*Record{valid, invalid})
+	remote.EXPECT().ListRecords(gomock.Any(), u.ID, 0, DefaultLimit).Return(nil, nil)
+	local.EXPECT().ListRecords(gomock.Any(), u.ID, 0, DefaultLimit).Return([]*Record{valid, invalid}, nil)
+	local.EXPECT().ListRecords(gomock.Any(), u.ID, DefaultLimit, DefaultLimit).Return(nil, nil)
+	remote.EXPECT().BatchGetRecords(gomock.Any(), u.ID, []string{valid.ID, invalid.ID}).
+		Return([]*BatchResult{
+			{ID: valid.ID, Err: ErrRecordNotFound},
+			{ID: invalid.ID, Err: ErrRecordNotFound},
+		}, nil)
+	remote.EXPECT().BatchUpsertRecords(gomock.Any(), u.ID, []*Record{valid, invalid}).
+		Return([]*BatchResult{{ID: valid.ID}, {ID: invalid.ID, Err: errInvalid}}, nil)
+	// The rejected record stays in the queue until it is corrected.
+	queue.EXPECT().Ack(valid).Return(nil)
+
+	s := NewSynchronizer(u, local, remote, queue, nil, time.Second)
+	if err := s.Sync(ctx); err != nil {
+		t.Errorf("Synchronizer.Sync() error = %v, want nil", err)
+	}
+
+	st := s.Status()
+	if len(st.Rejected) != 1 || st.Rejected[0] != invalid.ID {
+		t.Errorf("Synchronizer.Status() rejected = %v, want [%s]", st.Rejected, invalid.ID)
+	}
+	if st.LastSync.IsZero() {
+		t.Errorf("Synchronizer.Status() last sync is zero, want the time of the sync")
+	}
+}
+
+func TestSynchronizer_delay(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int
+		want     time.Duration
+	}{
+		{name: "no failures", failures: 0, want: time.Second},
+		{name: "one failure", failures: 1, want: 2 * time.Second},
+		{name: "three failures", failures: 3, want: 8 * time.Second},
+		{name: "max backoff", failures: 100, want: MaxSyncBackoff},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Synchronizer{tick: time.Second, status: SyncStatus{Failures: tt.failures}}
+			if got := s.delay(); got != tt.want {
+				t.Errorf("Synchronizer.delay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSynchronizer_Local(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	local := NewMockRecordStorage(ctrl)
+	queue := NewMockPendingQueue(ctrl)
+
+	u := &User{ID: uuid.NewString()}
+	r := generateAuthRecord(t)
+
+	queue.EXPECT().Len().AnyTimes().Return(1)
+	local.EXPECT().UpdateRecord(gomock.Any(), u.ID, r).Return(r, nil)
+	queue.EXPECT().Push(r).Return(nil)
+
+	var got SyncStatus
+	s := NewSynchronizer(u, local, nil, queue, nil, time.Second)
+	s.OnChange(func(st SyncStatus) { got = st })
+
+	if _, err := u.UpdateRecord(ctx, s.Local(), r); err != nil {
+		t.Errorf("User.UpdateRecord() error = %v", err)
+		return
+	}
+	if got.Pending != 1 {
+		t.Errorf("Synchronizer.OnChange() pending = %d, want 1", got.Pending)
+	}
+}
+
+func TestSynchronizer_SetPullOnly(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	local := NewMockRecordStorage(ctrl)
+	remote := NewMockRecordStorage(ctrl)
+	queue := NewMockPendingQueue(ctrl)
+
+	u := &User{ID: uuid.NewString()}
+	pending := generateAuthRecord(t)
+	pulled := generateAuthRecord(t)
+
+	queue.EXPECT().Len().AnyTimes().Return(1)
+	queue.EXPECT().Records().Return([]*Record{pending})
+
+	// The local records are neither listed nor pushed and the queue is not acked.
+	remote.EXPECT().ListRecords(gomock.Any(), u.ID, 0, DefaultLimit).Return([]*Record{pulled}, nil)
+	remote.EXPECT().ListRecords(gomock.Any(), u.ID, DefaultLimit, DefaultLimit).Return(nil, nil)
+	local.EXPECT().BatchGetRecords(gomock.Any(), u.ID, []string{pulled.ID}).
+		Return([]*BatchResult{{ID: pulled.ID, Err: ErrRecordNotFound}}, nil)
+	local.EXPECT().BatchUpsertRecords(gomock.Any(), u.ID, []*Record{pulled}).
+		Return([]*BatchResult{{ID: pulled.ID}}, nil)
+
+	s := NewSynchronizer(u, local, remote, queue, nil, time.Second)
+	s.SetPullOnly(true)
+	if err := s.Sync(ctx); err != nil {
+		t.Errorf("Synchronizer.Sync() error = %v", err)
+		return
+	}
+
+	st := s.Status()
+	if st.LastSync.IsZero() || st.Pending != 1 {
+		t.Errorf("Synchronizer.Status() = %+v, want successful sync with 1 pending change", st)
+	}
+}
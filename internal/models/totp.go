@@ -0,0 +1,130 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha1" //nolint:gosec // RFC 6238 uses HMAC-SHA1 by default.
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	totpScheme        = "otpauth"
+	totpDefaultDigits = 6
+	totpDefaultPeriod = 30
+	totpMaxDigits     = 10
+)
+
+// ErrInvalidTOTP - The error is returned if the secret of the one-time password generator cannot be parsed.
+var ErrInvalidTOTP = errors.New("invalid TOTP secret")
+
+// TOTP - The generator of the time-based one-time passwords (RFC 6238).
+type TOTP struct {
+	hash   func() hash.Hash
+	secret []byte
+	digits int
+	period time.Duration
+}
+
+// NewTOTP - Object Constructor. Accepts the base32 secret or the otpauth://totp/ URI
+// with the optional algorithm, digits and period parameters.
+func NewTOTP(s string) (*TOTP, error) {
+	t := &TOTP{
+		hash:   sha1.New,
+		digits: totpDefaultDigits,
+		period: totpDefaultPeriod * time.Second,
+	}
+
+	s = strings.TrimSpace(s)
+	secret := s
+	if strings.HasPrefix(strings.ToLower(s), totpScheme+"://") {
+		u, err := url.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidTOTP, err)
+		}
+		if !strings.EqualFold(u.Host, "totp") {
+			return nil, fmt.Errorf("%w: unsupported type %s", ErrInvalidTOTP, u.Host)
+		}
+		if err := t.setParams(u.Query()); err != nil {
+			return nil, err
+		}
+		secret = u.Query().Get("secret")
+	}
+
+	secret = strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(secret))
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.TrimRight(secret, "="))
+	if err != nil || len(key) == 0 {
+		return nil, fmt.Errorf("%w: the secret is not a base32 string", ErrInvalidTOTP)
+	}
+	t.secret = key
+
+	return t, nil
+}
+
+func (t *TOTP) setParams(q url.Values) error {
+	switch strings.ToUpper(q.Get("algorithm")) {
+	case "", "SHA1":
+	case "SHA256":
+		t.hash = sha256.New
+	case "SHA512":
+		t.hash = sha512.New
+	default:
+		return fmt.Errorf("%w: unsupported algorithm %s", ErrInvalidTOTP, q.Get("algorithm"))
+	}
+
+	if d := q.Get("digits"); d != "" {
+		digits, err := strconv.Atoi(d)
+		if err != nil || digits < 1 || digits > totpMaxDigits {
+			return fmt.Errorf("%w: invalid digits %s", ErrInvalidTOTP, d)
+		}
+		t.digits = digits
+	}
+
+	if p := q.Get("period"); p != "" {
+		period, err := strconv.Atoi(p)
+		if err != nil || period < 1 {
+			return fmt.Errorf("%w: invalid period %s", ErrInvalidTOTP, p)
+		}
+		t.period = time.Duration(period) * time.Second
+	}
+
+	return nil
+}
+
+// Code - Returns the one-time password for the moment and the time left until it expires.
+func (t *TOTP) Code(now time.Time) (string, time.Duration) {
+	period := int64(t.period / time.Second)
+	counter := now.Unix() / period
+
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+
+	mac := hmac.New(t.hash, t.secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	const (
+		offsetMask = 0x0f
+		codeMask   = 0x7fffffff
+		decimal    = 10
+	)
+	offset := sum[len(sum)-1] & offsetMask
+	code := uint64(binary.BigEndian.Uint32(sum[offset:]) & codeMask)
+
+	mod := uint64(1)
+	for i := 0; i < t.digits; i++ {
+		mod *= decimal
+	}
+
+	left := time.Unix((counter+1)*period, 0).Sub(now)
+
+	return fmt.Sprintf("%0*d", t.digits, code%mod), left
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTOTP_Code(t *testing.T) {
+	// Test vectors of RFC 6238, the secrets are "12345678901234567890" repeated to the length of the hash.
+	const (
+		sha1Secret   = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+		sha256Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZA"
+		sha512Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ" +
+			"GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNA"
+	)
+
+	tests := []struct {
+		name     string
+		secret   string
+		want     string
+		unix     int64
+		wantLeft time.Duration
+	}{
+		{
+			name:     "plain secret",
+			secret:   "gezd gnbv gy3t qojq gezd gnbv gy3t qojq",
+			unix:     59,
+			want:     "287082",
+			wantLeft: time.Second,
+		},
+		{
+			name:     "sha1",
+			secret:   "otpauth://totp/Example:user?secret=" + sha1Secret + "&digits=8&issuer=Example",
+			unix:     1111111109,
+			want:     "07081804",
+			wantLeft: 1 * time.Second,
+		},
+		{
+			name:     "sha256",
+			secret:   "otpauth://totp/user?secret=" + sha256Secret + "&algorithm=SHA256&digits=8",
+			unix:     1234567890,
+			want:     "91819424",
+			wantLeft: 30 * time.Second,
+		},
+		{
+			name:     "sha512",
+			secret:   "otpauth://totp/user?secret=" + sha512Secret + "&algorithm=SHA512&digits=8",
+			unix:     20000000000,
+			want:     "47863826",
+			wantLeft: 10 * time.Second,
+		},
+		{
+			name:     "period",
+			secret:   "otpauth://totp/user?secret=" + sha1Secret + "&period=60",
+			unix:     90,
+			want:     "287082",
+			wantLeft: 30 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			totp, err := NewTOTP(tt.secret)
+			if err != nil {
+				t.Fatalf("NewTOTP() error = %v", err)
+			}
+			got, left := totp.Code(time.Unix(tt.unix, 0))
+			if got != tt.want || left != tt.wantLeft {
+				t.Errorf("TOTP.Code() = %v, %v, want %v, %v", got, left, tt.want, tt.wantLeft)
+			}
+		})
+	}
+}
+
+func TestNewTOTP_Invalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"not base32!",
+		"otpauth://hotp/user?secret=GEZDGNBV",
+		"otpauth://totp/user?secret=GEZDGNBV&algorithm=MD5",
+		"otpauth://totp/user?secret=GEZDGNBV&digits=0",
+		"otpauth://totp/user?secret=GEZDGNBV&period=-1",
+	} {
+		if _, err := NewTOTP(s); !errors.Is(err, ErrInvalidTOTP) {
+			t.Errorf("NewTOTP(%q) error = %v, wantErr %v", s, err, ErrInvalidTOTP)
+		}
+	}
+}
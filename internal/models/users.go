@@ -7,6 +7,10 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/ArtemShalinFe/gophkeeper/internal/vectors"
 )
@@ -22,8 +26,13 @@ type User struct {
 	ID           string `cbor:"uuid"`
 	Login        string `cbor:"login"`
 	PasswordHash string `cbor:"password"`
+	// Disabled - the user has been disabled by the administrator, it is not kept in the session.
+	Disabled bool `cbor:"-"`
 }
 
+// tracer - traces the synchronization of the storages.
+var tracer = otel.Tracer("github.com/ArtemShalinFe/gophkeeper/internal/models")
+
 // ErrLoginIsBusy - The error is returned if the username is already occupied.
 var ErrLoginIsBusy = errors.New("login is busy")
 
@@ -133,20 +142,29 @@ func (u *User) DeleteRecord(ctx context.Context, db RecordStorage, recordID stri
 	return nil
 }
 
+// SyncReporter - An optional interface of the storage that is notified about every successful synchronization.
+type SyncReporter interface {
+	// ReportSync - called after the user records have been synchronized.
+	ReportSync(ctx context.Context, userID string) error
+}
+
 // SyncRecords - This method is used to synchronize user records between repositories.
-func (u *User) SyncRecords(ctx context.Context, stg1 RecordStorage, stg2 RecordStorage, tick int) error {
-	const t = "an error occured while sync stg1 (%T) with stg2 (%T), err: %w"
+//
+// Conflicts are reported to the handler and left in place until they are resolved through the normal update path.
+// If the handler is nil, both versions of the conflicting record are kept.
+func (u *User) SyncRecords(ctx context.Context,
+	local RecordStorage,
+	remote RecordStorage,
+	tick int,
+	handler ConflictHandler) error {
+	pull, push := u.conflictFuncs(local, remote, handler)
 
 	ticker := time.NewTicker(time.Second * time.Duration(tick))
 
 syncloop:
 	for {
-		if err := u.syncStorages(ctx, stg1, stg2); err != nil {
-			return fmt.Errorf(t, stg1, stg2, err)
-		}
-
-		if err := u.syncStorages(ctx, stg2, stg1); err != nil {
-			return fmt.Errorf(t, stg2, stg1, err)
+		if err := u.syncOnce(ctx, local, remote, pull, push, nil); err != nil {
+			return err
 		}
 
 		select {
@@ -159,54 +177,220 @@ syncloop:
 	return nil
 }
 
-func (u *User) syncStorages(ctx context.Context, stg1 RecordStorage, stg2 RecordStorage) error {
-	offset := 0
-	for {
-		stg2rs, err := stg2.ListRecords(ctx, u.ID, offset, DefaultLimit)
+// conflictFuncs - returns conflict funcs for pulling records from the remote storage
+// and for pushing records to the remote storage.
+func (u *User) conflictFuncs(local RecordStorage,
+	remote RecordStorage,
+	handler ConflictHandler) (conflictFunc, conflictFunc) {
+	if handler == nil {
+		return u.keepBoth(local, remote), u.keepBoth(remote, local)
+	}
+
+	pull := func(ctx context.Context, dst *Record, src *Record) error {
+		handler.HandleConflict(ctx, &Conflict{Local: dst, Remote: src})
+		return nil
+	}
+	push := func(ctx context.Context, dst *Record, src *Record) error {
+		handler.HandleConflict(ctx, &Conflict{Local: src, Remote: dst})
+		return nil
+	}
+
+	return pull, push
+}
+
+// syncOnce - pulls records from the remote storage, pushes records to the remote storage and reports the sync.
+func (u *User) syncOnce(ctx context.Context,
+	local RecordStorage,
+	remote RecordStorage,
+	pull conflictFunc,
+	push conflictFunc,
+	reject rejectFunc) error {
+	const t = "an error occured while sync stg1 (%T) with stg2 (%T), err: %w"
+
+	if err := u.syncStorages(ctx, local, remote, pull, reject); err != nil {
+		return fmt.Errorf(t, local, remote, err)
+	}
+
+	if err := u.syncStorages(ctx, remote, local, push, reject); err != nil {
+		return fmt.Errorf(t, remote, local, err)
+	}
+
+	if err := reportSync(ctx, u.ID, local, remote); err != nil {
+		return fmt.Errorf("an error occured while report sync, err: %w", err)
+	}
+
+	return nil
+}
+
+func reportSync(ctx context.Context, userID string, stgs ...RecordStorage) error {
+	for _, stg := range stgs {
+		if sr, ok := stg.(SyncReporter); ok {
+			if err := sr.ReportSync(ctx, userID); err != nil {
+				return fmt.Errorf("storage %T, err: %w", stg, err)
+			}
+		}
+	}
+	return nil
+}
+
+// conflictFunc - called with the version of the record from the destination storage
+// and the version of the record from the source storage.
+type conflictFunc func(ctx context.Context, dst *Record, src *Record) error
+
+// rejectFunc - called with the record that the destination storage has rejected as invalid
+// and the error of the storage, may be nil.
+type rejectFunc func(ctx context.Context, r *Record, err error)
+
+// keepBoth - returns a conflict func that overwrites the destination version by the source version
+// and stores a copy of the destination version with a new ID in the source storage.
+func (u *User) keepBoth(stg1 RecordStorage, stg2 RecordStorage) conflictFunc {
+	return func(ctx context.Context, r1 *Record, r2 *Record) error {
+		if _, err := stg1.UpdateRecord(ctx, u.ID, r2); err != nil {
+			return fmt.Errorf(errSyncRecordTmp, r2.ID, err)
+		}
+
+		r1.ID = uuid.NewString()
+		r1.Description = fmt.Sprintf("(COPY) %s", r1.Description)
+		if _, err := stg2.UpdateRecord(ctx, u.ID, r1); err != nil {
+			return fmt.Errorf(errSyncRecordTmp, r1.ID, err)
+		}
+		return nil
+	}
+}
+
+func (u *User) syncStorages(ctx context.Context,
+	stg1 RecordStorage,
+	stg2 RecordStorage,
+	conflict conflictFunc,
+	reject rejectFunc) error {
+	for offset := 0; ; offset += DefaultLimit {
+		n, err := u.syncPage(ctx, stg1, stg2, conflict, reject, offset)
 		if err != nil {
-			return fmt.Errorf("an error occured while retrieving list records from server, err: %w", err)
+			return err
+		}
+		if n == 0 {
+			return nil
 		}
+	}
+}
 
-		if len(stg2rs) == 0 {
-			break
+// syncPage - synchronizes one page of the records of stg2 with stg1 and returns the number of the records on the page.
+// Every page is traced by its own span, so the time of the storages and of the network can be told apart.
+func (u *User) syncPage(ctx context.Context,
+	stg1 RecordStorage,
+	stg2 RecordStorage,
+	conflict conflictFunc,
+	reject rejectFunc,
+	offset int) (n int, err error) {
+	ctx, span := tracer.Start(ctx, "syncStorages", trace.WithAttributes(
+		attribute.String("gophkeeper.sync.src", fmt.Sprintf("%T", stg2)),
+		attribute.String("gophkeeper.sync.dst", fmt.Sprintf("%T", stg1)),
+		attribute.Int("gophkeeper.sync.offset", offset),
+	))
+	defer func() {
+		span.SetAttributes(attribute.Int("gophkeeper.sync.records", n))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
 		}
+		span.End()
+	}()
 
-		for _, r2 := range stg2rs {
-			r1, err := stg1.GetRecord(ctx, u.ID, r2.ID)
-			if err != nil {
-				if !errors.Is(err, ErrRecordNotFound) {
-					return fmt.Errorf("an error occured while trying update record(ID=%s), err: %w", r2.ID, err)
-				}
-				_, err = stg1.UpdateRecord(ctx, u.ID, r2)
-				if err != nil {
-					return fmt.Errorf(errSyncRecordTmp, r2.ID, err)
-				}
-				continue
-			}
+	stg2rs, err := stg2.ListRecords(ctx, u.ID, offset, DefaultLimit)
+	if err != nil {
+		return 0, fmt.Errorf("an error occured while retrieving list records from server, err: %w", err)
+	}
 
-			switch vectors.NewComparison(r2, r1).Compare() {
-			case vectors.VectorAIsEqualsVectorB:
-			case vectors.VectorAIsHigherVectorB:
-				if _, err := stg1.UpdateRecord(ctx, u.ID, r2); err != nil {
-					return fmt.Errorf(errSyncRecordTmp, r2.ID, err)
-				}
-			case vectors.VectorAIsLowerVectorB:
-				if _, err := stg2.UpdateRecord(ctx, u.ID, r1); err != nil {
-					return fmt.Errorf(errSyncRecordTmp, r1.ID, err)
-				}
-			default:
-				if _, err := stg1.UpdateRecord(ctx, u.ID, r2); err != nil {
-					return fmt.Errorf(errSyncRecordTmp, r2.ID, err)
-				}
-
-				r1.ID = uuid.NewString()
-				r1.Description = fmt.Sprintf("(COPY) %s", r1.Description)
-				if _, err := stg2.UpdateRecord(ctx, u.ID, r1); err != nil {
-					return fmt.Errorf(errSyncRecordTmp, r1.ID, err)
-				}
+	if len(stg2rs) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]string, len(stg2rs))
+	for i, r2 := range stg2rs {
+		ids[i] = r2.ID
+	}
+
+	stg1brs, err := stg1.BatchGetRecords(ctx, u.ID, ids)
+	if err != nil {
+		return 0, fmt.Errorf("an error occured while retrieving records, err: %w", err)
+	}
+	if len(stg1brs) != len(ids) {
+		return 0, fmt.Errorf("an error occured while retrieving records, got %d results for %d records",
+			len(stg1brs), len(ids))
+	}
+
+	var to1 []*Record
+	var to2 []*Record
+	for i, r2 := range stg2rs {
+		br := stg1brs[i]
+		if br.Err != nil {
+			if !errors.Is(br.Err, ErrRecordNotFound) {
+				return 0, fmt.Errorf("an error occured while trying update record(ID=%s), err: %w", r2.ID, br.Err)
+			}
+			to1 = append(to1, r2)
+			continue
+		}
+		r1 := br.Record
+
+		switch vectors.NewComparison(r2, r1).Compare() {
+		case vectors.VectorAIsEqualsVectorB:
+		case vectors.VectorAIsHigherVectorB:
+			to1 = append(to1, r2)
+		case vectors.VectorAIsLowerVectorB:
+			to2 = append(to2, r1)
+		default:
+			if err := conflict(ctx, r1, r2); err != nil {
+				return 0, err
 			}
 		}
-		offset += DefaultLimit
+	}
+
+	if err := u.upsertRecords(ctx, stg1, to1, reject); err != nil {
+		return 0, err
+	}
+	if err := u.upsertRecords(ctx, stg2, to2, reject); err != nil {
+		return 0, err
+	}
+
+	span.SetAttributes(
+		attribute.Int("gophkeeper.sync.dst_updated", len(to1)),
+		attribute.Int("gophkeeper.sync.src_updated", len(to2)),
+	)
+
+	return len(stg2rs), nil
+}
+
+// upsertRecords - writes the records to the storage. The records that the storage rejects as invalid
+// are passed to reject and do not fail the synchronization, the other failed records do.
+func (u *User) upsertRecords(ctx context.Context, stg RecordStorage, rs []*Record, reject rejectFunc) error {
+	if len(rs) == 0 {
+		return nil
+	}
+
+	brs, err := stg.BatchUpsertRecords(ctx, u.ID, rs)
+	if err != nil {
+		return fmt.Errorf("an error occured while sync records, err: %w", err)
+	}
+
+	failed := make([]*BatchResult, 0, len(brs))
+	for i, br := range brs {
+		if br.Err == nil {
+			continue
+		}
+		if !errors.Is(br.Err, ErrInvalidRecord) || i >= len(rs) {
+			failed = append(failed, br)
+			continue
+		}
+
+		trace.SpanFromContext(ctx).AddEvent("record rejected", trace.WithAttributes(
+			attribute.String("gophkeeper.record.id", br.ID),
+		))
+		if reject != nil {
+			reject(ctx, rs[i], br.Err)
+		}
+	}
+	if err := BatchErr(failed); err != nil {
+		return fmt.Errorf("an error occured while sync records, err: %w", err)
 	}
 
 	return nil
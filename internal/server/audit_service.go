@@ -0,0 +1,191 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+// maxAuditDetails - the maximum length of the details of the event, the longer details are cut.
+const maxAuditDetails = 300
+
+// errAuditUnavailable - The error is returned if the server has no audit storage.
+var errAuditUnavailable = errors.New("audit log is not available")
+
+// auditLog - Writes the actions of the users to the audit storage.
+// The errors of the storage are logged and do not fail the requests of the users.
+type auditLog struct {
+	log *zap.Logger
+	stg models.AuditStorage
+	now func() time.Time
+}
+
+func newAuditLog(log *zap.Logger) *auditLog {
+	return &auditLog{
+		log: log,
+		now: time.Now,
+	}
+}
+
+// event - returns the event of the request: the device and the IP address are taken from the request.
+func (al *auditLog) event(ctx context.Context, userID string, action models.AuditAction) *models.AuditEvent {
+	e := &models.AuditEvent{
+		UserID: userID,
+		Action: action,
+		Time:   al.now(),
+		PeerIP: peerIP(ctx),
+	}
+	// The device header of the login is not checked, so only the well-formed IDs are written.
+	if did, err := getDeviceIDFromContext(ctx); err == nil {
+		if _, err := uuid.Parse(did); err == nil {
+			e.DeviceID = did
+		}
+	}
+	return e
+}
+
+// add - writes the event of the request, details must not contain the secret contents of the records.
+func (al *auditLog) add(ctx context.Context, userID string, action models.AuditAction, details string) {
+	e := al.event(ctx, userID, action)
+	e.Details = truncate(details, maxAuditDetails)
+	al.write(ctx, e)
+}
+
+// addRecords - writes one event of the request for every record.
+func (al *auditLog) addRecords(ctx context.Context, userID string, action models.AuditAction, recordIDs ...string) {
+	es := make([]*models.AuditEvent, 0, len(recordIDs))
+	for _, id := range recordIDs {
+		e := al.event(ctx, userID, action)
+		e.RecordID = id
+		es = append(es, e)
+	}
+	al.write(ctx, es...)
+}
+
+func (al *auditLog) write(ctx context.Context, es ...*models.AuditEvent) {
+	if al == nil || al.stg == nil || len(es) == 0 {
+		return
+	}
+	// The event is written even if the client has cancelled the request after the action was done.
+	if err := al.stg.AddAuditEvents(context.WithoutCancel(ctx), es); err != nil {
+		al.log.Error("an error occurred while write audit events",
+			zap.String("action", string(es[0].Action)),
+			zap.Int("count", len(es)),
+			zap.Error(err))
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	s = s[:n]
+	for !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// AuditService - Implements GRPC server methods that are responsible for the audit log of the user.
+type AuditService struct {
+	UnimplementedAuditServer
+	log   *zap.Logger
+	audit *auditLog
+}
+
+// NewAuditService - Object Constructor.
+func NewAuditService(log *zap.Logger) *AuditService {
+	return &AuditService{
+		log:   log,
+		audit: newAuditLog(log),
+	}
+}
+
+// ListAuditEvents - used to retrieving the audit log of the user, the latest events come first.
+func (as *AuditService) ListAuditEvents(ctx context.Context,
+	request *ListAuditEventsRequest) (*ListAuditEventsResponse, error) {
+	var resp ListAuditEventsResponse
+
+	uid, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return &resp, status.Errorf(codes.Unauthenticated, fmt.Sprintf(errUnauthenticatedTemplate, err))
+	}
+
+	if as.audit.stg == nil {
+		return &resp, status.Error(codes.Unimplemented, errAuditUnavailable.Error())
+	}
+
+	if request.GetOffset() < 0 {
+		return &resp, status.Error(codes.InvalidArgument, "offset must not be negative")
+	}
+	limit := int(request.GetLimit())
+	if limit <= 0 {
+		limit = models.DefaultLimit
+	}
+	limit = min(limit, models.MaxBatchSize)
+
+	u := &models.User{ID: uid}
+	es, err := u.GetAuditEvents(ctx, as.audit.stg, int(request.GetOffset()), limit)
+	if err != nil {
+		return &resp, status.Errorf(codes.Internal,
+			fmt.Sprintf("an occured error while retrieving audit events from storage, err: %v", err))
+	}
+
+	for _, e := range es {
+		resp.Events = append(resp.Events, convAuditEventToProtobuff(e))
+	}
+
+	return &resp, nil
+}
+
+// ReportExport - used to write the export of the records to the audit log.
+func (as *AuditService) ReportExport(ctx context.Context, request *ReportExportRequest) (*ReportExportResponse, error) {
+	var resp ReportExportResponse
+
+	uid, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return &resp, status.Errorf(codes.Unauthenticated, fmt.Sprintf(errUnauthenticatedTemplate, err))
+	}
+
+	as.audit.add(ctx, uid, models.AuditExport,
+		fmt.Sprintf("format: %s, records: %d", request.GetFormat(), request.GetRecords()))
+
+	return &resp, nil
+}
+
+func convAuditEventToProtobuff(e *models.AuditEvent) *AuditEvent {
+	return &AuditEvent{
+		Id:         e.ID,
+		Action:     string(e.Action),
+		Time:       timestamppb.New(e.Time),
+		DeviceId:   e.DeviceID,
+		DeviceName: e.DeviceName,
+		PeerIp:     e.PeerIP,
+		RecordId:   e.RecordID,
+		Details:    e.Details,
+	}
+}
+
+func convAuditEventFromProtobuff(userID string, e *AuditEvent) *models.AuditEvent {
+	return &models.AuditEvent{
+		ID:         e.GetId(),
+		UserID:     userID,
+		Action:     models.AuditAction(e.GetAction()),
+		Time:       e.GetTime().AsTime(),
+		DeviceID:   e.GetDeviceId(),
+		DeviceName: e.GetDeviceName(),
+		PeerIP:     e.GetPeerIp(),
+		RecordID:   e.GetRecordId(),
+		Details:    e.GetDetails(),
+	}
+}
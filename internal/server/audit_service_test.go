@@ -0,0 +1,225 @@
+package server
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	gomock "go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+// auditContext - returns the context of the request of the device from the address.
+func auditContext(userID string) context.Context {
+	ctx := metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs(userIDHeader, userID, deviceIDHeader, testDeviceID))
+	return peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 50000}})
+}
+
+// capturedEvents - returns the storage that keeps the written events.
+func capturedEvents(t *testing.T, events *[]*models.AuditEvent) *MockAuditStorage {
+	t.Helper()
+
+	as := NewMockAuditStorage(gomock.NewController(t))
+	as.EXPECT().AddAuditEvents(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(_ context.Context, es []*models.AuditEvent) error {
+			*events = append(*events, es...)
+			return nil
+		})
+	return as
+}
+
+func TestAuditService_ListAuditEvents(t *testing.T) {
+	u := user(t)
+	events := []*models.AuditEvent{{ID: 2, UserID: u.ID, Action: models.AuditLogin, DeviceID: testDeviceID}}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		limit     int32
+		noStorage bool
+		wantLimit int
+		wantCode  codes.Code
+	}{
+		{
+			name:     "user is not specified",
+			ctx:      context.Background(),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:      "audit log is not available",
+			ctx:       auditContext(u.ID),
+			noStorage: true,
+			wantCode:  codes.Unimplemented,
+		},
+		{
+			name:      "default limit",
+			ctx:       auditContext(u.ID),
+			wantLimit: models.DefaultLimit,
+			wantCode:  codes.OK,
+		},
+		{
+			name:      "limit is too large",
+			ctx:       auditContext(u.ID),
+			limit:     models.MaxBatchSize + 1,
+			wantLimit: models.MaxBatchSize,
+			wantCode:  codes.OK,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			srv := NewAuditService(zap.L())
+			if !tt.noStorage {
+				as := NewMockAuditStorage(gomock.NewController(t))
+				if tt.wantCode == codes.OK {
+					as.EXPECT().ListAuditEvents(gomock.Any(), u.ID, 0, tt.wantLimit).Return(events, nil)
+				}
+				srv.audit.stg = as
+			}
+
+			resp, err := srv.ListAuditEvents(tt.ctx, &ListAuditEventsRequest{Limit: tt.limit})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("AuditService.ListAuditEvents() error = %v, want code %v", err, tt.wantCode)
+			}
+			if tt.wantCode == codes.OK && len(resp.GetEvents()) != len(events) {
+				t.Errorf("AuditService.ListAuditEvents() events = %d, want %d", len(resp.GetEvents()), len(events))
+			}
+		})
+	}
+}
+
+func TestUsersService_LoginAudit(t *testing.T) {
+	u := user(t)
+
+	tests := []struct {
+		name        string
+		password    string
+		wantAction  models.AuditAction
+		wantDetails string
+	}{
+		{
+			name:       "successful login",
+			password:   strings.Repeat(gophkeeper, 2),
+			wantAction: models.AuditLogin,
+		},
+		{
+			name:        "wrong password",
+			password:    gophkeeper,
+			wantAction:  models.AuditLoginFailed,
+			wantDetails: "wrong password",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			us := NewMockUserStorage(gomock.NewController(t))
+			us.EXPECT().GetUser(gomock.Any(), gomock.Any()).Return(u, nil)
+
+			var events []*models.AuditEvent
+			srv := NewUsersService(zap.L(), us)
+			srv.audit.stg = capturedEvents(t, &events)
+
+			_, _ = srv.Login(auditContext(""), &LoginRequest{Login: u.Login, Password: tt.password})
+
+			if len(events) != 1 {
+				t.Fatalf("UsersService.Login() wrote %d audit events, want 1", len(events))
+			}
+			e := events[0]
+			if e.Action != tt.wantAction || e.Details != tt.wantDetails || e.UserID != u.ID {
+				t.Errorf("UsersService.Login() audit event = %+v, want action %s and details %q",
+					e, tt.wantAction, tt.wantDetails)
+			}
+			if e.DeviceID != testDeviceID || e.PeerIP != "192.0.2.1" {
+				t.Errorf("UsersService.Login() audit event device = %s, ip = %s, want %s, 192.0.2.1",
+					e.DeviceID, e.PeerIP, testDeviceID)
+			}
+		})
+	}
+}
+
+func TestRecordsService_BatchDeleteAudit(t *testing.T) {
+	u := user(t)
+	deleted := randomUUID
+	missing := "missing"
+
+	rs := NewMockRecordStorage(gomock.NewController(t))
+	rs.EXPECT().BatchDeleteRecords(gomock.Any(), u.ID, []string{deleted, missing}).Return([]*models.BatchResult{
+		{ID: deleted},
+		{ID: missing, Err: models.ErrRecordNotFound},
+	}, nil)
+
+	var events []*models.AuditEvent
+	srv := NewRecordsService(zap.L(), rs)
+	srv.audit.stg = capturedEvents(t, &events)
+
+	_, err := srv.BatchDeleteRecords(auditContext(u.ID), &BatchDeleteRecordsRequest{Ids: []string{deleted, missing}})
+	if err != nil {
+		t.Fatalf("RecordsService.BatchDeleteRecords() error = %v", err)
+	}
+
+	if len(events) != 1 || events[0].Action != models.AuditRecordDelete || events[0].RecordID != deleted {
+		t.Errorf("RecordsService.BatchDeleteRecords() audit events = %+v, want one deletion of %s", events, deleted)
+	}
+}
+
+func TestPeerIP(t *testing.T) {
+	loopback := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 50000}
+	remote := &net.TCPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 50000}
+
+	tests := []struct {
+		name string
+		addr net.Addr
+		md   metadata.MD
+		want string
+	}{
+		{
+			name: "direct client",
+			addr: remote,
+			want: "192.0.2.1",
+		},
+		{
+			name: "gateway forwards the client address",
+			addr: loopback,
+			md:   metadata.Pairs(forwardedForHeader, "198.51.100.7"),
+			want: "198.51.100.7",
+		},
+		{
+			name: "only the address appended by the gateway is trusted",
+			addr: loopback,
+			md:   metadata.Pairs(forwardedForHeader, "203.0.113.9, 198.51.100.7"),
+			want: "198.51.100.7",
+		},
+		{
+			name: "forwarded address of the remote client is ignored",
+			addr: remote,
+			md:   metadata.Pairs(forwardedForHeader, "198.51.100.7"),
+			want: "192.0.2.1",
+		},
+		{
+			name: "invalid forwarded address",
+			addr: loopback,
+			md:   metadata.Pairs(forwardedForHeader, "unknown"),
+			want: "127.0.0.1",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: tt.addr})
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+			if got := peerIP(ctx); got != tt.want {
+				t.Errorf("peerIP() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+const deviceIDHeader = "deviceid"
+
+// errWrongCredentials - the message of the error that is returned if the login or the password of the user is wrong.
+const errWrongCredentials = "wrong login or password"
+
+// DevicesService - Implements GRPC server methods that are responsible for working with user devices in storage.
+type DevicesService struct {
+	UnimplementedDevicesServer
+	log           *zap.Logger
+	deviceStorage models.DeviceStorage
+	userStorage   models.UserStorage
+	audit         *auditLog
+}
+
+// NewDevicesService - Object Constructor.
+// The user storage is used to check the credentials of the user when the device is registered.
+func NewDevicesService(log *zap.Logger,
+	deviceStorage models.DeviceStorage,
+	userStorage models.UserStorage) *DevicesService {
+	return &DevicesService{
+		log:           log,
+		deviceStorage: deviceStorage,
+		userStorage:   userStorage,
+		audit:         newAuditLog(log),
+	}
+}
+
+// RegisterDevice - used to register the client device.
+// The request has no device yet, so the login and the password of the user are checked instead,
+// otherwise a revoked device could register itself once more with the user ID only.
+func (ds *DevicesService) RegisterDevice(ctx context.Context,
+	request *RegisterDeviceRequest) (*RegisterDeviceResponse, error) {
+	var resp RegisterDeviceResponse
+
+	uid, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return &resp, status.Errorf(codes.Unauthenticated, fmt.Sprintf(errUnauthenticatedTemplate, err))
+	}
+
+	if err := ds.checkCredentials(ctx, uid, request); err != nil {
+		return &resp, err
+	}
+
+	u := &models.User{ID: uid}
+	if err := u.CheckEnabled(ctx, ds.deviceStorage); err != nil {
+		if errors.Is(err, models.ErrUserDisabled) {
+			return &resp, status.Error(codes.Unauthenticated, models.ErrUserDisabled.Error())
+		}
+		return &resp, status.Errorf(codes.Internal,
+			fmt.Sprintf("an error occurred while checking user, err: %v", err))
+	}
+
+	if err := u.CheckDeviceName(ctx, ds.deviceStorage, request.GetName()); err != nil {
+		if errors.Is(err, models.ErrDeviceRevoked) {
+			return &resp, status.Errorf(codes.PermissionDenied, models.ErrDeviceRevoked.Error())
+		}
+		return &resp, status.Errorf(codes.Internal,
+			fmt.Sprintf("an error occurred while checking device name, err: %v", err))
+	}
+
+	d, err := u.RegisterDevice(ctx, ds.deviceStorage, &models.DeviceDTO{
+		Name:     request.GetName(),
+		Platform: request.GetPlatform(),
+		Version:  request.GetVersion(),
+	})
+	if err != nil {
+		return &resp, status.Errorf(codes.Internal,
+			fmt.Sprintf("an error occurred while register device in storage, err: %v", err))
+	}
+
+	// The event belongs to the registered device, the request has no device yet.
+	e := ds.audit.event(ctx, uid, models.AuditDeviceRegister)
+	e.DeviceID = d.ID
+	e.Details = truncate(fmt.Sprintf("name: %s, platform: %s", d.Name, d.Platform), maxAuditDetails)
+	ds.audit.write(ctx, e)
+
+	resp.Device = convDeviceToProtobuff(d)
+	return &resp, nil
+}
+
+// ListDevices - used to retrieving user devices.
+func (ds *DevicesService) ListDevices(ctx context.Context, request *ListDevicesRequest) (*ListDevicesResponse, error) {
+	var resp ListDevicesResponse
+
+	uid, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return &resp, status.Errorf(codes.Unauthenticated, fmt.Sprintf(errUnauthenticatedTemplate, err))
+	}
+
+	u := &models.User{ID: uid}
+	devices, err := u.GetDevices(ctx, ds.deviceStorage)
+	if err != nil {
+		return &resp, status.Errorf(codes.Internal,
+			fmt.Sprintf("an occured error while retrieving device list from storage, err: %v", err))
+	}
+
+	for _, d := range devices {
+		resp.Devices = append(resp.Devices, convDeviceToProtobuff(d))
+	}
+
+	return &resp, nil
+}
+
+// RevokeDevice - mark device as revoked. All subsequent requests from the device are rejected.
+func (ds *DevicesService) RevokeDevice(ctx context.Context,
+	request *RevokeDeviceRequest) (*RevokeDeviceResponse, error) {
+	var resp RevokeDeviceResponse
+
+	uid, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return &resp, status.Errorf(codes.Unauthenticated, fmt.Sprintf(errUnauthenticatedTemplate, err))
+	}
+
+	u := &models.User{ID: uid}
+	if err := u.RevokeDevice(ctx, ds.deviceStorage, request.GetId()); err != nil {
+		if errors.Is(err, models.ErrDeviceNotFound) {
+			return &resp, status.Errorf(codes.NotFound, models.ErrDeviceNotFound.Error())
+		}
+		return &resp, status.Errorf(codes.Internal,
+			fmt.Sprintf("an error occurred while revoke device in storage, err: %v", err))
+	}
+	ds.audit.add(ctx, uid, models.AuditDeviceRevoke, "revoked device: "+request.GetId())
+
+	return &resp, nil
+}
+
+// ReportSync - used to report the successful synchronization of the device.
+func (ds *DevicesService) ReportSync(ctx context.Context, request *ReportSyncRequest) (*ReportSyncResponse, error) {
+	var resp ReportSyncResponse
+
+	uid, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return &resp, status.Errorf(codes.Unauthenticated, fmt.Sprintf(errUnauthenticatedTemplate, err))
+	}
+
+	did, err := getDeviceIDFromContext(ctx)
+	if err != nil {
+		return &resp, status.Errorf(codes.Unauthenticated, fmt.Sprintf(errUnauthenticatedTemplate, err))
+	}
+
+	if err := ds.deviceStorage.TouchDevice(ctx, uid, did, true); err != nil {
+		return &resp, status.Errorf(codes.Internal,
+			fmt.Sprintf("an error occurred while report sync in storage, err: %v", err))
+	}
+
+	return &resp, nil
+}
+
+// checkCredentials - checks that the login and the password of the request belong to the user of the request headers.
+func (ds *DevicesService) checkCredentials(ctx context.Context, userID string, request userRequest) error {
+	udto := getUserDTOFromRequest(request)
+	u, err := udto.GetUser(ctx, ds.userStorage)
+	if err != nil {
+		if errors.Is(err, models.ErrUnknowUser) {
+			return status.Error(codes.Unauthenticated, errWrongCredentials)
+		}
+		return status.Errorf(codes.Internal,
+			fmt.Sprintf("an error occurred while checking user, err: %v", err))
+	}
+
+	if u.ID != userID {
+		return status.Error(codes.Unauthenticated, errWrongCredentials)
+	}
+	if !checkPasswordHash(u.PasswordHash, udto.Password) {
+		ds.audit.add(ctx, userID, models.AuditLoginFailed, "wrong password of the device registration")
+		return status.Error(codes.Unauthenticated, errWrongCredentials)
+	}
+
+	return nil
+}
+
+// checkDevice - checks that the user is not disabled and the device from the request headers belongs to the user
+// and has not been revoked.
+func (ds *DevicesService) checkDevice(ctx context.Context) error {
+	uid, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return status.Errorf(codes.Unauthenticated, fmt.Sprintf(errUnauthenticatedTemplate, err))
+	}
+
+	did, err := getDeviceIDFromContext(ctx)
+	if err != nil {
+		return status.Errorf(codes.Unauthenticated, fmt.Sprintf(errUnauthenticatedTemplate, err))
+	}
+
+	u := &models.User{ID: uid}
+	if err := u.CheckDevice(ctx, ds.deviceStorage, did, false); err != nil {
+		switch {
+		case errors.Is(err, models.ErrUserDisabled):
+			return status.Error(codes.Unauthenticated, models.ErrUserDisabled.Error())
+		case errors.Is(err, models.ErrDeviceRevoked):
+			return status.Errorf(codes.PermissionDenied, models.ErrDeviceRevoked.Error())
+		case errors.Is(err, models.ErrDeviceNotFound):
+			return status.Errorf(codes.Unauthenticated, models.ErrDeviceNotFound.Error())
+		default:
+			return status.Errorf(codes.Internal,
+				fmt.Sprintf("an error occurred while checking device, err: %v", err))
+		}
+	}
+
+	return nil
+}
+
+func convDeviceToProtobuff(d *models.Device) *Device {
+	dpb := &Device{
+		Id:       d.ID,
+		Name:     d.Name,
+		Platform: d.Platform,
+		Version:  d.Version,
+		Created:  timestamppb.New(d.Created),
+		LastSeen: timestamppb.New(d.LastSeen),
+		Revoked:  d.Revoked,
+	}
+	if !d.LastSync.IsZero() {
+		dpb.LastSync = timestamppb.New(d.LastSync)
+	}
+	return dpb
+}
+
+func convDeviceFromProtobuff(userID string, d *Device) *models.Device {
+	md := &models.Device{
+		ID:       d.GetId(),
+		Owner:    userID,
+		Name:     d.GetName(),
+		Platform: d.GetPlatform(),
+		Version:  d.GetVersion(),
+		Created:  d.GetCreated().AsTime(),
+		LastSeen: d.GetLastSeen().AsTime(),
+		Revoked:  d.GetRevoked(),
+	}
+	if d.GetLastSync() != nil {
+		md.LastSync = d.GetLastSync().AsTime()
+	}
+	return md
+}
@@ -0,0 +1,298 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/config"
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+	"github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func NewDevicesServiceDialer(t *testing.T, ds models.DeviceStorage, us models.UserStorage) *recordDialer {
+	const bufSize = 1024 * 1024
+	lis := bufconn.Listen(bufSize)
+
+	log := zap.L()
+	s, err := InitServer(nil, us, ds, nil, log, config.NewServerCfg())
+	if err != nil {
+		t.Fatalf("an occured error when initial grpc server, err: %v", err)
+	}
+
+	RegisterDevicesServer(s.grpcServer, NewDevicesService(log, ds, us))
+
+	go func() {
+		if err := s.Serve(lis); err != nil {
+			t.Errorf("server exited with error: %v", err)
+		}
+	}()
+
+	return &recordDialer{
+		lis: lis,
+	}
+}
+
+func TestDevicesService_RevokeDevice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	userID := uuid.NewString()
+	revokedID := uuid.NewString()
+	unknownID := uuid.NewString()
+
+	ds := NewMockDeviceStorage(ctrl)
+	ds.EXPECT().GetDevice(gomock.Any(), userID, testDeviceID).AnyTimes().
+		Return(&models.Device{ID: testDeviceID}, nil)
+	ds.EXPECT().TouchDevice(gomock.Any(), userID, testDeviceID, false).AnyTimes().Return(nil)
+	ds.EXPECT().RevokeDevice(gomock.Any(), userID, revokedID).Return(nil)
+	ds.EXPECT().RevokeDevice(gomock.Any(), userID, unknownID).Return(models.ErrDeviceNotFound)
+
+	d := NewDevicesServiceDialer(t, ds, nil)
+	conn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(d.bufDialer),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Errorf("an occured error when dial bufnet, err: %v", err)
+	}
+	defer conn.Close()
+
+	client := NewDevicesClient(conn)
+
+	tests := []struct {
+		name     string
+		deviceID string
+		want     codes.Code
+	}{
+		{
+			name:     "positive case",
+			deviceID: revokedID,
+			want:     codes.OK,
+		},
+		{
+			name:     "device not found",
+			deviceID: unknownID,
+			want:     codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.RevokeDevice(contextWithUserID(ctx, userID), &RevokeDeviceRequest{Id: tt.deviceID})
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("DevicesService.RevokeDevice() code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDevicesService_RevokedDeviceRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	userID := uuid.NewString()
+
+	ds := NewMockDeviceStorage(ctrl)
+	ds.EXPECT().GetDevice(gomock.Any(), userID, testDeviceID).
+		Return(&models.Device{ID: testDeviceID, Revoked: true}, nil)
+
+	d := NewDevicesServiceDialer(t, ds, nil)
+	conn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(d.bufDialer),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Errorf("an occured error when dial bufnet, err: %v", err)
+	}
+	defer conn.Close()
+
+	client := NewDevicesClient(conn)
+
+	_, err = client.ListDevices(contextWithUserID(ctx, userID), &ListDevicesRequest{})
+	if got := status.Code(err); got != codes.PermissionDenied {
+		t.Errorf("DevicesService.ListDevices() code = %v, want %v", got, codes.PermissionDenied)
+	}
+}
+
+func TestDevicesService_RegisterDevice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	userID := uuid.NewString()
+
+	ds := NewMockDeviceStorage(ctrl)
+	ds.EXPECT().ListDevices(gomock.Any(), userID).AnyTimes().Return([]*models.Device{
+		{ID: uuid.NewString(), Owner: userID, Name: "laptop"},
+		{ID: uuid.NewString(), Owner: userID, Name: "lost phone", Revoked: true},
+	}, nil)
+	ds.EXPECT().AddDevice(gomock.Any(), userID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, userID string, d *models.DeviceDTO) (*models.Device, error) {
+			return &models.Device{ID: uuid.NewString(), Owner: userID, Name: d.Name}, nil
+		})
+
+	const password = "secret"
+	hp, err := hashPassword(password)
+	if err != nil {
+		t.Fatalf("an occured error when hashing password, err: %v", err)
+	}
+	us := NewMockUserStorage(ctrl)
+	us.EXPECT().GetUser(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(_ context.Context, u *models.UserDTO) (*models.User, error) {
+			switch u.Login {
+			case "user":
+				return &models.User{ID: userID, Login: u.Login, PasswordHash: hp}, nil
+			case "other":
+				return &models.User{ID: uuid.NewString(), Login: u.Login, PasswordHash: hp}, nil
+			default:
+				return nil, models.ErrUnknowUser
+			}
+		})
+
+	d := NewDevicesServiceDialer(t, ds, us)
+	conn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(d.bufDialer),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Errorf("an occured error when dial bufnet, err: %v", err)
+	}
+	defer conn.Close()
+
+	client := NewDevicesClient(conn)
+
+	tests := []struct {
+		name       string
+		deviceName string
+		login      string
+		password   string
+		want       codes.Code
+	}{
+		{
+			name:       "positive case",
+			deviceName: "laptop",
+			login:      "user",
+			password:   password,
+			want:       codes.OK,
+		},
+		{
+			name:       "revoked device is not registered again",
+			deviceName: "lost phone",
+			login:      "user",
+			password:   password,
+			want:       codes.PermissionDenied,
+		},
+		{
+			name:       "device is not registered with the user ID only",
+			deviceName: "new phone",
+			want:       codes.Unauthenticated,
+		},
+		{
+			name:       "device is not registered with the wrong password",
+			deviceName: "new phone",
+			login:      "user",
+			password:   "wrong",
+			want:       codes.Unauthenticated,
+		},
+		{
+			name:       "device is not registered with the credentials of another user",
+			deviceName: "new phone",
+			login:      "other",
+			password:   password,
+			want:       codes.Unauthenticated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.RegisterDevice(contextWithUserID(ctx, userID), &RegisterDeviceRequest{
+				Name:     tt.deviceName,
+				Login:    tt.login,
+				Password: tt.password,
+			})
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("DevicesService.RegisterDevice() code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// statusDeviceStorage - the device storage that also reports the status of the users.
+type statusDeviceStorage struct {
+	*MockDeviceStorage
+	*MockUserStatusReader
+}
+
+func TestDevicesService_DisabledUserRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	userID := uuid.NewString()
+
+	mds := NewMockDeviceStorage(ctrl)
+	mds.EXPECT().GetDevice(gomock.Any(), userID, testDeviceID).AnyTimes().
+		Return(&models.Device{ID: testDeviceID}, nil)
+	mds.EXPECT().TouchDevice(gomock.Any(), userID, testDeviceID, false).AnyTimes().Return(nil)
+	sr := NewMockUserStatusReader(ctrl)
+	sr.EXPECT().UserDisabled(gomock.Any(), userID).AnyTimes().Return(true, nil)
+
+	const password = "secret"
+	hp, err := hashPassword(password)
+	if err != nil {
+		t.Fatalf("an occured error when hashing password, err: %v", err)
+	}
+	us := NewMockUserStorage(ctrl)
+	us.EXPECT().GetUser(gomock.Any(), gomock.Any()).AnyTimes().
+		Return(&models.User{ID: userID, Login: "user", PasswordHash: hp}, nil)
+
+	d := NewDevicesServiceDialer(t, &statusDeviceStorage{MockDeviceStorage: mds, MockUserStatusReader: sr}, us)
+	conn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(d.bufDialer),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Errorf("an occured error when dial bufnet, err: %v", err)
+	}
+	defer conn.Close()
+
+	client := NewDevicesClient(conn)
+
+	tests := []struct {
+		name string
+		call func(ctx context.Context) error
+	}{
+		{
+			name: "the registered device is rejected",
+			call: func(ctx context.Context) error {
+				_, err := client.ListDevices(ctx, &ListDevicesRequest{})
+				return err
+			},
+		},
+		{
+			name: "the new device is not registered",
+			call: func(ctx context.Context) error {
+				_, err := client.RegisterDevice(ctx, &RegisterDeviceRequest{
+					Name:     "laptop",
+					Login:    "user",
+					Password: password,
+				})
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(contextWithUserID(ctx, userID))
+			if got := status.Code(err); got != codes.Unauthenticated {
+				t.Errorf("DevicesService code = %v, want %v", got, codes.Unauthenticated)
+			}
+			if got := status.Convert(err).Message(); got != models.ErrUserDisabled.Error() {
+				t.Errorf("DevicesService message = %q, want %q", got, models.ErrUserDisabled.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	_ "embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/config"
+)
+
+// openAPIPath - the path of the OpenAPI description of the gateway.
+const openAPIPath = "/openapi.json"
+
+// openAPISpec - the OpenAPI description that is generated from the proto files together with the gateway.
+//
+//go:embed gophkeeper.swagger.json
+var openAPISpec []byte
+
+// gateway - The REST/JSON gateway to the gRPC services.
+// The gateway is the client of the gRPC listener of the server, so the requests are checked
+// by the same interceptors as the gRPC requests. The address of the HTTP client is passed
+// in the x-forwarded-for metadata, it is used by the audit log and the rate limit instead of the loopback address.
+type gateway struct {
+	srv  *http.Server
+	conn *grpc.ClientConn
+	// certFile, keyFile - the certificate and the key of HTTPS, empty if the server does not use TLS.
+	certFile string
+	keyFile  string
+}
+
+// newGateway - returns the gateway on the address of the configuration.
+// The gateway uses the certificate of the gRPC listener for HTTPS as well.
+func newGateway(ctx context.Context, cfg *config.ServerCfg) (*gateway, error) {
+	creds, err := gatewayCreds(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.DialContext(ctx, gatewayTarget(cfg.Addr),
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(cfg.MaxMsgSize),
+			grpc.MaxCallSendMsgSize(cfg.MaxMsgSize)))
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while dial gRPC server from gateway, err: %w", err)
+	}
+
+	h, err := newGatewayHandler(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gateway{
+		srv: &http.Server{
+			Addr:              cfg.GatewayAddr,
+			Handler:           h,
+			ReadHeaderTimeout: httpReadTimeout,
+		},
+		conn:     conn,
+		certFile: cfg.CertFilePath,
+		keyFile:  cfg.PrivateCryptoKey,
+	}, nil
+}
+
+// newGatewayHandler - returns the handler of the HTTP requests of the Users and Records services
+// and of the OpenAPI description.
+func newGatewayHandler(ctx context.Context, conn *grpc.ClientConn) (http.Handler, error) {
+	gwmux := runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(gatewayHeaderMatcher))
+	if err := RegisterUsersHandler(ctx, gwmux, conn); err != nil {
+		return nil, fmt.Errorf("an error occured while register users gateway, err: %w", err)
+	}
+	if err := RegisterRecordsHandler(ctx, gwmux, conn); err != nil {
+		return nil, fmt.Errorf("an error occured while register records gateway, err: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/v1/", gwmux)
+	mux.HandleFunc(openAPIPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(openAPISpec)
+	})
+
+	return mux, nil
+}
+
+// gatewayHeaderMatcher - passes the user, the device and the request ID headers of the HTTP request
+// to the gRPC request as they are, the other headers are passed as the gateway does it by default.
+func gatewayHeaderMatcher(key string) (string, bool) {
+	switch k := strings.ToLower(key); k {
+	case userIDHeader, deviceIDHeader, requestIDHeader:
+		return k, true
+	default:
+		return runtime.DefaultHeaderMatcher(key)
+	}
+}
+
+// gatewayTarget - returns the address of the gRPC listener for the gateway,
+// the listener on all the interfaces is reached by the loopback address.
+func gatewayTarget(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	switch host {
+	case "", "0.0.0.0":
+		host = "127.0.0.1"
+	case "::":
+		host = "::1"
+	}
+
+	return net.JoinHostPort(host, port)
+}
+
+// gatewayCreds - returns the credentials of the gateway to the gRPC listener.
+// The certificate of the listener may be self-signed, so it is pinned instead of the verification.
+func gatewayCreds(cfg *config.ServerCfg) (credentials.TransportCredentials, error) {
+	if cfg.CertFilePath == "" {
+		return insecure.NewCredentials(), nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.CertFilePath, cfg.PrivateCryptoKey)
+	if err != nil {
+		return nil, fmt.Errorf("an occured error when loading TLS keys: %w", err)
+	}
+	pin := sha256.Sum256(cert.Certificate[0])
+
+	return getPinnedCreds("", hex.EncodeToString(pin[:]))
+}
+
+// serve - serves the HTTP requests on the listener, over TLS if the server has the certificate.
+func (g *gateway) serve(lis net.Listener) error {
+	var err error
+	if g.certFile != "" {
+		err = g.srv.ServeTLS(lis, g.certFile, g.keyFile)
+	} else {
+		err = g.srv.Serve(lis)
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("an error occured while serving gateway, err: %w", err)
+	}
+	return nil
+}
+
+// shutdown - stops the gateway, the requests that are being processed are completed.
+func (g *gateway) shutdown(ctx context.Context) error {
+	err := g.srv.Shutdown(ctx)
+	if cerr := g.conn.Close(); err == nil && cerr != nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("an error occured while shutdown gateway, err: %w", err)
+	}
+	return nil
+}
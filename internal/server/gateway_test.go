@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gomock "go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+func TestGateway(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	u := user(t)
+	us := NewMockUserStorage(ctrl)
+
+	r := generateTextRecord(t)
+	rs := NewMockRecordStorage(ctrl)
+	rs.EXPECT().GetRecord(gomock.Any(), u.ID, r.ID).AnyTimes().Return(r, nil)
+	rs.EXPECT().GetRecord(gomock.Any(), u.ID, randomUUID).AnyTimes().Return(nil, models.ErrRecordNotFound)
+
+	d, err := NewRecordServiceDialer(t, us, rs)
+	if err != nil {
+		t.Fatalf("an occured error when creating a new dialer, err: %v", err)
+	}
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(d.bufDialer),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to dial bufnet: %v", err)
+	}
+	defer conn.Close()
+
+	h, err := newGatewayHandler(context.Background(), conn)
+	if err != nil {
+		t.Fatalf("newGatewayHandler() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		userid   string
+		wantCode int
+		wantID   string
+	}{
+		{
+			name:     "OpenAPI description",
+			path:     openAPIPath,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "get record",
+			path:     "/v1/records/" + r.ID,
+			userid:   u.ID,
+			wantCode: http.StatusOK,
+			wantID:   r.ID,
+		},
+		{
+			name:     "user is not specified",
+			path:     "/v1/records/" + r.ID,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "record not found",
+			path:     "/v1/records/" + randomUUID,
+			userid:   u.ID,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "unknown path",
+			path:     "/v1/unknown",
+			userid:   u.ID,
+			wantCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, http.NoBody)
+			if tt.userid != "" {
+				req.Header.Set(userIDHeader, tt.userid)
+				req.Header.Set(deviceIDHeader, testDeviceID)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("gateway %s code = %d, want %d, body: %s", tt.path, rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantID == "" {
+				return
+			}
+
+			var resp struct {
+				Record struct {
+					ID string `json:"id"`
+				} `json:"record"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("gateway %s response is not JSON, err: %v", tt.path, err)
+			}
+			if resp.Record.ID != tt.wantID {
+				t.Errorf("gateway %s record id = %s, want %s", tt.path, resp.Record.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestGatewayTarget(t *testing.T) {
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{addr: "localhost:6085", want: "localhost:6085"},
+		{addr: ":6085", want: "127.0.0.1:6085"},
+		{addr: "0.0.0.0:6085", want: "127.0.0.1:6085"},
+		{addr: "[::]:6085", want: "[::1]:6085"},
+	}
+	for _, tt := range tests {
+		if got := gatewayTarget(tt.addr); got != tt.want {
+			t.Errorf("gatewayTarget(%s) = %s, want %s", tt.addr, got, tt.want)
+		}
+	}
+}
@@ -2,12 +2,21 @@ package server
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -28,6 +37,10 @@ type GKClient struct {
 	addr string
 	// certpath - absolute path to cert.crt file
 	certPath string
+	// certPin - SHA-256 fingerprint of the server certificate, empty if the certificate is not pinned.
+	certPin string
+	// deviceID - the ID of the device registered by the client, it is sent with every request.
+	deviceID string
 }
 
 // NewGKClient - Object Constructor.
@@ -36,6 +49,7 @@ func NewGKClient(ctx context.Context, cfg *config.ClientCfg, log *zap.Logger) (*
 		addr:     cfg.GKeeper,
 		log:      log,
 		certPath: cfg.CertFilePath,
+		certPin:  cfg.CertPin,
 	}
 
 	if err := c.setupConn(ctx); err != nil {
@@ -60,10 +74,84 @@ func getClientCreds(certFilePath string) (credentials.TransportCredentials, erro
 	return creds, nil
 }
 
+// ErrCertPinMismatch - The error is returned if the certificate of the server does not match the pinned one.
+var ErrCertPinMismatch = errors.New("the server certificate does not match the pinned fingerprint")
+
+// getPinnedCreds - returns the credentials that accept only the server certificate with the SHA-256 fingerprint.
+// If the path to the CA certificate is set, the certificate of the server is verified with it as well,
+// otherwise the pin replaces the verification, so self-signed certificates can be used.
+func getPinnedCreds(certFilePath string, pin string) (credentials.TransportCredentials, error) {
+	want, err := hex.DecodeString(strings.ReplaceAll(strings.TrimSpace(pin), ":", ""))
+	if err != nil || len(want) != sha256.Size {
+		return nil, fmt.Errorf("the certificate pin must be SHA-256 fingerprint in hex, got %q", pin)
+	}
+
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// The chain is verified by the pin, and by the CA below if it is set.
+		InsecureSkipVerify: true, //nolint:gosec // the certificate is verified by VerifyPeerCertificate
+	}
+
+	var roots *x509.CertPool
+	if certFilePath != "" {
+		b, err := os.ReadFile(certFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("failed to parse CA certificate %s", certFilePath)
+		}
+	}
+
+	cfg.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return ErrCertPinMismatch
+		}
+		got := sha256.Sum256(rawCerts[0])
+		if subtle.ConstantTimeCompare(got[:], want) != 1 {
+			return ErrCertPinMismatch
+		}
+		if roots == nil {
+			return nil
+		}
+		return verifyChain(rawCerts, roots)
+	}
+
+	return credentials.NewTLS(cfg), nil
+}
+
+func verifyChain(rawCerts [][]byte, roots *x509.CertPool) error {
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("failed to parse server certificate: %w", err)
+		}
+		certs[i] = cert
+	}
+
+	opts := x509.VerifyOptions{Roots: roots, Intermediates: x509.NewCertPool()}
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	if _, err := certs[0].Verify(opts); err != nil {
+		return fmt.Errorf("failed to verify server certificate: %w", err)
+	}
+
+	return nil
+}
+
 func (c *GKClient) setupConn(ctx context.Context) error {
 	opts := c.getDialOpts()
 
-	creds, err := getClientCreds(c.certPath)
+	var creds credentials.TransportCredentials
+	var err error
+	if c.certPin != "" {
+		creds, err = getPinnedCreds(c.certPath, c.certPin)
+	} else {
+		creds, err = getClientCreds(c.certPath)
+	}
 	if err != nil {
 		return fmt.Errorf("an error occured when retrieving client credentials: %w", err)
 	}
@@ -91,14 +179,65 @@ func (c *GKClient) getDialOpts() []grpc.DialOption {
 	}
 
 	chain := grpc.WithChainUnaryInterceptor(
+		clientValidationInterceptor,
 		grpc_retry.UnaryClientInterceptor(retryopts...),
+		deviceRevokedInterceptor,
 	)
 
-	opts = append(opts, chain)
+	// The stats handler sends the trace context of the calls in the metadata of the requests.
+	opts = append(opts, chain, grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
 
 	return opts
 }
 
+// deviceRevokedInterceptor - converts the rejection of the revoked device to models.ErrDeviceRevoked.
+func deviceRevokedInterceptor(ctx context.Context,
+	method string,
+	req, reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption) error {
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	if status.Code(err) == codes.PermissionDenied {
+		return fmt.Errorf("%w: %w", models.ErrDeviceRevoked, err)
+	}
+	return err
+}
+
+// outgoingContext - returns the context with the headers that identify the user and the device of the client.
+func (c *GKClient) outgoingContext(ctx context.Context, userID string) context.Context {
+	headers := map[string]string{
+		userIDHeader: userID,
+	}
+	if c.deviceID != "" {
+		headers[deviceIDHeader] = c.deviceID
+	}
+
+	return metadata.NewOutgoingContext(ctx, metadata.New(headers))
+}
+
+// DeviceID - Returns the ID of the device registered by the client.
+func (c *GKClient) DeviceID() string {
+	return c.deviceID
+}
+
+// SetDeviceID - Sets the ID of the device registered earlier, for example, restored from a saved session.
+func (c *GKClient) SetDeviceID(deviceID string) {
+	c.deviceID = deviceID
+}
+
+// Close - Closes the connection to the server.
+func (c *GKClient) Close() error {
+	cl, ok := c.cc.(io.Closer)
+	if !ok {
+		return nil
+	}
+	if err := cl.Close(); err != nil {
+		return fmt.Errorf("an error occured while close conn to server, err: %w", err)
+	}
+	return nil
+}
+
 // AddUser - The method is used when registering a user.
 func (c *GKClient) AddUser(ctx context.Context, us *models.UserDTO) (*models.User, error) {
 	resp, err := NewUsersClient(c.cc).Register(ctx, &RegisterRequest{
@@ -118,11 +257,20 @@ func (c *GKClient) AddUser(ctx context.Context, us *models.UserDTO) (*models.Use
 
 // GetUser - This method is used when the user logs in.
 func (c *GKClient) GetUser(ctx context.Context, us *models.UserDTO) (*models.User, error) {
+	// The device of the restored session is sent, so the login is written to the audit log with the device.
+	if c.deviceID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, deviceIDHeader, c.deviceID)
+	}
+
 	resp, err := NewUsersClient(c.cc).Login(ctx, &LoginRequest{
 		Login:    us.Login,
 		Password: us.Password,
 	})
 	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.Unauthenticated &&
+			st.Message() == models.ErrUserDisabled.Error() {
+			err = fmt.Errorf("%w: %w", models.ErrUserDisabled, err)
+		}
 		return nil, fmt.Errorf("an error occured while logged in user, err: %w", err)
 	}
 
@@ -137,11 +285,7 @@ func (c *GKClient) GetUser(ctx context.Context, us *models.UserDTO) (*models.Use
 func (c *GKClient) ListRecords(ctx context.Context, userID string, offset int, limit int) ([]*models.Record, error) {
 	serverStorage := NewRecordsClient(c.cc)
 
-	headers := map[string]string{
-		userIDHeader: userID,
-	}
-
-	mctx := metadata.NewOutgoingContext(ctx, metadata.New(headers))
+	mctx := c.outgoingContext(ctx, userID)
 	req := &ListRecordRequest{}
 	req.Offset = int32(offset)
 	req.Limit = int32(limit)
@@ -170,11 +314,7 @@ func (c *GKClient) ListRecords(ctx context.Context, userID string, offset int, l
 func (c *GKClient) GetRecord(ctx context.Context, userID string, recordID string) (*models.Record, error) {
 	serverStorage := NewRecordsClient(c.cc)
 
-	headers := map[string]string{
-		userIDHeader: userID,
-	}
-
-	mctx := metadata.NewOutgoingContext(ctx, metadata.New(headers))
+	mctx := c.outgoingContext(ctx, userID)
 	req := &GetRecordRequest{Id: recordID}
 	rr, err := serverStorage.GetRecord(mctx, req)
 	if err != nil {
@@ -200,11 +340,7 @@ func (c *GKClient) GetRecord(ctx context.Context, userID string, recordID string
 func (c *GKClient) DeleteRecord(ctx context.Context, userID string, recordID string) error {
 	serverStorage := NewRecordsClient(c.cc)
 
-	headers := map[string]string{
-		userIDHeader: userID,
-	}
-
-	mctx := metadata.NewOutgoingContext(ctx, metadata.New(headers))
+	mctx := c.outgoingContext(ctx, userID)
 	req := &DeleteRecordRequest{Id: recordID}
 	_, err := serverStorage.DeleteRecord(mctx, req)
 	if err != nil {
@@ -221,11 +357,7 @@ func (c *GKClient) AddRecord(ctx context.Context, userID string, record *models.
 	}
 	serverStorage := NewRecordsClient(c.cc)
 
-	headers := map[string]string{
-		userIDHeader: userID,
-	}
-
-	mctx := metadata.NewOutgoingContext(ctx, metadata.New(headers))
+	mctx := c.outgoingContext(ctx, userID)
 
 	id := uuid.NewString()
 	now := time.Now()
@@ -260,11 +392,7 @@ func (c *GKClient) AddRecord(ctx context.Context, userID string, record *models.
 func (c *GKClient) UpdateRecord(ctx context.Context, userID string, record *models.Record) (*models.Record, error) {
 	serverStorage := NewRecordsClient(c.cc)
 
-	headers := map[string]string{
-		userIDHeader: userID,
-	}
-
-	mctx := metadata.NewOutgoingContext(ctx, metadata.New(headers))
+	mctx := c.outgoingContext(ctx, userID)
 
 	rpb, err := convRecordToProtobuff(record)
 	if err != nil {
@@ -279,3 +407,292 @@ func (c *GKClient) UpdateRecord(ctx context.Context, userID string, record *mode
 
 	return record, nil
 }
+
+// BatchGetRecords - used to retrieving several records at once.
+// Records are requested in chunks of no more than models.MaxBatchSize.
+func (c *GKClient) BatchGetRecords(ctx context.Context,
+	userID string, recordIDs []string) ([]*models.BatchResult, error) {
+	serverStorage := NewRecordsClient(c.cc)
+
+	mctx := c.outgoingContext(ctx, userID)
+
+	brs := make([]*models.BatchResult, 0, len(recordIDs))
+	for _, ids := range chunks(recordIDs) {
+		resp, err := serverStorage.BatchGetRecords(mctx, &BatchGetRecordsRequest{Ids: ids})
+		if err != nil {
+			return nil, fmt.Errorf("an error occured while retrieving records, err: %w", err)
+		}
+
+		res, err := convBatchResultsFromProtobuff(resp.GetResults(), len(ids))
+		if err != nil {
+			return nil, fmt.Errorf("an error occured while retrieving records, err: %w", err)
+		}
+		brs = append(brs, res...)
+	}
+
+	return brs, nil
+}
+
+// BatchUpsertRecords - add or update several records at once.
+// Records are sent in chunks of no more than models.MaxBatchSize.
+// The records that violate the rules of the server are not sent, they are returned as failed,
+// so one invalid record does not block the synchronization of the others.
+func (c *GKClient) BatchUpsertRecords(ctx context.Context,
+	userID string, records []*models.Record) ([]*models.BatchResult, error) {
+	serverStorage := NewRecordsClient(c.cc)
+
+	mctx := c.outgoingContext(ctx, userID)
+
+	brs := make([]*models.BatchResult, 0, len(records))
+	for _, rs := range chunks(records) {
+		res := make([]*models.BatchResult, len(rs))
+		req := &BatchUpsertRecordsRequest{Records: make([]*Record, 0, len(rs))}
+		var sent []int
+		for i, r := range rs {
+			rpb, err := convRecordToProtobuff(r)
+			if err != nil {
+				return nil, fmt.Errorf("an error occured while convert record(ID=%s) to protobuff, err: %w", r.ID, err)
+			}
+			if err := validateRequest(&BatchUpsertRecordsRequest{Records: []*Record{rpb}}); err != nil {
+				res[i] = &models.BatchResult{ID: r.ID, Err: fmt.Errorf("%w: %w", models.ErrInvalidRecord, err)}
+				continue
+			}
+			req.Records = append(req.Records, rpb)
+			sent = append(sent, i)
+		}
+
+		if len(sent) > 0 {
+			resp, err := serverStorage.BatchUpsertRecords(mctx, req)
+			if err != nil {
+				return nil, fmt.Errorf("an error occured while updating records, err: %w", err)
+			}
+
+			sentRes, err := convBatchResultsFromProtobuff(resp.GetResults(), len(sent))
+			if err != nil {
+				return nil, fmt.Errorf("an error occured while updating records, err: %w", err)
+			}
+			for j, i := range sent {
+				res[i] = sentRes[j]
+			}
+		}
+		brs = append(brs, res...)
+	}
+
+	return brs, nil
+}
+
+// BatchDeleteRecords - mark several records as deleted at once.
+// Records are sent in chunks of no more than models.MaxBatchSize.
+func (c *GKClient) BatchDeleteRecords(ctx context.Context,
+	userID string, recordIDs []string) ([]*models.BatchResult, error) {
+	serverStorage := NewRecordsClient(c.cc)
+
+	mctx := c.outgoingContext(ctx, userID)
+
+	brs := make([]*models.BatchResult, 0, len(recordIDs))
+	for _, ids := range chunks(recordIDs) {
+		resp, err := serverStorage.BatchDeleteRecords(mctx, &BatchDeleteRecordsRequest{Ids: ids})
+		if err != nil {
+			return nil, fmt.Errorf("an error occured while removing records, err: %w", err)
+		}
+
+		res, err := convBatchResultsFromProtobuff(resp.GetResults(), len(ids))
+		if err != nil {
+			return nil, fmt.Errorf("an error occured while removing records, err: %w", err)
+		}
+		brs = append(brs, res...)
+	}
+
+	return brs, nil
+}
+
+func convBatchResultsFromProtobuff(results []*BatchResult, want int) ([]*models.BatchResult, error) {
+	if len(results) != want {
+		return nil, fmt.Errorf("the server returned %d results for %d records", len(results), want)
+	}
+
+	brs := make([]*models.BatchResult, len(results))
+	for i, res := range results {
+		br, err := convBatchResultFromProtobuff(res)
+		if err != nil {
+			return nil, err
+		}
+		brs[i] = br
+	}
+
+	return brs, nil
+}
+
+// chunks - splits items into parts of no more than models.MaxBatchSize.
+func chunks[T any](items []T) [][]T {
+	var cs [][]T
+	for len(items) > models.MaxBatchSize {
+		cs = append(cs, items[:models.MaxBatchSize])
+		items = items[models.MaxBatchSize:]
+	}
+	if len(items) > 0 {
+		cs = append(cs, items)
+	}
+	return cs
+}
+
+// AddDevice - registers the device of the user.
+// The registered device is used for all subsequent requests of the client.
+func (c *GKClient) AddDevice(ctx context.Context, userID string, device *models.DeviceDTO) (*models.Device, error) {
+	mctx := c.outgoingContext(ctx, userID)
+
+	resp, err := NewDevicesClient(c.cc).RegisterDevice(mctx, &RegisterDeviceRequest{
+		Name:     device.Name,
+		Platform: device.Platform,
+		Version:  device.Version,
+		Login:    device.Login,
+		Password: device.Password,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while register device, err: %w", err)
+	}
+
+	d := convDeviceFromProtobuff(userID, resp.GetDevice())
+	c.deviceID = d.ID
+
+	return d, nil
+}
+
+// GetDevice - used to retrieving device.
+func (c *GKClient) GetDevice(ctx context.Context, userID string, deviceID string) (*models.Device, error) {
+	ds, err := c.ListDevices(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range ds {
+		if d.ID == deviceID {
+			return d, nil
+		}
+	}
+
+	return nil, models.ErrDeviceNotFound
+}
+
+// ListDevices - used to retrieving user devices.
+func (c *GKClient) ListDevices(ctx context.Context, userID string) ([]*models.Device, error) {
+	mctx := c.outgoingContext(ctx, userID)
+
+	resp, err := NewDevicesClient(c.cc).ListDevices(mctx, &ListDevicesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while retrieving list devices, err: %w", err)
+	}
+
+	ds := make([]*models.Device, len(resp.GetDevices()))
+	for i, d := range resp.GetDevices() {
+		ds[i] = convDeviceFromProtobuff(userID, d)
+	}
+
+	return ds, nil
+}
+
+// RevokeDevice - mark device as revoked.
+func (c *GKClient) RevokeDevice(ctx context.Context, userID string, deviceID string) error {
+	mctx := c.outgoingContext(ctx, userID)
+
+	_, err := NewDevicesClient(c.cc).RevokeDevice(mctx, &RevokeDeviceRequest{Id: deviceID})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return models.ErrDeviceNotFound
+		}
+		return fmt.Errorf("an error occured while revoke device, err: %w", err)
+	}
+
+	return nil
+}
+
+// TouchDevice - reports the successful synchronization of the device, if synced is true.
+// The date of the last request from the device is updated by the server on every request.
+func (c *GKClient) TouchDevice(ctx context.Context, userID string, deviceID string, synced bool) error {
+	if !synced {
+		return nil
+	}
+
+	mctx := metadata.NewOutgoingContext(ctx, metadata.New(map[string]string{
+		userIDHeader:   userID,
+		deviceIDHeader: deviceID,
+	}))
+
+	if _, err := NewDevicesClient(c.cc).ReportSync(mctx, &ReportSyncRequest{}); err != nil {
+		return fmt.Errorf("an error occured while report sync, err: %w", err)
+	}
+
+	return nil
+}
+
+// ReportSync - Implements models.SyncReporter. Reports the successful synchronization of the registered device.
+func (c *GKClient) ReportSync(ctx context.Context, userID string) error {
+	if c.deviceID == "" {
+		return nil
+	}
+
+	return c.TouchDevice(ctx, userID, c.deviceID, true)
+}
+
+// ListAuditEvents - Implements models.AuditReader. Retrieves the audit log of the user, the latest events come first.
+func (c *GKClient) ListAuditEvents(ctx context.Context,
+	userID string,
+	offset int,
+	limit int) ([]*models.AuditEvent, error) {
+	mctx := c.outgoingContext(ctx, userID)
+
+	resp, err := NewAuditClient(c.cc).ListAuditEvents(mctx, &ListAuditEventsRequest{
+		Offset: int32(offset),
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while retrieving audit events, err: %w", err)
+	}
+
+	es := make([]*models.AuditEvent, len(resp.GetEvents()))
+	for i, e := range resp.GetEvents() {
+		es[i] = convAuditEventFromProtobuff(userID, e)
+	}
+
+	return es, nil
+}
+
+// ReportExport - Writes the export of the records by the client to the audit log of the user.
+// The servers without the audit log are ignored.
+func (c *GKClient) ReportExport(ctx context.Context, userID string, format string, records int) error {
+	mctx := c.outgoingContext(ctx, userID)
+
+	_, err := NewAuditClient(c.cc).ReportExport(mctx, &ReportExportRequest{
+		Format:  format,
+		Records: int32(records),
+	})
+	if err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			return nil
+		}
+		return fmt.Errorf("an error occured while report export, err: %w", err)
+	}
+
+	return nil
+}
+
+// GetServerInfo - Implements models.ServerInfoProvider. Retrieves the build, the protocol and the features
+// of the server. Returns models.ErrServerInfoUnavailable if the server does not implement the Info service.
+func (c *GKClient) GetServerInfo(ctx context.Context) (*models.ServerInfo, error) {
+	resp, err := NewInfoClient(c.cc).GetServerInfo(ctx, &GetServerInfoRequest{})
+	if err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			return nil, fmt.Errorf("%w: %w", models.ErrServerInfoUnavailable, err)
+		}
+		return nil, fmt.Errorf("an error occured while retrieving server info, err: %w", err)
+	}
+
+	return &models.ServerInfo{
+		Version:            resp.GetVersion(),
+		Date:               resp.GetDate(),
+		Commit:             resp.GetCommit(),
+		ProtocolVersion:    int(resp.GetProtocolVersion()),
+		MinProtocolVersion: int(resp.GetMinProtocolVersion()),
+		Features:           resp.GetFeatures(),
+	}, nil
+}
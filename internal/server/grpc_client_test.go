@@ -1,18 +1,32 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"io"
 	"net"
 	"reflect"
 	"testing"
 
+	"github.com/fxamacker/cbor/v2"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 	gomock "go.uber.org/mock/gomock"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 
+	"github.com/ArtemShalinFe/gophkeeper/internal/backup"
 	"github.com/ArtemShalinFe/gophkeeper/internal/config"
 	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+	"github.com/ArtemShalinFe/gophkeeper/internal/storage/mem"
 	"github.com/google/uuid"
 )
 
@@ -365,6 +379,7 @@ func TestGKClient_GetRecord(t *testing.T) {
 		recordID string
 		want     *models.Record
 		wantErr  bool
+		wantCode codes.Code
 	}{
 		{
 			name: "case get record",
@@ -387,14 +402,29 @@ func TestGKClient_GetRecord(t *testing.T) {
 			recordID: "",
 			want:     nil,
 			wantErr:  true,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "case get record with valid ID, but server return error",
+			us: &models.User{
+				ID:           uuid,
+				Login:        uuid,
+				PasswordHash: hp,
+			},
+			recordID: uuid,
+			want:     nil,
+			wantErr:  true,
 		},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			if tt.wantErr {
+			switch {
+			case tt.wantCode == codes.InvalidArgument:
+				// The request is rejected by the client, so the server is not called.
+			case tt.wantErr:
 				mock.EXPECT().GetRecord(gomock.Any(), gomock.Any()).Return(nil, errSomethingWentWrong)
-			} else {
+			default:
 				rpb, err := convRecordToProtobuff(tt.want)
 				if err != nil {
 					t.Errorf("an error occured while convert record to protobuff, err: %v", err)
@@ -408,6 +438,9 @@ func TestGKClient_GetRecord(t *testing.T) {
 				t.Errorf("GKClient.GetRecord() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.wantCode != codes.OK && status.Code(err) != tt.wantCode {
+				t.Errorf("GKClient.GetRecord() code = %v, want %v", status.Code(err), tt.wantCode)
+			}
 			if !tt.wantErr && got.ID != tt.want.ID {
 				t.Errorf("GKClient.GetRecord() = %v, want %v", got, tt.want)
 			}
@@ -456,6 +489,7 @@ func TestGKClient_DeleteRecord(t *testing.T) {
 		recordID string
 		want     *models.Record
 		wantErr  bool
+		wantCode codes.Code
 	}{
 		{
 			name: "case delete record",
@@ -478,14 +512,29 @@ func TestGKClient_DeleteRecord(t *testing.T) {
 			recordID: "",
 			want:     nil,
 			wantErr:  true,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "case delete record with valid ID, but server return error",
+			us: &models.User{
+				ID:           uuid,
+				Login:        uuid,
+				PasswordHash: hp,
+			},
+			recordID: uuid,
+			want:     nil,
+			wantErr:  true,
 		},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			if tt.wantErr {
+			switch {
+			case tt.wantCode == codes.InvalidArgument:
+				// The request is rejected by the client, so the server is not called.
+			case tt.wantErr:
 				mock.EXPECT().DeleteRecord(gomock.Any(), gomock.Any()).Return(nil, errSomethingWentWrong)
-			} else {
+			default:
 				mock.EXPECT().DeleteRecord(gomock.Any(), gomock.Any()).Return(&DeleteRecordResponse{}, nil)
 			}
 			err := c.DeleteRecord(ctx, tt.us.ID, tt.recordID)
@@ -493,6 +542,9 @@ func TestGKClient_DeleteRecord(t *testing.T) {
 				t.Errorf("GKClient.DeleteRecord() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.wantCode != codes.OK && status.Code(err) != tt.wantCode {
+				t.Errorf("GKClient.DeleteRecord() code = %v, want %v", status.Code(err), tt.wantCode)
+			}
 		})
 	}
 }
@@ -687,3 +739,242 @@ func TestGKClient_UpdateRecord(t *testing.T) {
 		})
 	}
 }
+
+func TestGKClient_GetServerInfo(t *testing.T) {
+	ctx := context.Background()
+
+	listener := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	RegisterInfoServer(srv, NewInfoService(zap.L()))
+	go func() {
+		if err := srv.Serve(listener); err != nil {
+			zap.S().Errorf("grpc serve failed, err: %v", err)
+		}
+	}()
+	defer srv.Stop()
+
+	creds, err := getClientCreds("")
+	if err != nil {
+		t.Fatalf("an error occured while get client gredentials, err: %v", err)
+	}
+	conn, err := grpc.DialContext(ctx, "", grpc.WithTransportCredentials(creds),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }))
+	if err != nil {
+		t.Fatalf("an occured error when getting conn grpc client, err: %v", err)
+	}
+	defer conn.Close()
+
+	c := &GKClient{cc: conn, log: zap.L()}
+	got, err := models.CheckServer(ctx, c)
+	if err != nil {
+		t.Fatalf("models.CheckServer() error = %v", err)
+	}
+	if got.Info == nil || got.Info.ProtocolVersion != models.ProtocolVersion || len(got.Warnings) != 0 {
+		t.Errorf("models.CheckServer() = %+v, want the info of the compatible server", got)
+	}
+
+	// The server that has been built before the Info service does not implement it.
+	old, err := grpc.DialContext(ctx, "", grpc.WithTransportCredentials(creds),
+		grpc.WithContextDialer(NewUserSrvListener(NewMockUsersServer(gomock.NewController(t)))))
+	if err != nil {
+		t.Fatalf("an occured error when getting conn grpc client, err: %v", err)
+	}
+	defer old.Close()
+
+	c.cc = old
+	if _, err := c.GetServerInfo(ctx); !errors.Is(err, models.ErrServerInfoUnavailable) {
+		t.Errorf("GKClient.GetServerInfo() error = %v, want %v", err, models.ErrServerInfoUnavailable)
+	}
+}
+
+func TestGKClient_TracePropagation(t *testing.T) {
+	ctx := context.Background()
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(trace.NewNoopTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	}()
+
+	var gotTraceID string
+	interceptor := func(ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		gotTraceID = traceID(ctx)
+		return handler(ctx, req)
+	}
+
+	listener := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer(grpc.UnaryInterceptor(interceptor), grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	RegisterInfoServer(srv, NewInfoService(zap.L()))
+	go func() {
+		if err := srv.Serve(listener); err != nil {
+			zap.S().Errorf("grpc serve failed, err: %v", err)
+		}
+	}()
+	defer srv.Stop()
+
+	c := &GKClient{log: zap.L()}
+	creds, err := getClientCreds("")
+	if err != nil {
+		t.Fatalf("an error occured while get client gredentials, err: %v", err)
+	}
+	opts := append(c.getDialOpts(), grpc.WithTransportCredentials(creds),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }))
+	conn, err := grpc.DialContext(ctx, "", opts...)
+	if err != nil {
+		t.Fatalf("an occured error when getting conn grpc client, err: %v", err)
+	}
+	defer conn.Close()
+	c.cc = conn
+
+	ctx, span := tp.Tracer("test").Start(ctx, "sync")
+	if _, err := c.GetServerInfo(ctx); err != nil {
+		t.Fatalf("GKClient.GetServerInfo() error = %v", err)
+	}
+	span.End()
+
+	want := span.SpanContext().TraceID().String()
+	if gotTraceID != want {
+		t.Errorf("the trace ID in the server interceptor = %q, want %q", gotTraceID, want)
+	}
+
+	kinds := make(map[trace.SpanKind]bool)
+	for _, s := range sr.Ended() {
+		if s.SpanContext().TraceID().String() == want {
+			kinds[s.SpanKind()] = true
+		}
+	}
+	if !kinds[trace.SpanKindClient] || !kinds[trace.SpanKindServer] {
+		t.Errorf("the trace has the span kinds %v, want the client and the server spans", kinds)
+	}
+}
+
+func TestGKClient_BackupRestore(t *testing.T) {
+	ctx := context.Background()
+
+	owner := &models.User{ID: uuid.NewString(), Login: uuid.NewString()}
+	restorer := &models.User{ID: uuid.NewString(), Login: uuid.NewString()}
+
+	ms := mem.NewMemStorage()
+	for _, u := range []*models.User{owner, restorer} {
+		if err := ms.AddUserRecordStorage(u.ID); err != nil {
+			t.Fatalf("an error occured while add user record storage, err: %v", err)
+		}
+	}
+
+	d, err := NewRecordServiceDialer(t, NewMockUserStorage(gomock.NewController(t)), ms)
+	if err != nil {
+		t.Fatalf("an occured error when creating a new dialer, err: %v", err)
+	}
+
+	cfg := config.NewClientCfg()
+	c := &GKClient{
+		addr:     cfg.GKeeper,
+		log:      zap.L(),
+		certPath: cfg.CertFilePath,
+		deviceID: testDeviceID,
+	}
+	creds, err := getClientCreds("")
+	if err != nil {
+		t.Fatalf("an error occured while get client gredentials, err: %v", err)
+	}
+	opts := append(c.getDialOpts(), grpc.WithContextDialer(d.bufDialer), grpc.WithTransportCredentials(creds))
+	conn, err := grpc.DialContext(ctx, "", opts...)
+	if err != nil {
+		t.Fatalf("an occured error when getting conn grpc client, err: %v", err)
+	}
+	defer conn.Close()
+	c.cc = conn
+
+	datas := []struct {
+		dt   models.DataType
+		data models.RecordData
+	}{
+		{dt: models.AuthType, data: &models.Auth{Login: "login", Password: "password"}},
+		{dt: models.TextType, data: &models.Text{Data: "text"}},
+		{dt: models.BinaryType, data: &models.Binary{Name: "report", Ext: ".pdf", Data: []byte("binary data")}},
+	}
+	for _, dd := range datas {
+		rdto, err := models.NewRecordDTO(string(dd.dt), dd.dt, dd.data, nil)
+		if err != nil {
+			t.Fatalf("an error occured while create record DTO, err: %v", err)
+		}
+		if _, err := c.AddRecord(ctx, owner.ID, rdto); err != nil {
+			t.Fatalf("GKClient.AddRecord() error = %v", err)
+		}
+	}
+
+	rs, err := owner.GetRecords(ctx, c, 0, models.DefaultLimit)
+	if err != nil {
+		t.Fatalf("User.GetRecords() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	bw, err := backup.NewWriter(&buf, []byte("passphrase"), backup.Manifest{Login: owner.Login})
+	if err != nil {
+		t.Fatalf("backup.NewWriter() error = %v", err)
+	}
+	for _, r := range rs {
+		if err := bw.WriteRecord(r); err != nil {
+			t.Fatalf("Writer.WriteRecord() error = %v", err)
+		}
+	}
+	if err := bw.Close(); err != nil {
+		t.Fatalf("Writer.Close() error = %v", err)
+	}
+
+	br, err := backup.NewReader(&buf, []byte("passphrase"))
+	if err != nil {
+		t.Fatalf("backup.NewReader() error = %v", err)
+	}
+	var brs []*models.Record
+	for {
+		r, err := br.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Reader.Next() error = %v", err)
+		}
+		brs = append(brs, r)
+	}
+
+	rr, err := restorer.NewRestorer(ctx, c)
+	if err != nil {
+		t.Fatalf("User.NewRestorer() error = %v", err)
+	}
+	results, err := rr.Restore(ctx, brs)
+	if err != nil {
+		t.Fatalf("Restorer.Restore() error = %v", err)
+	}
+	if len(results) != len(datas) {
+		t.Fatalf("Restorer.Restore() restored %d records, want %d", len(results), len(datas))
+	}
+	for _, res := range results {
+		if res.Err != nil || res.Duplicate {
+			t.Errorf("Restorer.Restore() record %s error = %v, duplicate %v", res.OldID, res.Err, res.Duplicate)
+		}
+	}
+
+	restored, err := ms.AllRecords(restorer.ID)
+	if err != nil {
+		t.Fatalf("MemStorage.AllRecords() error = %v", err)
+	}
+	for _, r := range restored {
+		if r.Type != string(models.BinaryType) {
+			continue
+		}
+		var bin models.Binary
+		if err := cbor.Unmarshal(r.Data, &bin); err != nil {
+			t.Fatalf("an error occured while decode binary, err: %v", err)
+		}
+		if !reflect.DeepEqual(&bin, datas[2].data) {
+			t.Errorf("restored binary = %+v, want %+v", bin, datas[2].data)
+		}
+	}
+}
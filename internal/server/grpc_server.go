@@ -2,15 +2,25 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"net/http"
+	"strings"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/ArtemShalinFe/gophkeeper/internal/config"
 	"github.com/ArtemShalinFe/gophkeeper/internal/models"
@@ -22,12 +32,26 @@ type GKServer struct {
 	log            *zap.Logger
 	UsersService   *UsersService
 	RecordsService *RecordsService
+	DevicesService *DevicesService
+	InfoService    *InfoService
+	AuditService   *AuditService
 	addr           string
+	// audit - the audit log that is shared by the services.
+	audit   *auditLog
+	health  *healthChecker
+	metrics *prometheus.Registry
+	// httpServers - the listeners of the HTTP health and metrics endpoints.
+	httpServers []*http.Server
+	// gateway - the REST/JSON gateway, nil if it is disabled.
+	gateway *gateway
 }
 
 // InitServer - Initiates the gophkeeper server object.
+// The actions of the users are not written to the audit log if the audit storage is nil.
 func InitServer(rs models.RecordStorage,
 	us models.UserStorage,
+	ds models.DeviceStorage,
+	as models.AuditStorage,
 	log *zap.Logger,
 	cfg *config.ServerCfg) (*GKServer, error) {
 	srv := &GKServer{
@@ -35,21 +59,84 @@ func InitServer(rs models.RecordStorage,
 		log:            log,
 		UsersService:   NewUsersService(log, us),
 		RecordsService: NewRecordsService(log, rs),
+		DevicesService: NewDevicesService(log, ds, us),
+		InfoService:    NewInfoService(log),
+		AuditService:   NewAuditService(log),
+		audit:          newAuditLog(log),
+		health:         newHealthChecker(log, cfg.HealthInterval),
+		metrics:        newMetricsRegistry(),
 	}
+	srv.audit.stg = as
+	srv.UsersService.audit = srv.audit
+	srv.RecordsService.audit = srv.audit
+	srv.DevicesService.audit = srv.audit
+	srv.AuditService.audit = srv.audit
+	srv.httpServers = newHTTPServers(cfg, srv.health, srv.metrics)
+	if cfg.GatewayAddr != "" {
+		gw, err := newGateway(context.Background(), cfg)
+		if err != nil {
+			return nil, err
+		}
+		srv.gateway = gw
+	}
+	srv.RecordsService.SetQuota(RecordsQuota{
+		MaxRecords:    cfg.QuotaRecords,
+		MaxRecordSize: cfg.QuotaRecordSize,
+	})
 
 	creds, err := serverCreds(cfg)
 	if err != nil {
 		return nil, err
 	}
-	opt := grpc.ChainUnaryInterceptor(
+	// The panics are recovered inside the metrics and the logging, so they are counted and logged as Internal.
+	// The cheap checks of the rate and the device come before the validation of the request.
+	// The rate is limited by the IP address first, and by the user only after the device of the user is checked.
+	rl := newRateLimiter(cfg.RateLimit, cfg.RateBurst)
+	unary := grpc.ChainUnaryInterceptor(
+		requestIDInterceptor(),
+		metricsInterceptor(),
 		srv.requestLogger(),
+		srv.recoveryInterceptor(),
+		rl.rateLimitInterceptor(),
+		deadlineInterceptor(cfg.RequestTimeout),
+		srv.deviceChecker(),
+		rl.userRateLimitInterceptor(),
+		validationInterceptor(),
+	)
+	stream := grpc.ChainStreamInterceptor(
+		streamRequestIDInterceptor(),
+		streamMetricsInterceptor(),
+		srv.streamRequestLogger(),
+		srv.streamRecoveryInterceptor(),
+		rl.streamRateLimitInterceptor(),
+		srv.streamDeviceChecker(),
+		rl.streamUserRateLimitInterceptor(),
+		streamValidationInterceptor(),
 	)
 
-	srv.grpcServer = grpc.NewServer(grpc.Creds(creds), opt)
+	// The stats handler extracts the trace context of the client before the interceptors are called,
+	// so the spans of the services and the storage are the children of the span of the request.
+	srv.grpcServer = grpc.NewServer(grpc.Creds(creds), unary, stream,
+		grpc.MaxRecvMsgSize(cfg.MaxMsgSize),
+		grpc.MaxSendMsgSize(cfg.MaxMsgSize),
+		grpc.KeepaliveParams(keepaliveParams(cfg)),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()))
 
 	return srv, nil
 }
 
+// keepaliveParams - limits the lifetime of the connections of the clients, so the idle connections
+// and the streams that are kept open do not hold the resources of the server forever.
+// The connection that has reached its age is given the request timeout to finish the requests and the streams.
+// The zero values of the settings mean no limit, as in keepalive.ServerParameters.
+func keepaliveParams(cfg *config.ServerCfg) keepalive.ServerParameters {
+	return keepalive.ServerParameters{
+		MaxConnectionIdle:     cfg.ConnIdleTimeout,
+		MaxConnectionAge:      cfg.ConnMaxAge,
+		MaxConnectionAgeGrace: cfg.RequestTimeout,
+	}
+}
+
 func serverCreds(cfg *config.ServerCfg) (credentials.TransportCredentials, error) {
 	if cfg.CertFilePath != "" && cfg.PrivateCryptoKey != "" {
 		creds, err := credentials.NewServerTLSFromFile(cfg.CertFilePath, cfg.PrivateCryptoKey)
@@ -63,6 +150,8 @@ func serverCreds(cfg *config.ServerCfg) (credentials.TransportCredentials, error
 	}
 }
 
+// requestLogger - logs the requests, the errors of the handlers are returned as they are,
+// so the status codes reach the client.
 func (s *GKServer) requestLogger() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context,
 		req interface{},
@@ -70,23 +159,113 @@ func (s *GKServer) requestLogger() grpc.UnaryServerInterceptor {
 		handler grpc.UnaryHandler) (interface{}, error) {
 		start := time.Now()
 		resp, err := handler(ctx, req)
-		duration := time.Since(start)
-		if err != nil {
-			s.log.Error("an error occurred while processing RPC request", zap.Error(err))
-			return nil, fmt.Errorf("an error occured while handle request, err: %w", err)
-		} else {
-			md, _ := metadata.FromIncomingContext(ctx)
-			s.log.Info("incomming request",
-				zap.String("RPC method", info.FullMethod),
-				zap.Any("headers", md),
-				zap.Duration("duration", duration),
-				zap.Any("body", req),
-			)
+		s.logRPC(ctx, info.FullMethod, time.Since(start), err, requestBody(info.FullMethod, req)...)
+		return resp, err
+	}
+}
+
+// requestBody - returns the field of the body of the request for the log.
+// The requests of the Users service contain the passwords, so their bodies are not logged.
+func requestBody(method string, req interface{}) []zap.Field {
+	const usersPrefix = "/gophkeeper.Users/"
+
+	if strings.HasPrefix(method, usersPrefix) {
+		return nil
+	}
+	return []zap.Field{zap.Any("body", req)}
+}
+
+// streamRequestLogger - logs the streams when they are finished.
+func (s *GKServer) streamRequestLogger() grpc.StreamServerInterceptor {
+	return func(srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		s.logRPC(ss.Context(), info.FullMethod, time.Since(start), err)
+		return err
+	}
+}
+
+// logRPC - the failures of the server are logged as errors, the rejected requests of the clients as warnings.
+func (s *GKServer) logRPC(ctx context.Context, method string, duration time.Duration, err error, fields ...zap.Field) {
+	fields = append(fields,
+		zap.String("RPC method", method),
+		zap.String("code", status.Code(err).String()),
+		zap.Duration("duration", duration),
+		zap.String("request_id", requestID(ctx)),
+		zap.String("trace_id", traceID(ctx)))
+
+	if err == nil {
+		md, _ := metadata.FromIncomingContext(ctx)
+		s.log.Info("incomming request", append(fields, zap.Any("headers", md))...)
+		return
+	}
+
+	fields = append(fields, zap.Error(err))
+	switch status.Code(err) {
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unimplemented:
+		s.log.Error("an error occurred while processing RPC request", fields...)
+	default:
+		s.log.Warn("RPC request has been rejected", fields...)
+	}
+}
+
+// traceID - returns the ID of the trace of the request, it is empty if the request is not traced.
+func traceID(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
+
+// deviceChecker - rejects requests from devices that are unknown or have been revoked by the user.
+// Only the requests of the Users service and the device registration do not require the device.
+func (s *GKServer) deviceChecker() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		if deviceRequired(info.FullMethod) {
+			if err := s.DevicesService.checkDevice(ctx); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// streamDeviceChecker - rejects streams from devices that are unknown or have been revoked by the user.
+func (s *GKServer) streamDeviceChecker() grpc.StreamServerInterceptor {
+	return func(srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		if deviceRequired(info.FullMethod) {
+			if err := s.DevicesService.checkDevice(ss.Context()); err != nil {
+				return err
+			}
 		}
-		return resp, nil
+		return handler(srv, ss)
 	}
 }
 
+func deviceRequired(method string) bool {
+	const (
+		recordsPrefix = "/gophkeeper.Records/"
+		devicesPrefix = "/gophkeeper.Devices/"
+		auditPrefix   = "/gophkeeper.Audit/"
+	)
+
+	if strings.HasPrefix(method, recordsPrefix) || strings.HasPrefix(method, auditPrefix) {
+		return true
+	}
+
+	return strings.HasPrefix(method, devicesPrefix) && method != Devices_RegisterDevice_FullMethodName
+}
+
 // Serve - starts servicing incoming requests. Used for tests.
 func (s *GKServer) Serve(lis net.Listener) error {
 	if err := s.grpcServer.Serve(lis); err != nil {
@@ -104,6 +283,35 @@ func (s *GKServer) ListenAndServe() error {
 
 	RegisterUsersServer(s.grpcServer, s.UsersService)
 	RegisterRecordsServer(s.grpcServer, s.RecordsService)
+	RegisterDevicesServer(s.grpcServer, s.DevicesService)
+	RegisterInfoServer(s.grpcServer, s.InfoService)
+	RegisterAuditServer(s.grpcServer, s.AuditService)
+	healthpb.RegisterHealthServer(s.grpcServer, s.health.srv)
+	s.health.run()
+
+	for _, hs := range s.httpServers {
+		hl, err := net.Listen("tcp", hs.Addr)
+		if err != nil {
+			return fmt.Errorf("an occured error when trying listen HTTP address %s, err: %w", hs.Addr, err)
+		}
+		go func(hs *http.Server) {
+			if err := hs.Serve(hl); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				s.log.Error("an error occurred while serving HTTP endpoints", zap.String("addr", hs.Addr), zap.Error(err))
+			}
+		}(hs)
+	}
+
+	if s.gateway != nil {
+		gl, err := net.Listen("tcp", s.gateway.srv.Addr)
+		if err != nil {
+			return fmt.Errorf("an occured error when trying listen gateway address %s, err: %w", s.gateway.srv.Addr, err)
+		}
+		go func() {
+			if err := s.gateway.serve(gl); err != nil {
+				s.log.Error("an error occurred while serving gateway", zap.String("addr", s.gateway.srv.Addr), zap.Error(err))
+			}
+		}()
+	}
 
 	if err := s.Serve(listen); err != nil {
 		return fmt.Errorf("an occured error when grpc server serve, err: %w", err)
@@ -112,7 +320,35 @@ func (s *GKServer) ListenAndServe() error {
 	return nil
 }
 
+// AddDependency - Adds the dependency of the services, for example, the database.
+// The services are reported as not serving by the health service while the check of the dependency fails.
+func (s *GKServer) AddDependency(name string, check DependencyCheck) {
+	s.health.addDependency(name, check)
+}
+
+// AddCollector - Adds the collector of the metrics, for example, the statistics of the database.
+func (s *GKServer) AddCollector(c prometheus.Collector) error {
+	if err := s.metrics.Register(c); err != nil {
+		return fmt.Errorf("an error occured while register metrics collector, err: %w", err)
+	}
+	return nil
+}
+
 // Shutdown - Implements a server graceful shutdown.
+// The health service reports NOT_SERVING and the readiness endpoint fails while the requests are completed.
+// The gateway is stopped first, so its requests are completed while the gRPC listener is still serving.
 func (s *GKServer) Shutdown(ctx context.Context) {
+	s.health.shutdownServing()
+	if s.gateway != nil {
+		if err := s.gateway.shutdown(ctx); err != nil {
+			s.log.Error("an error occurred while shutdown gateway", zap.Error(err))
+		}
+	}
 	s.grpcServer.GracefulStop()
+
+	for _, hs := range s.httpServers {
+		if err := hs.Shutdown(ctx); err != nil {
+			s.log.Error("an error occurred while shutdown HTTP endpoints", zap.String("addr", hs.Addr), zap.Error(err))
+		}
+	}
 }
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// healthCheckTimeout - the time limit of one check of the dependencies.
+const healthCheckTimeout = 3 * time.Second
+
+// errShuttingDown - The error is reported by the readiness endpoint while the server is shutting down.
+var errShuttingDown = errors.New("server is shutting down")
+
+// DependencyCheck - Checks the dependency that the services of the server require, for example, the database.
+type DependencyCheck func(ctx context.Context) error
+
+type dependency struct {
+	name  string
+	check DependencyCheck
+}
+
+// healthChecker - Keeps the statuses of the standard gRPC health service up to date.
+//
+// The services that work with the storage are serving only while all the dependencies pass their checks,
+// the Info service does not depend on the storage. The empty service name is the status of the whole server.
+type healthChecker struct {
+	srv      *health.Server
+	log      *zap.Logger
+	interval time.Duration
+	deps     []dependency
+	// storageServices - the services that depend on the storage.
+	storageServices []string
+	// independentServices - the services that are serving while the server is running.
+	independentServices []string
+
+	shutdown atomic.Bool
+	stop     chan struct{}
+	stopOnce sync.Once
+	mu       sync.Mutex
+	// lastErr - the error of the last failed check, nil if all the checks have passed.
+	lastErr error
+}
+
+func newHealthChecker(log *zap.Logger, interval time.Duration) *healthChecker {
+	return &healthChecker{
+		srv:      health.NewServer(),
+		log:      log,
+		interval: interval,
+		storageServices: []string{
+			Users_ServiceDesc.ServiceName,
+			Records_ServiceDesc.ServiceName,
+			Devices_ServiceDesc.ServiceName,
+			Audit_ServiceDesc.ServiceName,
+		},
+		independentServices: []string{Info_ServiceDesc.ServiceName},
+		stop:                make(chan struct{}),
+	}
+}
+
+// addDependency - adds the dependency that is checked before every update of the statuses.
+func (h *healthChecker) addDependency(name string, check DependencyCheck) {
+	h.deps = append(h.deps, dependency{name: name, check: check})
+}
+
+// run - checks the dependencies at once and then at every interval until the checker is stopped.
+func (h *healthChecker) run() {
+	h.update()
+
+	if h.interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(h.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-h.stop:
+				return
+			case <-ticker.C:
+				h.update()
+			}
+		}
+	}()
+}
+
+// update - checks the dependencies and sets the statuses of the services.
+func (h *healthChecker) update() {
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+
+	var errs []error
+	for _, d := range h.deps {
+		if err := d.check(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", d.name, err))
+		}
+	}
+	err := errors.Join(errs...)
+
+	h.mu.Lock()
+	changed := (err == nil) != (h.lastErr == nil)
+	h.lastErr = err
+	h.mu.Unlock()
+
+	status := healthpb.HealthCheckResponse_SERVING
+	if err != nil {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	if changed && err != nil {
+		h.log.Error("the dependencies of the server are unhealthy", zap.Error(err))
+	} else if changed {
+		h.log.Info("the dependencies of the server are healthy")
+	}
+
+	for _, name := range h.storageServices {
+		h.srv.SetServingStatus(name, status)
+	}
+	for _, name := range h.independentServices {
+		h.srv.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+	h.srv.SetServingStatus("", status)
+}
+
+// ready - returns nil if the server is ready to serve the requests.
+func (h *healthChecker) ready() error {
+	if h.shutdown.Load() {
+		return errShuttingDown
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.lastErr
+}
+
+// shutdownServing - sets all the services to NOT_SERVING, the statuses are not updated anymore.
+func (h *healthChecker) shutdownServing() {
+	h.shutdown.Store(true)
+	h.stopOnce.Do(func() { close(h.stop) })
+	h.srv.Shutdown()
+}
+
+// handle - adds the HTTP health endpoints to the mux.
+//
+// /healthz reports that the process is alive, /readyz reports whether the server is ready to serve the requests.
+func (h *healthChecker) handle(mux *http.ServeMux) {
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if err := h.ready(); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintln(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ok")
+	})
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestHealthChecker(t *testing.T) {
+	ctx := context.Background()
+
+	var dbErr error
+	h := newHealthChecker(zap.NewNop(), 0)
+	h.addDependency("postgres", func(ctx context.Context) error { return dbErr })
+	mux := http.NewServeMux()
+	h.handle(mux)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	tests := []struct {
+		name        string
+		dbErr       error
+		shutdown    bool
+		wantServer  healthpb.HealthCheckResponse_ServingStatus
+		wantRecords healthpb.HealthCheckResponse_ServingStatus
+		wantInfo    healthpb.HealthCheckResponse_ServingStatus
+		wantReady   int
+	}{
+		{
+			name:        "database is available",
+			wantServer:  healthpb.HealthCheckResponse_SERVING,
+			wantRecords: healthpb.HealthCheckResponse_SERVING,
+			wantInfo:    healthpb.HealthCheckResponse_SERVING,
+			wantReady:   http.StatusOK,
+		},
+		{
+			name:        "database is unavailable",
+			dbErr:       errSomethingWentWrong,
+			wantServer:  healthpb.HealthCheckResponse_NOT_SERVING,
+			wantRecords: healthpb.HealthCheckResponse_NOT_SERVING,
+			wantInfo:    healthpb.HealthCheckResponse_SERVING,
+			wantReady:   http.StatusServiceUnavailable,
+		},
+		{
+			name:        "server is shutting down",
+			shutdown:    true,
+			wantServer:  healthpb.HealthCheckResponse_NOT_SERVING,
+			wantRecords: healthpb.HealthCheckResponse_NOT_SERVING,
+			wantInfo:    healthpb.HealthCheckResponse_NOT_SERVING,
+			wantReady:   http.StatusServiceUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbErr = tt.dbErr
+			if tt.shutdown {
+				h.shutdownServing()
+			}
+			h.run()
+
+			for service, want := range map[string]healthpb.HealthCheckResponse_ServingStatus{
+				"":                              tt.wantServer,
+				Records_ServiceDesc.ServiceName: tt.wantRecords,
+				Info_ServiceDesc.ServiceName:    tt.wantInfo,
+			} {
+				resp, err := h.srv.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+				if err != nil {
+					t.Fatalf("health.Server.Check(%q) error = %v", service, err)
+				}
+				if resp.GetStatus() != want {
+					t.Errorf("health.Server.Check(%q) = %v, want %v", service, resp.GetStatus(), want)
+				}
+			}
+
+			for path, want := range map[string]int{"/healthz": http.StatusOK, "/readyz": tt.wantReady} {
+				resp, err := http.Get(ts.URL + path)
+				if err != nil {
+					t.Fatalf("http.Get(%s) error = %v", path, err)
+				}
+				resp.Body.Close()
+				if resp.StatusCode != want {
+					t.Errorf("GET %s = %d, want %d", path, resp.StatusCode, want)
+				}
+			}
+		})
+	}
+
+	if err := h.ready(); !errors.Is(err, errShuttingDown) {
+		t.Errorf("healthChecker.ready() error = %v, want %v", err, errShuttingDown)
+	}
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/config"
+)
+
+// httpReadTimeout - the time limit of reading the headers of the request of the HTTP endpoints.
+const httpReadTimeout = 5 * time.Second
+
+// newHTTPServers - returns the HTTP servers of the health and the metrics endpoints.
+// The endpoints with the same address are served by one server, the endpoints without the address are disabled.
+func newHTTPServers(cfg *config.ServerCfg, h *healthChecker, reg *prometheus.Registry) []*http.Server {
+	muxes := make(map[string]*http.ServeMux)
+	var addrs []string
+	mux := func(addr string) *http.ServeMux {
+		m, ok := muxes[addr]
+		if !ok {
+			m = http.NewServeMux()
+			muxes[addr] = m
+			addrs = append(addrs, addr)
+		}
+		return m
+	}
+
+	if cfg.HealthAddr != "" {
+		h.handle(mux(cfg.HealthAddr))
+	}
+	if cfg.MetricsAddr != "" {
+		mux(cfg.MetricsAddr).Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg}))
+	}
+
+	srvs := make([]*http.Server, 0, len(addrs))
+	for _, addr := range addrs {
+		srvs = append(srvs, &http.Server{
+			Addr:              addr,
+			Handler:           muxes[addr],
+			ReadHeaderTimeout: httpReadTimeout,
+		})
+	}
+
+	return srvs
+}
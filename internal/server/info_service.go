@@ -0,0 +1,37 @@
+package server
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/build"
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+// InfoService - Implements GRPC server methods that report the information about the server.
+type InfoService struct {
+	UnimplementedInfoServer
+	log *zap.Logger
+}
+
+// NewInfoService - Object Constructor.
+func NewInfoService(log *zap.Logger) *InfoService {
+	return &InfoService{
+		log: log,
+	}
+}
+
+// GetServerInfo - used to retrieving the build, the protocol and the features of the server.
+func (is *InfoService) GetServerInfo(ctx context.Context, request *GetServerInfoRequest) (*GetServerInfoResponse, error) {
+	b := build.NewBuild()
+
+	return &GetServerInfoResponse{
+		Version:            b.Version(),
+		Date:               b.Date(),
+		Commit:             b.Commit(),
+		ProtocolVersion:    models.ProtocolVersion,
+		MinProtocolVersion: models.MinProtocolVersion,
+		Features:           models.ServerFeatures,
+	}, nil
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDHeader - the header of the ID of the request, it is taken from the request or generated
+// and returned in the headers of the response.
+const requestIDHeader = "x-request-id"
+
+// maxRequestIDLen - the longer IDs of the requests are replaced by the generated ones.
+const maxRequestIDLen = 128
+
+// errInternal - the message of the error that is returned instead of the panic of the handler.
+const errInternal = "internal server error"
+
+type requestIDKey struct{}
+
+// requestID - returns the ID of the request, it is empty if the request has no ID.
+func requestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// withRequestID - returns the context with the ID of the request,
+// the ID is taken from the headers of the request or generated.
+func withRequestID(ctx context.Context) (context.Context, string) {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(requestIDHeader); len(ids) > 0 && ids[0] != "" && len(ids[0]) <= maxRequestIDLen {
+			id = ids[0]
+		}
+	}
+	if id == "" {
+		id = uuid.NewString()
+	}
+	return context.WithValue(ctx, requestIDKey{}, id), id
+}
+
+// serverStream - the stream with the context of the interceptors.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (ss *serverStream) Context() context.Context {
+	return ss.ctx
+}
+
+// requestIDInterceptor - sets the ID of the request and returns it in the headers of the response.
+func requestIDInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, id := withRequestID(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDHeader, id))
+		return handler(ctx, req)
+	}
+}
+
+// streamRequestIDInterceptor - sets the ID of the stream and returns it in the headers of the response.
+func streamRequestIDInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		ctx, id := withRequestID(ss.Context())
+		_ = ss.SetHeader(metadata.Pairs(requestIDHeader, id))
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// recoverPanic - converts the panic of the handler to codes.Internal, the details of the panic are only logged.
+func (s *GKServer) recoverPanic(ctx context.Context, method string, err *error) {
+	if p := recover(); p != nil {
+		s.log.Error("a panic occurred while processing RPC request",
+			zap.String("RPC method", method),
+			zap.String("request_id", requestID(ctx)),
+			zap.Any("panic", p),
+			zap.ByteString("stack", debug.Stack()))
+		*err = status.Error(codes.Internal, errInternal)
+	}
+}
+
+// recoveryInterceptor - the panic of the handler fails the request instead of the server.
+func (s *GKServer) recoveryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer s.recoverPanic(ctx, info.FullMethod, &err)
+		return handler(ctx, req)
+	}
+}
+
+// streamRecoveryInterceptor - the panic of the handler fails the stream instead of the server.
+func (s *GKServer) streamRecoveryInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) (err error) {
+		defer s.recoverPanic(ss.Context(), info.FullMethod, &err)
+		return handler(srv, ss)
+	}
+}
+
+// deadlineInterceptor - limits the time of processing of the unary request, the shorter deadline of the client is kept.
+// The streams are not limited by it, they are closed with their connections, see keepaliveParams.
+func deadlineInterceptor(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		if timeout <= 0 {
+			return handler(ctx, req)
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
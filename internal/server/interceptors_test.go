@@ -0,0 +1,203 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	gomock "go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/config"
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+func TestInterceptors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	u := user(t)
+	us := NewMockUserStorage(ctrl)
+	r := generateTextRecord(t)
+	missing := generateTextRecord(t)
+	broken := generateTextRecord(t)
+
+	rs := NewMockRecordStorage(ctrl)
+	rs.EXPECT().GetRecord(gomock.Any(), u.ID, r.ID).AnyTimes().Return(r, nil)
+	rs.EXPECT().GetRecord(gomock.Any(), u.ID, missing.ID).AnyTimes().Return(nil, models.ErrRecordNotFound)
+	rs.EXPECT().GetRecord(gomock.Any(), u.ID, broken.ID).AnyTimes().
+		DoAndReturn(func(context.Context, string, string) (*models.Record, error) {
+			panic("storage is broken")
+		})
+
+	d, err := NewRecordServiceDialer(t, us, rs)
+	if err != nil {
+		t.Fatalf("an occured error when creating a new dialer, err: %v", err)
+	}
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(d.bufDialer),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to dial bufnet: %v", err)
+	}
+	defer conn.Close()
+
+	c := &GKClient{cc: conn, log: zap.L(), deviceID: testDeviceID}
+
+	tests := []struct {
+		name      string
+		recordID  string
+		requestID string
+		wantCode  codes.Code
+		wantMsg   string
+	}{
+		{
+			name:      "the ID of the request is returned",
+			recordID:  r.ID,
+			requestID: "request-1",
+			wantCode:  codes.OK,
+		},
+		{
+			name:     "the ID of the request is generated",
+			recordID: r.ID,
+			wantCode: codes.OK,
+		},
+		{
+			name:     "status code is kept",
+			recordID: missing.ID,
+			wantCode: codes.NotFound,
+			wantMsg:  models.ErrRecordNotFound.Error(),
+		},
+		{
+			name:     "panic is recovered",
+			recordID: broken.ID,
+			wantCode: codes.Internal,
+			wantMsg:  errInternal,
+		},
+		{
+			name:     "server serves after panic",
+			recordID: r.ID,
+			wantCode: codes.OK,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := c.outgoingContext(context.Background(), u.ID)
+			if tt.requestID != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, requestIDHeader, tt.requestID)
+			}
+
+			var header metadata.MD
+			_, err := NewRecordsClient(conn).GetRecord(ctx, &GetRecordRequest{Id: tt.recordID}, grpc.Header(&header))
+			st := status.Convert(err)
+			if st.Code() != tt.wantCode {
+				t.Fatalf("GetRecord() code = %v, want %v, err: %v", st.Code(), tt.wantCode, err)
+			}
+			if tt.wantMsg != "" && st.Message() != tt.wantMsg {
+				t.Errorf("GetRecord() message = %q, want %q", st.Message(), tt.wantMsg)
+			}
+
+			ids := header.Get(requestIDHeader)
+			if len(ids) != 1 || ids[0] == "" || (tt.requestID != "" && ids[0] != tt.requestID) {
+				t.Errorf("GetRecord() request ID = %v, want %q", ids, tt.requestID)
+			}
+		})
+	}
+
+	if _, err := c.GetRecord(context.Background(), u.ID, missing.ID); !errors.Is(err, models.ErrRecordNotFound) {
+		t.Errorf("GKClient.GetRecord() error = %v, want %v", err, models.ErrRecordNotFound)
+	}
+}
+
+func TestRequestBody(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		want   int
+	}{
+		{name: "body of the record request is logged", method: Records_BatchUpsertRecords_FullMethodName, want: 1},
+		{name: "body of the users request is not logged", method: Users_Register_FullMethodName, want: 0},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requestBody(tt.method, &GetRecordRequest{}); len(got) != tt.want {
+				t.Errorf("requestBody() returns %d fields, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestDeadlineInterceptor(t *testing.T) {
+	const timeout = time.Minute
+
+	tests := []struct {
+		name     string
+		deadline time.Duration
+		want     time.Duration
+	}{
+		{name: "no deadline of the client", want: timeout},
+		{name: "shorter deadline of the client is kept", deadline: time.Second, want: time.Second},
+		{name: "longer deadline of the client is limited", deadline: time.Hour, want: timeout},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.deadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.deadline)
+				defer cancel()
+			}
+
+			var got time.Duration
+			_, _ = deadlineInterceptor(timeout)(ctx, nil, &grpc.UnaryServerInfo{},
+				func(ctx context.Context, _ interface{}) (interface{}, error) {
+					dl, _ := ctx.Deadline()
+					got = time.Until(dl)
+					return nil, nil
+				})
+			if got > tt.want || got < tt.want-time.Second {
+				t.Errorf("deadlineInterceptor() deadline in %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeepaliveParams(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *config.ServerCfg
+		want keepalive.ServerParameters
+	}{
+		{
+			name: "connections are limited",
+			cfg:  &config.ServerCfg{ConnIdleTimeout: time.Minute, ConnMaxAge: time.Hour, RequestTimeout: time.Second},
+			want: keepalive.ServerParameters{
+				MaxConnectionIdle:     time.Minute,
+				MaxConnectionAge:      time.Hour,
+				MaxConnectionAgeGrace: time.Second,
+			},
+		},
+		{
+			name: "no limits",
+			cfg:  &config.ServerCfg{},
+			want: keepalive.ServerParameters{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := keepaliveParams(tt.cfg); got != tt.want {
+				t.Errorf("keepaliveParams() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const metricsNamespace = "gophkeeper"
+
+// The reasons of the failed logins.
+const (
+	loginUnknownUser   = "unknown_user"
+	loginWrongPassword = "wrong_password"
+	loginDisabled      = "disabled"
+)
+
+// The types of the RPC in the metrics.
+const (
+	rpcTypeUnary  = "unary"
+	rpcTypeStream = "stream"
+)
+
+var (
+	rpcRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "grpc",
+		Name:      "requests_total",
+		Help:      "The number of the handled RPC requests by the method and the status code.",
+	}, []string{"method", "type", "code"})
+
+	rpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "grpc",
+		Name:      "request_duration_seconds",
+		Help:      "The latency of the RPC requests by the method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "type"})
+
+	loginFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "auth",
+		Name:      "login_failures_total",
+		Help:      "The number of the failed logins by the reason.",
+	}, []string{"reason"})
+
+	syncConflicts = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "sync",
+		Name:      "conflicts_total",
+		Help:      "The number of the pushed records that replaced a concurrent version of the record.",
+	})
+)
+
+// newMetricsRegistry - returns the registry of the metrics of the server, the Go runtime and the process.
+func newMetricsRegistry() *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		rpcRequests,
+		rpcDuration,
+		loginFailures,
+		syncConflicts,
+	)
+
+	return reg
+}
+
+// observeRPC - counts the request and its latency.
+func observeRPC(method string, rpcType string, start time.Time, err error) {
+	rpcRequests.WithLabelValues(method, rpcType, status.Code(err).String()).Inc()
+	rpcDuration.WithLabelValues(method, rpcType).Observe(time.Since(start).Seconds())
+}
+
+// metricsInterceptor - counts the unary requests, their status codes and latency.
+func metricsInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observeRPC(info.FullMethod, rpcTypeUnary, start, err)
+		return resp, err
+	}
+}
+
+// streamMetricsInterceptor - counts the streams, their status codes and duration.
+func streamMetricsInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observeRPC(info.FullMethod, rpcTypeStream, start, err)
+		return err
+	}
+}
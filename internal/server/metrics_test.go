@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/config"
+)
+
+func TestNewHTTPServers(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       *config.ServerCfg
+		wantAddrs []string
+	}{
+		{
+			name: "disabled",
+			cfg:  &config.ServerCfg{},
+		},
+		{
+			name:      "separate addresses",
+			cfg:       &config.ServerCfg{HealthAddr: ":6086", MetricsAddr: ":6087"},
+			wantAddrs: []string{":6086", ":6087"},
+		},
+		{
+			name:      "same address",
+			cfg:       &config.ServerCfg{HealthAddr: ":6086", MetricsAddr: ":6086"},
+			wantAddrs: []string{":6086"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			srvs := newHTTPServers(tt.cfg, newHealthChecker(zap.NewNop(), 0), newMetricsRegistry())
+			if len(srvs) != len(tt.wantAddrs) {
+				t.Fatalf("newHTTPServers() = %d servers, want %d", len(srvs), len(tt.wantAddrs))
+			}
+			for i, s := range srvs {
+				if s.Addr != tt.wantAddrs[i] {
+					t.Errorf("newHTTPServers()[%d].Addr = %s, want %s", i, s.Addr, tt.wantAddrs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMetricsInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/gophkeeper.Records/GetRecord"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	if _, err := metricsInterceptor()(context.Background(), nil, info, handler); status.Code(err) != codes.NotFound {
+		t.Fatalf("metricsInterceptor() error = %v, want %v", err, codes.NotFound)
+	}
+
+	cfg := &config.ServerCfg{MetricsAddr: ":6087"}
+	srvs := newHTTPServers(cfg, newHealthChecker(zap.NewNop(), 0), newMetricsRegistry())
+	ts := httptest.NewServer(srvs[0].Handler)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("http.Get() error = %v", err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("io.ReadAll() error = %v", err)
+	}
+
+	want := `gophkeeper_grpc_requests_total{code="NotFound",method="/gophkeeper.Records/GetRecord",type="unary"}`
+	if !strings.Contains(string(b), want) {
+		t.Errorf("GET /metrics does not contain %s", want)
+	}
+}
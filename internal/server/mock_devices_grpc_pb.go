@@ -0,0 +1,246 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/server/devices_grpc.pb.go
+
+// Package server is a generated GoMock package.
+package server
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+	grpc "google.golang.org/grpc"
+)
+
+// MockDevicesClient is a mock of DevicesClient interface.
+type MockDevicesClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockDevicesClientMockRecorder
+}
+
+// MockDevicesClientMockRecorder is the mock recorder for MockDevicesClient.
+type MockDevicesClientMockRecorder struct {
+	mock *MockDevicesClient
+}
+
+// NewMockDevicesClient creates a new mock instance.
+func NewMockDevicesClient(ctrl *gomock.Controller) *MockDevicesClient {
+	mock := &MockDevicesClient{ctrl: ctrl}
+	mock.recorder = &MockDevicesClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDevicesClient) EXPECT() *MockDevicesClientMockRecorder {
+	return m.recorder
+}
+
+// ListDevices mocks base method.
+func (m *MockDevicesClient) ListDevices(ctx context.Context, in *ListDevicesRequest, opts ...grpc.CallOption) (*ListDevicesResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListDevices", varargs...)
+	ret0, _ := ret[0].(*ListDevicesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDevices indicates an expected call of ListDevices.
+func (mr *MockDevicesClientMockRecorder) ListDevices(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDevices", reflect.TypeOf((*MockDevicesClient)(nil).ListDevices), varargs...)
+}
+
+// RegisterDevice mocks base method.
+func (m *MockDevicesClient) RegisterDevice(ctx context.Context, in *RegisterDeviceRequest, opts ...grpc.CallOption) (*RegisterDeviceResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RegisterDevice", varargs...)
+	ret0, _ := ret[0].(*RegisterDeviceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterDevice indicates an expected call of RegisterDevice.
+func (mr *MockDevicesClientMockRecorder) RegisterDevice(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterDevice", reflect.TypeOf((*MockDevicesClient)(nil).RegisterDevice), varargs...)
+}
+
+// ReportSync mocks base method.
+func (m *MockDevicesClient) ReportSync(ctx context.Context, in *ReportSyncRequest, opts ...grpc.CallOption) (*ReportSyncResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ReportSync", varargs...)
+	ret0, _ := ret[0].(*ReportSyncResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportSync indicates an expected call of ReportSync.
+func (mr *MockDevicesClientMockRecorder) ReportSync(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportSync", reflect.TypeOf((*MockDevicesClient)(nil).ReportSync), varargs...)
+}
+
+// RevokeDevice mocks base method.
+func (m *MockDevicesClient) RevokeDevice(ctx context.Context, in *RevokeDeviceRequest, opts ...grpc.CallOption) (*RevokeDeviceResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RevokeDevice", varargs...)
+	ret0, _ := ret[0].(*RevokeDeviceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeDevice indicates an expected call of RevokeDevice.
+func (mr *MockDevicesClientMockRecorder) RevokeDevice(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeDevice", reflect.TypeOf((*MockDevicesClient)(nil).RevokeDevice), varargs...)
+}
+
+// MockDevicesServer is a mock of DevicesServer interface.
+type MockDevicesServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockDevicesServerMockRecorder
+}
+
+// MockDevicesServerMockRecorder is the mock recorder for MockDevicesServer.
+type MockDevicesServerMockRecorder struct {
+	mock *MockDevicesServer
+}
+
+// NewMockDevicesServer creates a new mock instance.
+func NewMockDevicesServer(ctrl *gomock.Controller) *MockDevicesServer {
+	mock := &MockDevicesServer{ctrl: ctrl}
+	mock.recorder = &MockDevicesServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDevicesServer) EXPECT() *MockDevicesServerMockRecorder {
+	return m.recorder
+}
+
+// ListDevices mocks base method.
+func (m *MockDevicesServer) ListDevices(arg0 context.Context, arg1 *ListDevicesRequest) (*ListDevicesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDevices", arg0, arg1)
+	ret0, _ := ret[0].(*ListDevicesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDevices indicates an expected call of ListDevices.
+func (mr *MockDevicesServerMockRecorder) ListDevices(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDevices", reflect.TypeOf((*MockDevicesServer)(nil).ListDevices), arg0, arg1)
+}
+
+// RegisterDevice mocks base method.
+func (m *MockDevicesServer) RegisterDevice(arg0 context.Context, arg1 *RegisterDeviceRequest) (*RegisterDeviceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterDevice", arg0, arg1)
+	ret0, _ := ret[0].(*RegisterDeviceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterDevice indicates an expected call of RegisterDevice.
+func (mr *MockDevicesServerMockRecorder) RegisterDevice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterDevice", reflect.TypeOf((*MockDevicesServer)(nil).RegisterDevice), arg0, arg1)
+}
+
+// ReportSync mocks base method.
+func (m *MockDevicesServer) ReportSync(arg0 context.Context, arg1 *ReportSyncRequest) (*ReportSyncResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportSync", arg0, arg1)
+	ret0, _ := ret[0].(*ReportSyncResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportSync indicates an expected call of ReportSync.
+func (mr *MockDevicesServerMockRecorder) ReportSync(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportSync", reflect.TypeOf((*MockDevicesServer)(nil).ReportSync), arg0, arg1)
+}
+
+// RevokeDevice mocks base method.
+func (m *MockDevicesServer) RevokeDevice(arg0 context.Context, arg1 *RevokeDeviceRequest) (*RevokeDeviceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeDevice", arg0, arg1)
+	ret0, _ := ret[0].(*RevokeDeviceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeDevice indicates an expected call of RevokeDevice.
+func (mr *MockDevicesServerMockRecorder) RevokeDevice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeDevice", reflect.TypeOf((*MockDevicesServer)(nil).RevokeDevice), arg0, arg1)
+}
+
+// mustEmbedUnimplementedDevicesServer mocks base method.
+func (m *MockDevicesServer) mustEmbedUnimplementedDevicesServer() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "mustEmbedUnimplementedDevicesServer")
+}
+
+// mustEmbedUnimplementedDevicesServer indicates an expected call of mustEmbedUnimplementedDevicesServer.
+func (mr *MockDevicesServerMockRecorder) mustEmbedUnimplementedDevicesServer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "mustEmbedUnimplementedDevicesServer", reflect.TypeOf((*MockDevicesServer)(nil).mustEmbedUnimplementedDevicesServer))
+}
+
+// MockUnsafeDevicesServer is a mock of UnsafeDevicesServer interface.
+type MockUnsafeDevicesServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnsafeDevicesServerMockRecorder
+}
+
+// MockUnsafeDevicesServerMockRecorder is the mock recorder for MockUnsafeDevicesServer.
+type MockUnsafeDevicesServerMockRecorder struct {
+	mock *MockUnsafeDevicesServer
+}
+
+// NewMockUnsafeDevicesServer creates a new mock instance.
+func NewMockUnsafeDevicesServer(ctrl *gomock.Controller) *MockUnsafeDevicesServer {
+	mock := &MockUnsafeDevicesServer{ctrl: ctrl}
+	mock.recorder = &MockUnsafeDevicesServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnsafeDevicesServer) EXPECT() *MockUnsafeDevicesServerMockRecorder {
+	return m.recorder
+}
+
+// mustEmbedUnimplementedDevicesServer mocks base method.
+func (m *MockUnsafeDevicesServer) mustEmbedUnimplementedDevicesServer() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "mustEmbedUnimplementedDevicesServer")
+}
+
+// mustEmbedUnimplementedDevicesServer indicates an expected call of mustEmbedUnimplementedDevicesServer.
+func (mr *MockUnsafeDevicesServerMockRecorder) mustEmbedUnimplementedDevicesServer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "mustEmbedUnimplementedDevicesServer", reflect.TypeOf((*MockUnsafeDevicesServer)(nil).mustEmbedUnimplementedDevicesServer))
+}
@@ -0,0 +1,147 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/models/devices.go
+
+// Package server is a generated GoMock package.
+package server
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/ArtemShalinFe/gophkeeper/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDeviceStorage is a mock of DeviceStorage interface.
+type MockDeviceStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceStorageMockRecorder
+}
+
+// MockDeviceStorageMockRecorder is the mock recorder for MockDeviceStorage.
+type MockDeviceStorageMockRecorder struct {
+	mock *MockDeviceStorage
+}
+
+// NewMockDeviceStorage creates a new mock instance.
+func NewMockDeviceStorage(ctrl *gomock.Controller) *MockDeviceStorage {
+	mock := &MockDeviceStorage{ctrl: ctrl}
+	mock.recorder = &MockDeviceStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeviceStorage) EXPECT() *MockDeviceStorageMockRecorder {
+	return m.recorder
+}
+
+// AddDevice mocks base method.
+func (m *MockDeviceStorage) AddDevice(ctx context.Context, userID string, device *models.DeviceDTO) (*models.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDevice", ctx, userID, device)
+	ret0, _ := ret[0].(*models.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddDevice indicates an expected call of AddDevice.
+func (mr *MockDeviceStorageMockRecorder) AddDevice(ctx, userID, device interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDevice", reflect.TypeOf((*MockDeviceStorage)(nil).AddDevice), ctx, userID, device)
+}
+
+// GetDevice mocks base method.
+func (m *MockDeviceStorage) GetDevice(ctx context.Context, userID, deviceID string) (*models.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDevice", ctx, userID, deviceID)
+	ret0, _ := ret[0].(*models.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDevice indicates an expected call of GetDevice.
+func (mr *MockDeviceStorageMockRecorder) GetDevice(ctx, userID, deviceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDevice", reflect.TypeOf((*MockDeviceStorage)(nil).GetDevice), ctx, userID, deviceID)
+}
+
+// ListDevices mocks base method.
+func (m *MockDeviceStorage) ListDevices(ctx context.Context, userID string) ([]*models.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDevices", ctx, userID)
+	ret0, _ := ret[0].([]*models.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDevices indicates an expected call of ListDevices.
+func (mr *MockDeviceStorageMockRecorder) ListDevices(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDevices", reflect.TypeOf((*MockDeviceStorage)(nil).ListDevices), ctx, userID)
+}
+
+// RevokeDevice mocks base method.
+func (m *MockDeviceStorage) RevokeDevice(ctx context.Context, userID, deviceID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeDevice", ctx, userID, deviceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeDevice indicates an expected call of RevokeDevice.
+func (mr *MockDeviceStorageMockRecorder) RevokeDevice(ctx, userID, deviceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeDevice", reflect.TypeOf((*MockDeviceStorage)(nil).RevokeDevice), ctx, userID, deviceID)
+}
+
+// TouchDevice mocks base method.
+func (m *MockDeviceStorage) TouchDevice(ctx context.Context, userID, deviceID string, synced bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchDevice", ctx, userID, deviceID, synced)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchDevice indicates an expected call of TouchDevice.
+func (mr *MockDeviceStorageMockRecorder) TouchDevice(ctx, userID, deviceID, synced interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchDevice", reflect.TypeOf((*MockDeviceStorage)(nil).TouchDevice), ctx, userID, deviceID, synced)
+}
+
+// MockUserStatusReader is a mock of UserStatusReader interface.
+type MockUserStatusReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserStatusReaderMockRecorder
+}
+
+// MockUserStatusReaderMockRecorder is the mock recorder for MockUserStatusReader.
+type MockUserStatusReaderMockRecorder struct {
+	mock *MockUserStatusReader
+}
+
+// NewMockUserStatusReader creates a new mock instance.
+func NewMockUserStatusReader(ctrl *gomock.Controller) *MockUserStatusReader {
+	mock := &MockUserStatusReader{ctrl: ctrl}
+	mock.recorder = &MockUserStatusReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserStatusReader) EXPECT() *MockUserStatusReaderMockRecorder {
+	return m.recorder
+}
+
+// UserDisabled mocks base method.
+func (m *MockUserStatusReader) UserDisabled(ctx context.Context, userID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserDisabled", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserDisabled indicates an expected call of UserDisabled.
+func (mr *MockUserStatusReaderMockRecorder) UserDisabled(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserDisabled", reflect.TypeOf((*MockUserStatusReader)(nil).UserDisabled), ctx, userID)
+}
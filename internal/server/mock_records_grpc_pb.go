@@ -55,6 +55,66 @@ func (mr *MockRecordsClientMockRecorder) AddRecord(ctx, in interface{}, opts ...
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRecord", reflect.TypeOf((*MockRecordsClient)(nil).AddRecord), varargs...)
 }
 
+// BatchDeleteRecords mocks base method.
+func (m *MockRecordsClient) BatchDeleteRecords(ctx context.Context, in *BatchDeleteRecordsRequest, opts ...grpc.CallOption) (*BatchDeleteRecordsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BatchDeleteRecords", varargs...)
+	ret0, _ := ret[0].(*BatchDeleteRecordsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchDeleteRecords indicates an expected call of BatchDeleteRecords.
+func (mr *MockRecordsClientMockRecorder) BatchDeleteRecords(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchDeleteRecords", reflect.TypeOf((*MockRecordsClient)(nil).BatchDeleteRecords), varargs...)
+}
+
+// BatchGetRecords mocks base method.
+func (m *MockRecordsClient) BatchGetRecords(ctx context.Context, in *BatchGetRecordsRequest, opts ...grpc.CallOption) (*BatchGetRecordsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BatchGetRecords", varargs...)
+	ret0, _ := ret[0].(*BatchGetRecordsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchGetRecords indicates an expected call of BatchGetRecords.
+func (mr *MockRecordsClientMockRecorder) BatchGetRecords(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchGetRecords", reflect.TypeOf((*MockRecordsClient)(nil).BatchGetRecords), varargs...)
+}
+
+// BatchUpsertRecords mocks base method.
+func (m *MockRecordsClient) BatchUpsertRecords(ctx context.Context, in *BatchUpsertRecordsRequest, opts ...grpc.CallOption) (*BatchUpsertRecordsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BatchUpsertRecords", varargs...)
+	ret0, _ := ret[0].(*BatchUpsertRecordsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchUpsertRecords indicates an expected call of BatchUpsertRecords.
+func (mr *MockRecordsClientMockRecorder) BatchUpsertRecords(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchUpsertRecords", reflect.TypeOf((*MockRecordsClient)(nil).BatchUpsertRecords), varargs...)
+}
+
 // DeleteRecord mocks base method.
 func (m *MockRecordsClient) DeleteRecord(ctx context.Context, in *DeleteRecordRequest, opts ...grpc.CallOption) (*DeleteRecordResponse, error) {
 	m.ctrl.T.Helper()
@@ -173,6 +233,51 @@ func (mr *MockRecordsServerMockRecorder) AddRecord(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRecord", reflect.TypeOf((*MockRecordsServer)(nil).AddRecord), arg0, arg1)
 }
 
+// BatchDeleteRecords mocks base method.
+func (m *MockRecordsServer) BatchDeleteRecords(arg0 context.Context, arg1 *BatchDeleteRecordsRequest) (*BatchDeleteRecordsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchDeleteRecords", arg0, arg1)
+	ret0, _ := ret[0].(*BatchDeleteRecordsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchDeleteRecords indicates an expected call of BatchDeleteRecords.
+func (mr *MockRecordsServerMockRecorder) BatchDeleteRecords(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchDeleteRecords", reflect.TypeOf((*MockRecordsServer)(nil).BatchDeleteRecords), arg0, arg1)
+}
+
+// BatchGetRecords mocks base method.
+func (m *MockRecordsServer) BatchGetRecords(arg0 context.Context, arg1 *BatchGetRecordsRequest) (*BatchGetRecordsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchGetRecords", arg0, arg1)
+	ret0, _ := ret[0].(*BatchGetRecordsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchGetRecords indicates an expected call of BatchGetRecords.
+func (mr *MockRecordsServerMockRecorder) BatchGetRecords(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchGetRecords", reflect.TypeOf((*MockRecordsServer)(nil).BatchGetRecords), arg0, arg1)
+}
+
+// BatchUpsertRecords mocks base method.
+func (m *MockRecordsServer) BatchUpsertRecords(arg0 context.Context, arg1 *BatchUpsertRecordsRequest) (*BatchUpsertRecordsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchUpsertRecords", arg0, arg1)
+	ret0, _ := ret[0].(*BatchUpsertRecordsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchUpsertRecords indicates an expected call of BatchUpsertRecords.
+func (mr *MockRecordsServerMockRecorder) BatchUpsertRecords(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchUpsertRecords", reflect.TypeOf((*MockRecordsServer)(nil).BatchUpsertRecords), arg0, arg1)
+}
+
 // DeleteRecord mocks base method.
 func (m *MockRecordsServer) DeleteRecord(arg0 context.Context, arg1 *DeleteRecordRequest) (*DeleteRecordResponse, error) {
 	m.ctrl.T.Helper()
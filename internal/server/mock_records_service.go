@@ -50,6 +50,51 @@ func (mr *MockRecordStorageMockRecorder) AddRecord(ctx, userID, record interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRecord", reflect.TypeOf((*MockRecordStorage)(nil).AddRecord), ctx, userID, record)
 }
 
+// BatchDeleteRecords mocks base method.
+func (m *MockRecordStorage) BatchDeleteRecords(ctx context.Context, userID string, recordIDs []string) ([]*models.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchDeleteRecords", ctx, userID, recordIDs)
+	ret0, _ := ret[0].([]*models.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchDeleteRecords indicates an expected call of BatchDeleteRecords.
+func (mr *MockRecordStorageMockRecorder) BatchDeleteRecords(ctx, userID, recordIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchDeleteRecords", reflect.TypeOf((*MockRecordStorage)(nil).BatchDeleteRecords), ctx, userID, recordIDs)
+}
+
+// BatchGetRecords mocks base method.
+func (m *MockRecordStorage) BatchGetRecords(ctx context.Context, userID string, recordIDs []string) ([]*models.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchGetRecords", ctx, userID, recordIDs)
+	ret0, _ := ret[0].([]*models.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchGetRecords indicates an expected call of BatchGetRecords.
+func (mr *MockRecordStorageMockRecorder) BatchGetRecords(ctx, userID, recordIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchGetRecords", reflect.TypeOf((*MockRecordStorage)(nil).BatchGetRecords), ctx, userID, recordIDs)
+}
+
+// BatchUpsertRecords mocks base method.
+func (m *MockRecordStorage) BatchUpsertRecords(ctx context.Context, userID string, records []*models.Record) ([]*models.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchUpsertRecords", ctx, userID, records)
+	ret0, _ := ret[0].([]*models.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchUpsertRecords indicates an expected call of BatchUpsertRecords.
+func (mr *MockRecordStorageMockRecorder) BatchUpsertRecords(ctx, userID, records interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchUpsertRecords", reflect.TypeOf((*MockRecordStorage)(nil).BatchUpsertRecords), ctx, userID, records)
+}
+
 // DeleteRecord mocks base method.
 func (m *MockRecordStorage) DeleteRecord(ctx context.Context, userID, recordID string) error {
 	m.ctrl.T.Helper()
@@ -108,3 +153,41 @@ func (mr *MockRecordStorageMockRecorder) UpdateRecord(ctx, userID, record interf
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRecord", reflect.TypeOf((*MockRecordStorage)(nil).UpdateRecord), ctx, userID, record)
 }
+
+// MockUsageReporter is a mock of UsageReporter interface.
+type MockUsageReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsageReporterMockRecorder
+}
+
+// MockUsageReporterMockRecorder is the mock recorder for MockUsageReporter.
+type MockUsageReporterMockRecorder struct {
+	mock *MockUsageReporter
+}
+
+// NewMockUsageReporter creates a new mock instance.
+func NewMockUsageReporter(ctrl *gomock.Controller) *MockUsageReporter {
+	mock := &MockUsageReporter{ctrl: ctrl}
+	mock.recorder = &MockUsageReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsageReporter) EXPECT() *MockUsageReporterMockRecorder {
+	return m.recorder
+}
+
+// Usage mocks base method.
+func (m *MockUsageReporter) Usage(ctx context.Context, userID string) (*models.Usage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Usage", ctx, userID)
+	ret0, _ := ret[0].(*models.Usage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Usage indicates an expected call of Usage.
+func (mr *MockUsageReporterMockRecorder) Usage(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Usage", reflect.TypeOf((*MockUsageReporter)(nil).Usage), ctx, userID)
+}
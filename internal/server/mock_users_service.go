@@ -64,3 +64,40 @@ func (mr *MockUserStorageMockRecorder) GetUser(ctx, us interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockUserStorage)(nil).GetUser), ctx, us)
 }
+
+// MockSyncReporter is a mock of SyncReporter interface.
+type MockSyncReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockSyncReporterMockRecorder
+}
+
+// MockSyncReporterMockRecorder is the mock recorder for MockSyncReporter.
+type MockSyncReporterMockRecorder struct {
+	mock *MockSyncReporter
+}
+
+// NewMockSyncReporter creates a new mock instance.
+func NewMockSyncReporter(ctrl *gomock.Controller) *MockSyncReporter {
+	mock := &MockSyncReporter{ctrl: ctrl}
+	mock.recorder = &MockSyncReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSyncReporter) EXPECT() *MockSyncReporterMockRecorder {
+	return m.recorder
+}
+
+// ReportSync mocks base method.
+func (m *MockSyncReporter) ReportSync(ctx context.Context, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportSync", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReportSync indicates an expected call of ReportSync.
+func (mr *MockSyncReporterMockRecorder) ReportSync(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportSync", reflect.TypeOf((*MockSyncReporter)(nil).ReportSync), ctx, userID)
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+// RecordsQuota - The limits of the records of one user, zero means no limit.
+type RecordsQuota struct {
+	// MaxRecords - the maximum number of the records of the user.
+	// The quota is checked only if the storage implements models.UsageReporter.
+	MaxRecords int
+	// MaxRecordSize - the maximum size of the data of one record in bytes.
+	MaxRecordSize int
+}
+
+// SetQuota - Sets the limits of the records of the users.
+func (rs *RecordsService) SetQuota(q RecordsQuota) {
+	rs.quota = q
+}
+
+// checkRecordSize - returns models.ErrQuotaExceeded if the data of the record is larger than the quota.
+func (rs *RecordsService) checkRecordSize(size int) error {
+	if rs.quota.MaxRecordSize > 0 && size > rs.quota.MaxRecordSize {
+		return fmt.Errorf("%w: the record data should not exceed %d bytes", models.ErrQuotaExceeded,
+			rs.quota.MaxRecordSize)
+	}
+	return nil
+}
+
+// remainingRecords - returns the number of the records that the user may add, -1 means no limit.
+func (rs *RecordsService) remainingRecords(ctx context.Context, userID string) (int, error) {
+	ur, ok := rs.recordStorage.(models.UsageReporter)
+	if rs.quota.MaxRecords == 0 || !ok {
+		return -1, nil
+	}
+
+	u, err := ur.Usage(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("an error occured while retrieving usage, err: %w", err)
+	}
+
+	return max(rs.quota.MaxRecords-u.Records, 0), nil
+}
+
+// checkNewRecord - returns models.ErrQuotaExceeded if the user may not add one more record.
+func (rs *RecordsService) checkNewRecord(ctx context.Context, userID string) error {
+	n, err := rs.remainingRecords(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: the user should not have more than %d records", models.ErrQuotaExceeded,
+			rs.quota.MaxRecords)
+	}
+	return nil
+}
+
+// quotaStatus - converts the error of the quota check to the status of the response.
+func quotaStatus(err error) error {
+	if errors.Is(err, models.ErrQuotaExceeded) {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return status.Errorf(codes.Internal, "an error occurred while check quota, err: %v", err)
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	gomock "go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+// usageStorage - the record storage that reports the usage of the user.
+type usageStorage struct {
+	*MockRecordStorage
+	usage *models.Usage
+}
+
+func (s *usageStorage) Usage(context.Context, string) (*models.Usage, error) {
+	return s.usage, nil
+}
+
+func TestRecordsService_AddRecordQuota(t *testing.T) {
+	tests := []struct {
+		name     string
+		quota    RecordsQuota
+		usage    int
+		wantCode codes.Code
+	}{
+		{
+			name:     "record limit is reached",
+			quota:    RecordsQuota{MaxRecords: 2},
+			usage:    2,
+			wantCode: codes.ResourceExhausted,
+		},
+		{
+			name:     "record is too large",
+			quota:    RecordsQuota{MaxRecordSize: 1},
+			wantCode: codes.ResourceExhausted,
+		},
+		{
+			name:     "within quota",
+			quota:    RecordsQuota{MaxRecords: 2, MaxRecordSize: models.MaxFileSize},
+			usage:    1,
+			wantCode: codes.OK,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			u := user(t)
+			r := generateTextRecord(t)
+			rs := NewMockRecordStorage(gomock.NewController(t))
+			if tt.wantCode == codes.OK {
+				rs.EXPECT().AddRecord(gomock.Any(), u.ID, gomock.Any()).Return(r, nil)
+			}
+
+			srv := NewRecordsService(zap.L(), &usageStorage{MockRecordStorage: rs, usage: &models.Usage{Records: tt.usage}})
+			srv.SetQuota(tt.quota)
+
+			rpb, err := convRecordToProtobuff(r)
+			if err != nil {
+				t.Fatalf("an error occured while convert record to protobuff, err: %v", err)
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(userIDHeader, u.ID))
+			_, err = srv.AddRecord(ctx, &AddRecordRequest{Record: rpb})
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("RecordsService.AddRecord() error = %v, want code %v", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestRecordsService_BatchUpsertQuota(t *testing.T) {
+	u := user(t)
+	r1 := generateTextRecord(t)
+	r2 := generateTextRecord(t)
+	r3 := generateTextRecord(t)
+
+	rs := NewMockRecordStorage(gomock.NewController(t))
+	rs.EXPECT().BatchGetRecords(gomock.Any(), u.ID, []string{r1.ID, r2.ID, r3.ID}).Return([]*models.BatchResult{
+		{ID: r1.ID, Record: r1},
+		{ID: r2.ID, Err: models.ErrRecordNotFound},
+		{ID: r3.ID, Err: models.ErrRecordNotFound},
+	}, nil)
+	// The user may add one more record, so the update of r1 and the new r2 are accepted.
+	rs.EXPECT().BatchUpsertRecords(gomock.Any(), u.ID, gomock.Len(2)).Return([]*models.BatchResult{
+		{ID: r1.ID},
+		{ID: r2.ID},
+	}, nil)
+
+	srv := NewRecordsService(zap.L(), &usageStorage{MockRecordStorage: rs, usage: &models.Usage{Records: 2}})
+	srv.SetQuota(RecordsQuota{MaxRecords: 3})
+
+	var rpbs []*Record
+	for _, r := range []*models.Record{r1, r2, r3} {
+		rpb, err := convRecordToProtobuff(r)
+		if err != nil {
+			t.Fatalf("an error occured while convert record to protobuff, err: %v", err)
+		}
+		rpbs = append(rpbs, rpb)
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(userIDHeader, u.ID))
+	resp, err := srv.BatchUpsertRecords(ctx, &BatchUpsertRecordsRequest{Records: rpbs})
+	if err != nil {
+		t.Fatalf("RecordsService.BatchUpsertRecords() error = %v", err)
+	}
+
+	want := []BatchStatus{BatchStatus_BATCH_OK, BatchStatus_BATCH_OK, BatchStatus_BATCH_FAILED}
+	for i, res := range resp.GetResults() {
+		if res.GetStatus() != want[i] || res.GetId() != rpbs[i].GetId() {
+			t.Errorf("RecordsService.BatchUpsertRecords() result[%d] = %v, want %v", i, res, want[i])
+		}
+	}
+}
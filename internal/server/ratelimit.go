@@ -0,0 +1,222 @@
+package server
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// rateLimiterIdle - the limiter of the client is removed if the client has not sent requests for this time.
+const rateLimiterIdle = 10 * time.Minute
+
+// maxRateLimitClients - the limit of the clients that are tracked at the same time, the requests of the new clients
+// are rejected while the limit is reached and no client is idle, so the memory of the server is bounded.
+const maxRateLimitClients = 100_000
+
+// rateLimiter - limits the requests of every IP address of the clients and of every user.
+// The user is taken into account only after the device of the user is checked,
+// so the requests with a forged user can not exhaust the limit of another user.
+type rateLimiter struct {
+	mu         sync.Mutex
+	limit      rate.Limit
+	burst      int
+	clients    map[string]*clientLimiter
+	maxClients int
+	pruned     time.Time
+	now        func() time.Time
+}
+
+type clientLimiter struct {
+	lim  *rate.Limiter
+	seen time.Time
+}
+
+// newRateLimiter - returns the limiter of the requests, nil if the limit is not set.
+func newRateLimiter(limit float64, burst int) *rateLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	return &rateLimiter{
+		limit:      rate.Limit(limit),
+		burst:      burst,
+		clients:    make(map[string]*clientLimiter),
+		maxClients: maxRateLimitClients,
+		pruned:     time.Now(),
+		now:        time.Now,
+	}
+}
+
+// allow - reports whether the request of the client may be processed now.
+func (rl *rateLimiter) allow(key string) bool {
+	now := rl.now()
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if now.Sub(rl.pruned) > rateLimiterIdle {
+		rl.prune(now)
+	}
+
+	c, ok := rl.clients[key]
+	if !ok {
+		if len(rl.clients) >= rl.maxClients {
+			rl.prune(now)
+			if len(rl.clients) >= rl.maxClients {
+				return false
+			}
+		}
+		c = &clientLimiter{lim: rate.NewLimiter(rl.limit, rl.burst)}
+		rl.clients[key] = c
+	}
+	c.seen = now
+
+	return c.lim.AllowN(now, 1)
+}
+
+// prune - removes the limiters of the idle clients, it is called with the locked mutex.
+func (rl *rateLimiter) prune(now time.Time) {
+	for k, c := range rl.clients {
+		if now.Sub(c.seen) > rateLimiterIdle {
+			delete(rl.clients, k)
+		}
+	}
+	rl.pruned = now
+}
+
+// forwardedForHeader - the metadata where the gateway passes the address of the HTTP client.
+const forwardedForHeader = "x-forwarded-for"
+
+// peerIP - returns the IP address of the client, or the address as is if it has no port.
+// The requests of the gateway come from the loopback address, so for them the address of the HTTP client
+// that the gateway has forwarded is returned.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	addr := p.Addr.String()
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		if fwd := forwardedIP(ctx); fwd != "" {
+			return fwd
+		}
+	}
+	return host
+}
+
+// forwardedIP - returns the address of the HTTP client that the gateway has forwarded, empty if there is none.
+// The gateway appends the address of the client to the header of the HTTP request,
+// so only the last address is trusted, the previous ones are sent by the client.
+func forwardedIP(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	vs := md.Get(forwardedForHeader)
+	if len(vs) == 0 {
+		return ""
+	}
+
+	addrs := strings.Split(vs[len(vs)-1], ",")
+	ip := net.ParseIP(strings.TrimSpace(addrs[len(addrs)-1]))
+	if ip == nil {
+		return ""
+	}
+	return ip.String()
+}
+
+// ipRateLimitKey - returns the key of the IP address of the client.
+func ipRateLimitKey(ctx context.Context) string {
+	return "ip:" + peerIP(ctx)
+}
+
+// userRateLimitKey - returns the key of the user of the request, it is empty if the method does not check
+// the device of the user or the user is not specified.
+func userRateLimitKey(ctx context.Context, method string) string {
+	if !deviceRequired(method) {
+		return ""
+	}
+	uid, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return ""
+	}
+	return "user:" + uid
+}
+
+// checkRate - returns codes.ResourceExhausted if the client has sent too many requests.
+// The empty key is not limited.
+func (rl *rateLimiter) checkRate(key string) error {
+	if rl == nil || key == "" || rl.allow(key) {
+		return nil
+	}
+	return status.Error(codes.ResourceExhausted, "too many requests, try again later")
+}
+
+// rateLimitInterceptor - rejects the requests of the IP addresses that have exceeded the rate limit.
+func (rl *rateLimiter) rateLimitInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		if err := rl.checkRate(ipRateLimitKey(ctx)); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// userRateLimitInterceptor - rejects the requests of the users that have exceeded the rate limit.
+// It must follow the device checker.
+func (rl *rateLimiter) userRateLimitInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		if err := rl.checkRate(userRateLimitKey(ctx, info.FullMethod)); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// streamRateLimitInterceptor - rejects the streams of the IP addresses that have exceeded the rate limit.
+// The opening of the stream is counted as one request.
+func (rl *rateLimiter) streamRateLimitInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		if err := rl.checkRate(ipRateLimitKey(ss.Context())); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// streamUserRateLimitInterceptor - rejects the streams of the users that have exceeded the rate limit.
+// It must follow the device checker.
+func (rl *rateLimiter) streamUserRateLimitInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		if err := rl.checkRate(userRateLimitKey(ss.Context(), info.FullMethod)); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
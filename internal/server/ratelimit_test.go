@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Now()
+	rl := newRateLimiter(1, 2)
+	rl.now = func() time.Time { return now }
+
+	steps := []struct {
+		name  string
+		key   string
+		after time.Duration
+		want  bool
+	}{
+		{name: "first request", key: "user:1", want: true},
+		{name: "burst", key: "user:1", want: true},
+		{name: "burst is exceeded", key: "user:1", want: false},
+		{name: "other user is not limited", key: "user:2", want: true},
+		{name: "token is restored", key: "user:1", after: time.Second, want: true},
+		{name: "idle client is removed", key: "user:3", after: 2 * rateLimiterIdle, want: true},
+	}
+	for _, st := range steps {
+		now = now.Add(st.after)
+		if got := rl.allow(st.key); got != st.want {
+			t.Errorf("%s: rateLimiter.allow(%s) = %v, want %v", st.name, st.key, got, st.want)
+		}
+	}
+	if len(rl.clients) != 1 {
+		t.Errorf("rateLimiter keeps %d clients, want only the active one", len(rl.clients))
+	}
+
+	var disabled *rateLimiter
+	if err := disabled.checkRate("user:1"); err != nil {
+		t.Errorf("disabled rateLimiter.checkRate() error = %v", err)
+	}
+}
+
+func TestRateLimiter_MaxClients(t *testing.T) {
+	now := time.Now()
+	rl := newRateLimiter(1, 1)
+	rl.now = func() time.Time { return now }
+	rl.maxClients = 2
+
+	steps := []struct {
+		name  string
+		key   string
+		after time.Duration
+		want  bool
+	}{
+		{name: "first client", key: "ip:1", want: true},
+		{name: "second client", key: "ip:2", want: true},
+		{name: "new client is rejected while the limit is reached", key: "ip:3", want: false},
+		{name: "known client is served", key: "ip:1", after: time.Second, want: true},
+		{name: "new client replaces the idle ones", key: "ip:3", after: 2 * rateLimiterIdle, want: true},
+	}
+	for _, st := range steps {
+		now = now.Add(st.after)
+		if got := rl.allow(st.key); got != st.want {
+			t.Errorf("%s: rateLimiter.allow(%s) = %v, want %v", st.name, st.key, got, st.want)
+		}
+		if len(rl.clients) > rl.maxClients {
+			t.Errorf("%s: rateLimiter keeps %d clients, want at most %d", st.name, len(rl.clients), rl.maxClients)
+		}
+	}
+}
+
+func TestUserRateLimitKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		md     metadata.MD
+		want   string
+	}{
+		{
+			name:   "user of the checked device",
+			method: Records_BatchUpsertRecords_FullMethodName,
+			md:     metadata.Pairs(userIDHeader, "1"),
+			want:   "user:1",
+		},
+		{
+			name:   "user is not specified",
+			method: Records_BatchUpsertRecords_FullMethodName,
+			md:     metadata.MD{},
+			want:   "",
+		},
+		{
+			name:   "user of the method without the device check",
+			method: Users_Register_FullMethodName,
+			md:     metadata.Pairs(userIDHeader, "1"),
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			if got := userRateLimitKey(ctx, tt.method); got != tt.want {
+				t.Errorf("userRateLimitKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -19,11 +19,14 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	Records_GetRecord_FullMethodName    = "/gophkeeper.Records/GetRecord"
-	Records_AddRecord_FullMethodName    = "/gophkeeper.Records/AddRecord"
-	Records_UpdateRecord_FullMethodName = "/gophkeeper.Records/UpdateRecord"
-	Records_ListRecords_FullMethodName  = "/gophkeeper.Records/ListRecords"
-	Records_DeleteRecord_FullMethodName = "/gophkeeper.Records/DeleteRecord"
+	Records_GetRecord_FullMethodName          = "/gophkeeper.Records/GetRecord"
+	Records_AddRecord_FullMethodName          = "/gophkeeper.Records/AddRecord"
+	Records_UpdateRecord_FullMethodName       = "/gophkeeper.Records/UpdateRecord"
+	Records_ListRecords_FullMethodName        = "/gophkeeper.Records/ListRecords"
+	Records_DeleteRecord_FullMethodName       = "/gophkeeper.Records/DeleteRecord"
+	Records_BatchGetRecords_FullMethodName    = "/gophkeeper.Records/BatchGetRecords"
+	Records_BatchUpsertRecords_FullMethodName = "/gophkeeper.Records/BatchUpsertRecords"
+	Records_BatchDeleteRecords_FullMethodName = "/gophkeeper.Records/BatchDeleteRecords"
 )
 
 // RecordsClient is the client API for Records service.
@@ -35,6 +38,9 @@ type RecordsClient interface {
 	UpdateRecord(ctx context.Context, in *UpdateRecordRequest, opts ...grpc.CallOption) (*UpdateRecordResponse, error)
 	ListRecords(ctx context.Context, in *ListRecordRequest, opts ...grpc.CallOption) (*ListRecordResponse, error)
 	DeleteRecord(ctx context.Context, in *DeleteRecordRequest, opts ...grpc.CallOption) (*DeleteRecordResponse, error)
+	BatchGetRecords(ctx context.Context, in *BatchGetRecordsRequest, opts ...grpc.CallOption) (*BatchGetRecordsResponse, error)
+	BatchUpsertRecords(ctx context.Context, in *BatchUpsertRecordsRequest, opts ...grpc.CallOption) (*BatchUpsertRecordsResponse, error)
+	BatchDeleteRecords(ctx context.Context, in *BatchDeleteRecordsRequest, opts ...grpc.CallOption) (*BatchDeleteRecordsResponse, error)
 }
 
 type recordsClient struct {
@@ -90,6 +96,33 @@ func (c *recordsClient) DeleteRecord(ctx context.Context, in *DeleteRecordReques
 	return out, nil
 }
 
+func (c *recordsClient) BatchGetRecords(ctx context.Context, in *BatchGetRecordsRequest, opts ...grpc.CallOption) (*BatchGetRecordsResponse, error) {
+	out := new(BatchGetRecordsResponse)
+	err := c.cc.Invoke(ctx, Records_BatchGetRecords_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *recordsClient) BatchUpsertRecords(ctx context.Context, in *BatchUpsertRecordsRequest, opts ...grpc.CallOption) (*BatchUpsertRecordsResponse, error) {
+	out := new(BatchUpsertRecordsResponse)
+	err := c.cc.Invoke(ctx, Records_BatchUpsertRecords_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *recordsClient) BatchDeleteRecords(ctx context.Context, in *BatchDeleteRecordsRequest, opts ...grpc.CallOption) (*BatchDeleteRecordsResponse, error) {
+	out := new(BatchDeleteRecordsResponse)
+	err := c.cc.Invoke(ctx, Records_BatchDeleteRecords_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RecordsServer is the server API for Records service.
 // All implementations must embed UnimplementedRecordsServer
 // for forward compatibility
@@ -99,6 +132,9 @@ type RecordsServer interface {
 	UpdateRecord(context.Context, *UpdateRecordRequest) (*UpdateRecordResponse, error)
 	ListRecords(context.Context, *ListRecordRequest) (*ListRecordResponse, error)
 	DeleteRecord(context.Context, *DeleteRecordRequest) (*DeleteRecordResponse, error)
+	BatchGetRecords(context.Context, *BatchGetRecordsRequest) (*BatchGetRecordsResponse, error)
+	BatchUpsertRecords(context.Context, *BatchUpsertRecordsRequest) (*BatchUpsertRecordsResponse, error)
+	BatchDeleteRecords(context.Context, *BatchDeleteRecordsRequest) (*BatchDeleteRecordsResponse, error)
 	mustEmbedUnimplementedRecordsServer()
 }
 
@@ -121,6 +157,15 @@ func (UnimplementedRecordsServer) ListRecords(context.Context, *ListRecordReques
 func (UnimplementedRecordsServer) DeleteRecord(context.Context, *DeleteRecordRequest) (*DeleteRecordResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteRecord not implemented")
 }
+func (UnimplementedRecordsServer) BatchGetRecords(context.Context, *BatchGetRecordsRequest) (*BatchGetRecordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetRecords not implemented")
+}
+func (UnimplementedRecordsServer) BatchUpsertRecords(context.Context, *BatchUpsertRecordsRequest) (*BatchUpsertRecordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchUpsertRecords not implemented")
+}
+func (UnimplementedRecordsServer) BatchDeleteRecords(context.Context, *BatchDeleteRecordsRequest) (*BatchDeleteRecordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchDeleteRecords not implemented")
+}
 func (UnimplementedRecordsServer) mustEmbedUnimplementedRecordsServer() {}
 
 // UnsafeRecordsServer may be embedded to opt out of forward compatibility for this service.
@@ -224,6 +269,60 @@ func _Records_DeleteRecord_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Records_BatchGetRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecordsServer).BatchGetRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Records_BatchGetRecords_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecordsServer).BatchGetRecords(ctx, req.(*BatchGetRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Records_BatchUpsertRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchUpsertRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecordsServer).BatchUpsertRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Records_BatchUpsertRecords_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecordsServer).BatchUpsertRecords(ctx, req.(*BatchUpsertRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Records_BatchDeleteRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDeleteRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecordsServer).BatchDeleteRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Records_BatchDeleteRecords_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecordsServer).BatchDeleteRecords(ctx, req.(*BatchDeleteRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Records_ServiceDesc is the grpc.ServiceDesc for Records service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -251,6 +350,18 @@ var Records_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteRecord",
 			Handler:    _Records_DeleteRecord_Handler,
 		},
+		{
+			MethodName: "BatchGetRecords",
+			Handler:    _Records_BatchGetRecords_Handler,
+		},
+		{
+			MethodName: "BatchUpsertRecords",
+			Handler:    _Records_BatchUpsertRecords_Handler,
+		},
+		{
+			MethodName: "BatchDeleteRecords",
+			Handler:    _Records_BatchDeleteRecords_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "records.proto",
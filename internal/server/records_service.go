@@ -14,6 +14,7 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+	"github.com/ArtemShalinFe/gophkeeper/internal/vectors"
 )
 
 const userIDHeader = "userid"
@@ -23,6 +24,8 @@ type RecordsService struct {
 	UnimplementedRecordsServer
 	log           *zap.Logger
 	recordStorage models.RecordStorage
+	quota         RecordsQuota
+	audit         *auditLog
 }
 
 // NewRecordsService - Object Constructor.
@@ -30,6 +33,8 @@ func NewRecordsService(log *zap.Logger, recordStorage models.RecordStorage) *Rec
 	return &RecordsService{
 		log:           log,
 		recordStorage: recordStorage,
+		quota:         RecordsQuota{MaxRecordSize: models.MaxFileSize},
+		audit:         newAuditLog(log),
 	}
 }
 
@@ -60,6 +65,7 @@ func (rs *RecordsService) GetRecord(ctx context.Context, request *GetRecordReque
 	}
 
 	rr.Record = record
+	rs.audit.addRecords(ctx, uid, models.AuditRecordRead, r.ID)
 
 	return &rr, nil
 }
@@ -90,8 +96,11 @@ func (rs *RecordsService) AddRecord(ctx context.Context, request *AddRecordReque
 			fmt.Sprintf("an error occurred while encode record dto from request, err: %v", err))
 	}
 
-	if len(rdto.Data) > models.MaxFileSize {
-		return &rr, status.Errorf(codes.Internal, models.ErrLargeFile)
+	if err := rs.checkRecordSize(len(rdto.Data)); err != nil {
+		return &rr, quotaStatus(err)
+	}
+	if err := rs.checkNewRecord(ctx, uid); err != nil {
+		return &rr, quotaStatus(err)
 	}
 
 	r, err := rs.recordStorage.AddRecord(ctx, uid, rdto)
@@ -100,6 +109,7 @@ func (rs *RecordsService) AddRecord(ctx context.Context, request *AddRecordReque
 			fmt.Sprintf("an error occurred while add record in storage, err: %v", err))
 	}
 
+	rs.audit.addRecords(ctx, uid, models.AuditRecordCreate, r.ID)
 	rr.Id = r.ID
 	return &rr, nil
 }
@@ -122,12 +132,29 @@ func (rs *RecordsService) UpdateRecord(ctx context.Context,
 
 	r.Metadata = convMetadataFromProtobuff(request.Record.Metadata)
 
+	if err := rs.checkRecordSize(len(r.Data)); err != nil {
+		return &rr, quotaStatus(err)
+	}
+	// The record that is not stored yet is added by the update, so it is counted by the quota.
+	if rs.quota.MaxRecords > 0 {
+		if _, err := rs.recordStorage.GetRecord(ctx, uid, r.ID); errors.Is(err, models.ErrRecordNotFound) {
+			if err := rs.checkNewRecord(ctx, uid); err != nil {
+				return &rr, quotaStatus(err)
+			}
+		}
+	}
+
 	_, err = rs.recordStorage.UpdateRecord(ctx, uid, r)
 	if err != nil {
+		// The record of another user with the same ID is reported as not found.
+		if errors.Is(err, models.ErrRecordNotFound) {
+			return &rr, status.Errorf(codes.NotFound, models.ErrRecordNotFound.Error())
+		}
 		return &rr, status.Errorf(codes.Internal,
 			fmt.Sprintf("an error occurred while update record in storage, err: %v", err))
 	}
 
+	rs.audit.addRecords(ctx, uid, models.AuditRecordUpdate, r.ID)
 	rr.Id = r.ID
 	return &rr, nil
 }
@@ -144,9 +171,13 @@ func (rs *RecordsService) DeleteRecord(ctx context.Context,
 	recordID := request.GetId()
 
 	if err := rs.recordStorage.DeleteRecord(ctx, uid, recordID); err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			return &rr, status.Errorf(codes.NotFound, models.ErrRecordNotFound.Error())
+		}
 		return &rr, status.Errorf(codes.Internal,
 			fmt.Sprintf("an error occurred while add or delete record from storage, err: %v", err))
 	}
+	rs.audit.addRecords(ctx, uid, models.AuditRecordDelete, recordID)
 
 	return &rr, nil
 }
@@ -178,6 +209,255 @@ func (rs *RecordsService) ListRecords(ctx context.Context, request *ListRecordRe
 	return &lr, nil
 }
 
+// BatchGetRecords - used to retrieving several records at once.
+func (rs *RecordsService) BatchGetRecords(ctx context.Context,
+	request *BatchGetRecordsRequest) (*BatchGetRecordsResponse, error) {
+	var rr BatchGetRecordsResponse
+
+	uid, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return &rr, status.Errorf(codes.Unauthenticated, fmt.Sprintf(errUnauthenticatedTemplate, err))
+	}
+
+	if len(request.GetIds()) > models.MaxBatchSize {
+		return &rr, status.Errorf(codes.InvalidArgument, models.ErrBatchTooLarge.Error())
+	}
+
+	brs, err := rs.recordStorage.BatchGetRecords(ctx, uid, request.GetIds())
+	if err != nil {
+		return &rr, status.Errorf(codes.Internal,
+			fmt.Sprintf("an occured error while retrieving records from storage, err: %v", err))
+	}
+
+	for _, br := range brs {
+		res := convBatchResultToProtobuff(br)
+		if br.Err == nil {
+			record, err := convRecordToProtobuff(br.Record)
+			if err != nil {
+				res.Status = BatchStatus_BATCH_FAILED
+				res.Error = fmt.Sprintf("an occured error while decode record, err: %v", err)
+			}
+			res.Record = record
+		}
+		rr.Results = append(rr.Results, res)
+	}
+
+	return &rr, nil
+}
+
+// BatchUpsertRecords - add or update several records at once.
+func (rs *RecordsService) BatchUpsertRecords(ctx context.Context,
+	request *BatchUpsertRecordsRequest) (*BatchUpsertRecordsResponse, error) {
+	var rr BatchUpsertRecordsResponse
+
+	uid, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return &rr, status.Errorf(codes.Unauthenticated, fmt.Sprintf(errUnauthenticatedTemplate, err))
+	}
+
+	if len(request.GetRecords()) > models.MaxBatchSize {
+		return &rr, status.Errorf(codes.InvalidArgument, models.ErrBatchTooLarge.Error())
+	}
+
+	rr.Results = make([]*BatchResult, len(request.GetRecords()))
+	records := make([]*models.Record, 0, len(request.GetRecords()))
+	idxs := make([]int, 0, len(request.GetRecords()))
+	for i, rpb := range request.GetRecords() {
+		r, err := convRecordFromProtobuff(rpb)
+		if err != nil {
+			rr.Results[i] = &BatchResult{
+				Id:     rpb.GetId(),
+				Status: BatchStatus_BATCH_FAILED,
+				Error:  fmt.Sprintf("an error occurred while convert record from protobuff, err: %v", err),
+			}
+			continue
+		}
+		if err := rs.checkRecordSize(len(r.Data)); err != nil {
+			rr.Results[i] = &BatchResult{Id: r.ID, Status: BatchStatus_BATCH_FAILED, Error: err.Error()}
+			continue
+		}
+		records = append(records, r)
+		idxs = append(idxs, i)
+	}
+
+	records, idxs, err = rs.applyRecordsQuota(ctx, uid, records, idxs, rr.Results)
+	if err != nil {
+		return &rr, quotaStatus(err)
+	}
+
+	brs, err := rs.recordStorage.BatchUpsertRecords(ctx, uid, records)
+	if err != nil {
+		return &rr, status.Errorf(codes.Internal,
+			fmt.Sprintf("an error occurred while update records in storage, err: %v", err))
+	}
+
+	var created, updated []string
+	for i, br := range brs {
+		rr.Results[idxs[i]] = convBatchResultToProtobuff(br)
+		if br.Err != nil {
+			continue
+		}
+		if br.Replaced == nil {
+			created = append(created, br.ID)
+			continue
+		}
+		updated = append(updated, br.ID)
+		countConflict(records[i], br.Replaced)
+	}
+	rs.audit.addRecords(ctx, uid, models.AuditRecordCreate, created...)
+	rs.audit.addRecords(ctx, uid, models.AuditRecordUpdate, updated...)
+
+	return &rr, nil
+}
+
+// storedRecords - returns the stored versions of the records by the ID, the records that are not stored
+// are missing in the map.
+func (rs *RecordsService) storedRecords(ctx context.Context,
+	userID string,
+	records []*models.Record) (map[string]*models.Record, error) {
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(records))
+	for _, r := range records {
+		ids = append(ids, r.ID)
+	}
+
+	brs, err := rs.recordStorage.BatchGetRecords(ctx, userID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred while retrieving stored records, err: %w", err)
+	}
+
+	stored := make(map[string]*models.Record, len(brs))
+	for _, br := range brs {
+		if br.Err == nil && br.Record != nil {
+			stored[br.ID] = br.Record
+		}
+	}
+
+	return stored, nil
+}
+
+// countConflict - counts the pushed record that has replaced a version of the record the device has not seen.
+// The synchronization pushes only the records that are newer than the stored ones,
+// so such records mean that several devices have changed the record at the same time.
+func countConflict(pushed *models.Record, replaced *models.Record) {
+	switch vectors.NewComparison(pushed, replaced).Compare() {
+	case vectors.VectorAIsLowerVectorB, vectors.VectorAIsConflictVectorB:
+		syncConflicts.Inc()
+	}
+}
+
+// applyRecordsQuota - rejects the new records that exceed the quota of the user, their results are set to failed.
+// Returns the records that are left and their indexes in the request.
+// The stored records are retrieved to tell the new records apart only if the quota is set.
+func (rs *RecordsService) applyRecordsQuota(ctx context.Context,
+	userID string,
+	records []*models.Record,
+	idxs []int,
+	results []*BatchResult) ([]*models.Record, []int, error) {
+	remaining, err := rs.remainingRecords(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if remaining < 0 || len(records) == 0 {
+		return records, idxs, nil
+	}
+
+	stored, err := rs.storedRecords(ctx, userID, records)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	accepted := make([]*models.Record, 0, len(records))
+	acceptedIdxs := make([]int, 0, len(idxs))
+	for i, r := range records {
+		if _, ok := stored[r.ID]; !ok {
+			if remaining == 0 {
+				results[idxs[i]] = &BatchResult{
+					Id:     r.ID,
+					Status: BatchStatus_BATCH_FAILED,
+					Error: fmt.Sprintf("%v: the user should not have more than %d records",
+						models.ErrQuotaExceeded, rs.quota.MaxRecords),
+				}
+				continue
+			}
+			remaining--
+		}
+		accepted = append(accepted, r)
+		acceptedIdxs = append(acceptedIdxs, idxs[i])
+	}
+
+	return accepted, acceptedIdxs, nil
+}
+
+// BatchDeleteRecords - mark several records as deleted at once.
+func (rs *RecordsService) BatchDeleteRecords(ctx context.Context,
+	request *BatchDeleteRecordsRequest) (*BatchDeleteRecordsResponse, error) {
+	var rr BatchDeleteRecordsResponse
+
+	uid, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return &rr, status.Errorf(codes.Unauthenticated, fmt.Sprintf(errUnauthenticatedTemplate, err))
+	}
+
+	if len(request.GetIds()) > models.MaxBatchSize {
+		return &rr, status.Errorf(codes.InvalidArgument, models.ErrBatchTooLarge.Error())
+	}
+
+	brs, err := rs.recordStorage.BatchDeleteRecords(ctx, uid, request.GetIds())
+	if err != nil {
+		return &rr, status.Errorf(codes.Internal,
+			fmt.Sprintf("an error occurred while delete records in storage, err: %v", err))
+	}
+
+	var deleted []string
+	for _, br := range brs {
+		rr.Results = append(rr.Results, convBatchResultToProtobuff(br))
+		if br.Err == nil {
+			deleted = append(deleted, br.ID)
+		}
+	}
+	rs.audit.addRecords(ctx, uid, models.AuditRecordDelete, deleted...)
+
+	return &rr, nil
+}
+
+func convBatchResultToProtobuff(br *models.BatchResult) *BatchResult {
+	res := &BatchResult{Id: br.ID}
+	switch {
+	case br.Err == nil:
+		res.Status = BatchStatus_BATCH_OK
+	case errors.Is(br.Err, models.ErrRecordNotFound):
+		res.Status = BatchStatus_BATCH_NOT_FOUND
+		res.Error = models.ErrRecordNotFound.Error()
+	default:
+		res.Status = BatchStatus_BATCH_FAILED
+		res.Error = br.Err.Error()
+	}
+	return res
+}
+
+func convBatchResultFromProtobuff(res *BatchResult) (*models.BatchResult, error) {
+	br := &models.BatchResult{ID: res.GetId()}
+	switch res.GetStatus() {
+	case BatchStatus_BATCH_OK:
+		if res.GetRecord() != nil {
+			r, err := convRecordFromProtobuff(res.GetRecord())
+			if err != nil {
+				return nil, fmt.Errorf("an error occured while converting record from protobuff, err: %w", err)
+			}
+			br.Record = r
+		}
+	case BatchStatus_BATCH_NOT_FOUND:
+		br.Err = models.ErrRecordNotFound
+	default:
+		br.Err = errors.New(res.GetError())
+	}
+	return br, nil
+}
+
 func convDataRecordFromProtobuff(rData isRecord_Data) (models.RecordData, error) {
 	switch d := rData.(type) {
 	case *Record_Auth:
@@ -191,6 +471,8 @@ func convDataRecordFromProtobuff(rData isRecord_Data) (models.RecordData, error)
 		}, nil
 	case *Record_Binary:
 		return &models.Binary{
+			Name: d.Binary.GetName(),
+			Ext:  d.Binary.GetExt(),
 			Data: d.Binary.GetData(),
 		}, nil
 	case *Record_Card:
@@ -235,6 +517,8 @@ func convDataRecordToProtobuff(r *models.Record) (isRecord_Data, error) {
 
 		b := &Binary{}
 		b.Data = bin.Data
+		b.Name = bin.Name
+		b.Ext = bin.Ext
 
 		return &Record_Binary{Binary: b}, nil
 	case string(models.CardType):
@@ -265,7 +549,7 @@ func convRecordFromProtobuff(r *Record) (*models.Record, error) {
 		return nil, fmt.Errorf("an error occured while encode data record to bytes, err: %w", err)
 	}
 
-	return &models.Record{
+	mr := &models.Record{
 		ID:          r.GetId(),
 		Owner:       r.GetOwner(),
 		Description: r.GetDescription(),
@@ -273,8 +557,17 @@ func convRecordFromProtobuff(r *Record) (*models.Record, error) {
 		Data:        b,
 		Hashsum:     r.GetHashsum(),
 		Metadata:    convMetadataFromProtobuff(r.GetMetadata()),
+		Deleted:     r.GetDeleted(),
 		Version:     r.Version,
-	}, nil
+	}
+	if r.GetCreated() != nil {
+		mr.Created = r.GetCreated().AsTime()
+	}
+	if r.GetModified() != nil {
+		mr.Modified = r.GetModified().AsTime()
+	}
+
+	return mr, nil
 }
 
 func convDataTypeFromProtobuff(dt DataType) models.DataType {
@@ -323,6 +616,7 @@ func convRecordToProtobuff(r *models.Record) (*Record, error) {
 		Data:        data,
 		Hashsum:     r.Hashsum,
 		Metadata:    convMetadataToProtobuff(r.Metadata),
+		Deleted:     r.Deleted,
 		Version:     r.Version,
 	}, nil
 }
@@ -333,6 +627,7 @@ func convMetadataFromProtobuff(m []*Metadata) []*models.Metadata {
 		mi[i] = &models.Metadata{
 			Key:   m[i].Key,
 			Value: m[i].Value,
+			Type:  convMetadataTypeFromProtobuff(m[i].Type),
 		}
 	}
 	return mi
@@ -344,27 +639,67 @@ func convMetadataToProtobuff(m []*models.Metadata) []*Metadata {
 		mi[i] = &Metadata{
 			Key:   m[i].Key,
 			Value: m[i].Value,
+			Type:  convMetadataTypeToProtobuff(m[i].Type),
 		}
 	}
 	return mi
 }
 
+// convMetadataTypeFromProtobuff - the text is stored as the empty type.
+func convMetadataTypeFromProtobuff(t MetadataType) models.MetadataType {
+	switch t {
+	case MetadataType_METADATA_SECRET:
+		return models.MetadataSecret
+	case MetadataType_METADATA_URL:
+		return models.MetadataURL
+	case MetadataType_METADATA_DATE:
+		return models.MetadataDate
+	case MetadataType_METADATA_MULTILINE:
+		return models.MetadataMultiline
+	default:
+		return ""
+	}
+}
+
+func convMetadataTypeToProtobuff(t models.MetadataType) MetadataType {
+	switch t {
+	case models.MetadataSecret:
+		return MetadataType_METADATA_SECRET
+	case models.MetadataURL:
+		return MetadataType_METADATA_URL
+	case models.MetadataDate:
+		return MetadataType_METADATA_DATE
+	case models.MetadataMultiline:
+		return MetadataType_METADATA_MULTILINE
+	default:
+		return MetadataType_METADATA_TEXT
+	}
+}
+
 func getUserIDFromContext(ctx context.Context) (string, error) {
+	return getHeaderFromContext(ctx, userIDHeader)
+}
+
+func getDeviceIDFromContext(ctx context.Context) (string, error) {
+	return getHeaderFromContext(ctx, deviceIDHeader)
+}
+
+func getHeaderFromContext(ctx context.Context, header string) (string, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return "", errors.New("headers for request is required")
 	}
 
-	ids := md.Get(userIDHeader)
-	if len(ids) == 0 {
-		return "", fmt.Errorf("header %s is required", userIDHeader)
+	vs := md.Get(header)
+	if len(vs) == 0 {
+		return "", fmt.Errorf("header %s is required", header)
 	}
 
-	id := ids[0]
+	v := vs[0]
 
-	if strings.TrimSpace(id) == "" {
-		return "", fmt.Errorf("header %s is empty", userIDHeader)
+	if strings.TrimSpace(v) == "" {
+		return "", fmt.Errorf("header %s is empty", header)
 	}
 
-	return id, nil
+	return v, nil
 }
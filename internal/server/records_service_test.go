@@ -11,11 +11,14 @@ import (
 	"github.com/ArtemShalinFe/gophkeeper/internal/config"
 	"github.com/ArtemShalinFe/gophkeeper/internal/models"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	gomock "go.uber.org/mock/gomock"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 )
 
@@ -31,8 +34,12 @@ func NewRecordServiceDialer(t *testing.T, us models.UserStorage, rs models.Recor
 	const bufSize = 1024 * 1024
 	lis := bufconn.Listen(bufSize)
 
+	ds := NewMockDeviceStorage(gomock.NewController(t))
+	ds.EXPECT().GetDevice(gomock.Any(), gomock.Any(), testDeviceID).AnyTimes().Return(&models.Device{ID: testDeviceID}, nil)
+	ds.EXPECT().TouchDevice(gomock.Any(), gomock.Any(), testDeviceID, false).AnyTimes().Return(nil)
+
 	log := zap.L()
-	s, err := InitServer(rs, us, log, config.NewServerCfg())
+	s, err := InitServer(rs, us, ds, nil, log, config.NewServerCfg())
 	if err != nil {
 		t.Fatalf("an occured error when initial grpc server, err: %v", err)
 	}
@@ -53,9 +60,12 @@ func NewRecordServiceDialer(t *testing.T, us models.UserStorage, rs models.Recor
 	}, nil
 }
 
+var testDeviceID = uuid.NewString()
+
 func contextWithUserID(ctx context.Context, userID string) context.Context {
 	headers := map[string]string{
-		userIDHeader: userID,
+		userIDHeader:   userID,
+		deviceIDHeader: testDeviceID,
 	}
 
 	return metadata.NewOutgoingContext(ctx, metadata.New(headers))
@@ -113,6 +123,15 @@ func generateCardRecord(t *testing.T) *models.Record {
 	})
 }
 
+// generateValidCardRecord - returns the card record whose number passes the rules of the requests.
+func generateValidCardRecord(t *testing.T) *models.Record {
+	return generateRecord(t, models.CardType, &models.Card{
+		Number: "4111 1111 1111 1111",
+		Term:   time.Now(),
+		Owner:  uuid.NewString(),
+	})
+}
+
 func generateRecords(t *testing.T, limit int) []*models.Record {
 	var records []*models.Record
 	for i := 0; i < limit; i++ {
@@ -327,10 +346,16 @@ func TestRecordsService_Add(t *testing.T) {
 		t.Errorf("an error occured while encode card record to protobuff, err: %v", err)
 	}
 
+	r5 := generateValidCardRecord(t)
+	r5pb, err := convRecordToProtobuff(r5)
+	if err != nil {
+		t.Errorf("an error occured while encode card record to protobuff, err: %v", err)
+	}
+
 	rs.EXPECT().AddRecord(gomock.Any(), u.ID, gomock.Any()).Return(r1, nil)
 	rs.EXPECT().AddRecord(gomock.Any(), u.ID, gomock.Any()).Return(r2, nil)
 	rs.EXPECT().AddRecord(gomock.Any(), u.ID, gomock.Any()).Return(r3, nil)
-	rs.EXPECT().AddRecord(gomock.Any(), u.ID, gomock.Any()).Return(r4, nil)
+	rs.EXPECT().AddRecord(gomock.Any(), u.ID, gomock.Any()).Return(r5, nil)
 	rs.EXPECT().AddRecord(gomock.Any(), u.ID, gomock.Any()).Return(nil, errSomethingWentWrong)
 
 	d, err := NewRecordServiceDialer(t, us, rs)
@@ -339,11 +364,12 @@ func TestRecordsService_Add(t *testing.T) {
 	}
 
 	tests := []struct {
-		name    string
-		userid  string
-		request *AddRecordRequest
-		want    *AddRecordResponse
-		wantErr bool
+		name     string
+		userid   string
+		request  *AddRecordRequest
+		want     *AddRecordResponse
+		wantErr  bool
+		wantCode codes.Code
 	}{
 		{
 			name:   "positive case add auth record",
@@ -382,13 +408,22 @@ func TestRecordsService_Add(t *testing.T) {
 			name:   "positive case add card record",
 			userid: u.ID,
 			request: &AddRecordRequest{
-				Record: r4pb,
+				Record: r5pb,
 			},
 			want: &AddRecordResponse{
-				Id: r4.ID,
+				Id: r5.ID,
 			},
 			wantErr: false,
 		},
+		{
+			name:   "negative case card number is invalid",
+			userid: u.ID,
+			request: &AddRecordRequest{
+				Record: r4pb,
+			},
+			wantErr:  true,
+			wantCode: codes.InvalidArgument,
+		},
 		{
 			name:   "negative case not authorized empty string userid",
 			userid: " ",
@@ -415,10 +450,10 @@ func TestRecordsService_Add(t *testing.T) {
 			name:   "negative case storage error",
 			userid: u.ID,
 			request: &AddRecordRequest{
-				Record: r4pb,
+				Record: r5pb,
 			},
 			want: &AddRecordResponse{
-				Id: r4.ID,
+				Id: r5.ID,
 			},
 			wantErr: true,
 		},
@@ -446,6 +481,9 @@ func TestRecordsService_Add(t *testing.T) {
 				t.Errorf("RecordsService.AddRecord() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.wantCode != codes.OK && status.Code(err) != tt.wantCode {
+				t.Errorf("RecordsService.AddRecord() code = %v, want %v", status.Code(err), tt.wantCode)
+			}
 			if !tt.wantErr && !reflect.DeepEqual(got.Id, tt.want.Id) {
 				t.Errorf("RecordsService.AddRecord() = %v, want %v", got, tt.want)
 			}
@@ -487,11 +525,18 @@ func TestRecordsService_Update(t *testing.T) {
 		t.Errorf("an error occured while encode card record to protobuff, err: %v", err)
 	}
 
+	r5 := generateValidCardRecord(t)
+	r5pb, err := convRecordToProtobuff(r5)
+	if err != nil {
+		t.Errorf("an error occured while encode card record to protobuff, err: %v", err)
+	}
+
 	rs.EXPECT().UpdateRecord(gomock.Any(), u.ID, gomock.Any()).Return(r1, nil)
 	rs.EXPECT().UpdateRecord(gomock.Any(), u.ID, gomock.Any()).Return(r2, nil)
 	rs.EXPECT().UpdateRecord(gomock.Any(), u.ID, gomock.Any()).Return(r3, nil)
-	rs.EXPECT().UpdateRecord(gomock.Any(), u.ID, gomock.Any()).Return(r4, nil)
+	rs.EXPECT().UpdateRecord(gomock.Any(), u.ID, gomock.Any()).Return(r5, nil)
 	rs.EXPECT().UpdateRecord(gomock.Any(), u.ID, gomock.Any()).Return(nil, errSomethingWentWrong)
+	rs.EXPECT().UpdateRecord(gomock.Any(), u.ID, gomock.Any()).Return(nil, models.ErrRecordNotFound)
 
 	d, err := NewRecordServiceDialer(t, us, rs)
 	if err != nil {
@@ -499,11 +544,12 @@ func TestRecordsService_Update(t *testing.T) {
 	}
 
 	tests := []struct {
-		name    string
-		userid  string
-		request *UpdateRecordRequest
-		want    *UpdateRecordResponse
-		wantErr bool
+		name     string
+		userid   string
+		request  *UpdateRecordRequest
+		want     *UpdateRecordResponse
+		wantErr  bool
+		wantCode codes.Code
 	}{
 		{
 			name:   "positive case add auth record",
@@ -542,13 +588,22 @@ func TestRecordsService_Update(t *testing.T) {
 			name:   "positive case add card record",
 			userid: u.ID,
 			request: &UpdateRecordRequest{
-				Record: r4pb,
+				Record: r5pb,
 			},
 			want: &UpdateRecordResponse{
-				Id: r4.ID,
+				Id: r5.ID,
 			},
 			wantErr: false,
 		},
+		{
+			name:   "negative case card number is invalid",
+			userid: u.ID,
+			request: &UpdateRecordRequest{
+				Record: r4pb,
+			},
+			wantErr:  true,
+			wantCode: codes.InvalidArgument,
+		},
 		{
 			name:   "negative case not authorized empty string userid",
 			userid: " ",
@@ -575,13 +630,22 @@ func TestRecordsService_Update(t *testing.T) {
 			name:   "negative case storage error",
 			userid: u.ID,
 			request: &UpdateRecordRequest{
-				Record: r4pb,
+				Record: r5pb,
 			},
 			want: &UpdateRecordResponse{
-				Id: r4.ID,
+				Id: r5.ID,
 			},
 			wantErr: true,
 		},
+		{
+			name:   "negative case record of another user",
+			userid: u.ID,
+			request: &UpdateRecordRequest{
+				Record: r5pb,
+			},
+			wantErr:  true,
+			wantCode: codes.NotFound,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -606,6 +670,9 @@ func TestRecordsService_Update(t *testing.T) {
 				t.Errorf("RecordsService.UpdateRecord() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.wantCode != codes.OK && status.Code(err) != tt.wantCode {
+				t.Errorf("RecordsService.UpdateRecord() code = %v, want %v", status.Code(err), tt.wantCode)
+			}
 			if !tt.wantErr && !reflect.DeepEqual(got.Id, tt.want.Id) {
 				t.Errorf("RecordsService.UpdateRecord() = %v, want %v", got, tt.want)
 			}
@@ -633,6 +700,7 @@ func TestRecordsService_Delete(t *testing.T) {
 	rs.EXPECT().DeleteRecord(gomock.Any(), u.ID, r3.ID).Return(nil)
 	rs.EXPECT().DeleteRecord(gomock.Any(), u.ID, r4.ID).Return(nil)
 	rs.EXPECT().DeleteRecord(gomock.Any(), u.ID, r4.ID).Return(errSomethingWentWrong)
+	rs.EXPECT().DeleteRecord(gomock.Any(), u.ID, r1.ID).Return(models.ErrRecordNotFound)
 
 	d, err := NewRecordServiceDialer(t, us, rs)
 	if err != nil {
@@ -640,10 +708,11 @@ func TestRecordsService_Delete(t *testing.T) {
 	}
 
 	tests := []struct {
-		name    string
-		userid  string
-		request *DeleteRecordRequest
-		wantErr bool
+		name     string
+		userid   string
+		request  *DeleteRecordRequest
+		wantErr  bool
+		wantCode codes.Code
 	}{
 		{
 			name:   "positive case add auth record",
@@ -699,7 +768,17 @@ func TestRecordsService_Delete(t *testing.T) {
 			request: &DeleteRecordRequest{
 				Id: r4.ID,
 			},
-			wantErr: true,
+			wantErr:  true,
+			wantCode: codes.Internal,
+		},
+		{
+			name:   "negative case record not found",
+			userid: u.ID,
+			request: &DeleteRecordRequest{
+				Id: r1.ID,
+			},
+			wantErr:  true,
+			wantCode: codes.NotFound,
 		},
 	}
 	for _, tt := range tests {
@@ -725,6 +804,9 @@ func TestRecordsService_Delete(t *testing.T) {
 				t.Errorf("RecordsService.DeleteRecord() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.wantCode != codes.OK && status.Code(err) != tt.wantCode {
+				t.Errorf("RecordsService.DeleteRecord() code = %v, want %v", status.Code(err), tt.wantCode)
+			}
 		})
 	}
 }
@@ -819,3 +901,101 @@ func TestRecordsService_List(t *testing.T) {
 		})
 	}
 }
+
+func TestRecordsService_Batch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	us := NewMockUserStorage(ctrl)
+	u := user(t)
+
+	rs := NewMockRecordStorage(ctrl)
+
+	r1 := generateAuthRecord(t)
+	r2 := generateValidCardRecord(t)
+	r3 := generateTextRecord(t)
+
+	rs.EXPECT().BatchGetRecords(gomock.Any(), u.ID, []string{r1.ID, r2.ID}).Return([]*models.BatchResult{
+		{ID: r1.ID, Record: r1},
+		{ID: r2.ID, Err: models.ErrRecordNotFound},
+	}, nil)
+	// The stored version of r3 has been changed by another device, it conflicts with the pushed one.
+	concurrent := *r3
+	concurrent.Hashsum = uuid.NewString()
+	rs.EXPECT().BatchUpsertRecords(gomock.Any(), u.ID, gomock.Len(3)).Return([]*models.BatchResult{
+		{ID: r1.ID},
+		{ID: r2.ID, Err: errSomethingWentWrong},
+		{ID: r3.ID, Replaced: &concurrent},
+	}, nil)
+	rs.EXPECT().BatchDeleteRecords(gomock.Any(), u.ID, []string{r3.ID}).Return([]*models.BatchResult{
+		{ID: r3.ID},
+	}, nil)
+
+	d, err := NewRecordServiceDialer(t, us, rs)
+	if err != nil {
+		t.Errorf("an occured error when creating a new dialer, err: %v", err)
+	}
+
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(d.bufDialer),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Errorf("failed to dial bufnet: %v", err)
+	}
+	defer conn.Close()
+
+	client := NewRecordsClient(conn)
+	rctx := contextWithUserID(ctx, u.ID)
+
+	gr, err := client.BatchGetRecords(rctx, &BatchGetRecordsRequest{Ids: []string{r1.ID, r2.ID}})
+	if err != nil {
+		t.Errorf("RecordsService.BatchGetRecords() error = %v", err)
+		return
+	}
+	if gr.Results[0].GetStatus() != BatchStatus_BATCH_OK || gr.Results[0].GetRecord().GetId() != r1.ID {
+		t.Errorf("RecordsService.BatchGetRecords() result = %v, want record %s", gr.Results[0], r1.ID)
+	}
+	if gr.Results[1].GetStatus() != BatchStatus_BATCH_NOT_FOUND {
+		t.Errorf("RecordsService.BatchGetRecords() status = %v, want %v",
+			gr.Results[1].GetStatus(), BatchStatus_BATCH_NOT_FOUND)
+	}
+
+	var rpbs []*Record
+	for _, r := range []*models.Record{r1, r2, r3} {
+		rpb, err := convRecordToProtobuff(r)
+		if err != nil {
+			t.Errorf("an error occured while convert record to protobuff, err: %v", err)
+		}
+		rpbs = append(rpbs, rpb)
+	}
+	conflicts := testutil.ToFloat64(syncConflicts)
+	ur, err := client.BatchUpsertRecords(rctx, &BatchUpsertRecordsRequest{Records: rpbs})
+	if err != nil {
+		t.Errorf("RecordsService.BatchUpsertRecords() error = %v", err)
+		return
+	}
+	if got := testutil.ToFloat64(syncConflicts) - conflicts; got != 1 {
+		t.Errorf("RecordsService.BatchUpsertRecords() counted %v conflicts, want 1", got)
+	}
+	wantStatuses := []BatchStatus{BatchStatus_BATCH_OK, BatchStatus_BATCH_FAILED, BatchStatus_BATCH_OK}
+	for i, res := range ur.Results {
+		if res.GetStatus() != wantStatuses[i] {
+			t.Errorf("RecordsService.BatchUpsertRecords() status[%d] = %v, want %v", i, res.GetStatus(), wantStatuses[i])
+		}
+	}
+
+	dr, err := client.BatchDeleteRecords(rctx, &BatchDeleteRecordsRequest{Ids: []string{r3.ID}})
+	if err != nil {
+		t.Errorf("RecordsService.BatchDeleteRecords() error = %v", err)
+		return
+	}
+	if dr.Results[0].GetStatus() != BatchStatus_BATCH_OK {
+		t.Errorf("RecordsService.BatchDeleteRecords() status = %v, want %v", dr.Results[0].GetStatus(), BatchStatus_BATCH_OK)
+	}
+
+	ids := make([]string, models.MaxBatchSize+1)
+	_, err = client.BatchDeleteRecords(rctx, &BatchDeleteRecordsRequest{Ids: ids})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("RecordsService.BatchDeleteRecords() code = %v, want %v", status.Code(err), codes.InvalidArgument)
+	}
+}
@@ -2,10 +2,13 @@ package server
 
 import (
 	context "context"
+	"errors"
 	"fmt"
 
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/ArtemShalinFe/gophkeeper/internal/models"
 )
@@ -15,6 +18,7 @@ type UsersService struct {
 	UnimplementedUsersServer
 	log         *zap.Logger
 	userStorage models.UserStorage
+	audit       *auditLog
 }
 
 type userRequest interface {
@@ -27,6 +31,7 @@ func NewUsersService(log *zap.Logger, userStorage models.UserStorage) *UsersServ
 	return &UsersService{
 		log:         log,
 		userStorage: userStorage,
+		audit:       newAuditLog(log),
 	}
 }
 
@@ -59,13 +64,25 @@ func (us *UsersService) Login(ctx context.Context, request *LoginRequest) (*Logi
 
 	user, err := u.GetUser(ctx, us.userStorage)
 	if err != nil {
+		if errors.Is(err, models.ErrUnknowUser) {
+			loginFailures.WithLabelValues(loginUnknownUser).Inc()
+		}
 		return &resp, fmt.Errorf("logged in, err: %w", err)
 	}
 
 	if !checkPasswordHash(user.PasswordHash, u.Password) {
+		loginFailures.WithLabelValues(loginWrongPassword).Inc()
+		us.audit.add(ctx, user.ID, models.AuditLoginFailed, "wrong password")
 		return &resp, models.ErrUnknowUser
 	}
 
+	if user.Disabled {
+		loginFailures.WithLabelValues(loginDisabled).Inc()
+		us.audit.add(ctx, user.ID, models.AuditLoginFailed, models.ErrUserDisabled.Error())
+		return &resp, status.Error(codes.Unauthenticated, models.ErrUserDisabled.Error())
+	}
+
+	us.audit.add(ctx, user.ID, models.AuditLogin, "")
 	resp.User = &User{Id: user.ID}
 	return &resp, nil
 }
@@ -36,7 +36,7 @@ func NewUserServiceDialer(t *testing.T, us models.UserStorage) (*userDialer, err
 	lis := bufconn.Listen(bufSize)
 
 	log := zap.L()
-	s, err := InitServer(nil, us, log, config.NewServerCfg())
+	s, err := InitServer(nil, us, nil, nil, log, config.NewServerCfg())
 	if err != nil {
 		t.Fatalf("an occured error when initial grpc server, err: %v", err)
 	}
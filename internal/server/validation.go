@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/bufbuild/protovalidate-go"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// requestValidator - returns the validator of the rules of the proto files.
+// The rules of the messages are compiled at the first check of the message, so the validator is shared.
+var requestValidator = sync.OnceValues(func() (*protovalidate.Validator, error) {
+	return protovalidate.New()
+})
+
+// validateRequest - checks the request with the rules of the proto files.
+// The violated rules are returned as codes.InvalidArgument with the field violations in the details.
+func validateRequest(req any) error {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return nil
+	}
+
+	v, err := requestValidator()
+	if err != nil {
+		return status.Errorf(codes.Internal, "an error occured while create request validator, err: %v", err)
+	}
+
+	err = v.Validate(msg)
+	if err == nil {
+		return nil
+	}
+
+	var verr *protovalidate.ValidationError
+	if !errors.As(err, &verr) {
+		return status.Errorf(codes.Internal, "an error occured while validate request, err: %v", err)
+	}
+
+	br := &errdetails.BadRequest{}
+	descs := make([]string, 0, len(verr.Violations))
+	for _, v := range verr.Violations {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.GetFieldPath(),
+			Description: v.GetMessage(),
+		})
+		if v.GetFieldPath() != "" {
+			descs = append(descs, v.GetFieldPath()+": "+v.GetMessage())
+		} else {
+			descs = append(descs, v.GetMessage())
+		}
+	}
+
+	st := status.New(codes.InvalidArgument, "invalid request: "+strings.Join(descs, "; "))
+	if dst, err := st.WithDetails(br); err == nil {
+		st = dst
+	}
+	return st.Err()
+}
+
+// validationInterceptor - rejects the requests that violate the rules of the proto files.
+func validationInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		if err := validateRequest(req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// validatingStream - the stream that checks every received message.
+type validatingStream struct {
+	grpc.ServerStream
+}
+
+func (vs *validatingStream) RecvMsg(m interface{}) error {
+	if err := vs.ServerStream.RecvMsg(m); err != nil {
+		// io.EOF of the end of the stream is returned as is.
+		return err
+	}
+	return validateRequest(m)
+}
+
+// streamValidationInterceptor - rejects the messages of the streams that violate the rules of the proto files.
+func streamValidationInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		return handler(srv, &validatingStream{ServerStream: ss})
+	}
+}
+
+// clientValidationInterceptor - checks the requests of the client with the same rules as the server,
+// so the invalid requests are not sent and not retried.
+func clientValidationInterceptor(ctx context.Context,
+	method string,
+	req, reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption) error {
+	if err := validateRequest(req); err != nil {
+		return err
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
@@ -0,0 +1,206 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+func TestValidateRequest(t *testing.T) {
+	record := func(t *testing.T) *Record {
+		t.Helper()
+		r, err := convRecordToProtobuff(generateValidCardRecord(t))
+		if err != nil {
+			t.Fatalf("an error occured while convert record to protobuff, err: %v", err)
+		}
+		return r
+	}
+
+	tests := []struct {
+		name      string
+		req       func(t *testing.T) any
+		wantErr   bool
+		wantField string
+	}{
+		{
+			name: "valid record",
+			req: func(t *testing.T) any {
+				return &AddRecordRequest{Record: record(t)}
+			},
+		},
+		{
+			name: "the ID of the added record is assigned by the server",
+			req: func(t *testing.T) any {
+				r := record(t)
+				r.Id = ""
+				return &AddRecordRequest{Record: r}
+			},
+		},
+		{
+			name: "the ID of the updated record is required",
+			req: func(t *testing.T) any {
+				r := record(t)
+				r.Id = ""
+				return &UpdateRecordRequest{Record: r}
+			},
+			wantErr: true,
+		},
+		{
+			name: "record is not set",
+			req: func(t *testing.T) any {
+				return &AddRecordRequest{}
+			},
+			wantErr:   true,
+			wantField: "record",
+		},
+		{
+			name: "description is too long",
+			req: func(t *testing.T) any {
+				r := record(t)
+				r.Description = strings.Repeat("д", 301)
+				return &AddRecordRequest{Record: r}
+			},
+			wantErr:   true,
+			wantField: "record.description",
+		},
+		{
+			name: "data is not set",
+			req: func(t *testing.T) any {
+				r := record(t)
+				r.Data = nil
+				return &AddRecordRequest{Record: r}
+			},
+			wantErr:   true,
+			wantField: "record.data",
+		},
+		{
+			name: "card number is not a number",
+			req: func(t *testing.T) any {
+				r := record(t)
+				r.GetCard().Number = "not a card"
+				return &UpdateRecordRequest{Record: r}
+			},
+			wantErr:   true,
+			wantField: "record.card.number",
+		},
+		{
+			name: "record ID is not uuid",
+			req: func(t *testing.T) any {
+				return &GetRecordRequest{Id: "1"}
+			},
+			wantErr:   true,
+			wantField: "id",
+		},
+		{
+			name: "offset is negative",
+			req: func(t *testing.T) any {
+				return &ListRecordRequest{Offset: -1, Limit: models.DefaultLimit}
+			},
+			wantErr:   true,
+			wantField: "offset",
+		},
+		{
+			name: "limit is negative",
+			req: func(t *testing.T) any {
+				return &ListRecordRequest{Limit: -1}
+			},
+			wantErr:   true,
+			wantField: "limit",
+		},
+		{
+			name: "batch is too large",
+			req: func(t *testing.T) any {
+				ids := make([]string, models.MaxBatchSize+1)
+				for i := range ids {
+					ids[i] = uuid.NewString()
+				}
+				return &BatchDeleteRecordsRequest{Ids: ids}
+			},
+			wantErr:   true,
+			wantField: "ids",
+		},
+		{
+			name: "record ID of the batch is empty",
+			req: func(t *testing.T) any {
+				return &BatchGetRecordsRequest{Ids: []string{uuid.NewString(), ""}}
+			},
+			wantErr:   true,
+			wantField: "ids[1]",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRequest(tt.req(t))
+
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("validateRequest() error = %v, want nil", err)
+				}
+				return
+			}
+
+			st := status.Convert(err)
+			if st.Code() != codes.InvalidArgument {
+				t.Fatalf("validateRequest() error = %v, want code %v", err, codes.InvalidArgument)
+			}
+			if len(st.Details()) != 1 {
+				t.Fatalf("validateRequest() details = %v, want the field violations", st.Details())
+			}
+			br, ok := st.Details()[0].(*errdetails.BadRequest)
+			if !ok || len(br.GetFieldViolations()) == 0 {
+				t.Fatalf("validateRequest() details = %v, want the field violations", st.Details())
+			}
+			if got := br.GetFieldViolations()[0].GetField(); got != tt.wantField {
+				t.Errorf("validateRequest() field = %q, want %q", got, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestGKClient_BatchUpsertInvalidRecord(t *testing.T) {
+	ctx := context.Background()
+	mock := NewMockRecordsServer(gomock.NewController(t))
+
+	c := &GKClient{log: zap.L()}
+	opts := append(c.getDialOpts(),
+		grpc.WithContextDialer(NewRecordsSrvListener(mock)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.DialContext(ctx, "", opts...)
+	if err != nil {
+		t.Fatalf("an occured error when getting conn grpc client, err: %v", err)
+	}
+	defer conn.Close()
+	c.cc = conn
+
+	valid := generateValidCardRecord(t)
+	invalid := generateRecord(t, models.CardType, &models.Card{Number: "not a card"})
+
+	mock.EXPECT().BatchUpsertRecords(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *BatchUpsertRecordsRequest) (*BatchUpsertRecordsResponse, error) {
+			if len(req.GetRecords()) != 1 || req.GetRecords()[0].GetId() != valid.ID {
+				t.Errorf("GKClient.BatchUpsertRecords() sent %d records, want only the valid one", len(req.GetRecords()))
+			}
+			return &BatchUpsertRecordsResponse{Results: []*BatchResult{{Id: valid.ID}}}, nil
+		})
+
+	brs, err := c.BatchUpsertRecords(ctx, uuid.NewString(), []*models.Record{invalid, valid})
+	if err != nil {
+		t.Fatalf("GKClient.BatchUpsertRecords() error = %v", err)
+	}
+	if len(brs) != 2 || brs[0].ID != invalid.ID || status.Code(brs[0].Err) != codes.InvalidArgument ||
+		brs[1].ID != valid.ID || brs[1].Err != nil {
+		t.Errorf("GKClient.BatchUpsertRecords() results = %+v, want the invalid record failed and the valid one stored", brs)
+	}
+}
@@ -0,0 +1,270 @@
+package file
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+const (
+	defDirMode  = 0700
+	defFileMode = 0600
+
+	queueFile   = "pending.cbor"
+	keyringFile = "keyring.cbor"
+)
+
+// ErrQueueLocked - The error is returned if the locked queue is changed.
+var ErrQueueLocked = errors.New("the pending queue is locked")
+
+// UserQueuePath - Returns the path to the pending queue file of the user in the data directory of the client.
+func UserQueuePath(dataDir string, userID string) string {
+	return filepath.Join(dataDir, userID, queueFile)
+}
+
+// UserKeyringPath - Returns the path to the keyring file of the user in the data directory of the client.
+// The keyring seals the pending queue file.
+func UserKeyringPath(dataDir string, userID string) string {
+	return filepath.Join(dataDir, userID, keyringFile)
+}
+
+// Sealer - The interface of the encryption of the queue file, the queued records contain the secrets of the user.
+type Sealer interface {
+	// Seal - encrypts the data.
+	Seal(plain []byte) ([]byte, error)
+	// Open - decrypts the sealed data.
+	Open(sealed []byte) ([]byte, error)
+}
+
+// Queue - The durable queue of local changes that have not yet been written to the server.
+// The queue is stored in a sealed file and is rewritten entirely on every change.
+type Queue struct {
+	mutex  *sync.Mutex
+	path   string
+	sealer Sealer
+	// records - queued records in the order they were added, nil while the queue is locked.
+	records []*models.Record
+	// locked - the records are wiped from memory, pending is the number of the records in the file.
+	locked  bool
+	pending int
+}
+
+// NewQueue - Object Constructor. Loads the queue from the file, if the file exists.
+// The file is opened and written by the sealer.
+func NewQueue(path string, sealer Sealer) (*Queue, error) {
+	q := &Queue{
+		mutex:  &sync.Mutex{},
+		path:   path,
+		sealer: sealer,
+	}
+
+	if err := q.load(true); err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// Push - adds records to the queue. A record that is already in the queue is replaced.
+func (q *Queue) Push(records ...*models.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.locked {
+		return ErrQueueLocked
+	}
+
+	for _, r := range records {
+		cp := copyRecord(r)
+		if i := q.index(r.ID); i >= 0 {
+			q.records[i] = cp
+			continue
+		}
+		q.records = append(q.records, cp)
+	}
+
+	return q.save()
+}
+
+// Records - returns copies of the queued records.
+func (q *Queue) Records() []*models.Record {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	rs := make([]*models.Record, len(q.records))
+	for i, r := range q.records {
+		rs[i] = copyRecord(r)
+	}
+
+	return rs
+}
+
+// copyRecord - returns the copy of the record with its own data,
+// so the data wiped by Lock is not shared with the records of the callers.
+func copyRecord(r *models.Record) *models.Record {
+	cp := *r
+	cp.Data = bytes.Clone(r.Data)
+	return &cp
+}
+
+// Ack - removes records from the queue if they have not been changed since the copies were taken.
+func (q *Queue) Ack(records ...*models.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.locked {
+		return ErrQueueLocked
+	}
+
+	changed := false
+	for _, r := range records {
+		i := q.index(r.ID)
+		if i < 0 {
+			continue
+		}
+
+		qr := q.records[i]
+		if qr.Version != r.Version || qr.Hashsum != r.Hashsum || qr.Deleted != r.Deleted {
+			continue
+		}
+
+		q.records = append(q.records[:i], q.records[i+1:]...)
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return q.save()
+}
+
+// Len - returns the number of the queued records.
+func (q *Queue) Len() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.locked {
+		return q.pending
+	}
+	return len(q.records)
+}
+
+// Lock - wipes the queued records from memory, they are kept only in the sealed file.
+// The locked queue has no records and cannot be changed until it is unlocked.
+func (q *Queue) Lock() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.locked {
+		return
+	}
+
+	for _, r := range q.records {
+		clear(r.Data)
+	}
+	q.pending = len(q.records)
+	q.records = nil
+	q.locked = true
+}
+
+// Unlock - reloads the queued records from the sealed file, the sealer must be able to open it again.
+func (q *Queue) Unlock() error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if !q.locked {
+		return nil
+	}
+
+	if err := q.load(false); err != nil {
+		q.records = nil
+		return err
+	}
+	q.locked = false
+
+	return nil
+}
+
+func (q *Queue) index(id string) int {
+	for i, r := range q.records {
+		if r.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// load - reads the queue from the file.
+// If legacy is true, the queue file of the previous versions of the client that is not sealed
+// is accepted and sealed at once.
+func (q *Queue) load(legacy bool) error {
+	b, err := os.ReadFile(q.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("an error occured while read queue file, err: %w", err)
+	}
+
+	plain, err := q.sealer.Open(b)
+	if err == nil {
+		defer clear(plain)
+		if err := cbor.Unmarshal(plain, &q.records); err != nil {
+			return fmt.Errorf("an error occured while decode queue file, err: %w", err)
+		}
+		return nil
+	}
+
+	if !legacy || cbor.Unmarshal(b, &q.records) != nil {
+		return fmt.Errorf("an error occured while open queue file, err: %w", err)
+	}
+	clear(b)
+
+	return q.save()
+}
+
+// save - writes the sealed queue to a temporary file and replaces the queue file with it,
+// so that the queue file is never left half-written.
+func (q *Queue) save() error {
+	plain, err := cbor.Marshal(q.records)
+	if err != nil {
+		return fmt.Errorf("an error occured while encode queue, err: %w", err)
+	}
+	defer clear(plain)
+
+	b, err := q.sealer.Seal(plain)
+	if err != nil {
+		return fmt.Errorf("an error occured while seal queue, err: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(q.path), defDirMode); err != nil {
+		return fmt.Errorf("an error occured while create queue dir, err: %w", err)
+	}
+
+	tmp := q.path + ".tmp"
+	if err := os.WriteFile(tmp, b, defFileMode); err != nil {
+		return fmt.Errorf("an error occured while write queue file, err: %w", err)
+	}
+
+	if err := os.Rename(tmp, q.path); err != nil {
+		return fmt.Errorf("an error occured while replace queue file, err: %w", err)
+	}
+
+	return nil
+}
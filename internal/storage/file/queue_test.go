@@ -0,0 +1,172 @@
+package file
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/google/uuid"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+	"github.com/ArtemShalinFe/gophkeeper/internal/storage/sealed"
+)
+
+const (
+	testPassword = "master password"
+	testSecret   = "very secret password"
+)
+
+func newTestRecord(t *testing.T) *models.Record {
+	t.Helper()
+
+	r, err := models.NewRecord(uuid.NewString(), "site", models.AuthType,
+		time.Now(), time.Now().Add(-time.Hour),
+		&models.Auth{Login: "login", Password: testSecret},
+		nil, false, 1)
+	if err != nil {
+		t.Fatalf("models.NewRecord() error = %v", err)
+	}
+	return r
+}
+
+func TestQueue_Sealed(t *testing.T) {
+	dir := t.TempDir()
+	userID := uuid.NewString()
+	keysPath := UserKeyringPath(dir, userID)
+
+	keys, err := sealed.NewKeyring(testPassword)
+	if err != nil {
+		t.Fatalf("sealed.NewKeyring() error = %v", err)
+	}
+	if err := keys.Save(keysPath); err != nil {
+		t.Fatalf("Keyring.Save() error = %v", err)
+	}
+
+	r := newTestRecord(t)
+	legacy, err := cbor.Marshal([]*models.Record{r})
+	if err != nil {
+		t.Fatalf("cbor.Marshal() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, path string)
+	}{
+		{
+			name: "pushed records are sealed",
+			prepare: func(t *testing.T, path string) {
+				q, err := NewQueue(path, keys)
+				if err != nil {
+					t.Fatalf("NewQueue() error = %v", err)
+				}
+				if err := q.Push(r); err != nil {
+					t.Fatalf("Queue.Push() error = %v", err)
+				}
+			},
+		},
+		{
+			name: "plaintext queue of the previous version is sealed",
+			prepare: func(t *testing.T, path string) {
+				if err := os.MkdirAll(filepath.Dir(path), defDirMode); err != nil {
+					t.Fatalf("os.MkdirAll() error = %v", err)
+				}
+				if err := os.WriteFile(path, legacy, defFileMode); err != nil {
+					t.Fatalf("os.WriteFile() error = %v", err)
+				}
+				if _, err := NewQueue(path, keys); err != nil {
+					t.Fatalf("NewQueue() error = %v", err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), queueFile)
+			tt.prepare(t, path)
+
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("os.ReadFile() error = %v", err)
+			}
+			if bytes.Contains(b, []byte(testSecret)) || bytes.Contains(b, r.Data) {
+				t.Fatal("the queue file contains the plaintext of the record")
+			}
+
+			loaded, err := sealed.LoadKeyring(keysPath, testPassword)
+			if err != nil {
+				t.Fatalf("sealed.LoadKeyring() error = %v", err)
+			}
+			q, err := NewQueue(path, loaded)
+			if err != nil {
+				t.Fatalf("NewQueue() error = %v", err)
+			}
+			rs := q.Records()
+			if len(rs) != 1 || rs[0].ID != r.ID || !bytes.Equal(rs[0].Data, r.Data) {
+				t.Errorf("Queue.Records() = %v, want the pushed record", rs)
+			}
+		})
+	}
+
+	if _, err := sealed.LoadKeyring(keysPath, "wrong password"); err == nil {
+		t.Error("sealed.LoadKeyring() with the wrong password error = nil")
+	}
+}
+
+func TestQueue_LockUnlock(t *testing.T) {
+	keys, err := sealed.NewKeyring(testPassword)
+	if err != nil {
+		t.Fatalf("sealed.NewKeyring() error = %v", err)
+	}
+
+	q, err := NewQueue(filepath.Join(t.TempDir(), queueFile), keys)
+	if err != nil {
+		t.Fatalf("NewQueue() error = %v", err)
+	}
+
+	r := newTestRecord(t)
+	data := bytes.Clone(r.Data)
+	if err := q.Push(r); err != nil {
+		t.Fatalf("Queue.Push() error = %v", err)
+	}
+
+	taken := q.Records()
+
+	keys.Lock()
+	q.Lock()
+
+	// Only the records of the queue are wiped, the pushed record and the taken copies are kept.
+	if !bytes.Equal(r.Data, data) {
+		t.Error("Queue.Lock() has wiped the data of the pushed record")
+	}
+	if len(taken) != 1 || !bytes.Equal(taken[0].Data, data) {
+		t.Error("Queue.Lock() has wiped the data of the records taken before the lock")
+	}
+	if rs := q.Records(); len(rs) != 0 {
+		t.Errorf("Queue.Records() of the locked queue = %v, want no records", rs)
+	}
+	if got := q.Len(); got != 1 {
+		t.Errorf("Queue.Len() of the locked queue = %d, want 1", got)
+	}
+	if err := q.Push(newTestRecord(t)); !errors.Is(err, ErrQueueLocked) {
+		t.Errorf("Queue.Push() of the locked queue error = %v, want %v", err, ErrQueueLocked)
+	}
+	if err := q.Unlock(); err == nil {
+		t.Error("Queue.Unlock() with the locked keyring error = nil")
+	}
+
+	if err := keys.Unlock(testPassword); err != nil {
+		t.Fatalf("Keyring.Unlock() error = %v", err)
+	}
+	if err := q.Unlock(); err != nil {
+		t.Fatalf("Queue.Unlock() error = %v", err)
+	}
+	rs := q.Records()
+	if len(rs) != 1 || rs[0].ID != r.ID || !bytes.Equal(rs[0].Data, data) {
+		t.Errorf("Queue.Records() after unlock = %v, want the pushed record", rs)
+	}
+}
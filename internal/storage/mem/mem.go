@@ -1,6 +1,7 @@
 package mem
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/ArtemShalinFe/gophkeeper/internal/models"
@@ -49,3 +50,28 @@ func (ms *MemStorage) RemoveUserRecordStorage(userID string) error {
 
 	return nil
 }
+
+// ReplaceRecords - replaces every record of the user storage by the result of fn.
+// Unlike the updates, the modification date of the records is kept. If fn fails, the rest of the records are kept.
+func (ms *MemStorage) ReplaceRecords(userID string, fn func(r *models.Record) (*models.Record, error)) error {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	us, ok := ms.data[userID]
+	if !ok {
+		return models.ErrUserStorageNotFound
+	}
+
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	for id, r := range us.data {
+		nr, err := fn(r)
+		if err != nil {
+			return fmt.Errorf("an error occured while replace record (ID=%s), err: %w", id, err)
+		}
+		us.data[id] = nr
+	}
+
+	return nil
+}
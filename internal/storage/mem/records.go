@@ -2,6 +2,8 @@ package mem
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/ArtemShalinFe/gophkeeper/internal/models"
@@ -9,6 +11,7 @@ import (
 )
 
 // ListRecords - used to retrieving user records.
+// The records are ordered by ID, so the pages of the unchanged storage do not overlap.
 func (ms *MemStorage) ListRecords(ctx context.Context, userID string, offset int, limit int) ([]*models.Record, error) {
 	ms.mutex.RLock()
 	defer ms.mutex.RUnlock()
@@ -18,25 +21,48 @@ func (ms *MemStorage) ListRecords(ctx context.Context, userID string, offset int
 		return nil, models.ErrUserStorageNotFound
 	}
 
+	rs := us.sorted()
+
+	offset = max(offset, 0)
+	if offset >= len(rs) {
+		return nil, nil
+	}
+	rs = rs[offset:]
+	if limit > 0 && limit < len(rs) {
+		rs = rs[:limit]
+	}
+
+	return rs, nil
+}
+
+// AllRecords - returns all the records of the user at once, ordered by ID.
+// Unlike the pages of ListRecords, the records cannot be changed between the pages.
+func (ms *MemStorage) AllRecords(userID string) ([]*models.Record, error) {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	us, ok := ms.data[userID]
+	if !ok {
+		return nil, models.ErrUserStorageNotFound
+	}
+
+	return us.sorted(), nil
+}
+
+// sorted - returns the records of the user ordered by ID.
+func (us *UserRecordStorage) sorted() []*models.Record {
 	us.mutex.RLock()
 	defer us.mutex.RUnlock()
 
-	var rs []*models.Record //nolint // Number of records depends on offset and limit and may be less than in the cache
-	i := 0
+	rs := make([]*models.Record, 0, len(us.data))
 	for _, r := range us.data {
-		if i < offset {
-			i++
-			continue
-		}
 		rs = append(rs, r)
-		i++
-
-		if len(rs) == limit {
-			break
-		}
 	}
+	slices.SortFunc(rs, func(a, b *models.Record) int {
+		return strings.Compare(a.ID, b.ID)
+	})
 
-	return rs, nil
+	return rs
 }
 
 // GetRecord - used to retrieving record.
@@ -131,8 +157,95 @@ func (ms *MemStorage) DeleteRecord(ctx context.Context, userID string, recordID
 		return models.ErrRecordNotFound
 	}
 
-	r.Deleted = true
-	r.Modified = time.Now()
+	r.MarkDeleted()
 
 	return nil
 }
+
+// BatchGetRecords - used to retrieving several records at once.
+func (ms *MemStorage) BatchGetRecords(ctx context.Context,
+	userID string, recordIDs []string) ([]*models.BatchResult, error) {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	us, ok := ms.data[userID]
+	if !ok {
+		return nil, models.ErrUserStorageNotFound
+	}
+
+	us.mutex.RLock()
+	defer us.mutex.RUnlock()
+
+	brs := make([]*models.BatchResult, len(recordIDs))
+	for i, id := range recordIDs {
+		brs[i] = &models.BatchResult{ID: id}
+		r, ok := us.data[id]
+		if !ok {
+			brs[i].Err = models.ErrRecordNotFound
+			continue
+		}
+		brs[i].Record = r
+	}
+
+	return brs, nil
+}
+
+// BatchUpsertRecords - add or update several records at once.
+func (ms *MemStorage) BatchUpsertRecords(ctx context.Context,
+	userID string, records []*models.Record) ([]*models.BatchResult, error) {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	us, ok := ms.data[userID]
+	if !ok {
+		return nil, models.ErrUserStorageNotFound
+	}
+
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	now := time.Now()
+	brs := make([]*models.BatchResult, len(records))
+	for i, r := range records {
+		brs[i] = &models.BatchResult{ID: r.ID}
+		if r.ID == "" {
+			brs[i].Err = models.ErrEmptyID
+			continue
+		}
+		if old, ok := us.data[r.ID]; ok {
+			brs[i].Replaced = &models.Record{ID: old.ID, Hashsum: old.Hashsum, Version: old.Version, Deleted: old.Deleted}
+		}
+		r.Modified = now
+		us.data[r.ID] = r
+	}
+
+	return brs, nil
+}
+
+// BatchDeleteRecords - mark several records as deleted at once.
+func (ms *MemStorage) BatchDeleteRecords(ctx context.Context,
+	userID string, recordIDs []string) ([]*models.BatchResult, error) {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	us, ok := ms.data[userID]
+	if !ok {
+		return nil, models.ErrUserStorageNotFound
+	}
+
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	brs := make([]*models.BatchResult, len(recordIDs))
+	for i, id := range recordIDs {
+		brs[i] = &models.BatchResult{ID: id}
+		r, ok := us.data[id]
+		if !ok {
+			brs[i].Err = models.ErrRecordNotFound
+			continue
+		}
+		r.MarkDeleted()
+	}
+
+	return brs, nil
+}
@@ -0,0 +1,84 @@
+package mem
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+func TestMemStorage_ListRecords(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.NewString()
+
+	ms := NewMemStorage()
+	if err := ms.AddUserRecordStorage(userID); err != nil {
+		t.Fatalf("MemStorage.AddUserRecordStorage() error = %v", err)
+	}
+
+	const total = 2*models.DefaultLimit + 3
+	rs := make([]*models.Record, total)
+	for i := range rs {
+		r, err := models.NewRecord(uuid.NewString(), "note", models.TextType, time.Now(), time.Now(),
+			&models.Text{Data: "text"}, nil, false, 1)
+		if err != nil {
+			t.Fatalf("models.NewRecord() error = %v", err)
+		}
+		rs[i] = r
+	}
+	if _, err := ms.BatchUpsertRecords(ctx, userID, rs); err != nil {
+		t.Fatalf("MemStorage.BatchUpsertRecords() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		limit int
+	}{
+		{name: "pages of the default limit", limit: models.DefaultLimit},
+		{name: "pages of one record", limit: 1},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			seen := make(map[string]struct{})
+			for offset := 0; ; offset += tt.limit {
+				page, err := ms.ListRecords(ctx, userID, offset, tt.limit)
+				if err != nil {
+					t.Fatalf("MemStorage.ListRecords() error = %v", err)
+				}
+				if len(page) == 0 {
+					break
+				}
+				for _, r := range page {
+					if _, ok := seen[r.ID]; ok {
+						t.Fatalf("MemStorage.ListRecords() returned the record %s twice", r.ID)
+					}
+					seen[r.ID] = struct{}{}
+				}
+			}
+			if len(seen) != total {
+				t.Errorf("MemStorage.ListRecords() returned %d records, want %d", len(seen), total)
+			}
+		})
+	}
+
+	all, err := ms.AllRecords(userID)
+	if err != nil {
+		t.Fatalf("MemStorage.AllRecords() error = %v", err)
+	}
+	page, err := ms.ListRecords(ctx, userID, 0, total)
+	if err != nil {
+		t.Fatalf("MemStorage.ListRecords() error = %v", err)
+	}
+	if len(all) != total || len(page) != total {
+		t.Fatalf("MemStorage.AllRecords() returned %d records, want %d", len(all), total)
+	}
+	for i := range all {
+		if all[i].ID != page[i].ID {
+			t.Errorf("MemStorage.AllRecords()[%d] = %s, want the order of ListRecords %s", i, all[i].ID, page[i].ID)
+		}
+	}
+}
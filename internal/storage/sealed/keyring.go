@@ -0,0 +1,203 @@
+package sealed
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/nacl/box"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+const (
+	keyLen   = 32
+	nonceLen = 24
+	saltLen  = 16
+
+	argonTime    = 3
+	argonMemory  = 64 * 1024
+	argonThreads = 4
+
+	keyringDirMode  = 0700
+	keyringFileMode = 0600
+)
+
+// ErrWrongPassword - The error is returned if the private key cannot be decrypted with the master password.
+var ErrWrongPassword = errors.New("wrong master password")
+
+// ErrLocked - The error is returned if the records are read while the session is locked.
+var ErrLocked = errors.New("the session is locked")
+
+// ErrCorrupted - The error is returned if the sealed record cannot be opened.
+var ErrCorrupted = errors.New("the sealed record is corrupted")
+
+// Keyring - The key pair of the session.
+//
+// The records are sealed with the public key, so the records received while the session is locked
+// are sealed without the master password. The private key is kept encrypted with the key derived from
+// the master password, it is decrypted only while the session is unlocked.
+type Keyring struct {
+	public    *[keyLen]byte
+	private   *[keyLen]byte
+	encrypted []byte
+	salt      []byte
+	nonce     [nonceLen]byte
+	mu        sync.Mutex
+}
+
+// NewKeyring - Object Constructor. Generates the key pair of the session, the keyring is unlocked.
+func NewKeyring(password string) (*Keyring, error) {
+	public, private, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while generate session keys, err: %w", err)
+	}
+
+	k := &Keyring{public: public, private: private, salt: make([]byte, saltLen)}
+	if _, err := rand.Read(k.salt); err != nil {
+		return nil, fmt.Errorf("an error occured while generate salt, err: %w", err)
+	}
+	if _, err := rand.Read(k.nonce[:]); err != nil {
+		return nil, fmt.Errorf("an error occured while generate nonce, err: %w", err)
+	}
+
+	key := k.passwordKey(password)
+	defer clear(key[:])
+	k.encrypted = secretbox.Seal(nil, private[:], &k.nonce, key)
+
+	return k, nil
+}
+
+// keyringFile - The keyring in the file, the private key is stored encrypted with the master password.
+type keyringFile struct {
+	Public    []byte `cbor:"public"`
+	Encrypted []byte `cbor:"encrypted"`
+	Salt      []byte `cbor:"salt"`
+	Nonce     []byte `cbor:"nonce"`
+}
+
+// LoadKeyring - Reads the keyring from the file and unlocks it with the master password.
+// The error wraps os.ErrNotExist if the file does not exist.
+func LoadKeyring(path string, password string) (*Keyring, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while read keyring file, err: %w", err)
+	}
+
+	var kf keyringFile
+	if err := cbor.Unmarshal(b, &kf); err != nil {
+		return nil, fmt.Errorf("an error occured while decode keyring file, err: %w", err)
+	}
+	if len(kf.Public) != keyLen || len(kf.Nonce) != nonceLen || len(kf.Salt) != saltLen {
+		return nil, fmt.Errorf("an error occured while decode keyring file, err: %w", ErrCorrupted)
+	}
+
+	k := &Keyring{public: new([keyLen]byte), encrypted: kf.Encrypted, salt: kf.Salt}
+	copy(k.public[:], kf.Public)
+	copy(k.nonce[:], kf.Nonce)
+
+	if err := k.Unlock(password); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// Save - Writes the keyring to the file, the private key is written only encrypted with the master password.
+// The data sealed by the keyring can be opened after the restart of the client.
+func (k *Keyring) Save(path string) error {
+	b, err := cbor.Marshal(&keyringFile{
+		Public:    k.public[:],
+		Encrypted: k.encrypted,
+		Salt:      k.salt,
+		Nonce:     k.nonce[:],
+	})
+	if err != nil {
+		return fmt.Errorf("an error occured while encode keyring, err: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), keyringDirMode); err != nil {
+		return fmt.Errorf("an error occured while create keyring dir, err: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, keyringFileMode); err != nil {
+		return fmt.Errorf("an error occured while write keyring file, err: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("an error occured while replace keyring file, err: %w", err)
+	}
+
+	return nil
+}
+
+func (k *Keyring) passwordKey(password string) *[keyLen]byte {
+	var key [keyLen]byte
+	derived := argon2.IDKey([]byte(password), k.salt, argonTime, argonMemory, argonThreads, keyLen)
+	copy(key[:], derived)
+	clear(derived)
+	return &key
+}
+
+// Lock - Wipes the private key from memory.
+func (k *Keyring) Lock() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.private != nil {
+		clear(k.private[:])
+		k.private = nil
+	}
+}
+
+// Unlock - Decrypts the private key with the master password.
+func (k *Keyring) Unlock(password string) error {
+	key := k.passwordKey(password)
+	defer clear(key[:])
+
+	plain, ok := secretbox.Open(nil, k.encrypted, &k.nonce, key)
+	if !ok {
+		return ErrWrongPassword
+	}
+
+	var private [keyLen]byte
+	copy(private[:], plain)
+	clear(plain)
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.private = &private
+
+	return nil
+}
+
+// Seal - Encrypts the data with the public key, the keyring may be locked.
+func (k *Keyring) Seal(plain []byte) ([]byte, error) {
+	out, err := box.SealAnonymous(nil, plain, k.public, rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while seal data, err: %w", err)
+	}
+	return out, nil
+}
+
+// Open - Decrypts the sealed data with the private key, returns ErrLocked if the keyring is locked.
+func (k *Keyring) Open(sealed []byte) ([]byte, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.private == nil {
+		return nil, ErrLocked
+	}
+
+	plain, ok := box.OpenAnonymous(nil, sealed, k.public, k.private)
+	if !ok {
+		return nil, ErrCorrupted
+	}
+	return plain, nil
+}
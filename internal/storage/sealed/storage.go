@@ -0,0 +1,242 @@
+package sealed
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+	"github.com/ArtemShalinFe/gophkeeper/internal/storage/mem"
+)
+
+// secrets - The fields of the record that are sealed while the session is locked.
+type secrets struct {
+	Description string             `cbor:"description"`
+	Data        []byte             `cbor:"data"`
+	Metadata    []*models.Metadata `cbor:"metadata"`
+}
+
+// Storage - The in-memory record storage of the session that seals the records while the session is locked.
+//
+// Sealed records keep their ID, type, dates, version and hashsum, so the synchronization can still compare
+// them with the remote records and write the newer ones, which are sealed as well.
+// Reading the records while the session is locked returns ErrLocked, except BatchGetRecords
+// that is used for the comparison.
+type Storage struct {
+	*mem.MemStorage
+	keys *Keyring
+	// sealed - IDs of the sealed records.
+	sealed   map[string]struct{}
+	sealedMu sync.Mutex
+	// mu - the writes hold it for reading, so the records are not written while they are sealed or opened.
+	mu     sync.RWMutex
+	locked bool
+}
+
+// NewStorage - Object Constructor.
+func NewStorage(cache *mem.MemStorage, keys *Keyring) *Storage {
+	return &Storage{
+		MemStorage: cache,
+		keys:       keys,
+		sealed:     make(map[string]struct{}),
+	}
+}
+
+// Locked - Reports whether the session is locked.
+func (s *Storage) Locked() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.locked
+}
+
+// Lock - Seals the records of the user and wipes the private key and the data of the records from memory.
+func (s *Storage) Lock(userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.locked = true
+	s.keys.Lock()
+
+	err := s.ReplaceRecords(userID, func(r *models.Record) (*models.Record, error) {
+		if s.isSealed(r.ID) {
+			return r, nil
+		}
+		sr, err := s.seal(r)
+		if err != nil {
+			return nil, err
+		}
+		clear(r.Data)
+		return sr, nil
+	})
+	if err != nil {
+		return fmt.Errorf("an error occured while seal records, err: %w", err)
+	}
+
+	return nil
+}
+
+// Unlock - Checks the master password and opens the sealed records of the user.
+func (s *Storage) Unlock(userID string, password string) error {
+	if err := s.keys.Unlock(password); err != nil {
+		return fmt.Errorf("an error occured while unlock session, err: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.ReplaceRecords(userID, func(r *models.Record) (*models.Record, error) {
+		if !s.isSealed(r.ID) {
+			return r, nil
+		}
+		return s.open(r)
+	})
+	if err != nil {
+		s.keys.Lock()
+		return fmt.Errorf("an error occured while open sealed records, err: %w", err)
+	}
+	s.locked = false
+
+	return nil
+}
+
+// ListRecords - used to retrieving user records.
+func (s *Storage) ListRecords(ctx context.Context, userID string, offset int, limit int) ([]*models.Record, error) {
+	if s.Locked() {
+		return nil, ErrLocked
+	}
+	rs, err := s.MemStorage.ListRecords(ctx, userID, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while retrieving records, err: %w", err)
+	}
+	return rs, nil
+}
+
+// GetRecord - used to retrieving record.
+func (s *Storage) GetRecord(ctx context.Context, userID string, recordID string) (*models.Record, error) {
+	if s.Locked() {
+		return nil, ErrLocked
+	}
+	r, err := s.MemStorage.GetRecord(ctx, userID, recordID)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while retrieving record, err: %w", err)
+	}
+	return r, nil
+}
+
+// AddRecord - add new record to the storage, new records cannot be added while the session is locked.
+func (s *Storage) AddRecord(ctx context.Context, userID string, record *models.RecordDTO) (*models.Record, error) {
+	if s.Locked() {
+		return nil, ErrLocked
+	}
+	r, err := s.MemStorage.AddRecord(ctx, userID, record)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while add record, err: %w", err)
+	}
+	return r, nil
+}
+
+// UpdateRecord - update record to the storage, the record is sealed while the session is locked.
+func (s *Storage) UpdateRecord(ctx context.Context, userID string, record *models.Record) (*models.Record, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.locked {
+		sr, err := s.seal(record)
+		if err != nil {
+			return nil, err
+		}
+		record = sr
+	}
+
+	r, err := s.MemStorage.UpdateRecord(ctx, userID, record)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while update record, err: %w", err)
+	}
+	return r, nil
+}
+
+// BatchUpsertRecords - add or update several records at once, the records are sealed while the session is locked.
+func (s *Storage) BatchUpsertRecords(ctx context.Context,
+	userID string, records []*models.Record) ([]*models.BatchResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.locked {
+		srs := make([]*models.Record, len(records))
+		for i, r := range records {
+			sr, err := s.seal(r)
+			if err != nil {
+				return nil, err
+			}
+			srs[i] = sr
+		}
+		records = srs
+	}
+
+	brs, err := s.MemStorage.BatchUpsertRecords(ctx, userID, records)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while upsert records, err: %w", err)
+	}
+	return brs, nil
+}
+
+func (s *Storage) isSealed(recordID string) bool {
+	s.sealedMu.Lock()
+	defer s.sealedMu.Unlock()
+
+	_, ok := s.sealed[recordID]
+	return ok
+}
+
+// seal - returns the copy of the record with the sealed secrets.
+func (s *Storage) seal(r *models.Record) (*models.Record, error) {
+	b, err := cbor.Marshal(&secrets{Description: r.Description, Data: r.Data, Metadata: r.Metadata})
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while marshal record (ID=%s), err: %w", r.ID, err)
+	}
+	defer clear(b)
+
+	data, err := s.keys.Seal(b)
+	if err != nil {
+		return nil, err
+	}
+
+	sr := *r
+	sr.Description = ""
+	sr.Data = data
+	sr.Metadata = nil
+
+	s.sealedMu.Lock()
+	s.sealed[r.ID] = struct{}{}
+	s.sealedMu.Unlock()
+
+	return &sr, nil
+}
+
+// open - returns the copy of the sealed record with the opened secrets.
+func (s *Storage) open(r *models.Record) (*models.Record, error) {
+	b, err := s.keys.Open(r.Data)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(b)
+
+	var sec secrets
+	if err := cbor.Unmarshal(b, &sec); err != nil {
+		return nil, fmt.Errorf("an error occured while unmarshal record (ID=%s), err: %w", r.ID, err)
+	}
+
+	or := *r
+	or.Description = sec.Description
+	or.Data = sec.Data
+	or.Metadata = sec.Metadata
+
+	s.sealedMu.Lock()
+	delete(s.sealed, r.ID)
+	s.sealedMu.Unlock()
+
+	return &or, nil
+}
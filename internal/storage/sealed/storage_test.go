@@ -0,0 +1,175 @@
+package sealed
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+	"github.com/ArtemShalinFe/gophkeeper/internal/storage/mem"
+)
+
+const testPassword = "master password"
+
+func newTestRecord(t *testing.T, description string) *models.Record {
+	t.Helper()
+
+	r, err := models.NewRecord(uuid.NewString(), description, models.AuthType,
+		time.Now(), time.Now().Add(-time.Hour),
+		&models.Auth{Login: "login", Password: "password"},
+		[]*models.Metadata{{Key: "pin", Value: "1234", Type: models.MetadataSecret}},
+		false, 1)
+	if err != nil {
+		t.Fatalf("models.NewRecord() error = %v", err)
+	}
+	return r
+}
+
+func cloneRecord(r *models.Record) *models.Record {
+	c := *r
+	c.Data = bytes.Clone(r.Data)
+	return &c
+}
+
+func TestStorage_LockUnlock(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.NewString()
+
+	cache := mem.NewMemStorage()
+	if err := cache.AddUserRecordStorage(userID); err != nil {
+		t.Fatalf("MemStorage.AddUserRecordStorage() error = %v", err)
+	}
+	keys, err := NewKeyring(testPassword)
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+	s := NewStorage(cache, keys)
+
+	before := newTestRecord(t, "before lock")
+	want := map[string]*models.Record{before.ID: cloneRecord(before)}
+	if _, err := s.BatchUpsertRecords(ctx, userID, []*models.Record{before}); err != nil {
+		t.Fatalf("Storage.BatchUpsertRecords() error = %v", err)
+	}
+	// The cache sets the modification date on write, locking and unlocking must keep it.
+	want[before.ID].Modified = before.Modified
+
+	if err := s.Lock(userID); err != nil {
+		t.Fatalf("Storage.Lock() error = %v", err)
+	}
+	if !s.Locked() {
+		t.Errorf("Storage.Locked() = false after Lock")
+	}
+	if _, err := s.ListRecords(ctx, userID, 0, models.DefaultLimit); !errors.Is(err, ErrLocked) {
+		t.Errorf("Storage.ListRecords() error = %v, want %v", err, ErrLocked)
+	}
+	if _, err := s.GetRecord(ctx, userID, before.ID); !errors.Is(err, ErrLocked) {
+		t.Errorf("Storage.GetRecord() error = %v, want %v", err, ErrLocked)
+	}
+
+	// The synchronization writes the pulled records while the session is locked.
+	pulled := newTestRecord(t, "pulled while locked")
+	want[pulled.ID] = cloneRecord(pulled)
+	if _, err := s.BatchUpsertRecords(ctx, userID, []*models.Record{pulled}); err != nil {
+		t.Fatalf("Storage.BatchUpsertRecords() error = %v", err)
+	}
+
+	brs, err := s.BatchGetRecords(ctx, userID, []string{before.ID, pulled.ID})
+	if err != nil {
+		t.Fatalf("Storage.BatchGetRecords() error = %v", err)
+	}
+	for _, br := range brs {
+		w := want[br.ID]
+		got := br.Record
+		if got.Description != "" || got.Metadata != nil || bytes.Equal(got.Data, w.Data) {
+			t.Errorf("record %s is not sealed: %+v", br.ID, got)
+		}
+		if got.Hashsum != w.Hashsum || got.Version != w.Version {
+			t.Errorf("sealed record %s hashsum = %s, version = %d, want %s, %d",
+				br.ID, got.Hashsum, got.Version, w.Hashsum, w.Version)
+		}
+	}
+
+	if err := s.Unlock(userID, "wrong password"); !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("Storage.Unlock() error = %v, want %v", err, ErrWrongPassword)
+	}
+	if !s.Locked() {
+		t.Errorf("Storage.Locked() = false after the wrong password")
+	}
+
+	if err := s.Unlock(userID, testPassword); err != nil {
+		t.Fatalf("Storage.Unlock() error = %v", err)
+	}
+	if s.Locked() {
+		t.Errorf("Storage.Locked() = true after Unlock")
+	}
+
+	for id, w := range want {
+		got, err := s.GetRecord(ctx, userID, id)
+		if err != nil {
+			t.Fatalf("Storage.GetRecord() error = %v", err)
+		}
+		if got.Description != w.Description || !bytes.Equal(got.Data, w.Data) || got.Hashsum != w.Hashsum {
+			t.Errorf("Storage.GetRecord() = %+v, want %+v", got, w)
+		}
+		if len(got.Metadata) != 1 || *got.Metadata[0] != *w.Metadata[0] {
+			t.Errorf("Storage.GetRecord() metadata = %v, want %v", got.Metadata, w.Metadata)
+		}
+		if id == before.ID && !got.Modified.Equal(w.Modified) {
+			t.Errorf("Storage.GetRecord() modified = %v, want %v", got.Modified, w.Modified)
+		}
+		if err := got.VerifyHashsum(); err != nil {
+			t.Errorf("Record.VerifyHashsum() error = %v", err)
+		}
+	}
+}
+
+func TestKeyring_Open(t *testing.T) {
+	keys, err := NewKeyring(testPassword)
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+
+	sealed, err := keys.Seal([]byte("secret"))
+	if err != nil {
+		t.Fatalf("Keyring.Seal() error = %v", err)
+	}
+	corrupted := bytes.Clone(sealed)
+	corrupted[len(corrupted)-1] ^= 1
+
+	tests := []struct {
+		name    string
+		lock    bool
+		data    []byte
+		wantErr error
+	}{
+		{name: "positive case unlocked", data: sealed},
+		{name: "negative case locked", lock: true, data: sealed, wantErr: ErrLocked},
+		{name: "negative case corrupted", data: corrupted, wantErr: ErrCorrupted},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.lock {
+				keys.Lock()
+				defer func() {
+					if err := keys.Unlock(testPassword); err != nil {
+						t.Errorf("Keyring.Unlock() error = %v", err)
+					}
+				}()
+			}
+
+			got, err := keys.Open(tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Keyring.Open() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && string(got) != "secret" {
+				t.Errorf("Keyring.Open() = %q, want %q", got, "secret")
+			}
+		})
+	}
+}
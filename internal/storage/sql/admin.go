@@ -0,0 +1,147 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+// ListUsers - Returns all the users sorted by the login.
+func (db *DB) ListUsers(ctx context.Context) ([]*models.UserInfo, error) {
+	sql := `SELECT id, login, coalesce(created, 'epoch'::timestamptz), disabled
+	FROM users
+	ORDER BY login;`
+
+	rows, err := db.pool.Query(ctx, sql)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while retrieving users, err: %w", err)
+	}
+	defer rows.Close()
+
+	var us []*models.UserInfo
+	for rows.Next() {
+		var u models.UserInfo
+		if err := rows.Scan(&u.ID, &u.Login, &u.Created, &u.Disabled); err != nil {
+			return nil, fmt.Errorf("an error occured while scan user, err: %w", err)
+		}
+		us = append(us, &u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("an error occured while read users, err: %w", err)
+	}
+
+	return us, nil
+}
+
+// GetUserInfo - Returns the user by the login.
+func (db *DB) GetUserInfo(ctx context.Context, login string) (*models.UserInfo, error) {
+	sql := `SELECT id, login, coalesce(created, 'epoch'::timestamptz), disabled
+	FROM users
+	WHERE login = $1;`
+
+	var u models.UserInfo
+	if err := db.pool.QueryRow(ctx, sql, login).Scan(&u.ID, &u.Login, &u.Created, &u.Disabled); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrUnknowUser
+		}
+		return nil, fmt.Errorf("an error occured while retrieving user, err: %w", err)
+	}
+
+	return &u, nil
+}
+
+// UserDisabled - Returns true if the user has been disabled by the administrator.
+func (db *DB) UserDisabled(ctx context.Context, userID string) (bool, error) {
+	sql := `SELECT disabled FROM users WHERE id = $1;`
+
+	var disabled bool
+	if err := db.pool.QueryRow(ctx, sql, userID).Scan(&disabled); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, models.ErrUnknowUser
+		}
+		return false, fmt.Errorf("an error occured while retrieving user status, err: %w", err)
+	}
+
+	return disabled, nil
+}
+
+// SetUserDisabled - Disables or enables the login of the user.
+func (db *DB) SetUserDisabled(ctx context.Context, userID string, disabled bool) error {
+	sql := `UPDATE users SET disabled = $2 WHERE id = $1;`
+
+	tag, err := db.pool.Exec(ctx, sql, userID, disabled)
+	if err != nil {
+		return fmt.Errorf("an error occured while update user, err: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrUnknowUser
+	}
+
+	return nil
+}
+
+// SetUserPassword - Replaces the hash of the password of the user.
+func (db *DB) SetUserPassword(ctx context.Context, userID string, passwordHash string) error {
+	sql := `UPDATE users SET pass = $2 WHERE id = $1;`
+
+	tag, err := db.pool.Exec(ctx, sql, userID, passwordHash)
+	if err != nil {
+		return fmt.Errorf("an error occured while update user password, err: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrUnknowUser
+	}
+
+	return nil
+}
+
+// PurgeTombstones - Removes the deleted records that have not been changed since the time,
+// together with their data and metadata.
+func (db *DB) PurgeTombstones(ctx context.Context, before time.Time) (int, error) {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf(tmpErrBeginTxErr(), err)
+	}
+	defer func(tx pgx.Tx) {
+		if err := tx.Rollback(ctx); err != nil {
+			if !errors.Is(err, pgx.ErrTxClosed) {
+				db.log.Error(tmpErrRollbackTxErr(), zap.Error(err))
+			}
+		}
+	}(tx)
+
+	sql := `SELECT id FROM records WHERE deleted AND modified < $1 FOR UPDATE;`
+	rows, err := tx.Query(ctx, sql, before)
+	if err != nil {
+		return 0, fmt.Errorf("an error occured while retrieving tombstones, err: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, fmt.Errorf("an error occured while read tombstones, err: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	for _, q := range []string{
+		`DELETE FROM metadata WHERE recordid = any ($1);`,
+		`DELETE FROM datarecords WHERE recordid = any ($1);`,
+		`DELETE FROM records WHERE id = any ($1);`,
+	} {
+		if _, err := tx.Exec(ctx, q, ids); err != nil {
+			return 0, fmt.Errorf("an error occured while purge tombstones, err: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf(tmpErrCommitTxErr(), err)
+	}
+
+	return len(ids), nil
+}
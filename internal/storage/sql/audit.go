@@ -0,0 +1,84 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+// AddAuditEvents - Appends the events to the audit log in one statement.
+func (db *DB) AddAuditEvents(ctx context.Context, events []*models.AuditEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	var (
+		userIDs   = make([]string, len(events))
+		actions   = make([]string, len(events))
+		created   = make([]time.Time, len(events))
+		deviceIDs = make([]string, len(events))
+		peerIPs   = make([]string, len(events))
+		recordIDs = make([]string, len(events))
+		details   = make([]string, len(events))
+	)
+	for i, e := range events {
+		userIDs[i] = e.UserID
+		actions[i] = string(e.Action)
+		created[i] = e.Time
+		deviceIDs[i] = e.DeviceID
+		peerIPs[i] = e.PeerIP
+		recordIDs[i] = e.RecordID
+		details[i] = e.Details
+	}
+
+	sql := `INSERT INTO audit_events(userid, action, created, deviceid, peer_ip, recordid, details)
+	SELECT u::uuid, a, c, nullif(d, '')::uuid, p, nullif(r, '')::uuid, dt
+	FROM unnest($1::text[], $2::text[], $3::timestamptz[], $4::text[], $5::text[], $6::text[], $7::text[])
+		AS e(u, a, c, d, p, r, dt);`
+
+	if _, err := db.pool.Exec(ctx, sql, userIDs, actions, created, deviceIDs, peerIPs, recordIDs, details); err != nil {
+		return fmt.Errorf("an occured error while add audit events, err: %w", err)
+	}
+
+	return nil
+}
+
+// ListAuditEvents - used to retrieving the events of the user, the latest events come first.
+// The name of the device is taken from the devices of the user.
+func (db *DB) ListAuditEvents(ctx context.Context,
+	userID string,
+	offset int,
+	limit int) ([]*models.AuditEvent, error) {
+	sql := `SELECT e.id, e.userid, e.action, e.created, coalesce(e.deviceid::text, ''), coalesce(d.name, ''),
+		e.peer_ip, coalesce(e.recordid::text, ''), e.details
+	FROM audit_events AS e
+		LEFT JOIN devices AS d
+		ON d.id = e.deviceid AND d.userid = e.userid
+	WHERE e.userid = $1
+	ORDER BY e.id DESC
+	LIMIT $2
+	OFFSET $3;`
+
+	rows, err := db.pool.Query(ctx, sql, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("an occured error while getting audit events, err: %w", err)
+	}
+	defer rows.Close()
+
+	var es []*models.AuditEvent
+	for rows.Next() {
+		var e models.AuditEvent
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Action, &e.Time, &e.DeviceID, &e.DeviceName,
+			&e.PeerIP, &e.RecordID, &e.Details); err != nil {
+			return nil, fmt.Errorf("an error occurred when filling in an array of audit events, err: %w", err)
+		}
+		es = append(es, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("an error occured while read audit events, err: %w", err)
+	}
+
+	return es, nil
+}
@@ -0,0 +1,120 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+// AddDevice - registers the device of the user.
+// If the user already has an active device with the same name, this device is returned.
+func (db *DB) AddDevice(ctx context.Context, userID string, device *models.DeviceDTO) (*models.Device, error) {
+	sql := `INSERT INTO devices(userid, name, platform, version)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (userid, name) WHERE NOT revoked DO UPDATE
+		SET platform = $3, version = $4, last_seen = CURRENT_TIMESTAMP
+	RETURNING id, userid, name, platform, version, created, last_seen, last_sync, revoked;`
+
+	row := db.pool.QueryRow(ctx, sql, userID, device.Name, device.Platform, device.Version)
+	d, err := scanDevice(row)
+	if err != nil {
+		return nil, fmt.Errorf("an occured error while add device, err: %w", err)
+	}
+
+	return d, nil
+}
+
+// GetDevice - used to retrieving device.
+func (db *DB) GetDevice(ctx context.Context, userID string, deviceID string) (*models.Device, error) {
+	sql := `SELECT id, userid, name, platform, version, created, last_seen, last_sync, revoked
+	FROM devices
+	WHERE userid = $1 AND id = $2;`
+
+	row := db.pool.QueryRow(ctx, sql, userID, deviceID)
+	d, err := scanDevice(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrDeviceNotFound
+		}
+		return nil, fmt.Errorf("an occured error while getting device, err: %w", err)
+	}
+
+	return d, nil
+}
+
+// ListDevices - used to retrieving user devices.
+func (db *DB) ListDevices(ctx context.Context, userID string) ([]*models.Device, error) {
+	sql := `SELECT id, userid, name, platform, version, created, last_seen, last_sync, revoked
+	FROM devices
+	WHERE userid = $1
+	ORDER BY created;`
+
+	rows, err := db.pool.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, fmt.Errorf("an occured error while getting devices, err: %w", err)
+	}
+	defer rows.Close()
+
+	var ds []*models.Device
+	for rows.Next() {
+		d, err := scanDevice(rows)
+		if err != nil {
+			return nil, fmt.Errorf("an error occurred when filling in an array of devices, err: %w", err)
+		}
+		ds = append(ds, d)
+	}
+
+	return ds, nil
+}
+
+// RevokeDevice - mark device as revoked.
+func (db *DB) RevokeDevice(ctx context.Context, userID string, deviceID string) error {
+	sql := `UPDATE devices SET revoked = true WHERE userid = $1 AND id = $2;`
+
+	tag, err := db.pool.Exec(ctx, sql, userID, deviceID)
+	if err != nil {
+		return fmt.Errorf("an occured error while revoke device, err: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return models.ErrDeviceNotFound
+	}
+
+	return nil
+}
+
+// TouchDevice - updates the date of the last request from the device
+// and, if synced is true, the date of the last synchronization.
+func (db *DB) TouchDevice(ctx context.Context, userID string, deviceID string, synced bool) error {
+	sql := `UPDATE devices SET last_seen = CURRENT_TIMESTAMP WHERE userid = $1 AND id = $2;`
+	if synced {
+		sql = `UPDATE devices SET last_seen = CURRENT_TIMESTAMP, last_sync = CURRENT_TIMESTAMP
+		WHERE userid = $1 AND id = $2;`
+	}
+
+	if _, err := db.pool.Exec(ctx, sql, userID, deviceID); err != nil {
+		return fmt.Errorf("an occured error while touch device, err: %w", err)
+	}
+
+	return nil
+}
+
+func scanDevice(row pgx.Row) (*models.Device, error) {
+	var d models.Device
+	var lastSync *time.Time
+	if err := row.Scan(&d.ID, &d.Owner, &d.Name, &d.Platform, &d.Version,
+		&d.Created, &d.LastSeen, &lastSync, &d.Revoked); err != nil {
+		return nil, fmt.Errorf("an occured error while scan device, err: %w", err)
+	}
+
+	if lastSync != nil {
+		d.LastSync = *lastSync
+	}
+
+	return &d, nil
+}
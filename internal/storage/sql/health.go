@@ -0,0 +1,68 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// errMigrationsOutdated - The error is returned if the schema of the database does not match the migrations.
+var errMigrationsOutdated = errors.New("database schema does not match the migrations")
+
+// Ping - Checks that the database is reachable through the connection pool.
+func (db *DB) Ping(ctx context.Context) error {
+	if err := db.pool.Ping(ctx); err != nil {
+		return fmt.Errorf("an error occured while ping database, err: %w", err)
+	}
+	return nil
+}
+
+// CheckMigrations - Checks that all the migrations of the build have been applied
+// and the last migration has not failed halfway.
+func (db *DB) CheckMigrations(ctx context.Context) error {
+	want, err := latestMigration()
+	if err != nil {
+		return err
+	}
+
+	var (
+		version uint
+		dirty   bool
+	)
+	q := `SELECT version, dirty FROM schema_migrations LIMIT 1`
+	if err := db.pool.QueryRow(ctx, q).Scan(&version, &dirty); err != nil {
+		return fmt.Errorf("an error occured while retrieving migration version, err: %w", err)
+	}
+
+	if dirty || version != want {
+		return fmt.Errorf("%w: version %d (dirty %t), want %d", errMigrationsOutdated, version, dirty, want)
+	}
+
+	return nil
+}
+
+// latestMigration - returns the version of the last embedded migration.
+func latestMigration() (uint, error) {
+	entries, err := fs.ReadDir(migrationsDir, "migrations")
+	if err != nil {
+		return 0, fmt.Errorf("an error occured while read migrations, err: %w", err)
+	}
+
+	var latest uint
+	for _, e := range entries {
+		prefix, _, ok := strings.Cut(e.Name(), "_")
+		if !ok {
+			continue
+		}
+		v, err := strconv.ParseUint(prefix, 10, 0)
+		if err != nil {
+			return 0, fmt.Errorf("an error occured while parse migration %s, err: %w", e.Name(), err)
+		}
+		latest = max(latest, uint(v))
+	}
+
+	return latest, nil
+}
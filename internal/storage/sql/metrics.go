@@ -0,0 +1,158 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	metricsNamespace = "gophkeeper"
+	// collectTimeout - the time limit of the queries of one scrape.
+	collectTimeout = 5 * time.Second
+	// recordsStatsTTL - the time the number and the size of the records are cached for,
+	// the records are aggregated over the whole table so they are not counted on every scrape.
+	recordsStatsTTL = time.Minute
+)
+
+// recordsStat - the number and the size of the records of one data type.
+type recordsStat struct {
+	dtype string
+	count int64
+	size  int64
+}
+
+// collector - Exports the statistics of the connection pool and the number and the size of the records.
+type collector struct {
+	db *DB
+
+	acquiredConns   *prometheus.Desc
+	idleConns       *prometheus.Desc
+	totalConns      *prometheus.Desc
+	maxConns        *prometheus.Desc
+	acquireCount    *prometheus.Desc
+	acquireDuration *prometheus.Desc
+	canceledAcquire *prometheus.Desc
+	emptyAcquire    *prometheus.Desc
+	records         *prometheus.Desc
+	recordBytes     *prometheus.Desc
+
+	mutex       sync.Mutex
+	stats       []recordsStat
+	collectedAt time.Time
+}
+
+// Collector - Returns the collector of the metrics of the database for the Prometheus registry.
+func (db *DB) Collector() prometheus.Collector {
+	pool := func(name string, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "db_pool", name), help, nil, nil)
+	}
+	byType := func(name string, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", name), help, []string{"type"}, nil)
+	}
+
+	return &collector{
+		db:              db,
+		acquiredConns:   pool("acquired_conns", "The number of the connections that are in use."),
+		idleConns:       pool("idle_conns", "The number of the idle connections."),
+		totalConns:      pool("total_conns", "The number of the open connections."),
+		maxConns:        pool("max_conns", "The maximum size of the pool."),
+		acquireCount:    pool("acquire_total", "The number of the successful acquires of connections."),
+		acquireDuration: pool("acquire_duration_seconds_total", "The total time of the successful acquires."),
+		canceledAcquire: pool("canceled_acquire_total", "The number of the acquires canceled by the context."),
+		emptyAcquire:    pool("empty_acquire_total", "The number of the acquires that waited for a connection."),
+		records:         byType("records", "The number of the stored records by the data type."),
+		recordBytes:     byType("record_bytes", "The size of the data of the stored records by the data type."),
+	}
+}
+
+// Describe - Implements prometheus.Collector.
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.totalConns
+	ch <- c.maxConns
+	ch <- c.acquireCount
+	ch <- c.acquireDuration
+	ch <- c.canceledAcquire
+	ch <- c.emptyAcquire
+	ch <- c.records
+	ch <- c.recordBytes
+}
+
+// Collect - Implements prometheus.Collector.
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	st := c.db.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(st.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(st.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(st.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(st.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(st.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, st.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquire, prometheus.CounterValue, float64(st.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquire, prometheus.CounterValue, float64(st.EmptyAcquireCount()))
+
+	stats, err := c.recordsStats()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.records, err)
+		return
+	}
+	for _, st := range stats {
+		ch <- prometheus.MustNewConstMetric(c.records, prometheus.GaugeValue, float64(st.count), st.dtype)
+		ch <- prometheus.MustNewConstMetric(c.recordBytes, prometheus.GaugeValue, float64(st.size), st.dtype)
+	}
+}
+
+// recordsStats - returns the cached number and size of the records, they are counted again once the cache expires.
+func (c *collector) recordsStats() ([]recordsStat, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.stats != nil && time.Since(c.collectedAt) < recordsStatsTTL {
+		return c.stats, nil
+	}
+
+	stats, err := c.countRecords()
+	if err != nil {
+		return nil, err
+	}
+	c.stats = stats
+	c.collectedAt = time.Now()
+
+	return stats, nil
+}
+
+// countRecords - counts the number and the size of the records that are not deleted.
+func (c *collector) countRecords() ([]recordsStat, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	sql := `SELECT r.dtype, count(*), coalesce(sum(length(d.data)), 0)
+	FROM records r
+	LEFT JOIN datarecords d ON d.recordid = r.id
+	WHERE NOT r.deleted
+	GROUP BY r.dtype;`
+
+	rows, err := c.db.pool.Query(ctx, sql)
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while count records, err: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make([]recordsStat, 0)
+	for rows.Next() {
+		var st recordsStat
+		if err := rows.Scan(&st.dtype, &st.count, &st.size); err != nil {
+			return nil, fmt.Errorf("an error occured while scan records count, err: %w", err)
+		}
+		stats = append(stats, st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("an error occured while read records count, err: %w", err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,3 @@
+begin transaction;
+drop table devices;
+commit;
\ No newline at end of file
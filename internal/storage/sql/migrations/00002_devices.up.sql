@@ -0,0 +1,21 @@
+begin transaction;
+
+-- Устройства пользователей
+create table devices(
+    id uuid default gen_random_uuid(),
+    userid uuid not null,
+    name varchar(200) not null,
+    platform varchar(100) not null,
+    version varchar(200) not null,
+    created timestamp with time zone default current_timestamp,
+    last_seen timestamp with time zone default current_timestamp,
+    last_sync timestamp with time zone,
+    revoked boolean not null default false,
+    primary key (id),
+    foreign key (userid) references users (id)
+);
+
+-- У пользователя может быть только одно действующее устройство с одним именем
+create unique index devices_active_name_idx on devices (userid, name) where not revoked;
+
+commit;
\ No newline at end of file
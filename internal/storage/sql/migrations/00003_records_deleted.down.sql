@@ -0,0 +1,3 @@
+begin transaction;
+alter table records drop column deleted;
+commit;
\ No newline at end of file
@@ -0,0 +1,6 @@
+begin transaction;
+
+-- Признак удаления записи. Удаленная запись хранится, чтобы удаление распространялось при синхронизации
+alter table records add column deleted bool not null default false;
+
+commit;
\ No newline at end of file
@@ -0,0 +1,3 @@
+begin transaction;
+alter table metadata drop column mtype;
+commit;
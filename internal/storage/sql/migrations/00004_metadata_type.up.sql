@@ -0,0 +1,6 @@
+begin transaction;
+
+-- Тип значения метаинформации, пустая строка - обычный текст. Порядок элементов задается столбцом seq
+alter table metadata add column mtype varchar(20) not null default '';
+
+commit;
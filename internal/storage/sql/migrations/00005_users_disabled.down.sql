@@ -0,0 +1,4 @@
+begin transaction;
+alter table users drop column created;
+alter table users drop column disabled;
+commit;
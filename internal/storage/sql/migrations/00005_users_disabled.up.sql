@@ -0,0 +1,8 @@
+begin transaction;
+
+-- Признак блокировки пользователя администратором, заблокированный пользователь не может войти
+alter table users add column disabled bool not null default false;
+-- Дата регистрации пользователя
+alter table users add column created timestamp with time zone default current_timestamp;
+
+commit;
@@ -0,0 +1,4 @@
+begin transaction;
+drop table audit_events;
+drop function audit_events_append_only;
+commit;
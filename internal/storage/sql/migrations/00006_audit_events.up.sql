@@ -0,0 +1,30 @@
+begin transaction;
+
+-- Журнал аудита действий пользователей, секретное содержимое записей в него не попадает
+create table audit_events(
+    id bigint generated always as identity,
+    userid uuid not null,
+    action varchar(50) not null,
+    created timestamp with time zone not null default current_timestamp,
+    deviceid uuid,
+    peer_ip varchar(64) not null default '',
+    recordid uuid,
+    details varchar(300) not null default '',
+    primary key (id),
+    foreign key (userid) references users (id)
+);
+
+-- События пользователя читаются от последних к первым
+create index audit_events_user_idx on audit_events (userid, id desc);
+
+-- Журнал только дополняется, изменить или удалить события нельзя
+create function audit_events_append_only() returns trigger as $$
+begin
+    raise exception 'audit_events is append-only';
+end;
+$$ language plpgsql;
+
+create trigger audit_events_append_only before update or delete on audit_events
+    for each row execute function audit_events_append_only();
+
+commit;
@@ -24,6 +24,7 @@ func tmpErrCommitTxErr() string {
 }
 
 // ListRecords - used to retrieving user records.
+// The records are ordered by ID, so the pages do not overlap.
 func (db *DB) ListRecords(ctx context.Context, userID string, offset int, limit int) ([]*models.Record, error) {
 	tx, err := db.pool.Begin(ctx)
 	if err != nil {
@@ -38,12 +39,14 @@ func (db *DB) ListRecords(ctx context.Context, userID string, offset int, limit
 		}
 	}(tx)
 
-	sql := `SELECT r.id, r.userid, r.description, r.dtype, r.created, r.modified, r.hashsum, r.version, dr.data
+	sql := `SELECT r.id, r.userid, r.description, r.dtype, r.created, r.modified, r.hashsum, r.version, r.deleted,
+		dr.data
 	FROM records as r
 		LEFT JOIN datarecords as dr
 		ON r.id = dr.recordid
 	WHERE userid = $1
-	LIMIT $2 
+	ORDER BY r.id
+	LIMIT $2
 	OFFSET $3`
 
 	rows, err := tx.Query(ctx, sql, userID, limit, offset)
@@ -57,7 +60,7 @@ func (db *DB) ListRecords(ctx context.Context, userID string, offset int, limit
 	for rows.Next() {
 		var r models.Record
 		err := rows.Scan(&r.ID, &r.Owner, &r.Description, &r.Type, &r.Created, &r.Modified,
-			&r.Hashsum, &r.Version, &r.Data)
+			&r.Hashsum, &r.Version, &r.Deleted, &r.Data)
 		if err != nil {
 			return nil, fmt.Errorf("an error occurred when filling in an array of records, err: %w", err)
 		}
@@ -85,7 +88,8 @@ func (db *DB) ListRecords(ctx context.Context, userID string, offset int, limit
 
 // GetRecord - used to retrieving record.
 func (db *DB) GetRecord(ctx context.Context, userID string, recordID string) (*models.Record, error) {
-	sql := `SELECT r.id, r.userid, r.description, r.dtype, r.created, r.modified, r.hashsum, r.version, dr.data
+	sql := `SELECT r.id, r.userid, r.description, r.dtype, r.created, r.modified, r.hashsum, r.version, r.deleted,
+		dr.data
 	FROM records as r
 		LEFT JOIN datarecords as dr
 		ON r.id = dr.recordid
@@ -95,7 +99,8 @@ func (db *DB) GetRecord(ctx context.Context, userID string, recordID string) (*m
 
 	row := db.pool.QueryRow(ctx, sql, userID, recordID)
 	if err := row.Scan(&r.ID,
-		&r.Owner, &r.Description, &r.Type, &r.Created, &r.Modified, &r.Hashsum, &r.Version, &r.Data); err != nil {
+		&r.Owner, &r.Description, &r.Type, &r.Created, &r.Modified, &r.Hashsum, &r.Version, &r.Deleted,
+		&r.Data); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, models.ErrRecordNotFound
 		}
@@ -137,13 +142,13 @@ func (db *DB) AddRecord(ctx context.Context, userID string, record *models.Recor
 		return nil, fmt.Errorf("an occured error while add record, err: %w", err)
 	}
 
-	if err := db.addDataRecord(ctx, tx, r.ID, record.Data); err != nil {
+	if err := db.addDataRecord(ctx, tx, userID, r.ID, record.Data); err != nil {
 		return nil, fmt.Errorf("an occured error while add data for record, err: %w", err)
 	}
 
 	r.Data = record.Data
 
-	if err := db.updateMetadatas(ctx, tx, r.ID, record.Metadata); err != nil {
+	if err := db.updateMetadatas(ctx, tx, userID, r.ID, record.Metadata); err != nil {
 		return nil, fmt.Errorf("an occured error while add record metadata, err: %w", err)
 	}
 	r.Metadata = record.Metadata
@@ -170,41 +175,114 @@ func (db *DB) UpdateRecord(ctx context.Context, userID string, record *models.Re
 		}
 	}(tx)
 
-	var r models.Record
+	r, err := db.upsertRecord(ctx, tx, userID, record)
+	if err != nil {
+		return nil, err
+	}
 
-	sql := `INSERT INTO records(id, description, dtype, userid, hashsum, version) VALUES ($1, $2, $3, $4, $5, $6)
-	ON CONFLICT (id) DO UPDATE SET description = $2, modified = CURRENT_TIMESTAMP, hashsum = $5, version = $6
-	RETURNING 
-		id, userid, description, dtype, created, modified, hashsum, version;`
-	row := tx.QueryRow(ctx, sql, record.ID, record.Description, record.Type, userID, record.Hashsum, record.Version)
-	if err := row.Scan(
-		&r.ID, &r.Owner, &r.Description, &r.Type, &r.Created, &r.Modified, &r.Hashsum, &r.Version); err != nil {
-		return nil, fmt.Errorf("an occured error while update record, err: %w", err)
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf(tmpErrCommitTxErr(), err)
 	}
 
-	if err := db.addDataRecord(ctx, tx, r.ID, record.Data); err != nil {
-		return nil, fmt.Errorf("an occured error while update data for record, err: %w", err)
+	return r, nil
+}
+
+// DeleteRecord - mark records as deleted.
+// The record is kept with an incremented version, so that the deletion is propagated during synchronization.
+func (db *DB) DeleteRecord(ctx context.Context, userID string, recordID string) error {
+	sql := `UPDATE records
+	SET deleted = true, modified = CURRENT_TIMESTAMP, version = version + 1
+	WHERE userid = $1 AND id = $2`
+
+	tag, err := db.pool.Exec(ctx, sql, userID, recordID)
+	if err != nil {
+		return fmt.Errorf("an occured error while delete record, err: %w", err)
 	}
 
-	if err := db.updateMetadatas(ctx, tx, r.ID, record.Metadata); err != nil {
-		return nil, fmt.Errorf("an occured error while update record metadata, err: %w", err)
+	if tag.RowsAffected() == 0 {
+		return models.ErrRecordNotFound
 	}
 
-	r.Data = record.Data
-	r.Metadata = record.Metadata
+	return nil
+}
+
+// BatchGetRecords - used to retrieving several records at once.
+func (db *DB) BatchGetRecords(ctx context.Context,
+	userID string, recordIDs []string) ([]*models.BatchResult, error) {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(tmpErrBeginTxErr(), err)
+	}
+
+	defer func(tx pgx.Tx) {
+		if err := tx.Rollback(ctx); err != nil {
+			if !errors.Is(err, pgx.ErrTxClosed) {
+				db.log.Error(tmpErrRollbackTxErr(), zap.Error(err))
+			}
+		}
+	}(tx)
+
+	sql := `SELECT r.id, r.userid, r.description, r.dtype, r.created, r.modified, r.hashsum, r.version, r.deleted,
+		dr.data
+	FROM records as r
+		LEFT JOIN datarecords as dr
+		ON r.id = dr.recordid
+	WHERE r.userid = $1 AND r.id = any ($2)`
+
+	rows, err := tx.Query(ctx, sql, userID, recordIDs)
+	if err != nil {
+		return nil, fmt.Errorf("an occured error while geting records, err: %w", err)
+	}
+	defer rows.Close()
+
+	rs := make(map[string]*models.Record, len(recordIDs))
+	var rids []string
+	for rows.Next() {
+		var r models.Record
+		err := rows.Scan(&r.ID, &r.Owner, &r.Description, &r.Type, &r.Created, &r.Modified,
+			&r.Hashsum, &r.Version, &r.Deleted, &r.Data)
+		if err != nil {
+			return nil, fmt.Errorf("an error occurred when filling in an array of records, err: %w", err)
+		}
+		rs[r.ID] = &r
+		rids = append(rids, r.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("an occured error while geting records, err: %w", err)
+	}
+
+	rmi, err := db.getRecordsMetadatas(ctx, tx, rids)
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred while retrieving an array metadatas of record, err: %w", err)
+	}
 
 	if err := tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf(tmpErrCommitTxErr(), err)
 	}
 
-	return &r, nil
+	brs := make([]*models.BatchResult, len(recordIDs))
+	for i, id := range recordIDs {
+		brs[i] = &models.BatchResult{ID: id}
+		r, ok := rs[id]
+		if !ok {
+			brs[i].Err = models.ErrRecordNotFound
+			continue
+		}
+		r.Metadata = rmi[id]
+		brs[i].Record = r
+	}
+
+	return brs, nil
 }
 
-// DeleteRecord - used to delete records as deleted.
-func (db *DB) DeleteRecord(ctx context.Context, userID string, recordID string) error {
+// BatchUpsertRecords - add or update several records at once.
+// All records are written in one transaction, a failed record is rolled back to its savepoint
+// and does not affect the other records.
+func (db *DB) BatchUpsertRecords(ctx context.Context,
+	userID string, records []*models.Record) ([]*models.BatchResult, error) {
 	tx, err := db.pool.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf(tmpErrBeginTxErr(), err)
+		return nil, fmt.Errorf(tmpErrBeginTxErr(), err)
 	}
 
 	defer func(tx pgx.Tx) {
@@ -215,32 +293,146 @@ func (db *DB) DeleteRecord(ctx context.Context, userID string, recordID string)
 		}
 	}(tx)
 
-	sql := `DELETE FROM datarecords WHERE userid = $1 and recordid = $2`
-	if _, err := tx.Exec(ctx, sql, userID, recordID); err != nil {
-		return fmt.Errorf("an occured error while delete data for record, err: %w", err)
+	brs := make([]*models.BatchResult, len(records))
+	for i, record := range records {
+		brs[i] = &models.BatchResult{ID: record.ID}
+		brs[i].Replaced, brs[i].Err = db.upsertRecordSavepoint(ctx, tx, userID, record)
 	}
 
-	sql = `DELETE FROM records WHERE userid = $1 and recordid = $2`
-	if _, err := tx.Exec(ctx, sql, userID, recordID); err != nil {
-		return fmt.Errorf("an occured error while delete record, err: %w", err)
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf(tmpErrCommitTxErr(), err)
 	}
 
-	if err := db.cleanUpRecordMetadata(ctx, tx, recordID); err != nil {
-		return fmt.Errorf("an occured error while delete records metadata, err: %w", err)
+	return brs, nil
+}
+
+// BatchDeleteRecords - mark several records as deleted at once.
+func (db *DB) BatchDeleteRecords(ctx context.Context,
+	userID string, recordIDs []string) ([]*models.BatchResult, error) {
+	sql := `UPDATE records
+	SET deleted = true, modified = CURRENT_TIMESTAMP, version = version + 1
+	WHERE userid = $1 AND id = any ($2)
+	RETURNING id`
+
+	rows, err := db.pool.Query(ctx, sql, userID, recordIDs)
+	if err != nil {
+		return nil, fmt.Errorf("an occured error while delete records, err: %w", err)
 	}
+	defer rows.Close()
 
-	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf(tmpErrCommitTxErr(), err)
+	deleted := make(map[string]struct{}, len(recordIDs))
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("an error occurred when filling in an array of deleted records, err: %w", err)
+		}
+		deleted[id] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("an occured error while delete records, err: %w", err)
 	}
 
-	return nil
+	brs := make([]*models.BatchResult, len(recordIDs))
+	for i, id := range recordIDs {
+		brs[i] = &models.BatchResult{ID: id}
+		if _, ok := deleted[id]; !ok {
+			brs[i].Err = models.ErrRecordNotFound
+		}
+	}
+
+	return brs, nil
+}
+
+// upsertRecordSavepoint - add or update the record under the savepoint.
+// Returns the replaced version of the record, nil if the record is new.
+func (db *DB) upsertRecordSavepoint(ctx context.Context,
+	tx pgx.Tx, userID string, record *models.Record) (*models.Record, error) {
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("an occured error while trying create savepoint, err: %w", err)
+	}
+
+	replaced, err := db.lockRecordVersion(ctx, sp, userID, record.ID)
+	if err == nil {
+		_, err = db.upsertRecord(ctx, sp, userID, record)
+	}
+	if err != nil {
+		if err := sp.Rollback(ctx); err != nil {
+			db.log.Error("an occured error while trying rollback to savepoint", zap.Error(err))
+		}
+		return nil, err
+	}
+
+	if err := sp.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("an occured error while trying release savepoint, err: %w", err)
+	}
+
+	return replaced, nil
+}
+
+// lockRecordVersion - locks the stored record until the end of the transaction and returns its version,
+// hashsum and deleted flag. Returns nil if the record is not stored.
+func (db *DB) lockRecordVersion(ctx context.Context, tx pgx.Tx, userID string, recordID string) (*models.Record, error) {
+	sql := `SELECT id, hashsum, version, deleted
+	FROM records
+	WHERE userid = $1 AND id = $2
+	FOR UPDATE;`
+
+	r := models.Record{}
+	row := tx.QueryRow(ctx, sql, userID, recordID)
+	if err := row.Scan(&r.ID, &r.Hashsum, &r.Version, &r.Deleted); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("an occured error while lock record, err: %w", err)
+	}
+
+	return &r, nil
+}
+
+// upsertRecord - add or update the record of the user.
+// The record of another user with the same ID is not updated, ErrRecordNotFound is returned for it,
+// so the records of the other users are neither changed nor disclosed.
+func (db *DB) upsertRecord(ctx context.Context, tx pgx.Tx, userID string, record *models.Record) (*models.Record, error) {
+	var r models.Record
+
+	sql := `INSERT INTO records(id, description, dtype, userid, hashsum, version, deleted)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (id) DO UPDATE
+		SET description = $2, modified = CURRENT_TIMESTAMP, hashsum = $5, version = $6, deleted = $7
+		WHERE records.userid = EXCLUDED.userid
+	RETURNING 
+		id, userid, description, dtype, created, modified, hashsum, version, deleted;`
+	row := tx.QueryRow(ctx, sql, record.ID, record.Description, record.Type, userID, record.Hashsum, record.Version,
+		record.Deleted)
+	if err := row.Scan(&r.ID, &r.Owner, &r.Description, &r.Type, &r.Created, &r.Modified, &r.Hashsum, &r.Version,
+		&r.Deleted); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("an occured error while update record, err: %w", err)
+	}
+
+	if err := db.addDataRecord(ctx, tx, userID, r.ID, record.Data); err != nil {
+		return nil, fmt.Errorf("an occured error while update data for record, err: %w", err)
+	}
+
+	if err := db.updateMetadatas(ctx, tx, userID, r.ID, record.Metadata); err != nil {
+		return nil, fmt.Errorf("an occured error while update record metadata, err: %w", err)
+	}
+
+	r.Data = record.Data
+	r.Metadata = record.Metadata
+
+	return &r, nil
 }
 
-func (db *DB) addDataRecord(ctx context.Context, tx pgx.Tx, recordID string, recordData []byte) error {
+// addDataRecord - add or update the data of the record, only the record of the user is written.
+func (db *DB) addDataRecord(ctx context.Context, tx pgx.Tx, userID string, recordID string, recordData []byte) error {
 	sql := `INSERT INTO datarecords(recordid, data)
-	VALUES ($1, $2)
-	ON CONFLICT (recordid) DO UPDATE SET data = $2`
-	if _, err := tx.Exec(ctx, sql, recordID, recordData); err != nil {
+	SELECT id, $3 FROM records WHERE userid = $1 AND id = $2
+	ON CONFLICT (recordid) DO UPDATE SET data = EXCLUDED.data`
+	if _, err := tx.Exec(ctx, sql, userID, recordID, recordData); err != nil {
 		return fmt.Errorf("add or update data for record was failed, err: %w", err)
 	}
 
@@ -249,9 +441,10 @@ func (db *DB) addDataRecord(ctx context.Context, tx pgx.Tx, recordID string, rec
 
 func (db *DB) getRecordsMetadatas(ctx context.Context,
 	tx pgx.Tx, recordsID []string) (map[string][]*models.Metadata, error) {
-	sql := `SELECT recordid, key, value
+	sql := `SELECT recordid, key, value, mtype
 	FROM metadata
-	WHERE recordid = any ($1);`
+	WHERE recordid = any ($1)
+	ORDER BY seq;`
 	rows, err := tx.Query(ctx, sql, recordsID)
 	if err != nil {
 		return nil, fmt.Errorf("an occured error while geting metaoinfos, err: %w", err)
@@ -264,7 +457,7 @@ func (db *DB) getRecordsMetadatas(ctx context.Context,
 		recordID := ""
 		var mi models.Metadata
 
-		if err := rows.Scan(&recordID, &mi.Key, &mi.Value); err != nil {
+		if err := rows.Scan(&recordID, &mi.Key, &mi.Value, &mi.Type); err != nil {
 			return nil, fmt.Errorf("an error occurred when filling in an array of metadatas, err: %w", err)
 		}
 
@@ -274,34 +467,54 @@ func (db *DB) getRecordsMetadatas(ctx context.Context,
 	return rmi, nil
 }
 
-func (db *DB) updateMetadatas(ctx context.Context, tx pgx.Tx, recordID string, mis []*models.Metadata) error {
-	if err := db.cleanUpRecordMetadata(ctx, tx, recordID); err != nil {
+// updateMetadatas - replaces the metadata of the record, only the record of the user is written.
+func (db *DB) updateMetadatas(ctx context.Context,
+	tx pgx.Tx, userID string, recordID string, mis []*models.Metadata) error {
+	if err := db.cleanUpRecordMetadata(ctx, tx, userID, recordID); err != nil {
 		return fmt.Errorf("an occured error while do clean up metadata, err: %w", err)
 	}
 	for _, mi := range mis {
-		if err := db.addMetadata(ctx, tx, recordID, mi); err != nil {
+		if err := db.addMetadata(ctx, tx, userID, recordID, mi); err != nil {
 			return fmt.Errorf("an error occured while update metadata (recordID=%s), err: %w", recordID, err)
 		}
 	}
 	return nil
 }
 
-func (db *DB) cleanUpRecordMetadata(ctx context.Context, tx pgx.Tx, recordID string) error {
-	sql := `DELETE FROM metadata WHERE recordid = $1;`
+func (db *DB) cleanUpRecordMetadata(ctx context.Context, tx pgx.Tx, userID string, recordID string) error {
+	sql := `DELETE FROM metadata m
+	USING records r
+	WHERE m.recordid = r.id AND r.userid = $1 AND r.id = $2;`
 
-	if _, err := tx.Exec(ctx, sql, recordID); err != nil {
+	if _, err := tx.Exec(ctx, sql, userID, recordID); err != nil {
 		return fmt.Errorf("an occured error while cleaning up metadata, err: %w", err)
 	}
 
 	return nil
 }
 
-func (db *DB) addMetadata(ctx context.Context, tx pgx.Tx, recordID string, mi *models.Metadata) error {
-	sql := `INSERT INTO metadata(recordid, key, value) VALUES ($1, $2, $3);`
+func (db *DB) addMetadata(ctx context.Context, tx pgx.Tx, userID string, recordID string, mi *models.Metadata) error {
+	sql := `INSERT INTO metadata(recordid, key, value, mtype)
+	SELECT id, $3, $4, $5 FROM records WHERE userid = $1 AND id = $2;`
 
-	if _, err := tx.Exec(ctx, sql, recordID, mi.Key, mi.Value); err != nil {
+	if _, err := tx.Exec(ctx, sql, userID, recordID, mi.Key, mi.Value, mi.Type); err != nil {
 		return fmt.Errorf("an occured error while do add or update metadata, err: %w", err)
 	}
 
 	return nil
 }
+
+// Usage - Returns the number and the size of the records of the user that are not deleted.
+func (db *DB) Usage(ctx context.Context, userID string) (*models.Usage, error) {
+	sql := `SELECT count(*), coalesce(sum(length(d.data)), 0)
+	FROM records r
+	LEFT JOIN datarecords d ON d.recordid = r.id
+	WHERE r.userid = $1 AND NOT r.deleted;`
+
+	var u models.Usage
+	if err := db.pool.QueryRow(ctx, sql, userID).Scan(&u.Records, &u.Bytes); err != nil {
+		return nil, fmt.Errorf("an error occured while retrieving usage of user, err: %w", err)
+	}
+
+	return &u, nil
+}
@@ -0,0 +1,125 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/models"
+)
+
+// testDSNEnv - the DSN of the test database, the tests of the storage are skipped if it is not set.
+const testDSNEnv = "TEST_DATABASE_DSN"
+
+func newTestDB(t *testing.T) *DB {
+	t.Helper()
+
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDSNEnv)
+	}
+
+	db, err := NewDB(context.Background(), dsn, PoolCfg{}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewDB() error = %v", err)
+	}
+	t.Cleanup(db.Close)
+
+	return db
+}
+
+func newTestUser(t *testing.T, db *DB) *models.User {
+	t.Helper()
+
+	u, err := db.AddUser(context.Background(), &models.UserDTO{Login: uuid.NewString(), Password: "password"})
+	if err != nil {
+		t.Fatalf("AddUser() error = %v", err)
+	}
+	return u
+}
+
+func TestDB_UpsertRecordOfAnotherUser(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	owner := newTestUser(t, db)
+	other := newTestUser(t, db)
+
+	ownerMeta := []*models.Metadata{{Key: "site", Value: "owner.example"}}
+	rdto, err := models.NewRecordDTO("owner", models.TextType, &models.Text{Data: "owner data"}, ownerMeta)
+	if err != nil {
+		t.Fatalf("NewRecordDTO() error = %v", err)
+	}
+	stored, err := db.AddRecord(ctx, owner.ID, rdto)
+	if err != nil {
+		t.Fatalf("AddRecord() error = %v", err)
+	}
+
+	forged, err := models.NewRecord(stored.ID, "forged", models.TextType, time.Now(), time.Now(),
+		&models.Text{Data: "forged data"}, []*models.Metadata{{Key: "site", Value: "other.example"}},
+		false, stored.Version+1)
+	if err != nil {
+		t.Fatalf("NewRecord() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		upsert func() error
+	}{
+		{
+			name: "update record",
+			upsert: func() error {
+				_, err := db.UpdateRecord(ctx, other.ID, forged)
+				return err
+			},
+		},
+		{
+			name: "batch upsert records",
+			upsert: func() error {
+				brs, err := db.BatchUpsertRecords(ctx, other.ID, []*models.Record{forged})
+				if err != nil {
+					return err
+				}
+				return brs[0].Err
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.upsert(); !errors.Is(err, models.ErrRecordNotFound) {
+				t.Fatalf("upsert of the record of another user error = %v, want %v", err, models.ErrRecordNotFound)
+			}
+
+			got, err := db.GetRecord(ctx, owner.ID, stored.ID)
+			if err != nil {
+				t.Fatalf("GetRecord() error = %v", err)
+			}
+			if got.Description != stored.Description || got.Hashsum != stored.Hashsum ||
+				got.Version != stored.Version || string(got.Data) != string(rdto.Data) {
+				t.Errorf("the record of the owner is changed: %+v", got)
+			}
+			tx, err := db.pool.Begin(ctx)
+			if err != nil {
+				t.Fatalf("Begin() error = %v", err)
+			}
+			defer func() { _ = tx.Rollback(ctx) }()
+			mis, err := db.getRecordsMetadatas(ctx, tx, []string{stored.ID})
+			if err != nil {
+				t.Fatalf("getRecordsMetadatas() error = %v", err)
+			}
+			if len(mis[stored.ID]) != 1 || mis[stored.ID][0].Value != ownerMeta[0].Value {
+				t.Errorf("the metadata of the owner is changed: %+v", mis[stored.ID])
+			}
+
+			if _, err := db.GetRecord(ctx, other.ID, stored.ID); !errors.Is(err, models.ErrRecordNotFound) {
+				t.Errorf("GetRecord() of another user error = %v, want %v", err, models.ErrRecordNotFound)
+			}
+		})
+	}
+}
@@ -3,22 +3,55 @@ package sql
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
+// PoolCfg - The settings of the pool of the connections, the zero settings keep the defaults of pgx.
+type PoolCfg struct {
+	MaxConns        int
+	MinConns        int
+	MaxConnLifetime time.Duration
+	MaxConnIdleTime time.Duration
+}
+
 type DB struct {
 	pool *pgxpool.Pool
 	log  *zap.Logger
 }
 
-func NewDB(ctx context.Context, dsn string, log *zap.Logger) (*DB, error) {
+func NewDB(ctx context.Context, dsn string, pc PoolCfg, log *zap.Logger) (*DB, error) {
 	if err := runMigrations(dsn); err != nil {
 		return nil, fmt.Errorf("failed to run DB migrations: %w", err)
 	}
 
-	pool, err := pgxpool.New(ctx, dsn)
+	return OpenDB(ctx, dsn, pc, log)
+}
+
+// OpenDB - Opens the database without applying the migrations,
+// so the schema of the database is left as it is, for example, for its check by the administrator.
+func OpenDB(ctx context.Context, dsn string, pc PoolCfg, log *zap.Logger) (*DB, error) {
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DSN: %w", err)
+	}
+	cfg.ConnConfig.Tracer = newQueryTracer()
+	if pc.MaxConns > 0 {
+		cfg.MaxConns = int32(pc.MaxConns)
+	}
+	if pc.MinConns > 0 {
+		cfg.MinConns = int32(pc.MinConns)
+	}
+	if pc.MaxConnLifetime > 0 {
+		cfg.MaxConnLifetime = pc.MaxConnLifetime
+	}
+	if pc.MaxConnIdleTime > 0 {
+		cfg.MaxConnIdleTime = pc.MaxConnIdleTime
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create a connection pool: %w", err)
 	}
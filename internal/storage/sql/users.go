@@ -37,14 +37,14 @@ func (db *DB) AddUser(ctx context.Context, us *models.UserDTO) (*models.User, er
 
 // GetUser - This method is used when the user logs in.
 func (db *DB) GetUser(ctx context.Context, us *models.UserDTO) (*models.User, error) {
-	sql := `SELECT id, login, pass
+	sql := `SELECT id, login, pass, disabled
 	FROM users
 	WHERE login = $1;`
 
 	row := db.pool.QueryRow(ctx, sql, us.Login)
 
 	u := models.User{}
-	if err := row.Scan(&u.ID, &u.Login, &u.PasswordHash); err != nil {
+	if err := row.Scan(&u.ID, &u.Login, &u.PasswordHash, &u.Disabled); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, models.ErrUnknowUser
 		}
@@ -0,0 +1,90 @@
+// Package tracing configures the export of the OpenTelemetry traces of the client and the server.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+
+	"github.com/ArtemShalinFe/gophkeeper/internal/build"
+)
+
+// The exporters of the traces.
+const (
+	// ExporterNone - The traces are not recorded.
+	ExporterNone = "none"
+	// ExporterOTLP - The traces are sent to the collector over OTLP/gRPC.
+	// The collector is set by the standard OTEL_EXPORTER_OTLP_* environment variables.
+	ExporterOTLP = "otlp"
+	// ExporterStdout - The traces are written to the output as JSON, it is used for local debugging.
+	ExporterStdout = "stdout"
+)
+
+// Shutdown - Flushes the recorded spans and stops the exporter.
+type Shutdown func(ctx context.Context) error
+
+// Setup - Sets the global tracer provider with the exporter and the W3C trace context propagator.
+//
+// The spans of the stdout exporter are written to w. With the ExporterNone exporter
+// the spans are not recorded, but the trace context of the incoming requests is still propagated.
+func Setup(ctx context.Context, exporter string, service string, w io.Writer) (Shutdown, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exp sdktrace.SpanExporter
+	switch exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		e, err := otlptracegrpc.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("an error occured while create OTLP exporter, err: %w", err)
+		}
+		exp = e
+	case ExporterStdout:
+		if w == nil {
+			w = os.Stdout
+		}
+		e, err := stdouttrace.New(stdouttrace.WithWriter(w))
+		if err != nil {
+			return nil, fmt.Errorf("an error occured while create stdout exporter, err: %w", err)
+		}
+		exp = e
+	default:
+		return nil, fmt.Errorf("unknown traces exporter %q, want one of %s, %s, %s",
+			exporter, ExporterNone, ExporterOTLP, ExporterStdout)
+	}
+
+	b := build.NewBuild()
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(service),
+		semconv.ServiceVersion(b.Version()),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("an error occured while create trace resource, err: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+
+	return func(ctx context.Context) error {
+		if err := tp.Shutdown(ctx); err != nil {
+			return fmt.Errorf("an error occured while shutdown tracer provider, err: %w", err)
+		}
+		return nil
+	}, nil
+}
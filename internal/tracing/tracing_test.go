@@ -0,0 +1,63 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name     string
+		exporter string
+		wantSpan bool
+		wantErr  bool
+	}{
+		{
+			name:     "disabled by default",
+			exporter: "",
+		},
+		{
+			name:     "none exporter",
+			exporter: ExporterNone,
+		},
+		{
+			name:     "stdout exporter writes spans",
+			exporter: ExporterStdout,
+			wantSpan: true,
+		},
+		{
+			name:     "unknown exporter",
+			exporter: "jaeger",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			var b bytes.Buffer
+
+			shutdown, err := Setup(ctx, tt.exporter, "test", &b)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Setup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			_, span := otel.Tracer("test").Start(ctx, "test-span")
+			span.End()
+			if err := shutdown(ctx); err != nil {
+				t.Fatalf("Shutdown() error = %v", err)
+			}
+
+			if got := strings.Contains(b.String(), "test-span"); got != tt.wantSpan {
+				t.Errorf("the output contains the span = %v, want %v", got, tt.wantSpan)
+			}
+		})
+	}
+}
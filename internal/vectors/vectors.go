@@ -3,6 +3,7 @@ package vectors
 type Vector interface {
 	GetVersion() int64
 	GetHashsum() string
+	IsDeleted() bool
 }
 
 const (
@@ -33,7 +34,9 @@ func (c *Comparison) Compare() string {
 		return VectorAIsLowerVectorB
 	}
 
-	if c.VectorA.GetVersion() == c.VectorB.GetVersion() && c.VectorA.GetHashsum() == c.VectorB.GetHashsum() {
+	if c.VectorA.GetVersion() == c.VectorB.GetVersion() &&
+		c.VectorA.GetHashsum() == c.VectorB.GetHashsum() &&
+		c.VectorA.IsDeleted() == c.VectorB.IsDeleted() {
 		return VectorAIsEqualsVectorB
 	}
 
@@ -7,6 +7,7 @@ import (
 type vector struct {
 	vers    int64
 	hashsum string
+	deleted bool
 }
 
 func newVector(vers int64, hashsum string) *vector {
@@ -24,6 +25,10 @@ func (v *vector) GetHashsum() string {
 	return v.hashsum
 }
 
+func (v *vector) IsDeleted() bool {
+	return v.deleted
+}
+
 func TestComparison_Compare(t *testing.T) {
 	type fields struct {
 		VectorA Vector
@@ -66,6 +71,22 @@ func TestComparison_Compare(t *testing.T) {
 			},
 			want: VectorAIsConflictVectorB,
 		},
+		{
+			name: "IsConflictDeleted",
+			fields: fields{
+				VectorA: &vector{vers: 2, hashsum: "1", deleted: true},
+				VectorB: newVector(2, "1"),
+			},
+			want: VectorAIsConflictVectorB,
+		},
+		{
+			name: "IsHigherDeleted",
+			fields: fields{
+				VectorA: &vector{vers: 2, hashsum: "1", deleted: true},
+				VectorB: newVector(1, "1"),
+			},
+			want: VectorAIsHigherVectorB,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {